@@ -0,0 +1,68 @@
+// Command vault-hsm-plugin is the reference HSM plugin. It serves a
+// SoftwareHSM over the HSMPlugin protocol on a Unix socket, as a starting
+// point for vendor integrations and for testing the plugin path.
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/hsm/plugin"
+)
+
+func main() {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	socket := os.Getenv("VAULT_PLUGIN_SOCKET")
+	if socket == "" {
+		socket = "/tmp/vault-hsm-plugin.sock"
+	}
+	// A socket left behind by a previous run would fail Listen.
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Error("remove stale socket", "error", err)
+		os.Exit(1)
+	}
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		slog.Error("listen", "error", err)
+		os.Exit(1)
+	}
+	if err := os.Chmod(socket, 0o600); err != nil {
+		slog.Error("restrict socket", "error", err)
+		os.Exit(1)
+	}
+
+	// Without a keystore the keys only live as long as the process.
+	software := hsm.NewSoftwareHSM()
+	if path := os.Getenv("VAULT_PLUGIN_KEYSTORE"); path != "" {
+		if software, err = hsm.OpenSoftwareHSM(path); err != nil {
+			slog.Error("open keystore", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	srv := grpc.NewServer()
+	health := plugin.Register(srv, software)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		slog.Info("hsm plugin listening", "socket", socket)
+		if err := srv.Serve(lis); err != nil {
+			slog.Error("serve", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	slog.Info("shutting down")
+	health.Shutdown()
+	srv.GracefulStop()
+}
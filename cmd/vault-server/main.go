@@ -2,8 +2,15 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -11,15 +18,20 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	pb "github.com/glinharesb/vault-go/gen/vault/v1"
 	"github.com/glinharesb/vault-go/internal/audit"
 	"github.com/glinharesb/vault-go/internal/config"
+	"github.com/glinharesb/vault-go/internal/hostcmd"
 	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/hsm/plugin"
 	"github.com/glinharesb/vault-go/internal/interceptor"
 	"github.com/glinharesb/vault-go/internal/keystore"
 	"github.com/glinharesb/vault-go/internal/server"
+	"github.com/glinharesb/vault-go/internal/tokenize"
 )
 
 func main() {
@@ -30,40 +42,98 @@ func main() {
 	auditLogger := audit.NewLogger(cfg.AuditBuffer, os.Stdout)
 	defer auditLogger.Close()
 
+	healthSrv := health.NewServer()
+	providers, faults, software, err := newHSMRegistry(cfg, healthSrv)
+	if err != nil {
+		slog.Error("hsm providers", "error", err)
+		os.Exit(1)
+	}
+	defer providers.Close()
+	slog.Info("hsm providers", "names", providers.Names(), "default", providers.Default())
+	expvar.Publish("hsm", expvar.Func(func() any { return providers.Stats() }))
+
 	var store keystore.Store
+	var tokens tokenize.Table
 	if cfg.DataDir != "" {
 		ps, err := keystore.NewPersistentStore(filepath.Join(cfg.DataDir, "keys.json"))
 		if err != nil {
 			slog.Error("persistent store", "error", err)
 			os.Exit(1)
 		}
-		store = ps
+		pt, err := tokenize.NewPersistentTable(filepath.Join(cfg.DataDir, "tokens.json"))
+		if err != nil {
+			slog.Error("persistent token table", "error", err)
+			os.Exit(1)
+		}
+		// Keys saved with their private key move into the software
+		// provider, and out of keys.json.
+		if err := ps.AdoptSoftwareKeys(software); err != nil {
+			slog.Error("persistent store", "error", err)
+			os.Exit(1)
+		}
+		store, tokens = ps, pt
 		slog.Info("using persistent store", "path", cfg.DataDir)
 	} else {
-		store = keystore.NewMemoryStore()
+		store, tokens = keystore.NewMemoryStore(), tokenize.NewMemoryTable()
 		slog.Info("using in-memory store")
 	}
-	hsmProvider := hsm.NewSoftwareHSM()
+	// Keys derived by path resolve through the store without being saved.
+	store = keystore.NewDerivedStore(store, software)
+	if software != nil {
+		slog.Info("software hsm provider holds derived keys", "provider", software.Provider)
+	}
+
+	principals, err := newPrincipals(cfg)
+	if err != nil {
+		slog.Error("principals", "error", err)
+		os.Exit(1)
+	}
 
 	srv := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageSize),
 		grpc.ChainUnaryInterceptor(
 			interceptor.RecoveryUnary(),
 			interceptor.LoggingUnary(),
 			interceptor.RateLimitUnary(cfg.RateLimitRPS),
-			interceptor.AuthUnary(cfg.AuthToken),
+			interceptor.AuthUnary(principals),
 		),
 		grpc.ChainStreamInterceptor(
 			interceptor.RecoveryStream(),
 			interceptor.LoggingStream(),
 			interceptor.RateLimitStream(cfg.RateLimitRPS),
-			interceptor.AuthStream(cfg.AuthToken),
+			interceptor.AuthStream(principals),
 		),
 	)
 
-	pb.RegisterKeyManagementServiceServer(srv, server.NewKeyManagementServer(store, hsmProvider, auditLogger))
-	pb.RegisterSigningServiceServer(srv, server.NewSigningServer(store, hsmProvider, auditLogger))
-	pb.RegisterEncryptionServiceServer(srv, server.NewEncryptionServer(store, auditLogger))
+	keyServer := server.NewKeyManagementServer(store, providers, auditLogger)
+	macServer := server.NewMacServer(store, auditLogger)
+
+	pb.RegisterKeyManagementServiceServer(srv, keyServer)
+	if cfg.MaxBatchSignSize <= 0 {
+		slog.Error("invalid batch sign size", "size", cfg.MaxBatchSignSize)
+		os.Exit(1)
+	}
+	pb.RegisterSigningServiceServer(srv, server.NewSigningServer(store, providers, cfg.MaxBatchSignSize, auditLogger))
+	noncePolicy := server.NoncePolicy{WarnAt: cfg.EncryptionWarnAt}
+	switch cfg.EncryptionLimitAction {
+	case "rotate":
+		noncePolicy.Rotate = true
+	case "refuse":
+	default:
+		slog.Error("invalid encryption limit action", "action", cfg.EncryptionLimitAction)
+		os.Exit(1)
+	}
+	pb.RegisterEncryptionServiceServer(srv, server.NewEncryptionServer(store, keyServer, noncePolicy, auditLogger))
+	pb.RegisterMacServiceServer(srv, macServer)
+	pb.RegisterTokenizationServiceServer(srv, server.NewTokenizationServer(store, tokens, auditLogger))
 	pb.RegisterAuditServiceServer(srv, server.NewAuditServer(auditLogger))
+	pb.RegisterHSMAdminServiceServer(srv, server.NewHSMAdminServer(faults, auditLogger))
+	pb.RegisterCapabilitiesServiceServer(srv, server.NewCapabilitiesServer(providers, server.Limits{
+		MaxMessageSize:   maxMessageSize,
+		RateLimitRPS:     cfg.RateLimitRPS,
+		MaxBatchSignSize: cfg.MaxBatchSignSize,
+	}))
+	healthpb.RegisterHealthServer(srv, healthSrv)
 	reflection.Register(srv)
 
 	lis, err := net.Listen("tcp", cfg.GRPCAddr)
@@ -82,8 +152,37 @@ func main() {
 		}
 	}()
 
+	if cfg.HostCmdAddr != "" {
+		hostLis, err := newHostCmdListener(cfg)
+		if err != nil {
+			slog.Error("listen host commands", "error", err)
+			os.Exit(1)
+		}
+		hostSrv := hostcmd.NewServer(hostcmd.NewHandler(store, keyServer, macServer, auditLogger), cfg.HostCmdHeaderLen, cfg.RateLimitRPS)
+		go func() {
+			slog.Info("host command listener starting", "addr", hostLis.Addr().String())
+			if err := hostSrv.Serve(ctx, hostLis); err != nil {
+				slog.Error("serve host commands", "error", err)
+			}
+		}()
+	}
+
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		metricsSrv := &http.Server{Addr: cfg.MetricsAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			slog.Info("metrics listener starting", "addr", cfg.MetricsAddr)
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("serve metrics", "error", err)
+			}
+		}()
+		defer metricsSrv.Close()
+	}
+
 	<-ctx.Done()
 	slog.Info("shutting down")
+	healthSrv.Shutdown()
 
 	// Graceful shutdown with 10s timeout
 	done := make(chan struct{})
@@ -100,3 +199,203 @@ func main() {
 		srv.Stop()
 	}
 }
+
+// maxMessageSize is the largest request message the server accepts, the
+// gRPC default made explicit so it can be reported to clients.
+const maxMessageSize = 4 << 20
+
+// hsmHealthService prefixes the gRPC health service names that report
+// each HSM provider's health. Unlike the overall status, they need a token.
+const hsmHealthService = "vault.hsm."
+
+// newHSMRegistry opens the configured HSM providers, each behind a circuit
+// breaker, and publishes their health to healthSrv. It also returns the
+// providers that faults can be injected into, by name, and the first
+// provider of type software, which holds derived keys and those the store
+// file held itself, if there is one. It is picked by type so these keys
+// are never sent to another kind of provider that happens to be named
+// software.
+func newHSMRegistry(cfg config.Config, healthSrv *health.Server) (*hsm.Registry, map[string]*hsm.FaultHSM, *keystore.SoftwareKeys, error) {
+	r := hsm.NewRegistry()
+	update := func() {
+		for name, stats := range r.Stats() {
+			st := healthpb.HealthCheckResponse_NOT_SERVING
+			if stats.Healthy {
+				st = healthpb.HealthCheckResponse_SERVING
+			}
+			healthSrv.SetServingStatus(hsmHealthService+name, st)
+		}
+	}
+
+	breakers := make(map[string]*hsm.Breaker)
+	faults := make(map[string]*hsm.FaultHSM)
+	types := make(map[string]string)
+	var software *keystore.SoftwareKeys
+	closeAll := func() {
+		for _, b := range breakers {
+			b.Close()
+		}
+	}
+	for _, pc := range cfg.HSMProviders {
+		if _, dup := breakers[pc.Name]; dup {
+			closeAll()
+			return nil, nil, nil, fmt.Errorf("%s: provider configured twice", pc.Name)
+		}
+		p, err := openHSMProvider(pc, cfg.DataDir)
+		if err != nil {
+			closeAll()
+			return nil, nil, nil, fmt.Errorf("%s: %w", pc.Name, err)
+		}
+		name := pc.Name
+		if s, ok := p.(*hsm.SoftwareHSM); ok && software == nil {
+			software = &keystore.SoftwareKeys{Provider: name, HSM: s}
+		}
+		if pc.Faults {
+			// Faults go under the breaker so they exercise it too.
+			f := hsm.NewFaultHSM(p)
+			faults[name] = f
+			p = f
+			slog.Warn("hsm fault injection enabled", "provider", name)
+		}
+		breakers[name] = hsm.NewBreaker(name, p, hsm.BreakerConfig{
+			Threshold: cfg.HSMBreakerThreshold,
+			Cooldown:  cfg.HSMBreakerCooldown,
+			OnChange: func(healthy bool) {
+				if healthy {
+					slog.Info("hsm provider healthy", "provider", name)
+				} else {
+					slog.Warn("hsm provider unavailable", "provider", name)
+				}
+				update()
+			},
+		})
+		types[name] = pc.Type
+	}
+	if len(breakers) == 0 {
+		return nil, nil, nil, errors.New("no hsm providers configured")
+	}
+
+	for _, pc := range cfg.HSMProviders {
+		var p hsm.Provider = breakers[pc.Name]
+		if pc.Failover != "" {
+			secondary, ok := breakers[pc.Failover]
+			switch {
+			case !ok || pc.Failover == pc.Name:
+				closeAll()
+				return nil, nil, nil, fmt.Errorf("%s: unknown failover provider %q", pc.Name, pc.Failover)
+			case types[pc.Failover] != pc.Type:
+				// Handles are only valid on a provider of the type that
+				// issued them.
+				closeAll()
+				return nil, nil, nil, fmt.Errorf("%s: failover provider %q is %s, not %s", pc.Name, pc.Failover, types[pc.Failover], pc.Type)
+			}
+			p = hsm.NewFailover(p, secondary)
+		}
+		if err := r.Register(pc.Name, p); err != nil {
+			closeAll()
+			return nil, nil, nil, err
+		}
+	}
+	if cfg.HSMDefault != "" {
+		if err := r.SetDefault(cfg.HSMDefault); err != nil {
+			closeAll()
+			return nil, nil, nil, err
+		}
+	}
+
+	for _, b := range breakers {
+		b.Watch(cfg.HSMProbeInterval)
+	}
+	update()
+	return r, faults, software, nil
+}
+
+// openHSMProvider opens a provider of the configured type. A software
+// provider without a keystore of its own keeps its keys in dataDir, if
+// set, so their handles stay valid across restarts.
+func openHSMProvider(pc config.HSMProvider, dataDir string) (hsm.Provider, error) {
+	switch pc.Type {
+	case "software":
+		if pc.Keystore == "" {
+			if dataDir == "" {
+				return hsm.NewSoftwareHSM(), nil
+			}
+			return hsm.OpenSoftwareHSM(filepath.Join(dataDir, "hsm-"+pc.Name+".json"))
+		}
+		master, err := hex.DecodeString(pc.MasterKey)
+		if err != nil {
+			return nil, fmt.Errorf("master key: %w", err)
+		}
+		defer clear(master)
+		return hsm.OpenSealedSoftwareHSM(hsm.SealedConfig{Path: pc.Keystore, MasterKey: master})
+	case "pkcs11":
+		return hsm.OpenPKCS11(hsm.PKCS11Config{
+			Module:     pc.Module,
+			TokenLabel: pc.TokenLabel,
+			PIN:        pc.PIN,
+			Sessions:   pc.Sessions,
+		})
+	case "plugin":
+		return plugin.Dial(plugin.Config{Socket: pc.Socket, Timeout: pc.Timeout})
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", pc.Type)
+	}
+}
+
+// newPrincipals maps bearer tokens to principals. The default token
+// authenticates with no extra permissions; VAULT_PRINCIPALS adds named
+// tokens that may hold some. A token may belong to one principal only, so
+// a repeated one is rejected rather than silently taking over another
+// principal's permissions.
+func newPrincipals(cfg config.Config) (map[string]*interceptor.Principal, error) {
+	principals := map[string]*interceptor.Principal{
+		cfg.AuthToken: {Name: "default"},
+	}
+	for _, p := range cfg.Principals {
+		if other, ok := principals[p.Token]; ok {
+			return nil, fmt.Errorf("principal %q has the same token as %q", p.Name, other.Name)
+		}
+		principals[p.Token] = &interceptor.Principal{Name: p.Name, Permissions: p.Permissions}
+	}
+	return principals, nil
+}
+
+// newHostCmdListener listens for host commands over mutual TLS. The host
+// command set has no authentication of its own, so the listener refuses to
+// start without a server certificate and a CA for client certificates, and
+// binds to loopback when the address names no host.
+func newHostCmdListener(cfg config.Config) (net.Listener, error) {
+	if cfg.HostCmdTLSCert == "" || cfg.HostCmdTLSKey == "" || cfg.HostCmdClientCA == "" {
+		return nil, errors.New("VAULT_HOSTCMD_TLS_CERT, VAULT_HOSTCMD_TLS_KEY and VAULT_HOSTCMD_CLIENT_CA are required")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.HostCmdTLSCert, cfg.HostCmdTLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(cfg.HostCmdClientCA)
+	if err != nil {
+		return nil, fmt.Errorf("read client ca: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates in %s", cfg.HostCmdClientCA)
+	}
+
+	host, port, err := net.SplitHostPort(cfg.HostCmdAddr)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	lis, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(lis, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
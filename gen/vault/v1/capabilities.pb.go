@@ -0,0 +1,587 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: vault/v1/capabilities.proto
+
+package vaultpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SignatureFormat is the encoding of the signatures a provider returns.
+type SignatureFormat int32
+
+const (
+	SignatureFormat_SIGNATURE_FORMAT_UNSPECIFIED SignatureFormat = 0
+	// SIGNATURE_FORMAT_ASN1_DER is an ASN.1 DER ECDSA-Sig-Value.
+	SignatureFormat_SIGNATURE_FORMAT_ASN1_DER SignatureFormat = 1
+)
+
+// Enum value maps for SignatureFormat.
+var (
+	SignatureFormat_name = map[int32]string{
+		0: "SIGNATURE_FORMAT_UNSPECIFIED",
+		1: "SIGNATURE_FORMAT_ASN1_DER",
+	}
+	SignatureFormat_value = map[string]int32{
+		"SIGNATURE_FORMAT_UNSPECIFIED": 0,
+		"SIGNATURE_FORMAT_ASN1_DER":    1,
+	}
+)
+
+func (x SignatureFormat) Enum() *SignatureFormat {
+	p := new(SignatureFormat)
+	*p = x
+	return p
+}
+
+func (x SignatureFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SignatureFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_capabilities_proto_enumTypes[0].Descriptor()
+}
+
+func (SignatureFormat) Type() protoreflect.EnumType {
+	return &file_vault_v1_capabilities_proto_enumTypes[0]
+}
+
+func (x SignatureFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SignatureFormat.Descriptor instead.
+func (SignatureFormat) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_capabilities_proto_rawDescGZIP(), []int{0}
+}
+
+// HashFunction is a hash function a signature is computed over.
+type HashFunction int32
+
+const (
+	HashFunction_HASH_FUNCTION_UNSPECIFIED HashFunction = 0
+	HashFunction_HASH_FUNCTION_SHA256      HashFunction = 1
+	HashFunction_HASH_FUNCTION_SHA384      HashFunction = 2
+	HashFunction_HASH_FUNCTION_SHA512      HashFunction = 3
+)
+
+// Enum value maps for HashFunction.
+var (
+	HashFunction_name = map[int32]string{
+		0: "HASH_FUNCTION_UNSPECIFIED",
+		1: "HASH_FUNCTION_SHA256",
+		2: "HASH_FUNCTION_SHA384",
+		3: "HASH_FUNCTION_SHA512",
+	}
+	HashFunction_value = map[string]int32{
+		"HASH_FUNCTION_UNSPECIFIED": 0,
+		"HASH_FUNCTION_SHA256":      1,
+		"HASH_FUNCTION_SHA384":      2,
+		"HASH_FUNCTION_SHA512":      3,
+	}
+)
+
+func (x HashFunction) Enum() *HashFunction {
+	p := new(HashFunction)
+	*p = x
+	return p
+}
+
+func (x HashFunction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HashFunction) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_capabilities_proto_enumTypes[1].Descriptor()
+}
+
+func (HashFunction) Type() protoreflect.EnumType {
+	return &file_vault_v1_capabilities_proto_enumTypes[1]
+}
+
+func (x HashFunction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HashFunction.Descriptor instead.
+func (HashFunction) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_capabilities_proto_rawDescGZIP(), []int{1}
+}
+
+// GetCapabilitiesRequest takes no parameters.
+type GetCapabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_vault_v1_capabilities_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_capabilities_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_capabilities_proto_rawDescGZIP(), []int{0}
+}
+
+// ProviderCapabilities describes one HSM provider.
+type ProviderCapabilities struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the provider name keys are generated in.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// default is set on the provider keys go to when a request names none.
+	Default bool `protobuf:"varint,2,opt,name=default,proto3" json:"default,omitempty"`
+	// healthy reports whether the provider currently serves requests.
+	Healthy bool `protobuf:"varint,3,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// algorithms are the key algorithms the provider generates keys for.
+	Algorithms []KeyAlgorithm `protobuf:"varint,4,rep,packed,name=algorithms,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithms,omitempty"`
+	// operations are the operations the provider performs on its keys.
+	// Requests needing another one fail with FAILED_PRECONDITION.
+	Operations []HsmOperation `protobuf:"varint,5,rep,packed,name=operations,proto3,enum=vault.v1.HsmOperation" json:"operations,omitempty"`
+	// signature_hashes are the hashes data is signed over.
+	SignatureHashes []HashFunction `protobuf:"varint,6,rep,packed,name=signature_hashes,json=signatureHashes,proto3,enum=vault.v1.HashFunction" json:"signature_hashes,omitempty"`
+	// signature_formats are the encodings of the signatures returned.
+	SignatureFormats []SignatureFormat `protobuf:"varint,7,rep,packed,name=signature_formats,json=signatureFormats,proto3,enum=vault.v1.SignatureFormat" json:"signature_formats,omitempty"`
+	// derive_hashes are the HKDF hashes DeriveKey accepts for the
+	// provider's keys.
+	DeriveHashes []HkdfHash `protobuf:"varint,8,rep,packed,name=derive_hashes,json=deriveHashes,proto3,enum=vault.v1.HkdfHash" json:"derive_hashes,omitempty"`
+	// key_lifecycle is set when the provider deactivates and destroys keys
+	// along with the vault.
+	KeyLifecycle bool `protobuf:"varint,9,opt,name=key_lifecycle,json=keyLifecycle,proto3" json:"key_lifecycle,omitempty"`
+	// max_concurrency is the most operations the provider runs at once, or
+	// 0 for no fixed limit.
+	MaxConcurrency uint32 `protobuf:"varint,10,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	// error is set when the provider's capabilities could not be read, for
+	// instance from a plugin that is down. The other fields are then empty.
+	Error string `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	// key_import is set when ECDSA keys can be imported into the provider
+	// with KeyManagementService.ImportKey.
+	KeyImport     bool `protobuf:"varint,12,opt,name=key_import,json=keyImport,proto3" json:"key_import,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderCapabilities) Reset() {
+	*x = ProviderCapabilities{}
+	mi := &file_vault_v1_capabilities_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderCapabilities) ProtoMessage() {}
+
+func (x *ProviderCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_capabilities_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderCapabilities.ProtoReflect.Descriptor instead.
+func (*ProviderCapabilities) Descriptor() ([]byte, []int) {
+	return file_vault_v1_capabilities_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProviderCapabilities) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProviderCapabilities) GetDefault() bool {
+	if x != nil {
+		return x.Default
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetAlgorithms() []KeyAlgorithm {
+	if x != nil {
+		return x.Algorithms
+	}
+	return nil
+}
+
+func (x *ProviderCapabilities) GetOperations() []HsmOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *ProviderCapabilities) GetSignatureHashes() []HashFunction {
+	if x != nil {
+		return x.SignatureHashes
+	}
+	return nil
+}
+
+func (x *ProviderCapabilities) GetSignatureFormats() []SignatureFormat {
+	if x != nil {
+		return x.SignatureFormats
+	}
+	return nil
+}
+
+func (x *ProviderCapabilities) GetDeriveHashes() []HkdfHash {
+	if x != nil {
+		return x.DeriveHashes
+	}
+	return nil
+}
+
+func (x *ProviderCapabilities) GetKeyLifecycle() bool {
+	if x != nil {
+		return x.KeyLifecycle
+	}
+	return false
+}
+
+func (x *ProviderCapabilities) GetMaxConcurrency() uint32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *ProviderCapabilities) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ProviderCapabilities) GetKeyImport() bool {
+	if x != nil {
+		return x.KeyImport
+	}
+	return false
+}
+
+// Limits are the server's request limits.
+type Limits struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// max_batch_sign_size is the most items a BatchSign request may hold.
+	MaxBatchSignSize uint32 `protobuf:"varint,1,opt,name=max_batch_sign_size,json=maxBatchSignSize,proto3" json:"max_batch_sign_size,omitempty"`
+	// max_message_size is the largest request message, in bytes.
+	MaxMessageSize uint32 `protobuf:"varint,2,opt,name=max_message_size,json=maxMessageSize,proto3" json:"max_message_size,omitempty"`
+	// rate_limit_rps is the requests per second the server admits, shared
+	// by all clients. Requests above it fail with RESOURCE_EXHAUSTED.
+	RateLimitRps uint32 `protobuf:"varint,3,opt,name=rate_limit_rps,json=rateLimitRps,proto3" json:"rate_limit_rps,omitempty"`
+	// min_stream_segment_size and max_stream_segment_size bound the segment
+	// size of EncryptStream, in bytes.
+	MinStreamSegmentSize uint32 `protobuf:"varint,4,opt,name=min_stream_segment_size,json=minStreamSegmentSize,proto3" json:"min_stream_segment_size,omitempty"`
+	MaxStreamSegmentSize uint32 `protobuf:"varint,5,opt,name=max_stream_segment_size,json=maxStreamSegmentSize,proto3" json:"max_stream_segment_size,omitempty"`
+	// default_stream_segment_size is the segment size EncryptStream uses
+	// when none is given.
+	DefaultStreamSegmentSize uint32 `protobuf:"varint,6,opt,name=default_stream_segment_size,json=defaultStreamSegmentSize,proto3" json:"default_stream_segment_size,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
+	mi := &file_vault_v1_capabilities_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Limits) ProtoMessage() {}
+
+func (x *Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_capabilities_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
+	return file_vault_v1_capabilities_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Limits) GetMaxBatchSignSize() uint32 {
+	if x != nil {
+		return x.MaxBatchSignSize
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxMessageSize() uint32 {
+	if x != nil {
+		return x.MaxMessageSize
+	}
+	return 0
+}
+
+func (x *Limits) GetRateLimitRps() uint32 {
+	if x != nil {
+		return x.RateLimitRps
+	}
+	return 0
+}
+
+func (x *Limits) GetMinStreamSegmentSize() uint32 {
+	if x != nil {
+		return x.MinStreamSegmentSize
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxStreamSegmentSize() uint32 {
+	if x != nil {
+		return x.MaxStreamSegmentSize
+	}
+	return 0
+}
+
+func (x *Limits) GetDefaultStreamSegmentSize() uint32 {
+	if x != nil {
+		return x.DefaultStreamSegmentSize
+	}
+	return 0
+}
+
+// GetCapabilitiesResponse describes the server.
+type GetCapabilitiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// providers are the configured HSM providers, by name.
+	Providers []*ProviderCapabilities `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	// software_algorithms are the key algorithms the server generates and
+	// uses in software, whichever provider is named.
+	SoftwareAlgorithms []KeyAlgorithm `protobuf:"varint,2,rep,packed,name=software_algorithms,json=softwareAlgorithms,proto3,enum=vault.v1.KeyAlgorithm" json:"software_algorithms,omitempty"`
+	// limits are the server's request limits.
+	Limits        *Limits `protobuf:"bytes,3,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_vault_v1_capabilities_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_capabilities_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_capabilities_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCapabilitiesResponse) GetProviders() []*ProviderCapabilities {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *GetCapabilitiesResponse) GetSoftwareAlgorithms() []KeyAlgorithm {
+	if x != nil {
+		return x.SoftwareAlgorithms
+	}
+	return nil
+}
+
+func (x *GetCapabilitiesResponse) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+var File_vault_v1_capabilities_proto protoreflect.FileDescriptor
+
+const file_vault_v1_capabilities_proto_rawDesc = "" +
+	"\n" +
+	"\x1bvault/v1/capabilities.proto\x12\bvault.v1\x1a\x19vault/v1/encryption.proto\x1a\x18vault/v1/hsm_admin.proto\x1a\x16vault/v1/keymgmt.proto\"\x18\n" +
+	"\x16GetCapabilitiesRequest\"\x95\x04\n" +
+	"\x14ProviderCapabilities\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\adefault\x18\x02 \x01(\bR\adefault\x12\x18\n" +
+	"\ahealthy\x18\x03 \x01(\bR\ahealthy\x126\n" +
+	"\n" +
+	"algorithms\x18\x04 \x03(\x0e2\x16.vault.v1.KeyAlgorithmR\n" +
+	"algorithms\x126\n" +
+	"\n" +
+	"operations\x18\x05 \x03(\x0e2\x16.vault.v1.HsmOperationR\n" +
+	"operations\x12A\n" +
+	"\x10signature_hashes\x18\x06 \x03(\x0e2\x16.vault.v1.HashFunctionR\x0fsignatureHashes\x12F\n" +
+	"\x11signature_formats\x18\a \x03(\x0e2\x19.vault.v1.SignatureFormatR\x10signatureFormats\x127\n" +
+	"\rderive_hashes\x18\b \x03(\x0e2\x12.vault.v1.HkdfHashR\fderiveHashes\x12#\n" +
+	"\rkey_lifecycle\x18\t \x01(\bR\fkeyLifecycle\x12'\n" +
+	"\x0fmax_concurrency\x18\n" +
+	" \x01(\rR\x0emaxConcurrency\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"key_import\x18\f \x01(\bR\tkeyImport\"\xb4\x02\n" +
+	"\x06Limits\x12-\n" +
+	"\x13max_batch_sign_size\x18\x01 \x01(\rR\x10maxBatchSignSize\x12(\n" +
+	"\x10max_message_size\x18\x02 \x01(\rR\x0emaxMessageSize\x12$\n" +
+	"\x0erate_limit_rps\x18\x03 \x01(\rR\frateLimitRps\x125\n" +
+	"\x17min_stream_segment_size\x18\x04 \x01(\rR\x14minStreamSegmentSize\x125\n" +
+	"\x17max_stream_segment_size\x18\x05 \x01(\rR\x14maxStreamSegmentSize\x12=\n" +
+	"\x1bdefault_stream_segment_size\x18\x06 \x01(\rR\x18defaultStreamSegmentSize\"\xca\x01\n" +
+	"\x17GetCapabilitiesResponse\x12<\n" +
+	"\tproviders\x18\x01 \x03(\v2\x1e.vault.v1.ProviderCapabilitiesR\tproviders\x12G\n" +
+	"\x13software_algorithms\x18\x02 \x03(\x0e2\x16.vault.v1.KeyAlgorithmR\x12softwareAlgorithms\x12(\n" +
+	"\x06limits\x18\x03 \x01(\v2\x10.vault.v1.LimitsR\x06limits*R\n" +
+	"\x0fSignatureFormat\x12 \n" +
+	"\x1cSIGNATURE_FORMAT_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19SIGNATURE_FORMAT_ASN1_DER\x10\x01*{\n" +
+	"\fHashFunction\x12\x1d\n" +
+	"\x19HASH_FUNCTION_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14HASH_FUNCTION_SHA256\x10\x01\x12\x18\n" +
+	"\x14HASH_FUNCTION_SHA384\x10\x02\x12\x18\n" +
+	"\x14HASH_FUNCTION_SHA512\x10\x032m\n" +
+	"\x13CapabilitiesService\x12V\n" +
+	"\x0fGetCapabilities\x12 .vault.v1.GetCapabilitiesRequest\x1a!.vault.v1.GetCapabilitiesResponseB5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+
+var (
+	file_vault_v1_capabilities_proto_rawDescOnce sync.Once
+	file_vault_v1_capabilities_proto_rawDescData []byte
+)
+
+func file_vault_v1_capabilities_proto_rawDescGZIP() []byte {
+	file_vault_v1_capabilities_proto_rawDescOnce.Do(func() {
+		file_vault_v1_capabilities_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vault_v1_capabilities_proto_rawDesc), len(file_vault_v1_capabilities_proto_rawDesc)))
+	})
+	return file_vault_v1_capabilities_proto_rawDescData
+}
+
+var file_vault_v1_capabilities_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_vault_v1_capabilities_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_vault_v1_capabilities_proto_goTypes = []any{
+	(SignatureFormat)(0),            // 0: vault.v1.SignatureFormat
+	(HashFunction)(0),               // 1: vault.v1.HashFunction
+	(*GetCapabilitiesRequest)(nil),  // 2: vault.v1.GetCapabilitiesRequest
+	(*ProviderCapabilities)(nil),    // 3: vault.v1.ProviderCapabilities
+	(*Limits)(nil),                  // 4: vault.v1.Limits
+	(*GetCapabilitiesResponse)(nil), // 5: vault.v1.GetCapabilitiesResponse
+	(KeyAlgorithm)(0),               // 6: vault.v1.KeyAlgorithm
+	(HsmOperation)(0),               // 7: vault.v1.HsmOperation
+	(HkdfHash)(0),                   // 8: vault.v1.HkdfHash
+}
+var file_vault_v1_capabilities_proto_depIdxs = []int32{
+	6, // 0: vault.v1.ProviderCapabilities.algorithms:type_name -> vault.v1.KeyAlgorithm
+	7, // 1: vault.v1.ProviderCapabilities.operations:type_name -> vault.v1.HsmOperation
+	1, // 2: vault.v1.ProviderCapabilities.signature_hashes:type_name -> vault.v1.HashFunction
+	0, // 3: vault.v1.ProviderCapabilities.signature_formats:type_name -> vault.v1.SignatureFormat
+	8, // 4: vault.v1.ProviderCapabilities.derive_hashes:type_name -> vault.v1.HkdfHash
+	3, // 5: vault.v1.GetCapabilitiesResponse.providers:type_name -> vault.v1.ProviderCapabilities
+	6, // 6: vault.v1.GetCapabilitiesResponse.software_algorithms:type_name -> vault.v1.KeyAlgorithm
+	4, // 7: vault.v1.GetCapabilitiesResponse.limits:type_name -> vault.v1.Limits
+	2, // 8: vault.v1.CapabilitiesService.GetCapabilities:input_type -> vault.v1.GetCapabilitiesRequest
+	5, // 9: vault.v1.CapabilitiesService.GetCapabilities:output_type -> vault.v1.GetCapabilitiesResponse
+	9, // [9:10] is the sub-list for method output_type
+	8, // [8:9] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_vault_v1_capabilities_proto_init() }
+func file_vault_v1_capabilities_proto_init() {
+	if File_vault_v1_capabilities_proto != nil {
+		return
+	}
+	file_vault_v1_encryption_proto_init()
+	file_vault_v1_hsm_admin_proto_init()
+	file_vault_v1_keymgmt_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_capabilities_proto_rawDesc), len(file_vault_v1_capabilities_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vault_v1_capabilities_proto_goTypes,
+		DependencyIndexes: file_vault_v1_capabilities_proto_depIdxs,
+		EnumInfos:         file_vault_v1_capabilities_proto_enumTypes,
+		MessageInfos:      file_vault_v1_capabilities_proto_msgTypes,
+	}.Build()
+	File_vault_v1_capabilities_proto = out.File
+	file_vault_v1_capabilities_proto_goTypes = nil
+	file_vault_v1_capabilities_proto_depIdxs = nil
+}
@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v6.33.4
+// source: vault/v1/capabilities.proto
+
+package vaultpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CapabilitiesService_GetCapabilities_FullMethodName = "/vault.v1.CapabilitiesService/GetCapabilities"
+)
+
+// CapabilitiesServiceClient is the client API for CapabilitiesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CapabilitiesService reports what the running server supports, so clients
+// can negotiate features instead of probing with calls that fail.
+type CapabilitiesServiceClient interface {
+	// GetCapabilities returns the capabilities of each HSM provider and the
+	// server's limits.
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+}
+
+type capabilitiesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCapabilitiesServiceClient(cc grpc.ClientConnInterface) CapabilitiesServiceClient {
+	return &capabilitiesServiceClient{cc}
+}
+
+func (c *capabilitiesServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, CapabilitiesService_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CapabilitiesServiceServer is the server API for CapabilitiesService service.
+// All implementations must embed UnimplementedCapabilitiesServiceServer
+// for forward compatibility.
+//
+// CapabilitiesService reports what the running server supports, so clients
+// can negotiate features instead of probing with calls that fail.
+type CapabilitiesServiceServer interface {
+	// GetCapabilities returns the capabilities of each HSM provider and the
+	// server's limits.
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	mustEmbedUnimplementedCapabilitiesServiceServer()
+}
+
+// UnimplementedCapabilitiesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCapabilitiesServiceServer struct{}
+
+func (UnimplementedCapabilitiesServiceServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedCapabilitiesServiceServer) mustEmbedUnimplementedCapabilitiesServiceServer() {}
+func (UnimplementedCapabilitiesServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeCapabilitiesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CapabilitiesServiceServer will
+// result in compilation errors.
+type UnsafeCapabilitiesServiceServer interface {
+	mustEmbedUnimplementedCapabilitiesServiceServer()
+}
+
+func RegisterCapabilitiesServiceServer(s grpc.ServiceRegistrar, srv CapabilitiesServiceServer) {
+	// If the following call panics, it indicates UnimplementedCapabilitiesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CapabilitiesService_ServiceDesc, srv)
+}
+
+func _CapabilitiesService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CapabilitiesServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CapabilitiesService_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CapabilitiesServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CapabilitiesService_ServiceDesc is the grpc.ServiceDesc for CapabilitiesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CapabilitiesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vault.v1.CapabilitiesService",
+	HandlerType: (*CapabilitiesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _CapabilitiesService_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vault/v1/capabilities.proto",
+}
@@ -21,10 +21,253 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// FpeAlgorithm selects the format-preserving encryption mode.
+type FpeAlgorithm int32
+
+const (
+	// FPE_ALGORITHM_UNSPECIFIED defaults to FF1.
+	FpeAlgorithm_FPE_ALGORITHM_UNSPECIFIED FpeAlgorithm = 0
+	// FPE_ALGORITHM_FF1 accepts tweaks of any length.
+	FpeAlgorithm_FPE_ALGORITHM_FF1 FpeAlgorithm = 1
+	// FPE_ALGORITHM_FF3_1 requires a 7-byte (56-bit) tweak.
+	FpeAlgorithm_FPE_ALGORITHM_FF3_1 FpeAlgorithm = 2
+)
+
+// Enum value maps for FpeAlgorithm.
+var (
+	FpeAlgorithm_name = map[int32]string{
+		0: "FPE_ALGORITHM_UNSPECIFIED",
+		1: "FPE_ALGORITHM_FF1",
+		2: "FPE_ALGORITHM_FF3_1",
+	}
+	FpeAlgorithm_value = map[string]int32{
+		"FPE_ALGORITHM_UNSPECIFIED": 0,
+		"FPE_ALGORITHM_FF1":         1,
+		"FPE_ALGORITHM_FF3_1":       2,
+	}
+)
+
+func (x FpeAlgorithm) Enum() *FpeAlgorithm {
+	p := new(FpeAlgorithm)
+	*p = x
+	return p
+}
+
+func (x FpeAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FpeAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_encryption_proto_enumTypes[0].Descriptor()
+}
+
+func (FpeAlgorithm) Type() protoreflect.EnumType {
+	return &file_vault_v1_encryption_proto_enumTypes[0]
+}
+
+func (x FpeAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FpeAlgorithm.Descriptor instead.
+func (FpeAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{0}
+}
+
+// HkdfHash selects the hash function HKDF is instantiated with.
+type HkdfHash int32
+
+const (
+	// HKDF_HASH_UNSPECIFIED defaults to HKDF_HASH_SHA256.
+	HkdfHash_HKDF_HASH_UNSPECIFIED HkdfHash = 0
+	HkdfHash_HKDF_HASH_SHA256      HkdfHash = 1
+	HkdfHash_HKDF_HASH_SHA384      HkdfHash = 2
+	HkdfHash_HKDF_HASH_SHA512      HkdfHash = 3
+)
+
+// Enum value maps for HkdfHash.
+var (
+	HkdfHash_name = map[int32]string{
+		0: "HKDF_HASH_UNSPECIFIED",
+		1: "HKDF_HASH_SHA256",
+		2: "HKDF_HASH_SHA384",
+		3: "HKDF_HASH_SHA512",
+	}
+	HkdfHash_value = map[string]int32{
+		"HKDF_HASH_UNSPECIFIED": 0,
+		"HKDF_HASH_SHA256":      1,
+		"HKDF_HASH_SHA384":      2,
+		"HKDF_HASH_SHA512":      3,
+	}
+)
+
+func (x HkdfHash) Enum() *HkdfHash {
+	p := new(HkdfHash)
+	*p = x
+	return p
+}
+
+func (x HkdfHash) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HkdfHash) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_encryption_proto_enumTypes[1].Descriptor()
+}
+
+func (HkdfHash) Type() protoreflect.EnumType {
+	return &file_vault_v1_encryption_proto_enumTypes[1]
+}
+
+func (x HkdfHash) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HkdfHash.Descriptor instead.
+func (HkdfHash) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{1}
+}
+
+// HpkeAead selects the HPKE AEAD.
+type HpkeAead int32
+
+const (
+	// HPKE_AEAD_UNSPECIFIED defaults to AES-128-GCM.
+	HpkeAead_HPKE_AEAD_UNSPECIFIED HpkeAead = 0
+	// HPKE_AEAD_AES_128_GCM is AES-128-GCM.
+	HpkeAead_HPKE_AEAD_AES_128_GCM HpkeAead = 1
+	// HPKE_AEAD_AES_256_GCM is AES-256-GCM.
+	HpkeAead_HPKE_AEAD_AES_256_GCM HpkeAead = 2
+	// HPKE_AEAD_CHACHA20_POLY1305 is ChaCha20-Poly1305.
+	HpkeAead_HPKE_AEAD_CHACHA20_POLY1305 HpkeAead = 3
+)
+
+// Enum value maps for HpkeAead.
+var (
+	HpkeAead_name = map[int32]string{
+		0: "HPKE_AEAD_UNSPECIFIED",
+		1: "HPKE_AEAD_AES_128_GCM",
+		2: "HPKE_AEAD_AES_256_GCM",
+		3: "HPKE_AEAD_CHACHA20_POLY1305",
+	}
+	HpkeAead_value = map[string]int32{
+		"HPKE_AEAD_UNSPECIFIED":       0,
+		"HPKE_AEAD_AES_128_GCM":       1,
+		"HPKE_AEAD_AES_256_GCM":       2,
+		"HPKE_AEAD_CHACHA20_POLY1305": 3,
+	}
+)
+
+func (x HpkeAead) Enum() *HpkeAead {
+	p := new(HpkeAead)
+	*p = x
+	return p
+}
+
+func (x HpkeAead) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HpkeAead) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_encryption_proto_enumTypes[2].Descriptor()
+}
+
+func (HpkeAead) Type() protoreflect.EnumType {
+	return &file_vault_v1_encryption_proto_enumTypes[2]
+}
+
+func (x HpkeAead) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HpkeAead.Descriptor instead.
+func (HpkeAead) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{2}
+}
+
+// FpeFormat describes the character set and the parts of the input that
+// are left in the clear.
+type FpeFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// alphabet lists the characters of the domain in numeral order. When empty,
+	// the first radix characters of 0-9a-zA-Z are used.
+	Alphabet string `protobuf:"bytes,1,opt,name=alphabet,proto3" json:"alphabet,omitempty"`
+	// radix is the alphabet size. Defaults to 10; when alphabet is set it must
+	// be 0 or equal to the number of characters in alphabet.
+	Radix int32 `protobuf:"varint,2,opt,name=radix,proto3" json:"radix,omitempty"`
+	// preserve_prefix leaves this many leading characters unencrypted
+	// (e.g. 6 for the BIN of a PAN).
+	PreservePrefix int32 `protobuf:"varint,3,opt,name=preserve_prefix,json=preservePrefix,proto3" json:"preserve_prefix,omitempty"`
+	// preserve_suffix leaves this many trailing characters unencrypted
+	// (e.g. 4 for the last four digits of a PAN).
+	PreserveSuffix int32 `protobuf:"varint,4,opt,name=preserve_suffix,json=preserveSuffix,proto3" json:"preserve_suffix,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FpeFormat) Reset() {
+	*x = FpeFormat{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FpeFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FpeFormat) ProtoMessage() {}
+
+func (x *FpeFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FpeFormat.ProtoReflect.Descriptor instead.
+func (*FpeFormat) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FpeFormat) GetAlphabet() string {
+	if x != nil {
+		return x.Alphabet
+	}
+	return ""
+}
+
+func (x *FpeFormat) GetRadix() int32 {
+	if x != nil {
+		return x.Radix
+	}
+	return 0
+}
+
+func (x *FpeFormat) GetPreservePrefix() int32 {
+	if x != nil {
+		return x.PreservePrefix
+	}
+	return 0
+}
+
+func (x *FpeFormat) GetPreserveSuffix() int32 {
+	if x != nil {
+		return x.PreserveSuffix
+	}
+	return 0
+}
+
 // EncryptRequest is the request to encrypt data.
 type EncryptRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// key_id identifies the AES-256 encryption key.
+	// key_id identifies the encryption key: ECDSA, AES, CHACHA20_POLY1305
+	// or XCHACHA20_POLY1305.
 	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
 	// plaintext is the data to encrypt.
 	Plaintext []byte `protobuf:"bytes,2,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
@@ -37,7 +280,7 @@ type EncryptRequest struct {
 
 func (x *EncryptRequest) Reset() {
 	*x = EncryptRequest{}
-	mi := &file_vault_v1_encryption_proto_msgTypes[0]
+	mi := &file_vault_v1_encryption_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -49,7 +292,7 @@ func (x *EncryptRequest) String() string {
 func (*EncryptRequest) ProtoMessage() {}
 
 func (x *EncryptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_vault_v1_encryption_proto_msgTypes[0]
+	mi := &file_vault_v1_encryption_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -62,7 +305,7 @@ func (x *EncryptRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EncryptRequest.ProtoReflect.Descriptor instead.
 func (*EncryptRequest) Descriptor() ([]byte, []int) {
-	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{0}
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *EncryptRequest) GetKeyId() string {
@@ -89,9 +332,12 @@ func (x *EncryptRequest) GetAad() []byte {
 // EncryptResponse contains the encrypted data.
 type EncryptResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// ciphertext is the encrypted data with a 12-byte nonce prepended.
+	// ciphertext is the encrypted data with the nonce, and for symmetric keys
+	// the cipher, prepended.
 	Ciphertext []byte `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
-	// key_id is the identifier of the key used for encryption.
+	// key_id is the identifier of the key used for encryption. It differs
+	// from the requested key when that key was rotated on reaching its
+	// encryption limit; decrypt with this key.
 	KeyId         string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -99,7 +345,7 @@ type EncryptResponse struct {
 
 func (x *EncryptResponse) Reset() {
 	*x = EncryptResponse{}
-	mi := &file_vault_v1_encryption_proto_msgTypes[1]
+	mi := &file_vault_v1_encryption_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -111,7 +357,7 @@ func (x *EncryptResponse) String() string {
 func (*EncryptResponse) ProtoMessage() {}
 
 func (x *EncryptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_vault_v1_encryption_proto_msgTypes[1]
+	mi := &file_vault_v1_encryption_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -124,7 +370,7 @@ func (x *EncryptResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EncryptResponse.ProtoReflect.Descriptor instead.
 func (*EncryptResponse) Descriptor() ([]byte, []int) {
-	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{1}
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *EncryptResponse) GetCiphertext() []byte {
@@ -144,9 +390,9 @@ func (x *EncryptResponse) GetKeyId() string {
 // DecryptRequest is the request to decrypt data.
 type DecryptRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// key_id identifies the AES-256 decryption key.
+	// key_id identifies the key the data was encrypted with.
 	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
-	// ciphertext is the encrypted data with the 12-byte nonce prepended.
+	// ciphertext is the output of Encrypt.
 	Ciphertext []byte `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
 	// aad is the additional authenticated data that was used during encryption.
 	Aad           []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
@@ -156,7 +402,7 @@ type DecryptRequest struct {
 
 func (x *DecryptRequest) Reset() {
 	*x = DecryptRequest{}
-	mi := &file_vault_v1_encryption_proto_msgTypes[2]
+	mi := &file_vault_v1_encryption_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -168,7 +414,7 @@ func (x *DecryptRequest) String() string {
 func (*DecryptRequest) ProtoMessage() {}
 
 func (x *DecryptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_vault_v1_encryption_proto_msgTypes[2]
+	mi := &file_vault_v1_encryption_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -181,7 +427,7 @@ func (x *DecryptRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DecryptRequest.ProtoReflect.Descriptor instead.
 func (*DecryptRequest) Descriptor() ([]byte, []int) {
-	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{2}
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *DecryptRequest) GetKeyId() string {
@@ -216,7 +462,7 @@ type DecryptResponse struct {
 
 func (x *DecryptResponse) Reset() {
 	*x = DecryptResponse{}
-	mi := &file_vault_v1_encryption_proto_msgTypes[3]
+	mi := &file_vault_v1_encryption_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -228,7 +474,7 @@ func (x *DecryptResponse) String() string {
 func (*DecryptResponse) ProtoMessage() {}
 
 func (x *DecryptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_vault_v1_encryption_proto_msgTypes[3]
+	mi := &file_vault_v1_encryption_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -241,7 +487,7 @@ func (x *DecryptResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DecryptResponse.ProtoReflect.Descriptor instead.
 func (*DecryptResponse) Descriptor() ([]byte, []int) {
-	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{3}
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *DecryptResponse) GetPlaintext() []byte {
@@ -258,15 +504,31 @@ type DeriveKeyRequest struct {
 	RootKeyId string `protobuf:"bytes,1,opt,name=root_key_id,json=rootKeyId,proto3" json:"root_key_id,omitempty"`
 	// context is application-specific info passed to HKDF (the "info" parameter).
 	Context []byte `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
-	// length is the desired derived key length in bytes (1-64).
-	Length        int32 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	// length is the desired derived key length in bytes, up to 255 times
+	// the hash size (8160 for SHA-256). Ignored when store_key is set.
+	Length int32 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	// salt is the HKDF salt. Optional; HKDF uses a string of zeros when it
+	// is empty.
+	Salt []byte `protobuf:"bytes,4,opt,name=salt,proto3" json:"salt,omitempty"`
+	// hash selects the HKDF hash function. Defaults to SHA-256.
+	Hash HkdfHash `protobuf:"varint,5,opt,name=hash,proto3,enum=vault.v1.HkdfHash" json:"hash,omitempty"`
+	// store_key stores the derived key in the vault, as a child of the root
+	// key, instead of returning it. Only its metadata is returned.
+	StoreKey bool `protobuf:"varint,6,opt,name=store_key,json=storeKey,proto3" json:"store_key,omitempty"`
+	// derived_key_algorithm is the symmetric algorithm of the stored key,
+	// which also sets its length. Defaults to AES-256.
+	DerivedKeyAlgorithm KeyAlgorithm `protobuf:"varint,7,opt,name=derived_key_algorithm,json=derivedKeyAlgorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"derived_key_algorithm,omitempty"`
+	// derived_key_purpose restricts the stored key's operations.
+	DerivedKeyPurpose KeyPurpose `protobuf:"varint,8,opt,name=derived_key_purpose,json=derivedKeyPurpose,proto3,enum=vault.v1.KeyPurpose" json:"derived_key_purpose,omitempty"`
+	// labels are attached to the stored key.
+	Labels        map[string]string `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeriveKeyRequest) Reset() {
 	*x = DeriveKeyRequest{}
-	mi := &file_vault_v1_encryption_proto_msgTypes[4]
+	mi := &file_vault_v1_encryption_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -278,7 +540,7 @@ func (x *DeriveKeyRequest) String() string {
 func (*DeriveKeyRequest) ProtoMessage() {}
 
 func (x *DeriveKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_vault_v1_encryption_proto_msgTypes[4]
+	mi := &file_vault_v1_encryption_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -291,7 +553,7 @@ func (x *DeriveKeyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeriveKeyRequest.ProtoReflect.Descriptor instead.
 func (*DeriveKeyRequest) Descriptor() ([]byte, []int) {
-	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{4}
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *DeriveKeyRequest) GetRootKeyId() string {
@@ -315,18 +577,64 @@ func (x *DeriveKeyRequest) GetLength() int32 {
 	return 0
 }
 
-// DeriveKeyResponse contains the derived key material.
+func (x *DeriveKeyRequest) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *DeriveKeyRequest) GetHash() HkdfHash {
+	if x != nil {
+		return x.Hash
+	}
+	return HkdfHash_HKDF_HASH_UNSPECIFIED
+}
+
+func (x *DeriveKeyRequest) GetStoreKey() bool {
+	if x != nil {
+		return x.StoreKey
+	}
+	return false
+}
+
+func (x *DeriveKeyRequest) GetDerivedKeyAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.DerivedKeyAlgorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *DeriveKeyRequest) GetDerivedKeyPurpose() KeyPurpose {
+	if x != nil {
+		return x.DerivedKeyPurpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *DeriveKeyRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// DeriveKeyResponse contains the derived key material, or the metadata of
+// the stored key when store_key was set.
 type DeriveKeyResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// derived_key is the key material produced by HKDF-SHA256.
-	DerivedKey    []byte `protobuf:"bytes,1,opt,name=derived_key,json=derivedKey,proto3" json:"derived_key,omitempty"`
+	// derived_key is the key material produced by HKDF. Empty when the key
+	// was stored.
+	DerivedKey []byte `protobuf:"bytes,1,opt,name=derived_key,json=derivedKey,proto3" json:"derived_key,omitempty"`
+	// key is the metadata of the stored key.
+	Key           *KeyMetadata `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeriveKeyResponse) Reset() {
 	*x = DeriveKeyResponse{}
-	mi := &file_vault_v1_encryption_proto_msgTypes[5]
+	mi := &file_vault_v1_encryption_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -338,7 +646,7 @@ func (x *DeriveKeyResponse) String() string {
 func (*DeriveKeyResponse) ProtoMessage() {}
 
 func (x *DeriveKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_vault_v1_encryption_proto_msgTypes[5]
+	mi := &file_vault_v1_encryption_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -351,7 +659,7 @@ func (x *DeriveKeyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeriveKeyResponse.ProtoReflect.Descriptor instead.
 func (*DeriveKeyResponse) Descriptor() ([]byte, []int) {
-	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{5}
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *DeriveKeyResponse) GetDerivedKey() []byte {
@@ -361,39 +669,1005 @@ func (x *DeriveKeyResponse) GetDerivedKey() []byte {
 	return nil
 }
 
-var File_vault_v1_encryption_proto protoreflect.FileDescriptor
+func (x *DeriveKeyResponse) GetKey() *KeyMetadata {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
 
-const file_vault_v1_encryption_proto_rawDesc = "" +
-	"\n" +
-	"\x19vault/v1/encryption.proto\x12\bvault.v1\"W\n" +
-	"\x0eEncryptRequest\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
-	"\tplaintext\x18\x02 \x01(\fR\tplaintext\x12\x10\n" +
-	"\x03aad\x18\x03 \x01(\fR\x03aad\"H\n" +
-	"\x0fEncryptResponse\x12\x1e\n" +
-	"\n" +
-	"ciphertext\x18\x01 \x01(\fR\n" +
-	"ciphertext\x12\x15\n" +
-	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"Y\n" +
-	"\x0eDecryptRequest\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1e\n" +
-	"\n" +
-	"ciphertext\x18\x02 \x01(\fR\n" +
-	"ciphertext\x12\x10\n" +
-	"\x03aad\x18\x03 \x01(\fR\x03aad\"/\n" +
-	"\x0fDecryptResponse\x12\x1c\n" +
-	"\tplaintext\x18\x01 \x01(\fR\tplaintext\"d\n" +
-	"\x10DeriveKeyRequest\x12\x1e\n" +
-	"\vroot_key_id\x18\x01 \x01(\tR\trootKeyId\x12\x18\n" +
-	"\acontext\x18\x02 \x01(\fR\acontext\x12\x16\n" +
-	"\x06length\x18\x03 \x01(\x05R\x06length\"4\n" +
-	"\x11DeriveKeyResponse\x12\x1f\n" +
-	"\vderived_key\x18\x01 \x01(\fR\n" +
-	"derivedKey2\xd9\x01\n" +
+// EncryptFormatPreservingRequest is the request to encrypt a string
+// while preserving its format.
+type EncryptFormatPreservingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the FPE_AES_256 key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// algorithm selects FF1 or FF3-1.
+	Algorithm FpeAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.FpeAlgorithm" json:"algorithm,omitempty"`
+	// plaintext is the string to encrypt. Every character outside the
+	// preserved prefix and suffix must be in the alphabet.
+	Plaintext string `protobuf:"bytes,3,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	// tweak is optional public data that varies the permutation.
+	Tweak []byte `protobuf:"bytes,4,opt,name=tweak,proto3" json:"tweak,omitempty"`
+	// format describes the alphabet and preserved characters.
+	Format        *FpeFormat `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptFormatPreservingRequest) Reset() {
+	*x = EncryptFormatPreservingRequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptFormatPreservingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptFormatPreservingRequest) ProtoMessage() {}
+
+func (x *EncryptFormatPreservingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptFormatPreservingRequest.ProtoReflect.Descriptor instead.
+func (*EncryptFormatPreservingRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EncryptFormatPreservingRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncryptFormatPreservingRequest) GetAlgorithm() FpeAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return FpeAlgorithm_FPE_ALGORITHM_UNSPECIFIED
+}
+
+func (x *EncryptFormatPreservingRequest) GetPlaintext() string {
+	if x != nil {
+		return x.Plaintext
+	}
+	return ""
+}
+
+func (x *EncryptFormatPreservingRequest) GetTweak() []byte {
+	if x != nil {
+		return x.Tweak
+	}
+	return nil
+}
+
+func (x *EncryptFormatPreservingRequest) GetFormat() *FpeFormat {
+	if x != nil {
+		return x.Format
+	}
+	return nil
+}
+
+// EncryptFormatPreservingResponse contains the format-preserving ciphertext.
+type EncryptFormatPreservingResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ciphertext has the same length and alphabet as the plaintext.
+	Ciphertext string `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// key_id is the identifier of the key used for encryption.
+	KeyId         string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptFormatPreservingResponse) Reset() {
+	*x = EncryptFormatPreservingResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptFormatPreservingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptFormatPreservingResponse) ProtoMessage() {}
+
+func (x *EncryptFormatPreservingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptFormatPreservingResponse.ProtoReflect.Descriptor instead.
+func (*EncryptFormatPreservingResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EncryptFormatPreservingResponse) GetCiphertext() string {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return ""
+}
+
+func (x *EncryptFormatPreservingResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// DecryptFormatPreservingRequest is the request to decrypt a
+// format-preserving ciphertext.
+type DecryptFormatPreservingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the FPE_AES_256 key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// algorithm selects FF1 or FF3-1.
+	Algorithm FpeAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.FpeAlgorithm" json:"algorithm,omitempty"`
+	// ciphertext is the string produced by EncryptFormatPreserving.
+	Ciphertext string `protobuf:"bytes,3,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// tweak must match the tweak used for encryption.
+	Tweak []byte `protobuf:"bytes,4,opt,name=tweak,proto3" json:"tweak,omitempty"`
+	// format must match the format used for encryption.
+	Format        *FpeFormat `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptFormatPreservingRequest) Reset() {
+	*x = DecryptFormatPreservingRequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptFormatPreservingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptFormatPreservingRequest) ProtoMessage() {}
+
+func (x *DecryptFormatPreservingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptFormatPreservingRequest.ProtoReflect.Descriptor instead.
+func (*DecryptFormatPreservingRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DecryptFormatPreservingRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DecryptFormatPreservingRequest) GetAlgorithm() FpeAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return FpeAlgorithm_FPE_ALGORITHM_UNSPECIFIED
+}
+
+func (x *DecryptFormatPreservingRequest) GetCiphertext() string {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return ""
+}
+
+func (x *DecryptFormatPreservingRequest) GetTweak() []byte {
+	if x != nil {
+		return x.Tweak
+	}
+	return nil
+}
+
+func (x *DecryptFormatPreservingRequest) GetFormat() *FpeFormat {
+	if x != nil {
+		return x.Format
+	}
+	return nil
+}
+
+// DecryptFormatPreservingResponse contains the recovered plaintext.
+type DecryptFormatPreservingResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// plaintext is the original string.
+	Plaintext     string `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptFormatPreservingResponse) Reset() {
+	*x = DecryptFormatPreservingResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptFormatPreservingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptFormatPreservingResponse) ProtoMessage() {}
+
+func (x *DecryptFormatPreservingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptFormatPreservingResponse.ProtoReflect.Descriptor instead.
+func (*DecryptFormatPreservingResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DecryptFormatPreservingResponse) GetPlaintext() string {
+	if x != nil {
+		return x.Plaintext
+	}
+	return ""
+}
+
+// OpenHPKERequest carries a sealed message and the parameters it was
+// sealed with.
+type OpenHPKERequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the recipient key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// enc is the sender's encapsulated key.
+	Enc []byte `protobuf:"bytes,2,opt,name=enc,proto3" json:"enc,omitempty"`
+	// ciphertext is the sealed message.
+	Ciphertext []byte `protobuf:"bytes,3,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// info is the application-supplied HPKE info, which must match the
+	// sender's.
+	Info []byte `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+	// aad is the additional authenticated data passed to Seal.
+	Aad []byte `protobuf:"bytes,5,opt,name=aad,proto3" json:"aad,omitempty"`
+	// aead is the AEAD the message was sealed with.
+	Aead          HpkeAead `protobuf:"varint,6,opt,name=aead,proto3,enum=vault.v1.HpkeAead" json:"aead,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenHPKERequest) Reset() {
+	*x = OpenHPKERequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenHPKERequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenHPKERequest) ProtoMessage() {}
+
+func (x *OpenHPKERequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenHPKERequest.ProtoReflect.Descriptor instead.
+func (*OpenHPKERequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OpenHPKERequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *OpenHPKERequest) GetEnc() []byte {
+	if x != nil {
+		return x.Enc
+	}
+	return nil
+}
+
+func (x *OpenHPKERequest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *OpenHPKERequest) GetInfo() []byte {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *OpenHPKERequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+func (x *OpenHPKERequest) GetAead() HpkeAead {
+	if x != nil {
+		return x.Aead
+	}
+	return HpkeAead_HPKE_AEAD_UNSPECIFIED
+}
+
+// OpenHPKEResponse contains the opened message.
+type OpenHPKEResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// plaintext is the decrypted message.
+	Plaintext     []byte `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenHPKEResponse) Reset() {
+	*x = OpenHPKEResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenHPKEResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenHPKEResponse) ProtoMessage() {}
+
+func (x *OpenHPKEResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenHPKEResponse.ProtoReflect.Descriptor instead.
+func (*OpenHPKEResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OpenHPKEResponse) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+// EncryptDeterministicRequest is the request to deterministically encrypt
+// a value.
+type EncryptDeterministicRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the AES_SIV key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// plaintext is the value to encrypt.
+	Plaintext []byte `protobuf:"bytes,2,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	// aad is optional associated data, typically naming the field. Values
+	// are only comparable when encrypted with the same aad.
+	Aad           []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptDeterministicRequest) Reset() {
+	*x = EncryptDeterministicRequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptDeterministicRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptDeterministicRequest) ProtoMessage() {}
+
+func (x *EncryptDeterministicRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptDeterministicRequest.ProtoReflect.Descriptor instead.
+func (*EncryptDeterministicRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EncryptDeterministicRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncryptDeterministicRequest) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+func (x *EncryptDeterministicRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+// EncryptDeterministicResponse contains the deterministic ciphertext.
+type EncryptDeterministicResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ciphertext is the 16-byte synthetic IV followed by the encrypted value.
+	Ciphertext []byte `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// key_id is the identifier of the key used for encryption.
+	KeyId         string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptDeterministicResponse) Reset() {
+	*x = EncryptDeterministicResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptDeterministicResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptDeterministicResponse) ProtoMessage() {}
+
+func (x *EncryptDeterministicResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptDeterministicResponse.ProtoReflect.Descriptor instead.
+func (*EncryptDeterministicResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EncryptDeterministicResponse) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *EncryptDeterministicResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// DecryptDeterministicRequest is the request to decrypt a deterministic
+// ciphertext.
+type DecryptDeterministicRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the AES_SIV key the value was encrypted with.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// ciphertext is the output of EncryptDeterministic.
+	Ciphertext []byte `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// aad is the associated data used during encryption.
+	Aad           []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptDeterministicRequest) Reset() {
+	*x = DecryptDeterministicRequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptDeterministicRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptDeterministicRequest) ProtoMessage() {}
+
+func (x *DecryptDeterministicRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptDeterministicRequest.ProtoReflect.Descriptor instead.
+func (*DecryptDeterministicRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DecryptDeterministicRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DecryptDeterministicRequest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *DecryptDeterministicRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+// DecryptDeterministicResponse contains the decrypted value.
+type DecryptDeterministicResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// plaintext is the original value.
+	Plaintext     []byte `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptDeterministicResponse) Reset() {
+	*x = DecryptDeterministicResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptDeterministicResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptDeterministicResponse) ProtoMessage() {}
+
+func (x *DecryptDeterministicResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptDeterministicResponse.ProtoReflect.Descriptor instead.
+func (*DecryptDeterministicResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DecryptDeterministicResponse) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+// EncryptStreamRequest carries one chunk of a streamed plaintext. key_id
+// and aad are read from the first message only.
+type EncryptStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the encryption key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// aad is optional additional authenticated data for the whole message.
+	Aad []byte `protobuf:"bytes,2,opt,name=aad,proto3" json:"aad,omitempty"`
+	// chunk is the next piece of plaintext, of any size.
+	Chunk         []byte `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptStreamRequest) Reset() {
+	*x = EncryptStreamRequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptStreamRequest) ProtoMessage() {}
+
+func (x *EncryptStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptStreamRequest.ProtoReflect.Descriptor instead.
+func (*EncryptStreamRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EncryptStreamRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncryptStreamRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+func (x *EncryptStreamRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// EncryptStreamResponse carries the next piece of ciphertext.
+type EncryptStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// chunk is the next piece of ciphertext. Concatenated, the chunks form
+	// the ciphertext to pass to DecryptStream.
+	Chunk         []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptStreamResponse) Reset() {
+	*x = EncryptStreamResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptStreamResponse) ProtoMessage() {}
+
+func (x *EncryptStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptStreamResponse.ProtoReflect.Descriptor instead.
+func (*EncryptStreamResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EncryptStreamResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// DecryptStreamRequest carries one chunk of a streamed ciphertext. key_id
+// and aad are read from the first message only.
+type DecryptStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the key the message was encrypted with.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// aad is the additional authenticated data used during encryption.
+	Aad []byte `protobuf:"bytes,2,opt,name=aad,proto3" json:"aad,omitempty"`
+	// chunk is the next piece of ciphertext, of any size.
+	Chunk         []byte `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptStreamRequest) Reset() {
+	*x = DecryptStreamRequest{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptStreamRequest) ProtoMessage() {}
+
+func (x *DecryptStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptStreamRequest.ProtoReflect.Descriptor instead.
+func (*DecryptStreamRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DecryptStreamRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DecryptStreamRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+func (x *DecryptStreamRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// DecryptStreamResponse carries the next piece of plaintext.
+type DecryptStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// chunk is the plaintext of one authenticated segment.
+	Chunk         []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptStreamResponse) Reset() {
+	*x = DecryptStreamResponse{}
+	mi := &file_vault_v1_encryption_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptStreamResponse) ProtoMessage() {}
+
+func (x *DecryptStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_encryption_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptStreamResponse.ProtoReflect.Descriptor instead.
+func (*DecryptStreamResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_encryption_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DecryptStreamResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+var File_vault_v1_encryption_proto protoreflect.FileDescriptor
+
+const file_vault_v1_encryption_proto_rawDesc = "" +
+	"\n" +
+	"\x19vault/v1/encryption.proto\x12\bvault.v1\x1a\x16vault/v1/keymgmt.proto\"\x8f\x01\n" +
+	"\tFpeFormat\x12\x1a\n" +
+	"\balphabet\x18\x01 \x01(\tR\balphabet\x12\x14\n" +
+	"\x05radix\x18\x02 \x01(\x05R\x05radix\x12'\n" +
+	"\x0fpreserve_prefix\x18\x03 \x01(\x05R\x0epreservePrefix\x12'\n" +
+	"\x0fpreserve_suffix\x18\x04 \x01(\x05R\x0epreserveSuffix\"W\n" +
+	"\x0eEncryptRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tplaintext\x18\x02 \x01(\fR\tplaintext\x12\x10\n" +
+	"\x03aad\x18\x03 \x01(\fR\x03aad\"H\n" +
+	"\x0fEncryptResponse\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x01 \x01(\fR\n" +
+	"ciphertext\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"Y\n" +
+	"\x0eDecryptRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\x12\x10\n" +
+	"\x03aad\x18\x03 \x01(\fR\x03aad\"/\n" +
+	"\x0fDecryptResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\fR\tplaintext\"\xca\x03\n" +
+	"\x10DeriveKeyRequest\x12\x1e\n" +
+	"\vroot_key_id\x18\x01 \x01(\tR\trootKeyId\x12\x18\n" +
+	"\acontext\x18\x02 \x01(\fR\acontext\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x05R\x06length\x12\x12\n" +
+	"\x04salt\x18\x04 \x01(\fR\x04salt\x12&\n" +
+	"\x04hash\x18\x05 \x01(\x0e2\x12.vault.v1.HkdfHashR\x04hash\x12\x1b\n" +
+	"\tstore_key\x18\x06 \x01(\bR\bstoreKey\x12J\n" +
+	"\x15derived_key_algorithm\x18\a \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\x13derivedKeyAlgorithm\x12D\n" +
+	"\x13derived_key_purpose\x18\b \x01(\x0e2\x14.vault.v1.KeyPurposeR\x11derivedKeyPurpose\x12>\n" +
+	"\x06labels\x18\t \x03(\v2&.vault.v1.DeriveKeyRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\x11DeriveKeyResponse\x12\x1f\n" +
+	"\vderived_key\x18\x01 \x01(\fR\n" +
+	"derivedKey\x12'\n" +
+	"\x03key\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\x03key\"\xce\x01\n" +
+	"\x1eEncryptFormatPreservingRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.FpeAlgorithmR\talgorithm\x12\x1c\n" +
+	"\tplaintext\x18\x03 \x01(\tR\tplaintext\x12\x14\n" +
+	"\x05tweak\x18\x04 \x01(\fR\x05tweak\x12+\n" +
+	"\x06format\x18\x05 \x01(\v2\x13.vault.v1.FpeFormatR\x06format\"X\n" +
+	"\x1fEncryptFormatPreservingResponse\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x01 \x01(\tR\n" +
+	"ciphertext\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"\xd0\x01\n" +
+	"\x1eDecryptFormatPreservingRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.FpeAlgorithmR\talgorithm\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x03 \x01(\tR\n" +
+	"ciphertext\x12\x14\n" +
+	"\x05tweak\x18\x04 \x01(\fR\x05tweak\x12+\n" +
+	"\x06format\x18\x05 \x01(\v2\x13.vault.v1.FpeFormatR\x06format\"?\n" +
+	"\x1fDecryptFormatPreservingResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\tR\tplaintext\"\xa8\x01\n" +
+	"\x0fOpenHPKERequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x10\n" +
+	"\x03enc\x18\x02 \x01(\fR\x03enc\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x03 \x01(\fR\n" +
+	"ciphertext\x12\x12\n" +
+	"\x04info\x18\x04 \x01(\fR\x04info\x12\x10\n" +
+	"\x03aad\x18\x05 \x01(\fR\x03aad\x12&\n" +
+	"\x04aead\x18\x06 \x01(\x0e2\x12.vault.v1.HpkeAeadR\x04aead\"0\n" +
+	"\x10OpenHPKEResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\fR\tplaintext\"d\n" +
+	"\x1bEncryptDeterministicRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tplaintext\x18\x02 \x01(\fR\tplaintext\x12\x10\n" +
+	"\x03aad\x18\x03 \x01(\fR\x03aad\"U\n" +
+	"\x1cEncryptDeterministicResponse\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x01 \x01(\fR\n" +
+	"ciphertext\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"f\n" +
+	"\x1bDecryptDeterministicRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\x12\x10\n" +
+	"\x03aad\x18\x03 \x01(\fR\x03aad\"<\n" +
+	"\x1cDecryptDeterministicResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\fR\tplaintext\"U\n" +
+	"\x14EncryptStreamRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x10\n" +
+	"\x03aad\x18\x02 \x01(\fR\x03aad\x12\x14\n" +
+	"\x05chunk\x18\x03 \x01(\fR\x05chunk\"-\n" +
+	"\x15EncryptStreamResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk\"U\n" +
+	"\x14DecryptStreamRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x10\n" +
+	"\x03aad\x18\x02 \x01(\fR\x03aad\x12\x14\n" +
+	"\x05chunk\x18\x03 \x01(\fR\x05chunk\"-\n" +
+	"\x15DecryptStreamResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk*]\n" +
+	"\fFpeAlgorithm\x12\x1d\n" +
+	"\x19FPE_ALGORITHM_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11FPE_ALGORITHM_FF1\x10\x01\x12\x17\n" +
+	"\x13FPE_ALGORITHM_FF3_1\x10\x02*g\n" +
+	"\bHkdfHash\x12\x19\n" +
+	"\x15HKDF_HASH_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10HKDF_HASH_SHA256\x10\x01\x12\x14\n" +
+	"\x10HKDF_HASH_SHA384\x10\x02\x12\x14\n" +
+	"\x10HKDF_HASH_SHA512\x10\x03*|\n" +
+	"\bHpkeAead\x12\x19\n" +
+	"\x15HPKE_AEAD_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15HPKE_AEAD_AES_128_GCM\x10\x01\x12\x19\n" +
+	"\x15HPKE_AEAD_AES_256_GCM\x10\x02\x12\x1f\n" +
+	"\x1bHPKE_AEAD_CHACHA20_POLY1305\x10\x032\xf6\x06\n" +
 	"\x11EncryptionService\x12>\n" +
 	"\aEncrypt\x12\x18.vault.v1.EncryptRequest\x1a\x19.vault.v1.EncryptResponse\x12>\n" +
 	"\aDecrypt\x12\x18.vault.v1.DecryptRequest\x1a\x19.vault.v1.DecryptResponse\x12D\n" +
-	"\tDeriveKey\x12\x1a.vault.v1.DeriveKeyRequest\x1a\x1b.vault.v1.DeriveKeyResponseB5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+	"\tDeriveKey\x12\x1a.vault.v1.DeriveKeyRequest\x1a\x1b.vault.v1.DeriveKeyResponse\x12n\n" +
+	"\x17EncryptFormatPreserving\x12(.vault.v1.EncryptFormatPreservingRequest\x1a).vault.v1.EncryptFormatPreservingResponse\x12n\n" +
+	"\x17DecryptFormatPreserving\x12(.vault.v1.DecryptFormatPreservingRequest\x1a).vault.v1.DecryptFormatPreservingResponse\x12A\n" +
+	"\bOpenHPKE\x12\x19.vault.v1.OpenHPKERequest\x1a\x1a.vault.v1.OpenHPKEResponse\x12e\n" +
+	"\x14EncryptDeterministic\x12%.vault.v1.EncryptDeterministicRequest\x1a&.vault.v1.EncryptDeterministicResponse\x12e\n" +
+	"\x14DecryptDeterministic\x12%.vault.v1.DecryptDeterministicRequest\x1a&.vault.v1.DecryptDeterministicResponse\x12T\n" +
+	"\rEncryptStream\x12\x1e.vault.v1.EncryptStreamRequest\x1a\x1f.vault.v1.EncryptStreamResponse(\x010\x01\x12T\n" +
+	"\rDecryptStream\x12\x1e.vault.v1.DecryptStreamRequest\x1a\x1f.vault.v1.DecryptStreamResponse(\x010\x01B5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
 
 var (
 	file_vault_v1_encryption_proto_rawDescOnce sync.Once
@@ -407,27 +1681,74 @@ func file_vault_v1_encryption_proto_rawDescGZIP() []byte {
 	return file_vault_v1_encryption_proto_rawDescData
 }
 
-var file_vault_v1_encryption_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_vault_v1_encryption_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_vault_v1_encryption_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_vault_v1_encryption_proto_goTypes = []any{
-	(*EncryptRequest)(nil),    // 0: vault.v1.EncryptRequest
-	(*EncryptResponse)(nil),   // 1: vault.v1.EncryptResponse
-	(*DecryptRequest)(nil),    // 2: vault.v1.DecryptRequest
-	(*DecryptResponse)(nil),   // 3: vault.v1.DecryptResponse
-	(*DeriveKeyRequest)(nil),  // 4: vault.v1.DeriveKeyRequest
-	(*DeriveKeyResponse)(nil), // 5: vault.v1.DeriveKeyResponse
+	(FpeAlgorithm)(0),                       // 0: vault.v1.FpeAlgorithm
+	(HkdfHash)(0),                           // 1: vault.v1.HkdfHash
+	(HpkeAead)(0),                           // 2: vault.v1.HpkeAead
+	(*FpeFormat)(nil),                       // 3: vault.v1.FpeFormat
+	(*EncryptRequest)(nil),                  // 4: vault.v1.EncryptRequest
+	(*EncryptResponse)(nil),                 // 5: vault.v1.EncryptResponse
+	(*DecryptRequest)(nil),                  // 6: vault.v1.DecryptRequest
+	(*DecryptResponse)(nil),                 // 7: vault.v1.DecryptResponse
+	(*DeriveKeyRequest)(nil),                // 8: vault.v1.DeriveKeyRequest
+	(*DeriveKeyResponse)(nil),               // 9: vault.v1.DeriveKeyResponse
+	(*EncryptFormatPreservingRequest)(nil),  // 10: vault.v1.EncryptFormatPreservingRequest
+	(*EncryptFormatPreservingResponse)(nil), // 11: vault.v1.EncryptFormatPreservingResponse
+	(*DecryptFormatPreservingRequest)(nil),  // 12: vault.v1.DecryptFormatPreservingRequest
+	(*DecryptFormatPreservingResponse)(nil), // 13: vault.v1.DecryptFormatPreservingResponse
+	(*OpenHPKERequest)(nil),                 // 14: vault.v1.OpenHPKERequest
+	(*OpenHPKEResponse)(nil),                // 15: vault.v1.OpenHPKEResponse
+	(*EncryptDeterministicRequest)(nil),     // 16: vault.v1.EncryptDeterministicRequest
+	(*EncryptDeterministicResponse)(nil),    // 17: vault.v1.EncryptDeterministicResponse
+	(*DecryptDeterministicRequest)(nil),     // 18: vault.v1.DecryptDeterministicRequest
+	(*DecryptDeterministicResponse)(nil),    // 19: vault.v1.DecryptDeterministicResponse
+	(*EncryptStreamRequest)(nil),            // 20: vault.v1.EncryptStreamRequest
+	(*EncryptStreamResponse)(nil),           // 21: vault.v1.EncryptStreamResponse
+	(*DecryptStreamRequest)(nil),            // 22: vault.v1.DecryptStreamRequest
+	(*DecryptStreamResponse)(nil),           // 23: vault.v1.DecryptStreamResponse
+	nil,                                     // 24: vault.v1.DeriveKeyRequest.LabelsEntry
+	(KeyAlgorithm)(0),                       // 25: vault.v1.KeyAlgorithm
+	(KeyPurpose)(0),                         // 26: vault.v1.KeyPurpose
+	(*KeyMetadata)(nil),                     // 27: vault.v1.KeyMetadata
 }
 var file_vault_v1_encryption_proto_depIdxs = []int32{
-	0, // 0: vault.v1.EncryptionService.Encrypt:input_type -> vault.v1.EncryptRequest
-	2, // 1: vault.v1.EncryptionService.Decrypt:input_type -> vault.v1.DecryptRequest
-	4, // 2: vault.v1.EncryptionService.DeriveKey:input_type -> vault.v1.DeriveKeyRequest
-	1, // 3: vault.v1.EncryptionService.Encrypt:output_type -> vault.v1.EncryptResponse
-	3, // 4: vault.v1.EncryptionService.Decrypt:output_type -> vault.v1.DecryptResponse
-	5, // 5: vault.v1.EncryptionService.DeriveKey:output_type -> vault.v1.DeriveKeyResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1,  // 0: vault.v1.DeriveKeyRequest.hash:type_name -> vault.v1.HkdfHash
+	25, // 1: vault.v1.DeriveKeyRequest.derived_key_algorithm:type_name -> vault.v1.KeyAlgorithm
+	26, // 2: vault.v1.DeriveKeyRequest.derived_key_purpose:type_name -> vault.v1.KeyPurpose
+	24, // 3: vault.v1.DeriveKeyRequest.labels:type_name -> vault.v1.DeriveKeyRequest.LabelsEntry
+	27, // 4: vault.v1.DeriveKeyResponse.key:type_name -> vault.v1.KeyMetadata
+	0,  // 5: vault.v1.EncryptFormatPreservingRequest.algorithm:type_name -> vault.v1.FpeAlgorithm
+	3,  // 6: vault.v1.EncryptFormatPreservingRequest.format:type_name -> vault.v1.FpeFormat
+	0,  // 7: vault.v1.DecryptFormatPreservingRequest.algorithm:type_name -> vault.v1.FpeAlgorithm
+	3,  // 8: vault.v1.DecryptFormatPreservingRequest.format:type_name -> vault.v1.FpeFormat
+	2,  // 9: vault.v1.OpenHPKERequest.aead:type_name -> vault.v1.HpkeAead
+	4,  // 10: vault.v1.EncryptionService.Encrypt:input_type -> vault.v1.EncryptRequest
+	6,  // 11: vault.v1.EncryptionService.Decrypt:input_type -> vault.v1.DecryptRequest
+	8,  // 12: vault.v1.EncryptionService.DeriveKey:input_type -> vault.v1.DeriveKeyRequest
+	10, // 13: vault.v1.EncryptionService.EncryptFormatPreserving:input_type -> vault.v1.EncryptFormatPreservingRequest
+	12, // 14: vault.v1.EncryptionService.DecryptFormatPreserving:input_type -> vault.v1.DecryptFormatPreservingRequest
+	14, // 15: vault.v1.EncryptionService.OpenHPKE:input_type -> vault.v1.OpenHPKERequest
+	16, // 16: vault.v1.EncryptionService.EncryptDeterministic:input_type -> vault.v1.EncryptDeterministicRequest
+	18, // 17: vault.v1.EncryptionService.DecryptDeterministic:input_type -> vault.v1.DecryptDeterministicRequest
+	20, // 18: vault.v1.EncryptionService.EncryptStream:input_type -> vault.v1.EncryptStreamRequest
+	22, // 19: vault.v1.EncryptionService.DecryptStream:input_type -> vault.v1.DecryptStreamRequest
+	5,  // 20: vault.v1.EncryptionService.Encrypt:output_type -> vault.v1.EncryptResponse
+	7,  // 21: vault.v1.EncryptionService.Decrypt:output_type -> vault.v1.DecryptResponse
+	9,  // 22: vault.v1.EncryptionService.DeriveKey:output_type -> vault.v1.DeriveKeyResponse
+	11, // 23: vault.v1.EncryptionService.EncryptFormatPreserving:output_type -> vault.v1.EncryptFormatPreservingResponse
+	13, // 24: vault.v1.EncryptionService.DecryptFormatPreserving:output_type -> vault.v1.DecryptFormatPreservingResponse
+	15, // 25: vault.v1.EncryptionService.OpenHPKE:output_type -> vault.v1.OpenHPKEResponse
+	17, // 26: vault.v1.EncryptionService.EncryptDeterministic:output_type -> vault.v1.EncryptDeterministicResponse
+	19, // 27: vault.v1.EncryptionService.DecryptDeterministic:output_type -> vault.v1.DecryptDeterministicResponse
+	21, // 28: vault.v1.EncryptionService.EncryptStream:output_type -> vault.v1.EncryptStreamResponse
+	23, // 29: vault.v1.EncryptionService.DecryptStream:output_type -> vault.v1.DecryptStreamResponse
+	20, // [20:30] is the sub-list for method output_type
+	10, // [10:20] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_vault_v1_encryption_proto_init() }
@@ -435,18 +1756,20 @@ func file_vault_v1_encryption_proto_init() {
 	if File_vault_v1_encryption_proto != nil {
 		return
 	}
+	file_vault_v1_keymgmt_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_encryption_proto_rawDesc), len(file_vault_v1_encryption_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      3,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_vault_v1_encryption_proto_goTypes,
 		DependencyIndexes: file_vault_v1_encryption_proto_depIdxs,
+		EnumInfos:         file_vault_v1_encryption_proto_enumTypes,
 		MessageInfos:      file_vault_v1_encryption_proto_msgTypes,
 	}.Build()
 	File_vault_v1_encryption_proto = out.File
@@ -19,9 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	EncryptionService_Encrypt_FullMethodName   = "/vault.v1.EncryptionService/Encrypt"
-	EncryptionService_Decrypt_FullMethodName   = "/vault.v1.EncryptionService/Decrypt"
-	EncryptionService_DeriveKey_FullMethodName = "/vault.v1.EncryptionService/DeriveKey"
+	EncryptionService_Encrypt_FullMethodName                 = "/vault.v1.EncryptionService/Encrypt"
+	EncryptionService_Decrypt_FullMethodName                 = "/vault.v1.EncryptionService/Decrypt"
+	EncryptionService_DeriveKey_FullMethodName               = "/vault.v1.EncryptionService/DeriveKey"
+	EncryptionService_EncryptFormatPreserving_FullMethodName = "/vault.v1.EncryptionService/EncryptFormatPreserving"
+	EncryptionService_DecryptFormatPreserving_FullMethodName = "/vault.v1.EncryptionService/DecryptFormatPreserving"
+	EncryptionService_OpenHPKE_FullMethodName                = "/vault.v1.EncryptionService/OpenHPKE"
+	EncryptionService_EncryptDeterministic_FullMethodName    = "/vault.v1.EncryptionService/EncryptDeterministic"
+	EncryptionService_DecryptDeterministic_FullMethodName    = "/vault.v1.EncryptionService/DecryptDeterministic"
+	EncryptionService_EncryptStream_FullMethodName           = "/vault.v1.EncryptionService/EncryptStream"
+	EncryptionService_DecryptStream_FullMethodName           = "/vault.v1.EncryptionService/DecryptStream"
 )
 
 // EncryptionServiceClient is the client API for EncryptionService service.
@@ -29,17 +36,55 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
 // EncryptionService provides AES-256-GCM encryption, decryption,
-// and HKDF-SHA256 key derivation operations.
+// HKDF key derivation, FF1/FF3-1 format-preserving encryption and
+// HPKE decryption.
 type EncryptionServiceClient interface {
-	// Encrypt encrypts plaintext using AES-256-GCM with the specified key.
-	// The returned ciphertext has a 12-byte random nonce prepended.
+	// Encrypt encrypts plaintext with the specified key. ECDSA keys use
+	// AES-256-GCM under a derived key and return [nonce | ciphertext | tag].
+	// AES keys use AES-GCM and ChaCha20 keys (X)ChaCha20-Poly1305; their
+	// ciphertexts record the cipher: [algorithm | nonce | ciphertext | tag].
+	// Keys with random 96-bit nonces are counted against their
+	// encryption_limit; once it is reached the server either rotates the key
+	// and encrypts under the new version, returning its key_id, or refuses
+	// with RESOURCE_EXHAUSTED, depending on its configuration.
 	Encrypt(ctx context.Context, in *EncryptRequest, opts ...grpc.CallOption) (*EncryptResponse, error)
-	// Decrypt decrypts ciphertext that was produced by Encrypt.
-	// The nonce is extracted from the first 12 bytes of the ciphertext.
+	// Decrypt decrypts ciphertext that was produced by Encrypt. The cipher
+	// recorded in the ciphertext must be the one the key encrypts with.
 	Decrypt(ctx context.Context, in *DecryptRequest, opts ...grpc.CallOption) (*DecryptResponse, error)
-	// DeriveKey derives a new key from a root key using HKDF-SHA256.
-	// The derived key length must be between 1 and 64 bytes.
+	// DeriveKey derives a new key from a root key using HKDF with SHA-256,
+	// SHA-384 or SHA-512. The output is either returned to the caller or
+	// stored as a new vault key recording the root as its parent.
 	DeriveKey(ctx context.Context, in *DeriveKeyRequest, opts ...grpc.CallOption) (*DeriveKeyResponse, error)
+	// EncryptFormatPreserving encrypts a string so the ciphertext keeps its
+	// length and alphabet (NIST SP 800-38G). Requires an FPE_AES_256 key.
+	EncryptFormatPreserving(ctx context.Context, in *EncryptFormatPreservingRequest, opts ...grpc.CallOption) (*EncryptFormatPreservingResponse, error)
+	// DecryptFormatPreserving reverses EncryptFormatPreserving. The algorithm,
+	// alphabet, tweak and preserved lengths must match those used to encrypt.
+	DecryptFormatPreserving(ctx context.Context, in *DecryptFormatPreservingRequest, opts ...grpc.CallOption) (*DecryptFormatPreservingResponse, error)
+	// OpenHPKE decrypts an HPKE (RFC 9180) base-mode message sealed to the
+	// public key of an ECDSA P-256/P-384 or X25519 key, as returned by
+	// KeyManagementService.GetPublicKey. The KDF is HKDF-SHA256.
+	OpenHPKE(ctx context.Context, in *OpenHPKERequest, opts ...grpc.CallOption) (*OpenHPKEResponse, error)
+	// EncryptDeterministic encrypts with AES-SIV (RFC 5297) so that the same
+	// plaintext and aad always give the same ciphertext, allowing lookups by
+	// equality. This deliberately leaks which values are equal; use it only
+	// for fields that must be searchable. Requires an AES_SIV key.
+	EncryptDeterministic(ctx context.Context, in *EncryptDeterministicRequest, opts ...grpc.CallOption) (*EncryptDeterministicResponse, error)
+	// DecryptDeterministic reverses EncryptDeterministic.
+	DecryptDeterministic(ctx context.Context, in *DecryptDeterministicRequest, opts ...grpc.CallOption) (*DecryptDeterministicResponse, error)
+	// EncryptStream encrypts a message of any size sent as a sequence of
+	// chunks, replying with ciphertext chunks as segments are sealed. The
+	// message is split into 64 KiB segments sealed with a STREAM construction
+	// that authenticates their order and the end of the message, so the
+	// server holds at most one segment in memory. Requires an AES,
+	// CHACHA20_POLY1305 or XCHACHA20_POLY1305 key. The stream ends once the
+	// client closes its side and the final segment has been sent.
+	EncryptStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[EncryptStreamRequest, EncryptStreamResponse], error)
+	// DecryptStream reverses EncryptStream. Plaintext is returned segment by
+	// segment as each authenticates; if the RPC ends with an error, the
+	// ciphertext was truncated or corrupted and everything received must be
+	// discarded.
+	DecryptStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DecryptStreamRequest, DecryptStreamResponse], error)
 }
 
 type encryptionServiceClient struct {
@@ -80,22 +125,136 @@ func (c *encryptionServiceClient) DeriveKey(ctx context.Context, in *DeriveKeyRe
 	return out, nil
 }
 
+func (c *encryptionServiceClient) EncryptFormatPreserving(ctx context.Context, in *EncryptFormatPreservingRequest, opts ...grpc.CallOption) (*EncryptFormatPreservingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EncryptFormatPreservingResponse)
+	err := c.cc.Invoke(ctx, EncryptionService_EncryptFormatPreserving_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encryptionServiceClient) DecryptFormatPreserving(ctx context.Context, in *DecryptFormatPreservingRequest, opts ...grpc.CallOption) (*DecryptFormatPreservingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecryptFormatPreservingResponse)
+	err := c.cc.Invoke(ctx, EncryptionService_DecryptFormatPreserving_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encryptionServiceClient) OpenHPKE(ctx context.Context, in *OpenHPKERequest, opts ...grpc.CallOption) (*OpenHPKEResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OpenHPKEResponse)
+	err := c.cc.Invoke(ctx, EncryptionService_OpenHPKE_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encryptionServiceClient) EncryptDeterministic(ctx context.Context, in *EncryptDeterministicRequest, opts ...grpc.CallOption) (*EncryptDeterministicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EncryptDeterministicResponse)
+	err := c.cc.Invoke(ctx, EncryptionService_EncryptDeterministic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encryptionServiceClient) DecryptDeterministic(ctx context.Context, in *DecryptDeterministicRequest, opts ...grpc.CallOption) (*DecryptDeterministicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecryptDeterministicResponse)
+	err := c.cc.Invoke(ctx, EncryptionService_DecryptDeterministic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encryptionServiceClient) EncryptStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[EncryptStreamRequest, EncryptStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EncryptionService_ServiceDesc.Streams[0], EncryptionService_EncryptStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EncryptStreamRequest, EncryptStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EncryptionService_EncryptStreamClient = grpc.BidiStreamingClient[EncryptStreamRequest, EncryptStreamResponse]
+
+func (c *encryptionServiceClient) DecryptStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DecryptStreamRequest, DecryptStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EncryptionService_ServiceDesc.Streams[1], EncryptionService_DecryptStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DecryptStreamRequest, DecryptStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EncryptionService_DecryptStreamClient = grpc.BidiStreamingClient[DecryptStreamRequest, DecryptStreamResponse]
+
 // EncryptionServiceServer is the server API for EncryptionService service.
 // All implementations must embed UnimplementedEncryptionServiceServer
 // for forward compatibility.
 //
 // EncryptionService provides AES-256-GCM encryption, decryption,
-// and HKDF-SHA256 key derivation operations.
+// HKDF key derivation, FF1/FF3-1 format-preserving encryption and
+// HPKE decryption.
 type EncryptionServiceServer interface {
-	// Encrypt encrypts plaintext using AES-256-GCM with the specified key.
-	// The returned ciphertext has a 12-byte random nonce prepended.
+	// Encrypt encrypts plaintext with the specified key. ECDSA keys use
+	// AES-256-GCM under a derived key and return [nonce | ciphertext | tag].
+	// AES keys use AES-GCM and ChaCha20 keys (X)ChaCha20-Poly1305; their
+	// ciphertexts record the cipher: [algorithm | nonce | ciphertext | tag].
+	// Keys with random 96-bit nonces are counted against their
+	// encryption_limit; once it is reached the server either rotates the key
+	// and encrypts under the new version, returning its key_id, or refuses
+	// with RESOURCE_EXHAUSTED, depending on its configuration.
 	Encrypt(context.Context, *EncryptRequest) (*EncryptResponse, error)
-	// Decrypt decrypts ciphertext that was produced by Encrypt.
-	// The nonce is extracted from the first 12 bytes of the ciphertext.
+	// Decrypt decrypts ciphertext that was produced by Encrypt. The cipher
+	// recorded in the ciphertext must be the one the key encrypts with.
 	Decrypt(context.Context, *DecryptRequest) (*DecryptResponse, error)
-	// DeriveKey derives a new key from a root key using HKDF-SHA256.
-	// The derived key length must be between 1 and 64 bytes.
+	// DeriveKey derives a new key from a root key using HKDF with SHA-256,
+	// SHA-384 or SHA-512. The output is either returned to the caller or
+	// stored as a new vault key recording the root as its parent.
 	DeriveKey(context.Context, *DeriveKeyRequest) (*DeriveKeyResponse, error)
+	// EncryptFormatPreserving encrypts a string so the ciphertext keeps its
+	// length and alphabet (NIST SP 800-38G). Requires an FPE_AES_256 key.
+	EncryptFormatPreserving(context.Context, *EncryptFormatPreservingRequest) (*EncryptFormatPreservingResponse, error)
+	// DecryptFormatPreserving reverses EncryptFormatPreserving. The algorithm,
+	// alphabet, tweak and preserved lengths must match those used to encrypt.
+	DecryptFormatPreserving(context.Context, *DecryptFormatPreservingRequest) (*DecryptFormatPreservingResponse, error)
+	// OpenHPKE decrypts an HPKE (RFC 9180) base-mode message sealed to the
+	// public key of an ECDSA P-256/P-384 or X25519 key, as returned by
+	// KeyManagementService.GetPublicKey. The KDF is HKDF-SHA256.
+	OpenHPKE(context.Context, *OpenHPKERequest) (*OpenHPKEResponse, error)
+	// EncryptDeterministic encrypts with AES-SIV (RFC 5297) so that the same
+	// plaintext and aad always give the same ciphertext, allowing lookups by
+	// equality. This deliberately leaks which values are equal; use it only
+	// for fields that must be searchable. Requires an AES_SIV key.
+	EncryptDeterministic(context.Context, *EncryptDeterministicRequest) (*EncryptDeterministicResponse, error)
+	// DecryptDeterministic reverses EncryptDeterministic.
+	DecryptDeterministic(context.Context, *DecryptDeterministicRequest) (*DecryptDeterministicResponse, error)
+	// EncryptStream encrypts a message of any size sent as a sequence of
+	// chunks, replying with ciphertext chunks as segments are sealed. The
+	// message is split into 64 KiB segments sealed with a STREAM construction
+	// that authenticates their order and the end of the message, so the
+	// server holds at most one segment in memory. Requires an AES,
+	// CHACHA20_POLY1305 or XCHACHA20_POLY1305 key. The stream ends once the
+	// client closes its side and the final segment has been sent.
+	EncryptStream(grpc.BidiStreamingServer[EncryptStreamRequest, EncryptStreamResponse]) error
+	// DecryptStream reverses EncryptStream. Plaintext is returned segment by
+	// segment as each authenticates; if the RPC ends with an error, the
+	// ciphertext was truncated or corrupted and everything received must be
+	// discarded.
+	DecryptStream(grpc.BidiStreamingServer[DecryptStreamRequest, DecryptStreamResponse]) error
 	mustEmbedUnimplementedEncryptionServiceServer()
 }
 
@@ -115,6 +274,27 @@ func (UnimplementedEncryptionServiceServer) Decrypt(context.Context, *DecryptReq
 func (UnimplementedEncryptionServiceServer) DeriveKey(context.Context, *DeriveKeyRequest) (*DeriveKeyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeriveKey not implemented")
 }
+func (UnimplementedEncryptionServiceServer) EncryptFormatPreserving(context.Context, *EncryptFormatPreservingRequest) (*EncryptFormatPreservingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EncryptFormatPreserving not implemented")
+}
+func (UnimplementedEncryptionServiceServer) DecryptFormatPreserving(context.Context, *DecryptFormatPreservingRequest) (*DecryptFormatPreservingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DecryptFormatPreserving not implemented")
+}
+func (UnimplementedEncryptionServiceServer) OpenHPKE(context.Context, *OpenHPKERequest) (*OpenHPKEResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method OpenHPKE not implemented")
+}
+func (UnimplementedEncryptionServiceServer) EncryptDeterministic(context.Context, *EncryptDeterministicRequest) (*EncryptDeterministicResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EncryptDeterministic not implemented")
+}
+func (UnimplementedEncryptionServiceServer) DecryptDeterministic(context.Context, *DecryptDeterministicRequest) (*DecryptDeterministicResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DecryptDeterministic not implemented")
+}
+func (UnimplementedEncryptionServiceServer) EncryptStream(grpc.BidiStreamingServer[EncryptStreamRequest, EncryptStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method EncryptStream not implemented")
+}
+func (UnimplementedEncryptionServiceServer) DecryptStream(grpc.BidiStreamingServer[DecryptStreamRequest, DecryptStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method DecryptStream not implemented")
+}
 func (UnimplementedEncryptionServiceServer) mustEmbedUnimplementedEncryptionServiceServer() {}
 func (UnimplementedEncryptionServiceServer) testEmbeddedByValue()                           {}
 
@@ -190,6 +370,110 @@ func _EncryptionService_DeriveKey_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EncryptionService_EncryptFormatPreserving_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncryptFormatPreservingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncryptionServiceServer).EncryptFormatPreserving(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EncryptionService_EncryptFormatPreserving_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncryptionServiceServer).EncryptFormatPreserving(ctx, req.(*EncryptFormatPreservingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EncryptionService_DecryptFormatPreserving_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecryptFormatPreservingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncryptionServiceServer).DecryptFormatPreserving(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EncryptionService_DecryptFormatPreserving_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncryptionServiceServer).DecryptFormatPreserving(ctx, req.(*DecryptFormatPreservingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EncryptionService_OpenHPKE_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenHPKERequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncryptionServiceServer).OpenHPKE(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EncryptionService_OpenHPKE_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncryptionServiceServer).OpenHPKE(ctx, req.(*OpenHPKERequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EncryptionService_EncryptDeterministic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncryptDeterministicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncryptionServiceServer).EncryptDeterministic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EncryptionService_EncryptDeterministic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncryptionServiceServer).EncryptDeterministic(ctx, req.(*EncryptDeterministicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EncryptionService_DecryptDeterministic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecryptDeterministicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncryptionServiceServer).DecryptDeterministic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EncryptionService_DecryptDeterministic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncryptionServiceServer).DecryptDeterministic(ctx, req.(*DecryptDeterministicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EncryptionService_EncryptStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EncryptionServiceServer).EncryptStream(&grpc.GenericServerStream[EncryptStreamRequest, EncryptStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EncryptionService_EncryptStreamServer = grpc.BidiStreamingServer[EncryptStreamRequest, EncryptStreamResponse]
+
+func _EncryptionService_DecryptStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EncryptionServiceServer).DecryptStream(&grpc.GenericServerStream[DecryptStreamRequest, DecryptStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EncryptionService_DecryptStreamServer = grpc.BidiStreamingServer[DecryptStreamRequest, DecryptStreamResponse]
+
 // EncryptionService_ServiceDesc is the grpc.ServiceDesc for EncryptionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -209,7 +493,40 @@ var EncryptionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeriveKey",
 			Handler:    _EncryptionService_DeriveKey_Handler,
 		},
+		{
+			MethodName: "EncryptFormatPreserving",
+			Handler:    _EncryptionService_EncryptFormatPreserving_Handler,
+		},
+		{
+			MethodName: "DecryptFormatPreserving",
+			Handler:    _EncryptionService_DecryptFormatPreserving_Handler,
+		},
+		{
+			MethodName: "OpenHPKE",
+			Handler:    _EncryptionService_OpenHPKE_Handler,
+		},
+		{
+			MethodName: "EncryptDeterministic",
+			Handler:    _EncryptionService_EncryptDeterministic_Handler,
+		},
+		{
+			MethodName: "DecryptDeterministic",
+			Handler:    _EncryptionService_DecryptDeterministic_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EncryptStream",
+			Handler:       _EncryptionService_EncryptStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DecryptStream",
+			Handler:       _EncryptionService_DecryptStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "vault/v1/encryption.proto",
 }
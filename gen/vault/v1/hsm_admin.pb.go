@@ -0,0 +1,657 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: vault/v1/hsm_admin.proto
+
+package vaultpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HsmOperation is an HSM provider operation.
+type HsmOperation int32
+
+const (
+	// HSM_OPERATION_UNSPECIFIED, when setting a fault, applies to every
+	// operation without a fault of its own.
+	HsmOperation_HSM_OPERATION_UNSPECIFIED  HsmOperation = 0
+	HsmOperation_HSM_OPERATION_GENERATE_KEY HsmOperation = 1
+	HsmOperation_HSM_OPERATION_SIGN         HsmOperation = 2
+	HsmOperation_HSM_OPERATION_ECDH         HsmOperation = 3
+	HsmOperation_HSM_OPERATION_ENCRYPT      HsmOperation = 4
+	HsmOperation_HSM_OPERATION_DECRYPT      HsmOperation = 5
+	HsmOperation_HSM_OPERATION_DERIVE       HsmOperation = 6
+	// HSM_OPERATION_PROBE is the periodic health probe, whose failures open
+	// the provider's circuit breaker.
+	HsmOperation_HSM_OPERATION_PROBE HsmOperation = 7
+)
+
+// Enum value maps for HsmOperation.
+var (
+	HsmOperation_name = map[int32]string{
+		0: "HSM_OPERATION_UNSPECIFIED",
+		1: "HSM_OPERATION_GENERATE_KEY",
+		2: "HSM_OPERATION_SIGN",
+		3: "HSM_OPERATION_ECDH",
+		4: "HSM_OPERATION_ENCRYPT",
+		5: "HSM_OPERATION_DECRYPT",
+		6: "HSM_OPERATION_DERIVE",
+		7: "HSM_OPERATION_PROBE",
+	}
+	HsmOperation_value = map[string]int32{
+		"HSM_OPERATION_UNSPECIFIED":  0,
+		"HSM_OPERATION_GENERATE_KEY": 1,
+		"HSM_OPERATION_SIGN":         2,
+		"HSM_OPERATION_ECDH":         3,
+		"HSM_OPERATION_ENCRYPT":      4,
+		"HSM_OPERATION_DECRYPT":      5,
+		"HSM_OPERATION_DERIVE":       6,
+		"HSM_OPERATION_PROBE":        7,
+	}
+)
+
+func (x HsmOperation) Enum() *HsmOperation {
+	p := new(HsmOperation)
+	*p = x
+	return p
+}
+
+func (x HsmOperation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HsmOperation) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_hsm_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (HsmOperation) Type() protoreflect.EnumType {
+	return &file_vault_v1_hsm_admin_proto_enumTypes[0]
+}
+
+func (x HsmOperation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HsmOperation.Descriptor instead.
+func (HsmOperation) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{0}
+}
+
+// HsmFault describes how an operation misbehaves.
+type HsmFault struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// latency delays the operation before it runs.
+	Latency *durationpb.Duration `protobuf:"bytes,1,opt,name=latency,proto3" json:"latency,omitempty"`
+	// error_rate is the probability, from 0 to 1, that the operation fails
+	// as if the provider were unavailable.
+	ErrorRate float64 `protobuf:"fixed64,2,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	// corrupt_rate is the probability that a signature is returned with a
+	// flipped bit. It only applies to HSM_OPERATION_SIGN.
+	CorruptRate float64 `protobuf:"fixed64,3,opt,name=corrupt_rate,json=corruptRate,proto3" json:"corrupt_rate,omitempty"`
+	// hang blocks the operation until the fault is changed or cleared.
+	Hang          bool `protobuf:"varint,4,opt,name=hang,proto3" json:"hang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HsmFault) Reset() {
+	*x = HsmFault{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HsmFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HsmFault) ProtoMessage() {}
+
+func (x *HsmFault) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HsmFault.ProtoReflect.Descriptor instead.
+func (*HsmFault) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HsmFault) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *HsmFault) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *HsmFault) GetCorruptRate() float64 {
+	if x != nil {
+		return x.CorruptRate
+	}
+	return 0
+}
+
+func (x *HsmFault) GetHang() bool {
+	if x != nil {
+		return x.Hang
+	}
+	return false
+}
+
+// SetHSMFaultRequest names the provider and operation to fault.
+type SetHSMFaultRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// provider is the HSM provider name.
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// operation is the operation to fault.
+	Operation HsmOperation `protobuf:"varint,2,opt,name=operation,proto3,enum=vault.v1.HsmOperation" json:"operation,omitempty"`
+	// fault is the misbehaviour to inject.
+	Fault         *HsmFault `protobuf:"bytes,3,opt,name=fault,proto3" json:"fault,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetHSMFaultRequest) Reset() {
+	*x = SetHSMFaultRequest{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetHSMFaultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetHSMFaultRequest) ProtoMessage() {}
+
+func (x *SetHSMFaultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetHSMFaultRequest.ProtoReflect.Descriptor instead.
+func (*SetHSMFaultRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetHSMFaultRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *SetHSMFaultRequest) GetOperation() HsmOperation {
+	if x != nil {
+		return x.Operation
+	}
+	return HsmOperation_HSM_OPERATION_UNSPECIFIED
+}
+
+func (x *SetHSMFaultRequest) GetFault() *HsmFault {
+	if x != nil {
+		return x.Fault
+	}
+	return nil
+}
+
+// SetHSMFaultResponse is empty on success.
+type SetHSMFaultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetHSMFaultResponse) Reset() {
+	*x = SetHSMFaultResponse{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetHSMFaultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetHSMFaultResponse) ProtoMessage() {}
+
+func (x *SetHSMFaultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetHSMFaultResponse.ProtoReflect.Descriptor instead.
+func (*SetHSMFaultResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{2}
+}
+
+// ClearHSMFaultsRequest names the provider to restore.
+type ClearHSMFaultsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// provider is the HSM provider name.
+	Provider      string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearHSMFaultsRequest) Reset() {
+	*x = ClearHSMFaultsRequest{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearHSMFaultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearHSMFaultsRequest) ProtoMessage() {}
+
+func (x *ClearHSMFaultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearHSMFaultsRequest.ProtoReflect.Descriptor instead.
+func (*ClearHSMFaultsRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ClearHSMFaultsRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+// ClearHSMFaultsResponse is empty on success.
+type ClearHSMFaultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearHSMFaultsResponse) Reset() {
+	*x = ClearHSMFaultsResponse{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearHSMFaultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearHSMFaultsResponse) ProtoMessage() {}
+
+func (x *ClearHSMFaultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearHSMFaultsResponse.ProtoReflect.Descriptor instead.
+func (*ClearHSMFaultsResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{4}
+}
+
+// ListHSMFaultsRequest takes no parameters.
+type ListHSMFaultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHSMFaultsRequest) Reset() {
+	*x = ListHSMFaultsRequest{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHSMFaultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHSMFaultsRequest) ProtoMessage() {}
+
+func (x *ListHSMFaultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHSMFaultsRequest.ProtoReflect.Descriptor instead.
+func (*ListHSMFaultsRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{5}
+}
+
+// ProviderFaults lists the faults set on one provider.
+type ProviderFaults struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// provider is the HSM provider name.
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// faults are the faults set, one per operation.
+	Faults        []*OperationFault `protobuf:"bytes,2,rep,name=faults,proto3" json:"faults,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderFaults) Reset() {
+	*x = ProviderFaults{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderFaults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderFaults) ProtoMessage() {}
+
+func (x *ProviderFaults) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderFaults.ProtoReflect.Descriptor instead.
+func (*ProviderFaults) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProviderFaults) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderFaults) GetFaults() []*OperationFault {
+	if x != nil {
+		return x.Faults
+	}
+	return nil
+}
+
+// OperationFault is the fault set on one operation.
+type OperationFault struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operation     HsmOperation           `protobuf:"varint,1,opt,name=operation,proto3,enum=vault.v1.HsmOperation" json:"operation,omitempty"`
+	Fault         *HsmFault              `protobuf:"bytes,2,opt,name=fault,proto3" json:"fault,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationFault) Reset() {
+	*x = OperationFault{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationFault) ProtoMessage() {}
+
+func (x *OperationFault) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationFault.ProtoReflect.Descriptor instead.
+func (*OperationFault) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OperationFault) GetOperation() HsmOperation {
+	if x != nil {
+		return x.Operation
+	}
+	return HsmOperation_HSM_OPERATION_UNSPECIFIED
+}
+
+func (x *OperationFault) GetFault() *HsmFault {
+	if x != nil {
+		return x.Fault
+	}
+	return nil
+}
+
+// ListHSMFaultsResponse lists every fault-injecting provider, including
+// those with no faults set.
+type ListHSMFaultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Providers     []*ProviderFaults      `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHSMFaultsResponse) Reset() {
+	*x = ListHSMFaultsResponse{}
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHSMFaultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHSMFaultsResponse) ProtoMessage() {}
+
+func (x *ListHSMFaultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHSMFaultsResponse.ProtoReflect.Descriptor instead.
+func (*ListHSMFaultsResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListHSMFaultsResponse) GetProviders() []*ProviderFaults {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+var File_vault_v1_hsm_admin_proto protoreflect.FileDescriptor
+
+const file_vault_v1_hsm_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x18vault/v1/hsm_admin.proto\x12\bvault.v1\x1a\x1egoogle/protobuf/duration.proto\"\x95\x01\n" +
+	"\bHsmFault\x123\n" +
+	"\alatency\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\alatency\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\x02 \x01(\x01R\terrorRate\x12!\n" +
+	"\fcorrupt_rate\x18\x03 \x01(\x01R\vcorruptRate\x12\x12\n" +
+	"\x04hang\x18\x04 \x01(\bR\x04hang\"\x90\x01\n" +
+	"\x12SetHSMFaultRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x124\n" +
+	"\toperation\x18\x02 \x01(\x0e2\x16.vault.v1.HsmOperationR\toperation\x12(\n" +
+	"\x05fault\x18\x03 \x01(\v2\x12.vault.v1.HsmFaultR\x05fault\"\x15\n" +
+	"\x13SetHSMFaultResponse\"3\n" +
+	"\x15ClearHSMFaultsRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\"\x18\n" +
+	"\x16ClearHSMFaultsResponse\"\x16\n" +
+	"\x14ListHSMFaultsRequest\"^\n" +
+	"\x0eProviderFaults\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x120\n" +
+	"\x06faults\x18\x02 \x03(\v2\x18.vault.v1.OperationFaultR\x06faults\"p\n" +
+	"\x0eOperationFault\x124\n" +
+	"\toperation\x18\x01 \x01(\x0e2\x16.vault.v1.HsmOperationR\toperation\x12(\n" +
+	"\x05fault\x18\x02 \x01(\v2\x12.vault.v1.HsmFaultR\x05fault\"O\n" +
+	"\x15ListHSMFaultsResponse\x126\n" +
+	"\tproviders\x18\x01 \x03(\v2\x18.vault.v1.ProviderFaultsR\tproviders*\xe6\x01\n" +
+	"\fHsmOperation\x12\x1d\n" +
+	"\x19HSM_OPERATION_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aHSM_OPERATION_GENERATE_KEY\x10\x01\x12\x16\n" +
+	"\x12HSM_OPERATION_SIGN\x10\x02\x12\x16\n" +
+	"\x12HSM_OPERATION_ECDH\x10\x03\x12\x19\n" +
+	"\x15HSM_OPERATION_ENCRYPT\x10\x04\x12\x19\n" +
+	"\x15HSM_OPERATION_DECRYPT\x10\x05\x12\x18\n" +
+	"\x14HSM_OPERATION_DERIVE\x10\x06\x12\x17\n" +
+	"\x13HSM_OPERATION_PROBE\x10\a2\x84\x02\n" +
+	"\x0fHSMAdminService\x12J\n" +
+	"\vSetHSMFault\x12\x1c.vault.v1.SetHSMFaultRequest\x1a\x1d.vault.v1.SetHSMFaultResponse\x12S\n" +
+	"\x0eClearHSMFaults\x12\x1f.vault.v1.ClearHSMFaultsRequest\x1a .vault.v1.ClearHSMFaultsResponse\x12P\n" +
+	"\rListHSMFaults\x12\x1e.vault.v1.ListHSMFaultsRequest\x1a\x1f.vault.v1.ListHSMFaultsResponseB5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+
+var (
+	file_vault_v1_hsm_admin_proto_rawDescOnce sync.Once
+	file_vault_v1_hsm_admin_proto_rawDescData []byte
+)
+
+func file_vault_v1_hsm_admin_proto_rawDescGZIP() []byte {
+	file_vault_v1_hsm_admin_proto_rawDescOnce.Do(func() {
+		file_vault_v1_hsm_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vault_v1_hsm_admin_proto_rawDesc), len(file_vault_v1_hsm_admin_proto_rawDesc)))
+	})
+	return file_vault_v1_hsm_admin_proto_rawDescData
+}
+
+var file_vault_v1_hsm_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_vault_v1_hsm_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_vault_v1_hsm_admin_proto_goTypes = []any{
+	(HsmOperation)(0),              // 0: vault.v1.HsmOperation
+	(*HsmFault)(nil),               // 1: vault.v1.HsmFault
+	(*SetHSMFaultRequest)(nil),     // 2: vault.v1.SetHSMFaultRequest
+	(*SetHSMFaultResponse)(nil),    // 3: vault.v1.SetHSMFaultResponse
+	(*ClearHSMFaultsRequest)(nil),  // 4: vault.v1.ClearHSMFaultsRequest
+	(*ClearHSMFaultsResponse)(nil), // 5: vault.v1.ClearHSMFaultsResponse
+	(*ListHSMFaultsRequest)(nil),   // 6: vault.v1.ListHSMFaultsRequest
+	(*ProviderFaults)(nil),         // 7: vault.v1.ProviderFaults
+	(*OperationFault)(nil),         // 8: vault.v1.OperationFault
+	(*ListHSMFaultsResponse)(nil),  // 9: vault.v1.ListHSMFaultsResponse
+	(*durationpb.Duration)(nil),    // 10: google.protobuf.Duration
+}
+var file_vault_v1_hsm_admin_proto_depIdxs = []int32{
+	10, // 0: vault.v1.HsmFault.latency:type_name -> google.protobuf.Duration
+	0,  // 1: vault.v1.SetHSMFaultRequest.operation:type_name -> vault.v1.HsmOperation
+	1,  // 2: vault.v1.SetHSMFaultRequest.fault:type_name -> vault.v1.HsmFault
+	8,  // 3: vault.v1.ProviderFaults.faults:type_name -> vault.v1.OperationFault
+	0,  // 4: vault.v1.OperationFault.operation:type_name -> vault.v1.HsmOperation
+	1,  // 5: vault.v1.OperationFault.fault:type_name -> vault.v1.HsmFault
+	7,  // 6: vault.v1.ListHSMFaultsResponse.providers:type_name -> vault.v1.ProviderFaults
+	2,  // 7: vault.v1.HSMAdminService.SetHSMFault:input_type -> vault.v1.SetHSMFaultRequest
+	4,  // 8: vault.v1.HSMAdminService.ClearHSMFaults:input_type -> vault.v1.ClearHSMFaultsRequest
+	6,  // 9: vault.v1.HSMAdminService.ListHSMFaults:input_type -> vault.v1.ListHSMFaultsRequest
+	3,  // 10: vault.v1.HSMAdminService.SetHSMFault:output_type -> vault.v1.SetHSMFaultResponse
+	5,  // 11: vault.v1.HSMAdminService.ClearHSMFaults:output_type -> vault.v1.ClearHSMFaultsResponse
+	9,  // 12: vault.v1.HSMAdminService.ListHSMFaults:output_type -> vault.v1.ListHSMFaultsResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_vault_v1_hsm_admin_proto_init() }
+func file_vault_v1_hsm_admin_proto_init() {
+	if File_vault_v1_hsm_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_hsm_admin_proto_rawDesc), len(file_vault_v1_hsm_admin_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vault_v1_hsm_admin_proto_goTypes,
+		DependencyIndexes: file_vault_v1_hsm_admin_proto_depIdxs,
+		EnumInfos:         file_vault_v1_hsm_admin_proto_enumTypes,
+		MessageInfos:      file_vault_v1_hsm_admin_proto_msgTypes,
+	}.Build()
+	File_vault_v1_hsm_admin_proto = out.File
+	file_vault_v1_hsm_admin_proto_goTypes = nil
+	file_vault_v1_hsm_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v6.33.4
+// source: vault/v1/hsm_admin.proto
+
+package vaultpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HSMAdminService_SetHSMFault_FullMethodName    = "/vault.v1.HSMAdminService/SetHSMFault"
+	HSMAdminService_ClearHSMFaults_FullMethodName = "/vault.v1.HSMAdminService/ClearHSMFaults"
+	HSMAdminService_ListHSMFaults_FullMethodName  = "/vault.v1.HSMAdminService/ListHSMFaults"
+)
+
+// HSMAdminServiceClient is the client API for HSMAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HSMAdminService injects faults into HSM providers at runtime, so
+// integration suites can exercise retries, timeouts and failover against a
+// running server. Only providers started with fault injection enabled
+// (VAULT_HSM_<NAME>_FAULTS=true) accept faults, and callers need the
+// "hsm-faults" permission.
+type HSMAdminServiceClient interface {
+	// SetHSMFault sets the fault for one operation of a provider, replacing
+	// any it had. An empty fault clears it.
+	SetHSMFault(ctx context.Context, in *SetHSMFaultRequest, opts ...grpc.CallOption) (*SetHSMFaultResponse, error)
+	// ClearHSMFaults removes every fault of a provider and releases the
+	// operations hung by one.
+	ClearHSMFaults(ctx context.Context, in *ClearHSMFaultsRequest, opts ...grpc.CallOption) (*ClearHSMFaultsResponse, error)
+	// ListHSMFaults returns the faults set on each fault-injecting provider.
+	ListHSMFaults(ctx context.Context, in *ListHSMFaultsRequest, opts ...grpc.CallOption) (*ListHSMFaultsResponse, error)
+}
+
+type hSMAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHSMAdminServiceClient(cc grpc.ClientConnInterface) HSMAdminServiceClient {
+	return &hSMAdminServiceClient{cc}
+}
+
+func (c *hSMAdminServiceClient) SetHSMFault(ctx context.Context, in *SetHSMFaultRequest, opts ...grpc.CallOption) (*SetHSMFaultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetHSMFaultResponse)
+	err := c.cc.Invoke(ctx, HSMAdminService_SetHSMFault_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMAdminServiceClient) ClearHSMFaults(ctx context.Context, in *ClearHSMFaultsRequest, opts ...grpc.CallOption) (*ClearHSMFaultsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearHSMFaultsResponse)
+	err := c.cc.Invoke(ctx, HSMAdminService_ClearHSMFaults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMAdminServiceClient) ListHSMFaults(ctx context.Context, in *ListHSMFaultsRequest, opts ...grpc.CallOption) (*ListHSMFaultsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHSMFaultsResponse)
+	err := c.cc.Invoke(ctx, HSMAdminService_ListHSMFaults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HSMAdminServiceServer is the server API for HSMAdminService service.
+// All implementations must embed UnimplementedHSMAdminServiceServer
+// for forward compatibility.
+//
+// HSMAdminService injects faults into HSM providers at runtime, so
+// integration suites can exercise retries, timeouts and failover against a
+// running server. Only providers started with fault injection enabled
+// (VAULT_HSM_<NAME>_FAULTS=true) accept faults, and callers need the
+// "hsm-faults" permission.
+type HSMAdminServiceServer interface {
+	// SetHSMFault sets the fault for one operation of a provider, replacing
+	// any it had. An empty fault clears it.
+	SetHSMFault(context.Context, *SetHSMFaultRequest) (*SetHSMFaultResponse, error)
+	// ClearHSMFaults removes every fault of a provider and releases the
+	// operations hung by one.
+	ClearHSMFaults(context.Context, *ClearHSMFaultsRequest) (*ClearHSMFaultsResponse, error)
+	// ListHSMFaults returns the faults set on each fault-injecting provider.
+	ListHSMFaults(context.Context, *ListHSMFaultsRequest) (*ListHSMFaultsResponse, error)
+	mustEmbedUnimplementedHSMAdminServiceServer()
+}
+
+// UnimplementedHSMAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHSMAdminServiceServer struct{}
+
+func (UnimplementedHSMAdminServiceServer) SetHSMFault(context.Context, *SetHSMFaultRequest) (*SetHSMFaultResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetHSMFault not implemented")
+}
+func (UnimplementedHSMAdminServiceServer) ClearHSMFaults(context.Context, *ClearHSMFaultsRequest) (*ClearHSMFaultsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearHSMFaults not implemented")
+}
+func (UnimplementedHSMAdminServiceServer) ListHSMFaults(context.Context, *ListHSMFaultsRequest) (*ListHSMFaultsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListHSMFaults not implemented")
+}
+func (UnimplementedHSMAdminServiceServer) mustEmbedUnimplementedHSMAdminServiceServer() {}
+func (UnimplementedHSMAdminServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeHSMAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HSMAdminServiceServer will
+// result in compilation errors.
+type UnsafeHSMAdminServiceServer interface {
+	mustEmbedUnimplementedHSMAdminServiceServer()
+}
+
+func RegisterHSMAdminServiceServer(s grpc.ServiceRegistrar, srv HSMAdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedHSMAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HSMAdminService_ServiceDesc, srv)
+}
+
+func _HSMAdminService_SetHSMFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHSMFaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMAdminServiceServer).SetHSMFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMAdminService_SetHSMFault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMAdminServiceServer).SetHSMFault(ctx, req.(*SetHSMFaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMAdminService_ClearHSMFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearHSMFaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMAdminServiceServer).ClearHSMFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMAdminService_ClearHSMFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMAdminServiceServer).ClearHSMFaults(ctx, req.(*ClearHSMFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMAdminService_ListHSMFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHSMFaultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMAdminServiceServer).ListHSMFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMAdminService_ListHSMFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMAdminServiceServer).ListHSMFaults(ctx, req.(*ListHSMFaultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HSMAdminService_ServiceDesc is the grpc.ServiceDesc for HSMAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HSMAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vault.v1.HSMAdminService",
+	HandlerType: (*HSMAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetHSMFault",
+			Handler:    _HSMAdminService_SetHSMFault_Handler,
+		},
+		{
+			MethodName: "ClearHSMFaults",
+			Handler:    _HSMAdminService_ClearHSMFaults_Handler,
+		},
+		{
+			MethodName: "ListHSMFaults",
+			Handler:    _HSMAdminService_ListHSMFaults_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vault/v1/hsm_admin.proto",
+}
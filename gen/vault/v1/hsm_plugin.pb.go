@@ -0,0 +1,945 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: vault/v1/hsm_plugin.proto
+
+package vaultpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PluginGenerateKeyRequest names the vault key and its curve.
+type PluginGenerateKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id is the vault key ID, which the plugin may use to label the key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// algorithm is KEY_ALGORITHM_ECDSA_P256 or KEY_ALGORITHM_ECDSA_P384.
+	Algorithm     KeyAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginGenerateKeyRequest) Reset() {
+	*x = PluginGenerateKeyRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginGenerateKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginGenerateKeyRequest) ProtoMessage() {}
+
+func (x *PluginGenerateKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginGenerateKeyRequest.ProtoReflect.Descriptor instead.
+func (*PluginGenerateKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PluginGenerateKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *PluginGenerateKeyRequest) GetAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+// PluginGenerateKeyResponse returns the new key's handle and public key.
+type PluginGenerateKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// handle refers to the private key in later calls. The server stores it
+	// as given.
+	Handle string `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// public_key_der is the PKIX DER encoding of the public key.
+	PublicKeyDer  []byte `protobuf:"bytes,2,opt,name=public_key_der,json=publicKeyDer,proto3" json:"public_key_der,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginGenerateKeyResponse) Reset() {
+	*x = PluginGenerateKeyResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginGenerateKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginGenerateKeyResponse) ProtoMessage() {}
+
+func (x *PluginGenerateKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginGenerateKeyResponse.ProtoReflect.Descriptor instead.
+func (*PluginGenerateKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PluginGenerateKeyResponse) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *PluginGenerateKeyResponse) GetPublicKeyDer() []byte {
+	if x != nil {
+		return x.PublicKeyDer
+	}
+	return nil
+}
+
+// PluginSignRequest signs data with the key behind handle.
+type PluginSignRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// handle is the key handle returned by GenerateKey.
+	Handle string `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// data is the raw bytes to sign.
+	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginSignRequest) Reset() {
+	*x = PluginSignRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginSignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginSignRequest) ProtoMessage() {}
+
+func (x *PluginSignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginSignRequest.ProtoReflect.Descriptor instead.
+func (*PluginSignRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PluginSignRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *PluginSignRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// PluginSignResponse contains the computed signature.
+type PluginSignResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// signature is the ECDSA signature in ASN.1 DER format.
+	Signature     []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginSignResponse) Reset() {
+	*x = PluginSignResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginSignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginSignResponse) ProtoMessage() {}
+
+func (x *PluginSignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginSignResponse.ProtoReflect.Descriptor instead.
+func (*PluginSignResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PluginSignResponse) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// PluginECDHRequest agrees a shared secret with a peer public key.
+type PluginECDHRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// handle is the key handle returned by GenerateKey.
+	Handle string `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// peer_public_key_der is a PKIX DER public key on the key's curve.
+	PeerPublicKeyDer []byte `protobuf:"bytes,2,opt,name=peer_public_key_der,json=peerPublicKeyDer,proto3" json:"peer_public_key_der,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PluginECDHRequest) Reset() {
+	*x = PluginECDHRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginECDHRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginECDHRequest) ProtoMessage() {}
+
+func (x *PluginECDHRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginECDHRequest.ProtoReflect.Descriptor instead.
+func (*PluginECDHRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PluginECDHRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *PluginECDHRequest) GetPeerPublicKeyDer() []byte {
+	if x != nil {
+		return x.PeerPublicKeyDer
+	}
+	return nil
+}
+
+// PluginECDHResponse contains the raw shared secret.
+type PluginECDHResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// shared_secret is the x-coordinate of the agreed point.
+	SharedSecret  []byte `protobuf:"bytes,1,opt,name=shared_secret,json=sharedSecret,proto3" json:"shared_secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginECDHResponse) Reset() {
+	*x = PluginECDHResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginECDHResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginECDHResponse) ProtoMessage() {}
+
+func (x *PluginECDHResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginECDHResponse.ProtoReflect.Descriptor instead.
+func (*PluginECDHResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PluginECDHResponse) GetSharedSecret() []byte {
+	if x != nil {
+		return x.SharedSecret
+	}
+	return nil
+}
+
+// PluginEncryptRequest encrypts data under the key behind handle.
+type PluginEncryptRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// handle is the key handle returned by GenerateKey.
+	Handle string `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// plaintext is the data to encrypt.
+	Plaintext []byte `protobuf:"bytes,2,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	// aad is additional authenticated data, bound to the ciphertext.
+	Aad           []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginEncryptRequest) Reset() {
+	*x = PluginEncryptRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginEncryptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginEncryptRequest) ProtoMessage() {}
+
+func (x *PluginEncryptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginEncryptRequest.ProtoReflect.Descriptor instead.
+func (*PluginEncryptRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PluginEncryptRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *PluginEncryptRequest) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+func (x *PluginEncryptRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+// PluginEncryptResponse contains the ciphertext.
+type PluginEncryptResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ciphertext is [nonce | ciphertext | tag].
+	Ciphertext    []byte `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginEncryptResponse) Reset() {
+	*x = PluginEncryptResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginEncryptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginEncryptResponse) ProtoMessage() {}
+
+func (x *PluginEncryptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginEncryptResponse.ProtoReflect.Descriptor instead.
+func (*PluginEncryptResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PluginEncryptResponse) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+// PluginDecryptRequest decrypts data sealed by Encrypt.
+type PluginDecryptRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// handle is the key handle returned by GenerateKey.
+	Handle string `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// ciphertext is the output of Encrypt.
+	Ciphertext []byte `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// aad must match the aad given to Encrypt.
+	Aad           []byte `protobuf:"bytes,3,opt,name=aad,proto3" json:"aad,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginDecryptRequest) Reset() {
+	*x = PluginDecryptRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginDecryptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginDecryptRequest) ProtoMessage() {}
+
+func (x *PluginDecryptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginDecryptRequest.ProtoReflect.Descriptor instead.
+func (*PluginDecryptRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PluginDecryptRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *PluginDecryptRequest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *PluginDecryptRequest) GetAad() []byte {
+	if x != nil {
+		return x.Aad
+	}
+	return nil
+}
+
+// PluginDecryptResponse contains the recovered plaintext.
+type PluginDecryptResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// plaintext is the decrypted data.
+	Plaintext     []byte `protobuf:"bytes,1,opt,name=plaintext,proto3" json:"plaintext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginDecryptResponse) Reset() {
+	*x = PluginDecryptResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginDecryptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginDecryptResponse) ProtoMessage() {}
+
+func (x *PluginDecryptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginDecryptResponse.ProtoReflect.Descriptor instead.
+func (*PluginDecryptResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PluginDecryptResponse) GetPlaintext() []byte {
+	if x != nil {
+		return x.Plaintext
+	}
+	return nil
+}
+
+// PluginDeriveRequest derives key material from the key behind handle.
+type PluginDeriveRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// handle is the key handle returned by GenerateKey.
+	Handle string `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// hash selects the HKDF hash function.
+	Hash HkdfHash `protobuf:"varint,2,opt,name=hash,proto3,enum=vault.v1.HkdfHash" json:"hash,omitempty"`
+	// salt is the optional HKDF salt.
+	Salt []byte `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
+	// info is the HKDF context and application specific information.
+	Info []byte `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+	// length is the output length in bytes.
+	Length        uint32 `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginDeriveRequest) Reset() {
+	*x = PluginDeriveRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginDeriveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginDeriveRequest) ProtoMessage() {}
+
+func (x *PluginDeriveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginDeriveRequest.ProtoReflect.Descriptor instead.
+func (*PluginDeriveRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PluginDeriveRequest) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *PluginDeriveRequest) GetHash() HkdfHash {
+	if x != nil {
+		return x.Hash
+	}
+	return HkdfHash_HKDF_HASH_UNSPECIFIED
+}
+
+func (x *PluginDeriveRequest) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *PluginDeriveRequest) GetInfo() []byte {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *PluginDeriveRequest) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+// PluginDeriveResponse contains the derived key material.
+type PluginDeriveResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// output is the HKDF output.
+	Output        []byte `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginDeriveResponse) Reset() {
+	*x = PluginDeriveResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginDeriveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginDeriveResponse) ProtoMessage() {}
+
+func (x *PluginDeriveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginDeriveResponse.ProtoReflect.Descriptor instead.
+func (*PluginDeriveResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PluginDeriveResponse) GetOutput() []byte {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+// PluginGetCapabilitiesRequest takes no parameters.
+type PluginGetCapabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginGetCapabilitiesRequest) Reset() {
+	*x = PluginGetCapabilitiesRequest{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginGetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginGetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *PluginGetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginGetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*PluginGetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{12}
+}
+
+// PluginGetCapabilitiesResponse describes what the plugin supports.
+type PluginGetCapabilitiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// algorithms are the ECDSA algorithms GenerateKey accepts.
+	Algorithms []KeyAlgorithm `protobuf:"varint,1,rep,packed,name=algorithms,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithms,omitempty"`
+	// operations are the operations the plugin performs; the others return
+	// UNIMPLEMENTED.
+	Operations []HsmOperation `protobuf:"varint,2,rep,packed,name=operations,proto3,enum=vault.v1.HsmOperation" json:"operations,omitempty"`
+	// derive_hashes are the hashes Derive accepts.
+	DeriveHashes []HkdfHash `protobuf:"varint,3,rep,packed,name=derive_hashes,json=deriveHashes,proto3,enum=vault.v1.HkdfHash" json:"derive_hashes,omitempty"`
+	// max_concurrency is the most calls the plugin serves at once, or 0 for
+	// no fixed limit.
+	MaxConcurrency uint32 `protobuf:"varint,4,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PluginGetCapabilitiesResponse) Reset() {
+	*x = PluginGetCapabilitiesResponse{}
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginGetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginGetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *PluginGetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_hsm_plugin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginGetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*PluginGetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_hsm_plugin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PluginGetCapabilitiesResponse) GetAlgorithms() []KeyAlgorithm {
+	if x != nil {
+		return x.Algorithms
+	}
+	return nil
+}
+
+func (x *PluginGetCapabilitiesResponse) GetOperations() []HsmOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *PluginGetCapabilitiesResponse) GetDeriveHashes() []HkdfHash {
+	if x != nil {
+		return x.DeriveHashes
+	}
+	return nil
+}
+
+func (x *PluginGetCapabilitiesResponse) GetMaxConcurrency() uint32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+var File_vault_v1_hsm_plugin_proto protoreflect.FileDescriptor
+
+const file_vault_v1_hsm_plugin_proto_rawDesc = "" +
+	"\n" +
+	"\x19vault/v1/hsm_plugin.proto\x12\bvault.v1\x1a\x19vault/v1/encryption.proto\x1a\x18vault/v1/hsm_admin.proto\x1a\x16vault/v1/keymgmt.proto\"g\n" +
+	"\x18PluginGenerateKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\"Y\n" +
+	"\x19PluginGenerateKeyResponse\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12$\n" +
+	"\x0epublic_key_der\x18\x02 \x01(\fR\fpublicKeyDer\"?\n" +
+	"\x11PluginSignRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"2\n" +
+	"\x12PluginSignResponse\x12\x1c\n" +
+	"\tsignature\x18\x01 \x01(\fR\tsignature\"Z\n" +
+	"\x11PluginECDHRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12-\n" +
+	"\x13peer_public_key_der\x18\x02 \x01(\fR\x10peerPublicKeyDer\"9\n" +
+	"\x12PluginECDHResponse\x12#\n" +
+	"\rshared_secret\x18\x01 \x01(\fR\fsharedSecret\"^\n" +
+	"\x14PluginEncryptRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12\x1c\n" +
+	"\tplaintext\x18\x02 \x01(\fR\tplaintext\x12\x10\n" +
+	"\x03aad\x18\x03 \x01(\fR\x03aad\"7\n" +
+	"\x15PluginEncryptResponse\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x01 \x01(\fR\n" +
+	"ciphertext\"`\n" +
+	"\x14PluginDecryptRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\x12\x10\n" +
+	"\x03aad\x18\x03 \x01(\fR\x03aad\"5\n" +
+	"\x15PluginDecryptResponse\x12\x1c\n" +
+	"\tplaintext\x18\x01 \x01(\fR\tplaintext\"\x95\x01\n" +
+	"\x13PluginDeriveRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12&\n" +
+	"\x04hash\x18\x02 \x01(\x0e2\x12.vault.v1.HkdfHashR\x04hash\x12\x12\n" +
+	"\x04salt\x18\x03 \x01(\fR\x04salt\x12\x12\n" +
+	"\x04info\x18\x04 \x01(\fR\x04info\x12\x16\n" +
+	"\x06length\x18\x05 \x01(\rR\x06length\".\n" +
+	"\x14PluginDeriveResponse\x12\x16\n" +
+	"\x06output\x18\x01 \x01(\fR\x06output\"\x1e\n" +
+	"\x1cPluginGetCapabilitiesRequest\"\xf1\x01\n" +
+	"\x1dPluginGetCapabilitiesResponse\x126\n" +
+	"\n" +
+	"algorithms\x18\x01 \x03(\x0e2\x16.vault.v1.KeyAlgorithmR\n" +
+	"algorithms\x126\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\x0e2\x16.vault.v1.HsmOperationR\n" +
+	"operations\x127\n" +
+	"\rderive_hashes\x18\x03 \x03(\x0e2\x12.vault.v1.HkdfHashR\fderiveHashes\x12'\n" +
+	"\x0fmax_concurrency\x18\x04 \x01(\rR\x0emaxConcurrency2\xae\x04\n" +
+	"\tHSMPlugin\x12V\n" +
+	"\vGenerateKey\x12\".vault.v1.PluginGenerateKeyRequest\x1a#.vault.v1.PluginGenerateKeyResponse\x12A\n" +
+	"\x04Sign\x12\x1b.vault.v1.PluginSignRequest\x1a\x1c.vault.v1.PluginSignResponse\x12A\n" +
+	"\x04ECDH\x12\x1b.vault.v1.PluginECDHRequest\x1a\x1c.vault.v1.PluginECDHResponse\x12J\n" +
+	"\aEncrypt\x12\x1e.vault.v1.PluginEncryptRequest\x1a\x1f.vault.v1.PluginEncryptResponse\x12J\n" +
+	"\aDecrypt\x12\x1e.vault.v1.PluginDecryptRequest\x1a\x1f.vault.v1.PluginDecryptResponse\x12G\n" +
+	"\x06Derive\x12\x1d.vault.v1.PluginDeriveRequest\x1a\x1e.vault.v1.PluginDeriveResponse\x12b\n" +
+	"\x0fGetCapabilities\x12&.vault.v1.PluginGetCapabilitiesRequest\x1a'.vault.v1.PluginGetCapabilitiesResponseB5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+
+var (
+	file_vault_v1_hsm_plugin_proto_rawDescOnce sync.Once
+	file_vault_v1_hsm_plugin_proto_rawDescData []byte
+)
+
+func file_vault_v1_hsm_plugin_proto_rawDescGZIP() []byte {
+	file_vault_v1_hsm_plugin_proto_rawDescOnce.Do(func() {
+		file_vault_v1_hsm_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vault_v1_hsm_plugin_proto_rawDesc), len(file_vault_v1_hsm_plugin_proto_rawDesc)))
+	})
+	return file_vault_v1_hsm_plugin_proto_rawDescData
+}
+
+var file_vault_v1_hsm_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_vault_v1_hsm_plugin_proto_goTypes = []any{
+	(*PluginGenerateKeyRequest)(nil),      // 0: vault.v1.PluginGenerateKeyRequest
+	(*PluginGenerateKeyResponse)(nil),     // 1: vault.v1.PluginGenerateKeyResponse
+	(*PluginSignRequest)(nil),             // 2: vault.v1.PluginSignRequest
+	(*PluginSignResponse)(nil),            // 3: vault.v1.PluginSignResponse
+	(*PluginECDHRequest)(nil),             // 4: vault.v1.PluginECDHRequest
+	(*PluginECDHResponse)(nil),            // 5: vault.v1.PluginECDHResponse
+	(*PluginEncryptRequest)(nil),          // 6: vault.v1.PluginEncryptRequest
+	(*PluginEncryptResponse)(nil),         // 7: vault.v1.PluginEncryptResponse
+	(*PluginDecryptRequest)(nil),          // 8: vault.v1.PluginDecryptRequest
+	(*PluginDecryptResponse)(nil),         // 9: vault.v1.PluginDecryptResponse
+	(*PluginDeriveRequest)(nil),           // 10: vault.v1.PluginDeriveRequest
+	(*PluginDeriveResponse)(nil),          // 11: vault.v1.PluginDeriveResponse
+	(*PluginGetCapabilitiesRequest)(nil),  // 12: vault.v1.PluginGetCapabilitiesRequest
+	(*PluginGetCapabilitiesResponse)(nil), // 13: vault.v1.PluginGetCapabilitiesResponse
+	(KeyAlgorithm)(0),                     // 14: vault.v1.KeyAlgorithm
+	(HkdfHash)(0),                         // 15: vault.v1.HkdfHash
+	(HsmOperation)(0),                     // 16: vault.v1.HsmOperation
+}
+var file_vault_v1_hsm_plugin_proto_depIdxs = []int32{
+	14, // 0: vault.v1.PluginGenerateKeyRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
+	15, // 1: vault.v1.PluginDeriveRequest.hash:type_name -> vault.v1.HkdfHash
+	14, // 2: vault.v1.PluginGetCapabilitiesResponse.algorithms:type_name -> vault.v1.KeyAlgorithm
+	16, // 3: vault.v1.PluginGetCapabilitiesResponse.operations:type_name -> vault.v1.HsmOperation
+	15, // 4: vault.v1.PluginGetCapabilitiesResponse.derive_hashes:type_name -> vault.v1.HkdfHash
+	0,  // 5: vault.v1.HSMPlugin.GenerateKey:input_type -> vault.v1.PluginGenerateKeyRequest
+	2,  // 6: vault.v1.HSMPlugin.Sign:input_type -> vault.v1.PluginSignRequest
+	4,  // 7: vault.v1.HSMPlugin.ECDH:input_type -> vault.v1.PluginECDHRequest
+	6,  // 8: vault.v1.HSMPlugin.Encrypt:input_type -> vault.v1.PluginEncryptRequest
+	8,  // 9: vault.v1.HSMPlugin.Decrypt:input_type -> vault.v1.PluginDecryptRequest
+	10, // 10: vault.v1.HSMPlugin.Derive:input_type -> vault.v1.PluginDeriveRequest
+	12, // 11: vault.v1.HSMPlugin.GetCapabilities:input_type -> vault.v1.PluginGetCapabilitiesRequest
+	1,  // 12: vault.v1.HSMPlugin.GenerateKey:output_type -> vault.v1.PluginGenerateKeyResponse
+	3,  // 13: vault.v1.HSMPlugin.Sign:output_type -> vault.v1.PluginSignResponse
+	5,  // 14: vault.v1.HSMPlugin.ECDH:output_type -> vault.v1.PluginECDHResponse
+	7,  // 15: vault.v1.HSMPlugin.Encrypt:output_type -> vault.v1.PluginEncryptResponse
+	9,  // 16: vault.v1.HSMPlugin.Decrypt:output_type -> vault.v1.PluginDecryptResponse
+	11, // 17: vault.v1.HSMPlugin.Derive:output_type -> vault.v1.PluginDeriveResponse
+	13, // 18: vault.v1.HSMPlugin.GetCapabilities:output_type -> vault.v1.PluginGetCapabilitiesResponse
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_vault_v1_hsm_plugin_proto_init() }
+func file_vault_v1_hsm_plugin_proto_init() {
+	if File_vault_v1_hsm_plugin_proto != nil {
+		return
+	}
+	file_vault_v1_encryption_proto_init()
+	file_vault_v1_hsm_admin_proto_init()
+	file_vault_v1_keymgmt_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_hsm_plugin_proto_rawDesc), len(file_vault_v1_hsm_plugin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vault_v1_hsm_plugin_proto_goTypes,
+		DependencyIndexes: file_vault_v1_hsm_plugin_proto_depIdxs,
+		MessageInfos:      file_vault_v1_hsm_plugin_proto_msgTypes,
+	}.Build()
+	File_vault_v1_hsm_plugin_proto = out.File
+	file_vault_v1_hsm_plugin_proto_goTypes = nil
+	file_vault_v1_hsm_plugin_proto_depIdxs = nil
+}
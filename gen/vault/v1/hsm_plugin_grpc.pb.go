@@ -0,0 +1,393 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v6.33.4
+// source: vault/v1/hsm_plugin.proto
+
+package vaultpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HSMPlugin_GenerateKey_FullMethodName     = "/vault.v1.HSMPlugin/GenerateKey"
+	HSMPlugin_Sign_FullMethodName            = "/vault.v1.HSMPlugin/Sign"
+	HSMPlugin_ECDH_FullMethodName            = "/vault.v1.HSMPlugin/ECDH"
+	HSMPlugin_Encrypt_FullMethodName         = "/vault.v1.HSMPlugin/Encrypt"
+	HSMPlugin_Decrypt_FullMethodName         = "/vault.v1.HSMPlugin/Decrypt"
+	HSMPlugin_Derive_FullMethodName          = "/vault.v1.HSMPlugin/Derive"
+	HSMPlugin_GetCapabilities_FullMethodName = "/vault.v1.HSMPlugin/GetCapabilities"
+)
+
+// HSMPluginClient is the client API for HSMPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HSMPlugin is served by an out-of-process HSM provider over a Unix socket,
+// so vendor SDKs can be linked into a separate binary. It mirrors the
+// server's HSM provider interface: private keys stay in the plugin and
+// are referred to by the opaque handle GenerateKey returns. Signature
+// verification only needs the public key and is done by the server.
+//
+// Plugins also serve grpc.health.v1.Health; the server checks it
+// periodically and reconnects to a plugin that restarts.
+//
+// Errors are reported with status codes: INVALID_ARGUMENT for a handle the
+// plugin did not issue, NOT_FOUND for a key it no longer holds and
+// UNIMPLEMENTED for an operation it does not support.
+type HSMPluginClient interface {
+	// GenerateKey creates an ECDSA key pair for a vault key.
+	GenerateKey(ctx context.Context, in *PluginGenerateKeyRequest, opts ...grpc.CallOption) (*PluginGenerateKeyResponse, error)
+	// Sign signs the SHA-256 digest of data.
+	Sign(ctx context.Context, in *PluginSignRequest, opts ...grpc.CallOption) (*PluginSignResponse, error)
+	// ECDH computes the shared secret with a peer public key.
+	ECDH(ctx context.Context, in *PluginECDHRequest, opts ...grpc.CallOption) (*PluginECDHResponse, error)
+	// Encrypt seals data with AES-256-GCM under a key derived from the
+	// private key: [nonce | ciphertext | tag].
+	Encrypt(ctx context.Context, in *PluginEncryptRequest, opts ...grpc.CallOption) (*PluginEncryptResponse, error)
+	// Decrypt reverses Encrypt.
+	Decrypt(ctx context.Context, in *PluginDecryptRequest, opts ...grpc.CallOption) (*PluginDecryptResponse, error)
+	// Derive returns HKDF output keyed by the private key.
+	Derive(ctx context.Context, in *PluginDeriveRequest, opts ...grpc.CallOption) (*PluginDeriveResponse, error)
+	// GetCapabilities reports what the plugin supports. The server treats a
+	// plugin that does not implement it as supporting every operation.
+	GetCapabilities(ctx context.Context, in *PluginGetCapabilitiesRequest, opts ...grpc.CallOption) (*PluginGetCapabilitiesResponse, error)
+}
+
+type hSMPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHSMPluginClient(cc grpc.ClientConnInterface) HSMPluginClient {
+	return &hSMPluginClient{cc}
+}
+
+func (c *hSMPluginClient) GenerateKey(ctx context.Context, in *PluginGenerateKeyRequest, opts ...grpc.CallOption) (*PluginGenerateKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginGenerateKeyResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_GenerateKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMPluginClient) Sign(ctx context.Context, in *PluginSignRequest, opts ...grpc.CallOption) (*PluginSignResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginSignResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_Sign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMPluginClient) ECDH(ctx context.Context, in *PluginECDHRequest, opts ...grpc.CallOption) (*PluginECDHResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginECDHResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_ECDH_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMPluginClient) Encrypt(ctx context.Context, in *PluginEncryptRequest, opts ...grpc.CallOption) (*PluginEncryptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginEncryptResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_Encrypt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMPluginClient) Decrypt(ctx context.Context, in *PluginDecryptRequest, opts ...grpc.CallOption) (*PluginDecryptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginDecryptResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_Decrypt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMPluginClient) Derive(ctx context.Context, in *PluginDeriveRequest, opts ...grpc.CallOption) (*PluginDeriveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginDeriveResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_Derive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hSMPluginClient) GetCapabilities(ctx context.Context, in *PluginGetCapabilitiesRequest, opts ...grpc.CallOption) (*PluginGetCapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginGetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, HSMPlugin_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HSMPluginServer is the server API for HSMPlugin service.
+// All implementations must embed UnimplementedHSMPluginServer
+// for forward compatibility.
+//
+// HSMPlugin is served by an out-of-process HSM provider over a Unix socket,
+// so vendor SDKs can be linked into a separate binary. It mirrors the
+// server's HSM provider interface: private keys stay in the plugin and
+// are referred to by the opaque handle GenerateKey returns. Signature
+// verification only needs the public key and is done by the server.
+//
+// Plugins also serve grpc.health.v1.Health; the server checks it
+// periodically and reconnects to a plugin that restarts.
+//
+// Errors are reported with status codes: INVALID_ARGUMENT for a handle the
+// plugin did not issue, NOT_FOUND for a key it no longer holds and
+// UNIMPLEMENTED for an operation it does not support.
+type HSMPluginServer interface {
+	// GenerateKey creates an ECDSA key pair for a vault key.
+	GenerateKey(context.Context, *PluginGenerateKeyRequest) (*PluginGenerateKeyResponse, error)
+	// Sign signs the SHA-256 digest of data.
+	Sign(context.Context, *PluginSignRequest) (*PluginSignResponse, error)
+	// ECDH computes the shared secret with a peer public key.
+	ECDH(context.Context, *PluginECDHRequest) (*PluginECDHResponse, error)
+	// Encrypt seals data with AES-256-GCM under a key derived from the
+	// private key: [nonce | ciphertext | tag].
+	Encrypt(context.Context, *PluginEncryptRequest) (*PluginEncryptResponse, error)
+	// Decrypt reverses Encrypt.
+	Decrypt(context.Context, *PluginDecryptRequest) (*PluginDecryptResponse, error)
+	// Derive returns HKDF output keyed by the private key.
+	Derive(context.Context, *PluginDeriveRequest) (*PluginDeriveResponse, error)
+	// GetCapabilities reports what the plugin supports. The server treats a
+	// plugin that does not implement it as supporting every operation.
+	GetCapabilities(context.Context, *PluginGetCapabilitiesRequest) (*PluginGetCapabilitiesResponse, error)
+	mustEmbedUnimplementedHSMPluginServer()
+}
+
+// UnimplementedHSMPluginServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHSMPluginServer struct{}
+
+func (UnimplementedHSMPluginServer) GenerateKey(context.Context, *PluginGenerateKeyRequest) (*PluginGenerateKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateKey not implemented")
+}
+func (UnimplementedHSMPluginServer) Sign(context.Context, *PluginSignRequest) (*PluginSignResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Sign not implemented")
+}
+func (UnimplementedHSMPluginServer) ECDH(context.Context, *PluginECDHRequest) (*PluginECDHResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ECDH not implemented")
+}
+func (UnimplementedHSMPluginServer) Encrypt(context.Context, *PluginEncryptRequest) (*PluginEncryptResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Encrypt not implemented")
+}
+func (UnimplementedHSMPluginServer) Decrypt(context.Context, *PluginDecryptRequest) (*PluginDecryptResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Decrypt not implemented")
+}
+func (UnimplementedHSMPluginServer) Derive(context.Context, *PluginDeriveRequest) (*PluginDeriveResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Derive not implemented")
+}
+func (UnimplementedHSMPluginServer) GetCapabilities(context.Context, *PluginGetCapabilitiesRequest) (*PluginGetCapabilitiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedHSMPluginServer) mustEmbedUnimplementedHSMPluginServer() {}
+func (UnimplementedHSMPluginServer) testEmbeddedByValue()                   {}
+
+// UnsafeHSMPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HSMPluginServer will
+// result in compilation errors.
+type UnsafeHSMPluginServer interface {
+	mustEmbedUnimplementedHSMPluginServer()
+}
+
+func RegisterHSMPluginServer(s grpc.ServiceRegistrar, srv HSMPluginServer) {
+	// If the following call panics, it indicates UnimplementedHSMPluginServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HSMPlugin_ServiceDesc, srv)
+}
+
+func _HSMPlugin_GenerateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginGenerateKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).GenerateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_GenerateKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).GenerateKey(ctx, req.(*PluginGenerateKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMPlugin_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginSignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).Sign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_Sign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).Sign(ctx, req.(*PluginSignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMPlugin_ECDH_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginECDHRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).ECDH(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_ECDH_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).ECDH(ctx, req.(*PluginECDHRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMPlugin_Encrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginEncryptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).Encrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_Encrypt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).Encrypt(ctx, req.(*PluginEncryptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMPlugin_Decrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginDecryptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).Decrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_Decrypt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).Decrypt(ctx, req.(*PluginDecryptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMPlugin_Derive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginDeriveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).Derive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_Derive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).Derive(ctx, req.(*PluginDeriveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HSMPlugin_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginGetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HSMPluginServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HSMPlugin_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HSMPluginServer).GetCapabilities(ctx, req.(*PluginGetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HSMPlugin_ServiceDesc is the grpc.ServiceDesc for HSMPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HSMPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vault.v1.HSMPlugin",
+	HandlerType: (*HSMPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateKey",
+			Handler:    _HSMPlugin_GenerateKey_Handler,
+		},
+		{
+			MethodName: "Sign",
+			Handler:    _HSMPlugin_Sign_Handler,
+		},
+		{
+			MethodName: "ECDH",
+			Handler:    _HSMPlugin_ECDH_Handler,
+		},
+		{
+			MethodName: "Encrypt",
+			Handler:    _HSMPlugin_Encrypt_Handler,
+		},
+		{
+			MethodName: "Decrypt",
+			Handler:    _HSMPlugin_Decrypt_Handler,
+		},
+		{
+			MethodName: "Derive",
+			Handler:    _HSMPlugin_Derive_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _HSMPlugin_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vault/v1/hsm_plugin.proto",
+}
@@ -22,7 +22,7 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// KeyAlgorithm specifies the elliptic curve algorithm for key generation.
+// KeyAlgorithm specifies the algorithm and size of a key.
 type KeyAlgorithm int32
 
 const (
@@ -32,19 +32,92 @@ const (
 	KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256 KeyAlgorithm = 1
 	// KEY_ALGORITHM_ECDSA_P384 selects the NIST P-384 curve.
 	KeyAlgorithm_KEY_ALGORITHM_ECDSA_P384 KeyAlgorithm = 2
+	// KEY_ALGORITHM_TDEA_2KEY selects a double-length (112-bit) TDEA key.
+	KeyAlgorithm_KEY_ALGORITHM_TDEA_2KEY KeyAlgorithm = 3
+	// KEY_ALGORITHM_TDEA_3KEY selects a triple-length (168-bit) TDEA key.
+	KeyAlgorithm_KEY_ALGORITHM_TDEA_3KEY KeyAlgorithm = 4
+	// KEY_ALGORITHM_AES_128 selects a 128-bit AES key.
+	KeyAlgorithm_KEY_ALGORITHM_AES_128 KeyAlgorithm = 5
+	// KEY_ALGORITHM_AES_256 selects a 256-bit AES key.
+	KeyAlgorithm_KEY_ALGORITHM_AES_256 KeyAlgorithm = 6
+	// KEY_ALGORITHM_HMAC_SHA256 selects a 256-bit HMAC-SHA256 key.
+	KeyAlgorithm_KEY_ALGORITHM_HMAC_SHA256 KeyAlgorithm = 7
+	// KEY_ALGORITHM_HMAC_SHA512 selects a 512-bit HMAC-SHA512 key.
+	KeyAlgorithm_KEY_ALGORITHM_HMAC_SHA512 KeyAlgorithm = 8
+	// KEY_ALGORITHM_FPE_AES_256 selects a 256-bit AES key for FF1/FF3-1
+	// format-preserving encryption.
+	KeyAlgorithm_KEY_ALGORITHM_FPE_AES_256 KeyAlgorithm = 9
+	// KEY_ALGORITHM_X25519 selects an X25519 key agreement key pair.
+	KeyAlgorithm_KEY_ALGORITHM_X25519 KeyAlgorithm = 10
+	// KEY_ALGORITHM_ML_KEM_768 selects an ML-KEM-768 (FIPS 203) key pair.
+	KeyAlgorithm_KEY_ALGORITHM_ML_KEM_768 KeyAlgorithm = 11
+	// KEY_ALGORITHM_ML_KEM_1024 selects an ML-KEM-1024 (FIPS 203) key pair.
+	KeyAlgorithm_KEY_ALGORITHM_ML_KEM_1024 KeyAlgorithm = 12
+	// KEY_ALGORITHM_ML_KEM_768_X25519 selects the X-Wing hybrid of ML-KEM-768
+	// and X25519 (draft-connolly-cfrg-xwing-kem).
+	KeyAlgorithm_KEY_ALGORITHM_ML_KEM_768_X25519 KeyAlgorithm = 13
+	// KEY_ALGORITHM_CHACHA20_POLY1305 selects a 256-bit ChaCha20-Poly1305
+	// data encryption key.
+	KeyAlgorithm_KEY_ALGORITHM_CHACHA20_POLY1305 KeyAlgorithm = 14
+	// KEY_ALGORITHM_XCHACHA20_POLY1305 selects a 256-bit XChaCha20-Poly1305
+	// data encryption key.
+	KeyAlgorithm_KEY_ALGORITHM_XCHACHA20_POLY1305 KeyAlgorithm = 15
+	// KEY_ALGORITHM_AES_SIV selects a 512-bit AES-SIV (RFC 5297) key for
+	// deterministic encryption. It requires KEY_PURPOSE_DETERMINISTIC_ENCRYPTION.
+	KeyAlgorithm_KEY_ALGORITHM_AES_SIV KeyAlgorithm = 16
+	// KEY_ALGORITHM_RSA_2048, KEY_ALGORITHM_RSA_3072 and KEY_ALGORITHM_RSA_4096
+	// are trusted RSA public keys uploaded with ImportWrappingKey. They can
+	// only wrap exported keys and cannot be generated.
+	KeyAlgorithm_KEY_ALGORITHM_RSA_2048 KeyAlgorithm = 17
+	KeyAlgorithm_KEY_ALGORITHM_RSA_3072 KeyAlgorithm = 18
+	KeyAlgorithm_KEY_ALGORITHM_RSA_4096 KeyAlgorithm = 19
 )
 
 // Enum value maps for KeyAlgorithm.
 var (
 	KeyAlgorithm_name = map[int32]string{
-		0: "KEY_ALGORITHM_UNSPECIFIED",
-		1: "KEY_ALGORITHM_ECDSA_P256",
-		2: "KEY_ALGORITHM_ECDSA_P384",
+		0:  "KEY_ALGORITHM_UNSPECIFIED",
+		1:  "KEY_ALGORITHM_ECDSA_P256",
+		2:  "KEY_ALGORITHM_ECDSA_P384",
+		3:  "KEY_ALGORITHM_TDEA_2KEY",
+		4:  "KEY_ALGORITHM_TDEA_3KEY",
+		5:  "KEY_ALGORITHM_AES_128",
+		6:  "KEY_ALGORITHM_AES_256",
+		7:  "KEY_ALGORITHM_HMAC_SHA256",
+		8:  "KEY_ALGORITHM_HMAC_SHA512",
+		9:  "KEY_ALGORITHM_FPE_AES_256",
+		10: "KEY_ALGORITHM_X25519",
+		11: "KEY_ALGORITHM_ML_KEM_768",
+		12: "KEY_ALGORITHM_ML_KEM_1024",
+		13: "KEY_ALGORITHM_ML_KEM_768_X25519",
+		14: "KEY_ALGORITHM_CHACHA20_POLY1305",
+		15: "KEY_ALGORITHM_XCHACHA20_POLY1305",
+		16: "KEY_ALGORITHM_AES_SIV",
+		17: "KEY_ALGORITHM_RSA_2048",
+		18: "KEY_ALGORITHM_RSA_3072",
+		19: "KEY_ALGORITHM_RSA_4096",
 	}
 	KeyAlgorithm_value = map[string]int32{
-		"KEY_ALGORITHM_UNSPECIFIED": 0,
-		"KEY_ALGORITHM_ECDSA_P256":  1,
-		"KEY_ALGORITHM_ECDSA_P384":  2,
+		"KEY_ALGORITHM_UNSPECIFIED":        0,
+		"KEY_ALGORITHM_ECDSA_P256":         1,
+		"KEY_ALGORITHM_ECDSA_P384":         2,
+		"KEY_ALGORITHM_TDEA_2KEY":          3,
+		"KEY_ALGORITHM_TDEA_3KEY":          4,
+		"KEY_ALGORITHM_AES_128":            5,
+		"KEY_ALGORITHM_AES_256":            6,
+		"KEY_ALGORITHM_HMAC_SHA256":        7,
+		"KEY_ALGORITHM_HMAC_SHA512":        8,
+		"KEY_ALGORITHM_FPE_AES_256":        9,
+		"KEY_ALGORITHM_X25519":             10,
+		"KEY_ALGORITHM_ML_KEM_768":         11,
+		"KEY_ALGORITHM_ML_KEM_1024":        12,
+		"KEY_ALGORITHM_ML_KEM_768_X25519":  13,
+		"KEY_ALGORITHM_CHACHA20_POLY1305":  14,
+		"KEY_ALGORITHM_XCHACHA20_POLY1305": 15,
+		"KEY_ALGORITHM_AES_SIV":            16,
+		"KEY_ALGORITHM_RSA_2048":           17,
+		"KEY_ALGORITHM_RSA_3072":           18,
+		"KEY_ALGORITHM_RSA_4096":           19,
 	}
 )
 
@@ -134,6 +207,295 @@ func (KeyStatus) EnumDescriptor() ([]byte, []int) {
 	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{1}
 }
 
+// KeyPurpose restricts the operations a key may be used for. It corresponds
+// to the key usage field of a TR-31 key block header.
+type KeyPurpose int32
+
+const (
+	// KEY_PURPOSE_UNSPECIFIED places no restriction on the key.
+	KeyPurpose_KEY_PURPOSE_UNSPECIFIED KeyPurpose = 0
+	// KEY_PURPOSE_SIGNING allows signing and verification.
+	KeyPurpose_KEY_PURPOSE_SIGNING KeyPurpose = 1
+	// KEY_PURPOSE_DATA_ENCRYPTION allows encryption and decryption (TR-31 D0).
+	KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION KeyPurpose = 2
+	// KEY_PURPOSE_MAC allows MAC generation and verification (TR-31 M0-M7).
+	KeyPurpose_KEY_PURPOSE_MAC KeyPurpose = 3
+	// KEY_PURPOSE_PIN_ENCRYPTION marks a PIN encryption key (TR-31 P0).
+	KeyPurpose_KEY_PURPOSE_PIN_ENCRYPTION KeyPurpose = 4
+	// KEY_PURPOSE_KEY_ENCRYPTION marks a key encryption key (TR-31 K0).
+	KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION KeyPurpose = 5
+	// KEY_PURPOSE_KEY_BLOCK_PROTECTION marks a TR-31 key block protection
+	// key (TR-31 K1).
+	KeyPurpose_KEY_PURPOSE_KEY_BLOCK_PROTECTION KeyPurpose = 6
+	// KEY_PURPOSE_CARD_VERIFICATION marks a card verification key (TR-31 C0).
+	KeyPurpose_KEY_PURPOSE_CARD_VERIFICATION KeyPurpose = 7
+	// KEY_PURPOSE_PIN_VERIFICATION marks a PIN verification key (TR-31 V0-V2).
+	KeyPurpose_KEY_PURPOSE_PIN_VERIFICATION KeyPurpose = 8
+	// KEY_PURPOSE_BASE_DERIVATION marks a base derivation key (TR-31 B0).
+	KeyPurpose_KEY_PURPOSE_BASE_DERIVATION KeyPurpose = 9
+	// KEY_PURPOSE_KEY_AGREEMENT allows ECDH key agreement with an ECDSA or
+	// X25519 key pair, and encapsulation and decapsulation with a KEM key.
+	KeyPurpose_KEY_PURPOSE_KEY_AGREEMENT KeyPurpose = 10
+	// KEY_PURPOSE_DETERMINISTIC_ENCRYPTION allows only the deterministic
+	// EncryptDeterministic and DecryptDeterministic operations, never the
+	// randomized Encrypt. Only AES_SIV keys carry this purpose.
+	KeyPurpose_KEY_PURPOSE_DETERMINISTIC_ENCRYPTION KeyPurpose = 11
+	// KEY_PURPOSE_TOKEN_FINGERPRINT marks the HMAC key the tokenization
+	// service identifies a domain's PANs with. It permits no operation and
+	// cannot be requested: the service creates one per domain.
+	KeyPurpose_KEY_PURPOSE_TOKEN_FINGERPRINT KeyPurpose = 12
+)
+
+// Enum value maps for KeyPurpose.
+var (
+	KeyPurpose_name = map[int32]string{
+		0:  "KEY_PURPOSE_UNSPECIFIED",
+		1:  "KEY_PURPOSE_SIGNING",
+		2:  "KEY_PURPOSE_DATA_ENCRYPTION",
+		3:  "KEY_PURPOSE_MAC",
+		4:  "KEY_PURPOSE_PIN_ENCRYPTION",
+		5:  "KEY_PURPOSE_KEY_ENCRYPTION",
+		6:  "KEY_PURPOSE_KEY_BLOCK_PROTECTION",
+		7:  "KEY_PURPOSE_CARD_VERIFICATION",
+		8:  "KEY_PURPOSE_PIN_VERIFICATION",
+		9:  "KEY_PURPOSE_BASE_DERIVATION",
+		10: "KEY_PURPOSE_KEY_AGREEMENT",
+		11: "KEY_PURPOSE_DETERMINISTIC_ENCRYPTION",
+		12: "KEY_PURPOSE_TOKEN_FINGERPRINT",
+	}
+	KeyPurpose_value = map[string]int32{
+		"KEY_PURPOSE_UNSPECIFIED":              0,
+		"KEY_PURPOSE_SIGNING":                  1,
+		"KEY_PURPOSE_DATA_ENCRYPTION":          2,
+		"KEY_PURPOSE_MAC":                      3,
+		"KEY_PURPOSE_PIN_ENCRYPTION":           4,
+		"KEY_PURPOSE_KEY_ENCRYPTION":           5,
+		"KEY_PURPOSE_KEY_BLOCK_PROTECTION":     6,
+		"KEY_PURPOSE_CARD_VERIFICATION":        7,
+		"KEY_PURPOSE_PIN_VERIFICATION":         8,
+		"KEY_PURPOSE_BASE_DERIVATION":          9,
+		"KEY_PURPOSE_KEY_AGREEMENT":            10,
+		"KEY_PURPOSE_DETERMINISTIC_ENCRYPTION": 11,
+		"KEY_PURPOSE_TOKEN_FINGERPRINT":        12,
+	}
+)
+
+func (x KeyPurpose) Enum() *KeyPurpose {
+	p := new(KeyPurpose)
+	*p = x
+	return p
+}
+
+func (x KeyPurpose) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyPurpose) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_keymgmt_proto_enumTypes[2].Descriptor()
+}
+
+func (KeyPurpose) Type() protoreflect.EnumType {
+	return &file_vault_v1_keymgmt_proto_enumTypes[2]
+}
+
+func (x KeyPurpose) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyPurpose.Descriptor instead.
+func (KeyPurpose) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{2}
+}
+
+// KeyModeOfUse restricts a key to one direction of its purpose. It
+// corresponds to the mode of use field of a TR-31 key block header.
+type KeyModeOfUse int32
+
+const (
+	// KEY_MODE_OF_USE_UNSPECIFIED allows every operation of the key's purpose.
+	KeyModeOfUse_KEY_MODE_OF_USE_UNSPECIFIED KeyModeOfUse = 0
+	// KEY_MODE_OF_USE_ENCRYPT_ONLY allows encryption and wrapping only.
+	KeyModeOfUse_KEY_MODE_OF_USE_ENCRYPT_ONLY KeyModeOfUse = 1
+	// KEY_MODE_OF_USE_DECRYPT_ONLY allows decryption and unwrapping only.
+	KeyModeOfUse_KEY_MODE_OF_USE_DECRYPT_ONLY KeyModeOfUse = 2
+	// KEY_MODE_OF_USE_GENERATE_ONLY allows signing and MAC generation only.
+	KeyModeOfUse_KEY_MODE_OF_USE_GENERATE_ONLY KeyModeOfUse = 3
+	// KEY_MODE_OF_USE_VERIFY_ONLY allows verification only.
+	KeyModeOfUse_KEY_MODE_OF_USE_VERIFY_ONLY KeyModeOfUse = 4
+	// KEY_MODE_OF_USE_DERIVE_ONLY allows key derivation and key agreement only.
+	KeyModeOfUse_KEY_MODE_OF_USE_DERIVE_ONLY KeyModeOfUse = 5
+)
+
+// Enum value maps for KeyModeOfUse.
+var (
+	KeyModeOfUse_name = map[int32]string{
+		0: "KEY_MODE_OF_USE_UNSPECIFIED",
+		1: "KEY_MODE_OF_USE_ENCRYPT_ONLY",
+		2: "KEY_MODE_OF_USE_DECRYPT_ONLY",
+		3: "KEY_MODE_OF_USE_GENERATE_ONLY",
+		4: "KEY_MODE_OF_USE_VERIFY_ONLY",
+		5: "KEY_MODE_OF_USE_DERIVE_ONLY",
+	}
+	KeyModeOfUse_value = map[string]int32{
+		"KEY_MODE_OF_USE_UNSPECIFIED":   0,
+		"KEY_MODE_OF_USE_ENCRYPT_ONLY":  1,
+		"KEY_MODE_OF_USE_DECRYPT_ONLY":  2,
+		"KEY_MODE_OF_USE_GENERATE_ONLY": 3,
+		"KEY_MODE_OF_USE_VERIFY_ONLY":   4,
+		"KEY_MODE_OF_USE_DERIVE_ONLY":   5,
+	}
+)
+
+func (x KeyModeOfUse) Enum() *KeyModeOfUse {
+	p := new(KeyModeOfUse)
+	*p = x
+	return p
+}
+
+func (x KeyModeOfUse) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyModeOfUse) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_keymgmt_proto_enumTypes[3].Descriptor()
+}
+
+func (KeyModeOfUse) Type() protoreflect.EnumType {
+	return &file_vault_v1_keymgmt_proto_enumTypes[3]
+}
+
+func (x KeyModeOfUse) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyModeOfUse.Descriptor instead.
+func (KeyModeOfUse) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{3}
+}
+
+// KeyOrigin records where a key's material came from.
+type KeyOrigin int32
+
+const (
+	KeyOrigin_KEY_ORIGIN_UNSPECIFIED KeyOrigin = 0
+	// KEY_ORIGIN_GENERATED is key material generated or derived in the vault.
+	KeyOrigin_KEY_ORIGIN_GENERATED KeyOrigin = 1
+	// KEY_ORIGIN_IMPORTED is key material brought in from outside the vault:
+	// with ImportKey, as a TR-31 key block or as key components.
+	KeyOrigin_KEY_ORIGIN_IMPORTED KeyOrigin = 2
+)
+
+// Enum value maps for KeyOrigin.
+var (
+	KeyOrigin_name = map[int32]string{
+		0: "KEY_ORIGIN_UNSPECIFIED",
+		1: "KEY_ORIGIN_GENERATED",
+		2: "KEY_ORIGIN_IMPORTED",
+	}
+	KeyOrigin_value = map[string]int32{
+		"KEY_ORIGIN_UNSPECIFIED": 0,
+		"KEY_ORIGIN_GENERATED":   1,
+		"KEY_ORIGIN_IMPORTED":    2,
+	}
+)
+
+func (x KeyOrigin) Enum() *KeyOrigin {
+	p := new(KeyOrigin)
+	*p = x
+	return p
+}
+
+func (x KeyOrigin) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyOrigin) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_keymgmt_proto_enumTypes[4].Descriptor()
+}
+
+func (KeyOrigin) Type() protoreflect.EnumType {
+	return &file_vault_v1_keymgmt_proto_enumTypes[4]
+}
+
+func (x KeyOrigin) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyOrigin.Descriptor instead.
+func (KeyOrigin) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{4}
+}
+
+// KeyWrapAlgorithm is a scheme key material is wrapped with to cross into
+// or out of the vault.
+type KeyWrapAlgorithm int32
+
+const (
+	KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_UNSPECIFIED KeyWrapAlgorithm = 0
+	// KEY_WRAP_ALGORITHM_RSA_AES_KWP wraps a fresh AES-256 key with RSA-OAEP
+	// (SHA-256, MGF1-SHA-256, no label), followed by the key material
+	// wrapped under that AES key with AES-KWP (RFC 5649). It matches PKCS#11
+	// CKM_RSA_AES_KEY_WRAP.
+	KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_AES_KWP KeyWrapAlgorithm = 1
+	// KEY_WRAP_ALGORITHM_AES_KW wraps key material of a multiple of 8 bytes
+	// under an AES key with AES-KW (RFC 3394). It matches PKCS#11
+	// CKM_AES_KEY_WRAP.
+	KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_AES_KW KeyWrapAlgorithm = 2
+	// KEY_WRAP_ALGORITHM_AES_KWP wraps key material of any length under an
+	// AES key with AES-KWP (RFC 5649). It matches PKCS#11
+	// CKM_AES_KEY_WRAP_KWP.
+	KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_AES_KWP KeyWrapAlgorithm = 3
+	// KEY_WRAP_ALGORITHM_RSA_OAEP encrypts the key material directly with
+	// RSA-OAEP (SHA-256, MGF1-SHA-256, no label). It matches PKCS#11
+	// CKM_RSA_PKCS_OAEP.
+	KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_OAEP KeyWrapAlgorithm = 4
+)
+
+// Enum value maps for KeyWrapAlgorithm.
+var (
+	KeyWrapAlgorithm_name = map[int32]string{
+		0: "KEY_WRAP_ALGORITHM_UNSPECIFIED",
+		1: "KEY_WRAP_ALGORITHM_RSA_AES_KWP",
+		2: "KEY_WRAP_ALGORITHM_AES_KW",
+		3: "KEY_WRAP_ALGORITHM_AES_KWP",
+		4: "KEY_WRAP_ALGORITHM_RSA_OAEP",
+	}
+	KeyWrapAlgorithm_value = map[string]int32{
+		"KEY_WRAP_ALGORITHM_UNSPECIFIED": 0,
+		"KEY_WRAP_ALGORITHM_RSA_AES_KWP": 1,
+		"KEY_WRAP_ALGORITHM_AES_KW":      2,
+		"KEY_WRAP_ALGORITHM_AES_KWP":     3,
+		"KEY_WRAP_ALGORITHM_RSA_OAEP":    4,
+	}
+)
+
+func (x KeyWrapAlgorithm) Enum() *KeyWrapAlgorithm {
+	p := new(KeyWrapAlgorithm)
+	*p = x
+	return p
+}
+
+func (x KeyWrapAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyWrapAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_keymgmt_proto_enumTypes[5].Descriptor()
+}
+
+func (KeyWrapAlgorithm) Type() protoreflect.EnumType {
+	return &file_vault_v1_keymgmt_proto_enumTypes[5]
+}
+
+func (x KeyWrapAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyWrapAlgorithm.Descriptor instead.
+func (KeyWrapAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{5}
+}
+
 // KeyEventType classifies a key lifecycle event.
 type KeyEventType int32
 
@@ -175,11 +537,11 @@ func (x KeyEventType) String() string {
 }
 
 func (KeyEventType) Descriptor() protoreflect.EnumDescriptor {
-	return file_vault_v1_keymgmt_proto_enumTypes[2].Descriptor()
+	return file_vault_v1_keymgmt_proto_enumTypes[6].Descriptor()
 }
 
 func (KeyEventType) Type() protoreflect.EnumType {
-	return &file_vault_v1_keymgmt_proto_enumTypes[2]
+	return &file_vault_v1_keymgmt_proto_enumTypes[6]
 }
 
 func (x KeyEventType) Number() protoreflect.EnumNumber {
@@ -188,7 +550,66 @@ func (x KeyEventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use KeyEventType.Descriptor instead.
 func (KeyEventType) EnumDescriptor() ([]byte, []int) {
-	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{2}
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{6}
+}
+
+// SharedSecretOutput selects how DeriveSharedSecret, Encapsulate and
+// Decapsulate return the shared secret.
+type SharedSecretOutput int32
+
+const (
+	// SHARED_SECRET_OUTPUT_UNSPECIFIED defaults to SHARED_SECRET_OUTPUT_KEY.
+	SharedSecretOutput_SHARED_SECRET_OUTPUT_UNSPECIFIED SharedSecretOutput = 0
+	// SHARED_SECRET_OUTPUT_KEY derives a symmetric key with HKDF and stores
+	// it in the vault; only its metadata is returned.
+	SharedSecretOutput_SHARED_SECRET_OUTPUT_KEY SharedSecretOutput = 1
+	// SHARED_SECRET_OUTPUT_DERIVED returns the HKDF output.
+	SharedSecretOutput_SHARED_SECRET_OUTPUT_DERIVED SharedSecretOutput = 2
+	// SHARED_SECRET_OUTPUT_RAW returns the raw ECDH shared secret.
+	SharedSecretOutput_SHARED_SECRET_OUTPUT_RAW SharedSecretOutput = 3
+)
+
+// Enum value maps for SharedSecretOutput.
+var (
+	SharedSecretOutput_name = map[int32]string{
+		0: "SHARED_SECRET_OUTPUT_UNSPECIFIED",
+		1: "SHARED_SECRET_OUTPUT_KEY",
+		2: "SHARED_SECRET_OUTPUT_DERIVED",
+		3: "SHARED_SECRET_OUTPUT_RAW",
+	}
+	SharedSecretOutput_value = map[string]int32{
+		"SHARED_SECRET_OUTPUT_UNSPECIFIED": 0,
+		"SHARED_SECRET_OUTPUT_KEY":         1,
+		"SHARED_SECRET_OUTPUT_DERIVED":     2,
+		"SHARED_SECRET_OUTPUT_RAW":         3,
+	}
+)
+
+func (x SharedSecretOutput) Enum() *SharedSecretOutput {
+	p := new(SharedSecretOutput)
+	*p = x
+	return p
+}
+
+func (x SharedSecretOutput) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SharedSecretOutput) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_keymgmt_proto_enumTypes[7].Descriptor()
+}
+
+func (SharedSecretOutput) Type() protoreflect.EnumType {
+	return &file_vault_v1_keymgmt_proto_enumTypes[7]
+}
+
+func (x SharedSecretOutput) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SharedSecretOutput.Descriptor instead.
+func (SharedSecretOutput) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{7}
 }
 
 // KeyMetadata contains the identifying information and state of a key.
@@ -196,7 +617,7 @@ type KeyMetadata struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// key_id is the unique identifier for this key.
 	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
-	// algorithm is the elliptic curve algorithm used by this key.
+	// algorithm is the algorithm used by this key.
 	Algorithm KeyAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
 	// status is the current lifecycle state of the key.
 	Status KeyStatus `protobuf:"varint,3,opt,name=status,proto3,enum=vault.v1.KeyStatus" json:"status,omitempty"`
@@ -205,7 +626,35 @@ type KeyMetadata struct {
 	// rotated_at is the timestamp when the key was rotated, if applicable.
 	RotatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=rotated_at,json=rotatedAt,proto3" json:"rotated_at,omitempty"`
 	// labels are user-defined key-value pairs for organizing keys.
-	Labels        map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Labels map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// purpose restricts the operations the key may be used for.
+	Purpose KeyPurpose `protobuf:"varint,7,opt,name=purpose,proto3,enum=vault.v1.KeyPurpose" json:"purpose,omitempty"`
+	// mode_of_use restricts the key to one direction of its purpose.
+	ModeOfUse KeyModeOfUse `protobuf:"varint,8,opt,name=mode_of_use,json=modeOfUse,proto3,enum=vault.v1.KeyModeOfUse" json:"mode_of_use,omitempty"`
+	// exportable is true when the key may leave the vault wrapped under
+	// another key or as split-knowledge components. Keys are never exported
+	// in plaintext.
+	Exportable bool `protobuf:"varint,9,opt,name=exportable,proto3" json:"exportable,omitempty"`
+	// encryption_count is the number of Encrypt calls counted against
+	// encryption_limit, including any refused once the limit was reached.
+	// After a server restart it may be overstated, never understated.
+	EncryptionCount uint64 `protobuf:"varint,10,opt,name=encryption_count,json=encryptionCount,proto3" json:"encryption_count,omitempty"`
+	// encryption_limit is the number of messages the key may encrypt before
+	// random nonces risk colliding (2^32 for AES-GCM and ChaCha20-Poly1305,
+	// per NIST SP 800-38D), or 0 when there is no limit.
+	EncryptionLimit uint64 `protobuf:"varint,11,opt,name=encryption_limit,json=encryptionLimit,proto3" json:"encryption_limit,omitempty"`
+	// parent_key_id is the root key this key was derived from with
+	// EncryptionService.DeriveKey, or empty for keys that were not derived.
+	ParentKeyId string `protobuf:"bytes,12,opt,name=parent_key_id,json=parentKeyId,proto3" json:"parent_key_id,omitempty"`
+	// derivation_path is the path below parent_key_id a DeriveChildKey key
+	// was derived at.
+	DerivationPath string `protobuf:"bytes,13,opt,name=derivation_path,json=derivationPath,proto3" json:"derivation_path,omitempty"`
+	// provider is the name of the HSM provider holding an ECDSA key's
+	// private key.
+	Provider string `protobuf:"bytes,14,opt,name=provider,proto3" json:"provider,omitempty"`
+	// origin records whether the key was generated in the vault or
+	// imported.
+	Origin        KeyOrigin `protobuf:"varint,15,opt,name=origin,proto3,enum=vault.v1.KeyOrigin" json:"origin,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -282,13 +731,86 @@ func (x *KeyMetadata) GetLabels() map[string]string {
 	return nil
 }
 
-// GenerateKeyRequest is the request to create a new key pair.
+func (x *KeyMetadata) GetPurpose() KeyPurpose {
+	if x != nil {
+		return x.Purpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *KeyMetadata) GetModeOfUse() KeyModeOfUse {
+	if x != nil {
+		return x.ModeOfUse
+	}
+	return KeyModeOfUse_KEY_MODE_OF_USE_UNSPECIFIED
+}
+
+func (x *KeyMetadata) GetExportable() bool {
+	if x != nil {
+		return x.Exportable
+	}
+	return false
+}
+
+func (x *KeyMetadata) GetEncryptionCount() uint64 {
+	if x != nil {
+		return x.EncryptionCount
+	}
+	return 0
+}
+
+func (x *KeyMetadata) GetEncryptionLimit() uint64 {
+	if x != nil {
+		return x.EncryptionLimit
+	}
+	return 0
+}
+
+func (x *KeyMetadata) GetParentKeyId() string {
+	if x != nil {
+		return x.ParentKeyId
+	}
+	return ""
+}
+
+func (x *KeyMetadata) GetDerivationPath() string {
+	if x != nil {
+		return x.DerivationPath
+	}
+	return ""
+}
+
+func (x *KeyMetadata) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *KeyMetadata) GetOrigin() KeyOrigin {
+	if x != nil {
+		return x.Origin
+	}
+	return KeyOrigin_KEY_ORIGIN_UNSPECIFIED
+}
+
+// GenerateKeyRequest is the request to create a new key.
 type GenerateKeyRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// algorithm selects the curve. Defaults to P-256 when unspecified.
+	// algorithm selects the key type. Defaults to ECDSA P-256 when unspecified.
 	Algorithm KeyAlgorithm `protobuf:"varint,1,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
 	// labels are optional key-value pairs attached to the key.
-	Labels        map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// purpose restricts the operations the key may be used for.
+	Purpose KeyPurpose `protobuf:"varint,3,opt,name=purpose,proto3,enum=vault.v1.KeyPurpose" json:"purpose,omitempty"`
+	// mode_of_use restricts the key to one direction of its purpose.
+	ModeOfUse KeyModeOfUse `protobuf:"varint,4,opt,name=mode_of_use,json=modeOfUse,proto3,enum=vault.v1.KeyModeOfUse" json:"mode_of_use,omitempty"`
+	// exportable allows the key to be exported wrapped under another key.
+	// It cannot be changed after generation.
+	Exportable bool `protobuf:"varint,5,opt,name=exportable,proto3" json:"exportable,omitempty"`
+	// provider names the HSM provider to generate an ECDSA key in. Defaults
+	// to the server's default provider; other algorithms ignore it.
+	Provider      string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -337,6 +859,34 @@ func (x *GenerateKeyRequest) GetLabels() map[string]string {
 	return nil
 }
 
+func (x *GenerateKeyRequest) GetPurpose() KeyPurpose {
+	if x != nil {
+		return x.Purpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *GenerateKeyRequest) GetModeOfUse() KeyModeOfUse {
+	if x != nil {
+		return x.ModeOfUse
+	}
+	return KeyModeOfUse_KEY_MODE_OF_USE_UNSPECIFIED
+}
+
+func (x *GenerateKeyRequest) GetExportable() bool {
+	if x != nil {
+		return x.Exportable
+	}
+	return false
+}
+
+func (x *GenerateKeyRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
 // GenerateKeyResponse contains the metadata of the newly created key.
 type GenerateKeyResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -384,6 +934,7 @@ func (x *GenerateKeyResponse) GetMetadata() *KeyMetadata {
 }
 
 // GetPublicKeyRequest identifies the key whose public key is requested.
+// Symmetric keys have no public key and are rejected.
 type GetPublicKeyRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// key_id is the unique identifier of the key.
@@ -434,12 +985,17 @@ type GetPublicKeyResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// key_id is the identifier of the key.
 	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
-	// public_key_der is the public key encoded in DER (SubjectPublicKeyInfo) format.
+	// public_key_der is the public key encoded in DER (SubjectPublicKeyInfo)
+	// format. It is empty for KEM keys.
 	PublicKeyDer []byte `protobuf:"bytes,2,opt,name=public_key_der,json=publicKeyDer,proto3" json:"public_key_der,omitempty"`
-	// algorithm is the curve used by this key.
-	Algorithm     KeyAlgorithm `protobuf:"varint,3,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// algorithm is the algorithm of this key.
+	Algorithm KeyAlgorithm `protobuf:"varint,3,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
+	// encapsulation_key is the raw public key of a KEM key: the FIPS 203
+	// encoding for ML-KEM, or the ML-KEM-768 key followed by the 32-byte
+	// X25519 public key for X-Wing.
+	EncapsulationKey []byte `protobuf:"bytes,4,opt,name=encapsulation_key,json=encapsulationKey,proto3" json:"encapsulation_key,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *GetPublicKeyResponse) Reset() {
@@ -493,14 +1049,25 @@ func (x *GetPublicKeyResponse) GetAlgorithm() KeyAlgorithm {
 	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
 }
 
+func (x *GetPublicKeyResponse) GetEncapsulationKey() []byte {
+	if x != nil {
+		return x.EncapsulationKey
+	}
+	return nil
+}
+
 // ListKeysRequest optionally filters the returned keys by status.
 type ListKeysRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// status_filter limits results to keys with this status.
 	// When unspecified, all keys are returned.
-	StatusFilter  KeyStatus `protobuf:"varint,1,opt,name=status_filter,json=statusFilter,proto3,enum=vault.v1.KeyStatus" json:"status_filter,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	StatusFilter KeyStatus `protobuf:"varint,1,opt,name=status_filter,json=statusFilter,proto3,enum=vault.v1.KeyStatus" json:"status_filter,omitempty"`
+	// derivation_path_prefix limits results to derived keys whose path
+	// starts with these segments. Only keys derived since the server started
+	// are listed.
+	DerivationPathPrefix string `protobuf:"bytes,2,opt,name=derivation_path_prefix,json=derivationPathPrefix,proto3" json:"derivation_path_prefix,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *ListKeysRequest) Reset() {
@@ -540,6 +1107,13 @@ func (x *ListKeysRequest) GetStatusFilter() KeyStatus {
 	return KeyStatus_KEY_STATUS_UNSPECIFIED
 }
 
+func (x *ListKeysRequest) GetDerivationPathPrefix() string {
+	if x != nil {
+		return x.DerivationPathPrefix
+	}
+	return ""
+}
+
 // ListKeysResponse contains the list of matching keys.
 type ListKeysResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -881,76 +1455,2271 @@ func (x *KeyEvent) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
-var File_vault_v1_keymgmt_proto protoreflect.FileDescriptor
+// ImportKeyBlockRequest carries a TR-31 key block to import.
+type ImportKeyBlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// kbpk_key_id identifies the key block protection key (TDEA for version B,
+	// AES for version D). Must be active and permit unwrapping.
+	KbpkKeyId string `protobuf:"bytes,1,opt,name=kbpk_key_id,json=kbpkKeyId,proto3" json:"kbpk_key_id,omitempty"`
+	// key_block is the ASCII key block, including header and MAC.
+	KeyBlock string `protobuf:"bytes,2,opt,name=key_block,json=keyBlock,proto3" json:"key_block,omitempty"`
+	// labels are optional key-value pairs attached to the imported key.
+	Labels        map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_vault_v1_keymgmt_proto_rawDesc = "" +
-	"\n" +
-	"\x16vault/v1/keymgmt.proto\x12\bvault.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf3\x02\n" +
-	"\vKeyMetadata\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
-	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12+\n" +
-	"\x06status\x18\x03 \x01(\x0e2\x13.vault.v1.KeyStatusR\x06status\x129\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"rotated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\trotatedAt\x129\n" +
-	"\x06labels\x18\x06 \x03(\v2!.vault.v1.KeyMetadata.LabelsEntryR\x06labels\x1a9\n" +
-	"\vLabelsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc7\x01\n" +
-	"\x12GenerateKeyRequest\x124\n" +
-	"\talgorithm\x18\x01 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12@\n" +
-	"\x06labels\x18\x02 \x03(\v2(.vault.v1.GenerateKeyRequest.LabelsEntryR\x06labels\x1a9\n" +
-	"\vLabelsEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"H\n" +
-	"\x13GenerateKeyResponse\x121\n" +
-	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\",\n" +
-	"\x13GetPublicKeyRequest\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"\x89\x01\n" +
-	"\x14GetPublicKeyResponse\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12$\n" +
-	"\x0epublic_key_der\x18\x02 \x01(\fR\fpublicKeyDer\x124\n" +
-	"\talgorithm\x18\x03 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\"K\n" +
-	"\x0fListKeysRequest\x128\n" +
-	"\rstatus_filter\x18\x01 \x01(\x0e2\x13.vault.v1.KeyStatusR\fstatusFilter\"=\n" +
-	"\x10ListKeysResponse\x12)\n" +
-	"\x04keys\x18\x01 \x03(\v2\x15.vault.v1.KeyMetadataR\x04keys\")\n" +
-	"\x10RotateKeyRequest\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"s\n" +
-	"\x11RotateKeyResponse\x12.\n" +
-	"\aold_key\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\x06oldKey\x12.\n" +
-	"\anew_key\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\x06newKey\"-\n" +
-	"\x14DeactivateKeyRequest\x12\x15\n" +
-	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"J\n" +
-	"\x15DeactivateKeyResponse\x121\n" +
-	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"\x17\n" +
-	"\x15WatchKeyEventsRequest\"\xa3\x01\n" +
-	"\bKeyEvent\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.vault.v1.KeyEventTypeR\x04type\x121\n" +
-	"\bmetadata\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\x128\n" +
-	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp*i\n" +
-	"\fKeyAlgorithm\x12\x1d\n" +
-	"\x19KEY_ALGORITHM_UNSPECIFIED\x10\x00\x12\x1c\n" +
-	"\x18KEY_ALGORITHM_ECDSA_P256\x10\x01\x12\x1c\n" +
-	"\x18KEY_ALGORITHM_ECDSA_P384\x10\x02*r\n" +
+func (x *ImportKeyBlockRequest) Reset() {
+	*x = ImportKeyBlockRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportKeyBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportKeyBlockRequest) ProtoMessage() {}
+
+func (x *ImportKeyBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportKeyBlockRequest.ProtoReflect.Descriptor instead.
+func (*ImportKeyBlockRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ImportKeyBlockRequest) GetKbpkKeyId() string {
+	if x != nil {
+		return x.KbpkKeyId
+	}
+	return ""
+}
+
+func (x *ImportKeyBlockRequest) GetKeyBlock() string {
+	if x != nil {
+		return x.KeyBlock
+	}
+	return ""
+}
+
+func (x *ImportKeyBlockRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// ImportKeyBlockResponse contains the metadata of the imported key.
+type ImportKeyBlockResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// metadata is the imported key's metadata.
+	Metadata      *KeyMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportKeyBlockResponse) Reset() {
+	*x = ImportKeyBlockResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportKeyBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportKeyBlockResponse) ProtoMessage() {}
+
+func (x *ImportKeyBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportKeyBlockResponse.ProtoReflect.Descriptor instead.
+func (*ImportKeyBlockResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ImportKeyBlockResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// ExportKeyBlockRequest identifies the key to export and the protection key.
+type ExportKeyBlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// kbpk_key_id identifies the key block protection key. Must be active and
+	// permit wrapping.
+	KbpkKeyId string `protobuf:"bytes,1,opt,name=kbpk_key_id,json=kbpkKeyId,proto3" json:"kbpk_key_id,omitempty"`
+	// key_id identifies the key to export. Must be exportable, have a purpose,
+	// and be no stronger than the protection key.
+	KeyId         string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportKeyBlockRequest) Reset() {
+	*x = ExportKeyBlockRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportKeyBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportKeyBlockRequest) ProtoMessage() {}
+
+func (x *ExportKeyBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportKeyBlockRequest.ProtoReflect.Descriptor instead.
+func (*ExportKeyBlockRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportKeyBlockRequest) GetKbpkKeyId() string {
+	if x != nil {
+		return x.KbpkKeyId
+	}
+	return ""
+}
+
+func (x *ExportKeyBlockRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// ExportKeyBlockResponse contains the exported key block.
+type ExportKeyBlockResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_block is the ASCII TR-31 key block.
+	KeyBlock      string `protobuf:"bytes,1,opt,name=key_block,json=keyBlock,proto3" json:"key_block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportKeyBlockResponse) Reset() {
+	*x = ExportKeyBlockResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportKeyBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportKeyBlockResponse) ProtoMessage() {}
+
+func (x *ExportKeyBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportKeyBlockResponse.ProtoReflect.Descriptor instead.
+func (*ExportKeyBlockResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportKeyBlockResponse) GetKeyBlock() string {
+	if x != nil {
+		return x.KeyBlock
+	}
+	return ""
+}
+
+// BeginComponentImportRequest opens a component import ceremony.
+type BeginComponentImportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// algorithm is the type of the key being loaded: TDEA or AES.
+	Algorithm KeyAlgorithm `protobuf:"varint,1,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
+	// component_count is the number of components (2-9).
+	ComponentCount int32 `protobuf:"varint,2,opt,name=component_count,json=componentCount,proto3" json:"component_count,omitempty"`
+	// kcv is the check value of the combined key: 3 bytes for TDEA,
+	// 5 bytes (AES-CMAC) for AES.
+	Kcv []byte `protobuf:"bytes,3,opt,name=kcv,proto3" json:"kcv,omitempty"`
+	// purpose restricts the operations the loaded key may be used for.
+	Purpose KeyPurpose `protobuf:"varint,4,opt,name=purpose,proto3,enum=vault.v1.KeyPurpose" json:"purpose,omitempty"`
+	// mode_of_use restricts the loaded key to one direction of its purpose.
+	ModeOfUse KeyModeOfUse `protobuf:"varint,5,opt,name=mode_of_use,json=modeOfUse,proto3,enum=vault.v1.KeyModeOfUse" json:"mode_of_use,omitempty"`
+	// exportable allows the loaded key to be exported.
+	Exportable bool `protobuf:"varint,6,opt,name=exportable,proto3" json:"exportable,omitempty"`
+	// labels are optional key-value pairs attached to the loaded key.
+	Labels map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// timeout_seconds bounds the ceremony. Defaults to 600, at most 86400.
+	TimeoutSeconds int32 `protobuf:"varint,8,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BeginComponentImportRequest) Reset() {
+	*x = BeginComponentImportRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginComponentImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginComponentImportRequest) ProtoMessage() {}
+
+func (x *BeginComponentImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginComponentImportRequest.ProtoReflect.Descriptor instead.
+func (*BeginComponentImportRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BeginComponentImportRequest) GetAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *BeginComponentImportRequest) GetComponentCount() int32 {
+	if x != nil {
+		return x.ComponentCount
+	}
+	return 0
+}
+
+func (x *BeginComponentImportRequest) GetKcv() []byte {
+	if x != nil {
+		return x.Kcv
+	}
+	return nil
+}
+
+func (x *BeginComponentImportRequest) GetPurpose() KeyPurpose {
+	if x != nil {
+		return x.Purpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *BeginComponentImportRequest) GetModeOfUse() KeyModeOfUse {
+	if x != nil {
+		return x.ModeOfUse
+	}
+	return KeyModeOfUse_KEY_MODE_OF_USE_UNSPECIFIED
+}
+
+func (x *BeginComponentImportRequest) GetExportable() bool {
+	if x != nil {
+		return x.Exportable
+	}
+	return false
+}
+
+func (x *BeginComponentImportRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *BeginComponentImportRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+// BeginComponentImportResponse identifies the new ceremony.
+type BeginComponentImportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ceremony_id is passed to SubmitKeyComponent.
+	CeremonyId string `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// expires_at is when pending components are discarded.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginComponentImportResponse) Reset() {
+	*x = BeginComponentImportResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginComponentImportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginComponentImportResponse) ProtoMessage() {}
+
+func (x *BeginComponentImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginComponentImportResponse.ProtoReflect.Descriptor instead.
+func (*BeginComponentImportResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BeginComponentImportResponse) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *BeginComponentImportResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// SubmitKeyComponentRequest delivers one key component.
+type SubmitKeyComponentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ceremony_id identifies the import ceremony.
+	CeremonyId string `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// component is the clear key component.
+	Component []byte `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"`
+	// kcv is the check value of this component.
+	Kcv           []byte `protobuf:"bytes,3,opt,name=kcv,proto3" json:"kcv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitKeyComponentRequest) Reset() {
+	*x = SubmitKeyComponentRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitKeyComponentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitKeyComponentRequest) ProtoMessage() {}
+
+func (x *SubmitKeyComponentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitKeyComponentRequest.ProtoReflect.Descriptor instead.
+func (*SubmitKeyComponentRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SubmitKeyComponentRequest) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *SubmitKeyComponentRequest) GetComponent() []byte {
+	if x != nil {
+		return x.Component
+	}
+	return nil
+}
+
+func (x *SubmitKeyComponentRequest) GetKcv() []byte {
+	if x != nil {
+		return x.Kcv
+	}
+	return nil
+}
+
+// SubmitKeyComponentResponse reports the ceremony's progress.
+type SubmitKeyComponentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// components_received counts the accepted components so far.
+	ComponentsReceived int32 `protobuf:"varint,1,opt,name=components_received,json=componentsReceived,proto3" json:"components_received,omitempty"`
+	// components_required is the ceremony's component count.
+	ComponentsRequired int32 `protobuf:"varint,2,opt,name=components_required,json=componentsRequired,proto3" json:"components_required,omitempty"`
+	// metadata is set once the last component has created the key.
+	Metadata      *KeyMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitKeyComponentResponse) Reset() {
+	*x = SubmitKeyComponentResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitKeyComponentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitKeyComponentResponse) ProtoMessage() {}
+
+func (x *SubmitKeyComponentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitKeyComponentResponse.ProtoReflect.Descriptor instead.
+func (*SubmitKeyComponentResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SubmitKeyComponentResponse) GetComponentsReceived() int32 {
+	if x != nil {
+		return x.ComponentsReceived
+	}
+	return 0
+}
+
+func (x *SubmitKeyComponentResponse) GetComponentsRequired() int32 {
+	if x != nil {
+		return x.ComponentsRequired
+	}
+	return 0
+}
+
+func (x *SubmitKeyComponentResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// BeginComponentExportRequest opens a component export ceremony.
+type BeginComponentExportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the exportable key to split.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// component_count is the number of components (2-9).
+	ComponentCount int32 `protobuf:"varint,2,opt,name=component_count,json=componentCount,proto3" json:"component_count,omitempty"`
+	// timeout_seconds bounds the ceremony. Defaults to 600, at most 86400.
+	TimeoutSeconds int32 `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BeginComponentExportRequest) Reset() {
+	*x = BeginComponentExportRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginComponentExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginComponentExportRequest) ProtoMessage() {}
+
+func (x *BeginComponentExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginComponentExportRequest.ProtoReflect.Descriptor instead.
+func (*BeginComponentExportRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BeginComponentExportRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *BeginComponentExportRequest) GetComponentCount() int32 {
+	if x != nil {
+		return x.ComponentCount
+	}
+	return 0
+}
+
+func (x *BeginComponentExportRequest) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+// BeginComponentExportResponse identifies the new ceremony.
+type BeginComponentExportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ceremony_id is passed to RetrieveKeyComponent.
+	CeremonyId string `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	// expires_at is when components not yet retrieved are discarded.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// kcv is the check value of the whole key.
+	Kcv           []byte `protobuf:"bytes,3,opt,name=kcv,proto3" json:"kcv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginComponentExportResponse) Reset() {
+	*x = BeginComponentExportResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginComponentExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginComponentExportResponse) ProtoMessage() {}
+
+func (x *BeginComponentExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginComponentExportResponse.ProtoReflect.Descriptor instead.
+func (*BeginComponentExportResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BeginComponentExportResponse) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+func (x *BeginComponentExportResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *BeginComponentExportResponse) GetKcv() []byte {
+	if x != nil {
+		return x.Kcv
+	}
+	return nil
+}
+
+// RetrieveKeyComponentRequest asks for the caller's component.
+type RetrieveKeyComponentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ceremony_id identifies the export ceremony.
+	CeremonyId    string `protobuf:"bytes,1,opt,name=ceremony_id,json=ceremonyId,proto3" json:"ceremony_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetrieveKeyComponentRequest) Reset() {
+	*x = RetrieveKeyComponentRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrieveKeyComponentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveKeyComponentRequest) ProtoMessage() {}
+
+func (x *RetrieveKeyComponentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveKeyComponentRequest.ProtoReflect.Descriptor instead.
+func (*RetrieveKeyComponentRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RetrieveKeyComponentRequest) GetCeremonyId() string {
+	if x != nil {
+		return x.CeremonyId
+	}
+	return ""
+}
+
+// RetrieveKeyComponentResponse carries one key component.
+type RetrieveKeyComponentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// component is the clear key component.
+	Component []byte `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	// kcv is the check value of this component.
+	Kcv []byte `protobuf:"bytes,2,opt,name=kcv,proto3" json:"kcv,omitempty"`
+	// index is the component's 1-based position.
+	Index int32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	// components_remaining counts the components still to be retrieved.
+	ComponentsRemaining int32 `protobuf:"varint,4,opt,name=components_remaining,json=componentsRemaining,proto3" json:"components_remaining,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RetrieveKeyComponentResponse) Reset() {
+	*x = RetrieveKeyComponentResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrieveKeyComponentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveKeyComponentResponse) ProtoMessage() {}
+
+func (x *RetrieveKeyComponentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveKeyComponentResponse.ProtoReflect.Descriptor instead.
+func (*RetrieveKeyComponentResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RetrieveKeyComponentResponse) GetComponent() []byte {
+	if x != nil {
+		return x.Component
+	}
+	return nil
+}
+
+func (x *RetrieveKeyComponentResponse) GetKcv() []byte {
+	if x != nil {
+		return x.Kcv
+	}
+	return nil
+}
+
+func (x *RetrieveKeyComponentResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *RetrieveKeyComponentResponse) GetComponentsRemaining() int32 {
+	if x != nil {
+		return x.ComponentsRemaining
+	}
+	return 0
+}
+
+// KdfParams configures the HKDF-SHA256 step applied to a shared secret.
+type KdfParams struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// info is the HKDF info input used for domain separation.
+	Info []byte `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	// length is the derived output length in bytes (1-64) for
+	// SHARED_SECRET_OUTPUT_DERIVED. Defaults to 32. Stored keys take the
+	// length of their algorithm.
+	Length        int32 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KdfParams) Reset() {
+	*x = KdfParams{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KdfParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KdfParams) ProtoMessage() {}
+
+func (x *KdfParams) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KdfParams.ProtoReflect.Descriptor instead.
+func (*KdfParams) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *KdfParams) GetInfo() []byte {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *KdfParams) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+// DeriveSharedSecretRequest identifies the private key, the peer public
+// key and how the shared secret is returned.
+type DeriveSharedSecretRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies an ECDSA P-256/P-384 or X25519 key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// peer_public_key_der is the peer's public key in DER
+	// (SubjectPublicKeyInfo) format, on the same curve as the vault key.
+	PeerPublicKeyDer []byte `protobuf:"bytes,2,opt,name=peer_public_key_der,json=peerPublicKeyDer,proto3" json:"peer_public_key_der,omitempty"`
+	// kdf_params configures HKDF for the KEY and DERIVED outputs.
+	KdfParams *KdfParams `protobuf:"bytes,3,opt,name=kdf_params,json=kdfParams,proto3" json:"kdf_params,omitempty"`
+	// output selects the form of the result.
+	Output SharedSecretOutput `protobuf:"varint,4,opt,name=output,proto3,enum=vault.v1.SharedSecretOutput" json:"output,omitempty"`
+	// derived_key_algorithm is the symmetric algorithm of the stored key.
+	// Defaults to AES-256.
+	DerivedKeyAlgorithm KeyAlgorithm `protobuf:"varint,5,opt,name=derived_key_algorithm,json=derivedKeyAlgorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"derived_key_algorithm,omitempty"`
+	// derived_key_purpose restricts the stored key's operations.
+	DerivedKeyPurpose KeyPurpose `protobuf:"varint,6,opt,name=derived_key_purpose,json=derivedKeyPurpose,proto3,enum=vault.v1.KeyPurpose" json:"derived_key_purpose,omitempty"`
+	// labels are attached to the stored key.
+	Labels        map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeriveSharedSecretRequest) Reset() {
+	*x = DeriveSharedSecretRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeriveSharedSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeriveSharedSecretRequest) ProtoMessage() {}
+
+func (x *DeriveSharedSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeriveSharedSecretRequest.ProtoReflect.Descriptor instead.
+func (*DeriveSharedSecretRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeriveSharedSecretRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DeriveSharedSecretRequest) GetPeerPublicKeyDer() []byte {
+	if x != nil {
+		return x.PeerPublicKeyDer
+	}
+	return nil
+}
+
+func (x *DeriveSharedSecretRequest) GetKdfParams() *KdfParams {
+	if x != nil {
+		return x.KdfParams
+	}
+	return nil
+}
+
+func (x *DeriveSharedSecretRequest) GetOutput() SharedSecretOutput {
+	if x != nil {
+		return x.Output
+	}
+	return SharedSecretOutput_SHARED_SECRET_OUTPUT_UNSPECIFIED
+}
+
+func (x *DeriveSharedSecretRequest) GetDerivedKeyAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.DerivedKeyAlgorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *DeriveSharedSecretRequest) GetDerivedKeyPurpose() KeyPurpose {
+	if x != nil {
+		return x.DerivedKeyPurpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *DeriveSharedSecretRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// DeriveSharedSecretResponse carries either the secret bytes or the
+// metadata of the stored key, depending on the requested output.
+type DeriveSharedSecretResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// shared_secret is the raw or derived secret for the RAW and DERIVED
+	// outputs.
+	SharedSecret []byte `protobuf:"bytes,1,opt,name=shared_secret,json=sharedSecret,proto3" json:"shared_secret,omitempty"`
+	// derived_key is the stored key for the KEY output.
+	DerivedKey    *KeyMetadata `protobuf:"bytes,2,opt,name=derived_key,json=derivedKey,proto3" json:"derived_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeriveSharedSecretResponse) Reset() {
+	*x = DeriveSharedSecretResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeriveSharedSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeriveSharedSecretResponse) ProtoMessage() {}
+
+func (x *DeriveSharedSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeriveSharedSecretResponse.ProtoReflect.Descriptor instead.
+func (*DeriveSharedSecretResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeriveSharedSecretResponse) GetSharedSecret() []byte {
+	if x != nil {
+		return x.SharedSecret
+	}
+	return nil
+}
+
+func (x *DeriveSharedSecretResponse) GetDerivedKey() *KeyMetadata {
+	if x != nil {
+		return x.DerivedKey
+	}
+	return nil
+}
+
+// EncapsulateRequest identifies the recipient's public key and how the
+// shared secret is returned.
+type EncapsulateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies an ML-KEM vault key to encapsulate to. Leave empty
+	// to use encapsulation_key instead.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// encapsulation_key is an external recipient's raw public key, in the
+	// encoding of GetPublicKeyResponse.encapsulation_key.
+	EncapsulationKey []byte `protobuf:"bytes,2,opt,name=encapsulation_key,json=encapsulationKey,proto3" json:"encapsulation_key,omitempty"`
+	// algorithm is the KEM of encapsulation_key. Ignored with key_id.
+	Algorithm KeyAlgorithm `protobuf:"varint,3,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
+	// kdf_params configures HKDF for the KEY and DERIVED outputs.
+	KdfParams *KdfParams `protobuf:"bytes,4,opt,name=kdf_params,json=kdfParams,proto3" json:"kdf_params,omitempty"`
+	// output selects the form of the shared secret.
+	Output SharedSecretOutput `protobuf:"varint,5,opt,name=output,proto3,enum=vault.v1.SharedSecretOutput" json:"output,omitempty"`
+	// derived_key_algorithm is the symmetric algorithm of the stored key.
+	// Defaults to AES-256.
+	DerivedKeyAlgorithm KeyAlgorithm `protobuf:"varint,6,opt,name=derived_key_algorithm,json=derivedKeyAlgorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"derived_key_algorithm,omitempty"`
+	// derived_key_purpose restricts the stored key's operations.
+	DerivedKeyPurpose KeyPurpose `protobuf:"varint,7,opt,name=derived_key_purpose,json=derivedKeyPurpose,proto3,enum=vault.v1.KeyPurpose" json:"derived_key_purpose,omitempty"`
+	// labels are attached to the stored key.
+	Labels        map[string]string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncapsulateRequest) Reset() {
+	*x = EncapsulateRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncapsulateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncapsulateRequest) ProtoMessage() {}
+
+func (x *EncapsulateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncapsulateRequest.ProtoReflect.Descriptor instead.
+func (*EncapsulateRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EncapsulateRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *EncapsulateRequest) GetEncapsulationKey() []byte {
+	if x != nil {
+		return x.EncapsulationKey
+	}
+	return nil
+}
+
+func (x *EncapsulateRequest) GetAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *EncapsulateRequest) GetKdfParams() *KdfParams {
+	if x != nil {
+		return x.KdfParams
+	}
+	return nil
+}
+
+func (x *EncapsulateRequest) GetOutput() SharedSecretOutput {
+	if x != nil {
+		return x.Output
+	}
+	return SharedSecretOutput_SHARED_SECRET_OUTPUT_UNSPECIFIED
+}
+
+func (x *EncapsulateRequest) GetDerivedKeyAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.DerivedKeyAlgorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *EncapsulateRequest) GetDerivedKeyPurpose() KeyPurpose {
+	if x != nil {
+		return x.DerivedKeyPurpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *EncapsulateRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// EncapsulateResponse carries the KEM ciphertext for the recipient and the
+// sender's copy of the shared secret.
+type EncapsulateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ciphertext is sent to the recipient to decapsulate.
+	Ciphertext []byte `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// shared_secret is the raw or derived secret for the RAW and DERIVED
+	// outputs.
+	SharedSecret []byte `protobuf:"bytes,2,opt,name=shared_secret,json=sharedSecret,proto3" json:"shared_secret,omitempty"`
+	// derived_key is the stored key for the KEY output.
+	DerivedKey    *KeyMetadata `protobuf:"bytes,3,opt,name=derived_key,json=derivedKey,proto3" json:"derived_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncapsulateResponse) Reset() {
+	*x = EncapsulateResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncapsulateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncapsulateResponse) ProtoMessage() {}
+
+func (x *EncapsulateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncapsulateResponse.ProtoReflect.Descriptor instead.
+func (*EncapsulateResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *EncapsulateResponse) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *EncapsulateResponse) GetSharedSecret() []byte {
+	if x != nil {
+		return x.SharedSecret
+	}
+	return nil
+}
+
+func (x *EncapsulateResponse) GetDerivedKey() *KeyMetadata {
+	if x != nil {
+		return x.DerivedKey
+	}
+	return nil
+}
+
+// DecapsulateRequest identifies the KEM key, the ciphertext and how the
+// shared secret is returned.
+type DecapsulateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies an ML-KEM key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// ciphertext is the KEM ciphertext produced by the sender.
+	Ciphertext []byte `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// kdf_params configures HKDF for the KEY and DERIVED outputs.
+	KdfParams *KdfParams `protobuf:"bytes,3,opt,name=kdf_params,json=kdfParams,proto3" json:"kdf_params,omitempty"`
+	// output selects the form of the shared secret.
+	Output SharedSecretOutput `protobuf:"varint,4,opt,name=output,proto3,enum=vault.v1.SharedSecretOutput" json:"output,omitempty"`
+	// derived_key_algorithm is the symmetric algorithm of the stored key.
+	// Defaults to AES-256.
+	DerivedKeyAlgorithm KeyAlgorithm `protobuf:"varint,5,opt,name=derived_key_algorithm,json=derivedKeyAlgorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"derived_key_algorithm,omitempty"`
+	// derived_key_purpose restricts the stored key's operations.
+	DerivedKeyPurpose KeyPurpose `protobuf:"varint,6,opt,name=derived_key_purpose,json=derivedKeyPurpose,proto3,enum=vault.v1.KeyPurpose" json:"derived_key_purpose,omitempty"`
+	// labels are attached to the stored key.
+	Labels        map[string]string `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecapsulateRequest) Reset() {
+	*x = DecapsulateRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecapsulateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecapsulateRequest) ProtoMessage() {}
+
+func (x *DecapsulateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecapsulateRequest.ProtoReflect.Descriptor instead.
+func (*DecapsulateRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DecapsulateRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DecapsulateRequest) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *DecapsulateRequest) GetKdfParams() *KdfParams {
+	if x != nil {
+		return x.KdfParams
+	}
+	return nil
+}
+
+func (x *DecapsulateRequest) GetOutput() SharedSecretOutput {
+	if x != nil {
+		return x.Output
+	}
+	return SharedSecretOutput_SHARED_SECRET_OUTPUT_UNSPECIFIED
+}
+
+func (x *DecapsulateRequest) GetDerivedKeyAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.DerivedKeyAlgorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *DecapsulateRequest) GetDerivedKeyPurpose() KeyPurpose {
+	if x != nil {
+		return x.DerivedKeyPurpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *DecapsulateRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// DecapsulateResponse carries either the secret bytes or the metadata of
+// the stored key, depending on the requested output.
+type DecapsulateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// shared_secret is the raw or derived secret for the RAW and DERIVED
+	// outputs.
+	SharedSecret []byte `protobuf:"bytes,1,opt,name=shared_secret,json=sharedSecret,proto3" json:"shared_secret,omitempty"`
+	// derived_key is the stored key for the KEY output.
+	DerivedKey    *KeyMetadata `protobuf:"bytes,2,opt,name=derived_key,json=derivedKey,proto3" json:"derived_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecapsulateResponse) Reset() {
+	*x = DecapsulateResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecapsulateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecapsulateResponse) ProtoMessage() {}
+
+func (x *DecapsulateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecapsulateResponse.ProtoReflect.Descriptor instead.
+func (*DecapsulateResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DecapsulateResponse) GetSharedSecret() []byte {
+	if x != nil {
+		return x.SharedSecret
+	}
+	return nil
+}
+
+func (x *DecapsulateResponse) GetDerivedKey() *KeyMetadata {
+	if x != nil {
+		return x.DerivedKey
+	}
+	return nil
+}
+
+// DeriveChildKeyRequest names the root key, path and algorithm of a
+// derived key.
+type DeriveChildKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// root_key_id identifies a symmetric key whose purpose allows derivation,
+	// such as KEY_PURPOSE_BASE_DERIVATION.
+	RootKeyId string `protobuf:"bytes,1,opt,name=root_key_id,json=rootKeyId,proto3" json:"root_key_id,omitempty"`
+	// path is 1-16 segments separated by '/', each 1-64 letters, digits,
+	// '.', '_' or '-'.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// algorithm of the derived key: ECDSA P-256/P-384 (signing), AES or
+	// (X)ChaCha20-Poly1305 (data encryption), AES-SIV (deterministic
+	// encryption) or HMAC. Defaults to AES-256.
+	Algorithm     KeyAlgorithm `protobuf:"varint,3,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeriveChildKeyRequest) Reset() {
+	*x = DeriveChildKeyRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeriveChildKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeriveChildKeyRequest) ProtoMessage() {}
+
+func (x *DeriveChildKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeriveChildKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeriveChildKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeriveChildKeyRequest) GetRootKeyId() string {
+	if x != nil {
+		return x.RootKeyId
+	}
+	return ""
+}
+
+func (x *DeriveChildKeyRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeriveChildKeyRequest) GetAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+// DeriveChildKeyResponse contains the derived key's metadata.
+type DeriveChildKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// metadata is the derived key's metadata; its key_id can be passed to
+	// any operation its purpose allows.
+	Metadata      *KeyMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeriveChildKeyResponse) Reset() {
+	*x = DeriveChildKeyResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeriveChildKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeriveChildKeyResponse) ProtoMessage() {}
+
+func (x *DeriveChildKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeriveChildKeyResponse.ProtoReflect.Descriptor instead.
+func (*DeriveChildKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeriveChildKeyResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// GetImportParametersRequest describes the key to import.
+type GetImportParametersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// algorithm is the type of the key to import. Every algorithm but the
+	// unspecified and RSA ones can be imported.
+	Algorithm KeyAlgorithm `protobuf:"varint,1,opt,name=algorithm,proto3,enum=vault.v1.KeyAlgorithm" json:"algorithm,omitempty"`
+	// purpose restricts the operations the imported key may be used for.
+	Purpose KeyPurpose `protobuf:"varint,2,opt,name=purpose,proto3,enum=vault.v1.KeyPurpose" json:"purpose,omitempty"`
+	// mode_of_use restricts the imported key to one direction of its
+	// purpose.
+	ModeOfUse KeyModeOfUse `protobuf:"varint,3,opt,name=mode_of_use,json=modeOfUse,proto3,enum=vault.v1.KeyModeOfUse" json:"mode_of_use,omitempty"`
+	// exportable allows the imported key to be exported again, wrapped.
+	Exportable bool `protobuf:"varint,4,opt,name=exportable,proto3" json:"exportable,omitempty"`
+	// labels are optional key-value pairs attached to the imported key.
+	Labels map[string]string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// provider names the HSM provider to import an ECDSA key into. Defaults
+	// to the server's default provider; other algorithms ignore it.
+	Provider      string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImportParametersRequest) Reset() {
+	*x = GetImportParametersRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImportParametersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImportParametersRequest) ProtoMessage() {}
+
+func (x *GetImportParametersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImportParametersRequest.ProtoReflect.Descriptor instead.
+func (*GetImportParametersRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetImportParametersRequest) GetAlgorithm() KeyAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
+}
+
+func (x *GetImportParametersRequest) GetPurpose() KeyPurpose {
+	if x != nil {
+		return x.Purpose
+	}
+	return KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+}
+
+func (x *GetImportParametersRequest) GetModeOfUse() KeyModeOfUse {
+	if x != nil {
+		return x.ModeOfUse
+	}
+	return KeyModeOfUse_KEY_MODE_OF_USE_UNSPECIFIED
+}
+
+func (x *GetImportParametersRequest) GetExportable() bool {
+	if x != nil {
+		return x.Exportable
+	}
+	return false
+}
+
+func (x *GetImportParametersRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *GetImportParametersRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+// GetImportParametersResponse contains what the client needs to wrap the
+// key material.
+type GetImportParametersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// import_token is passed to ImportKey. It is valid once.
+	ImportToken string `protobuf:"bytes,1,opt,name=import_token,json=importToken,proto3" json:"import_token,omitempty"`
+	// wrapping_public_key_der is the PKIX DER encoding of the 3072-bit RSA
+	// key to wrap the key material for.
+	WrappingPublicKeyDer []byte `protobuf:"bytes,2,opt,name=wrapping_public_key_der,json=wrappingPublicKeyDer,proto3" json:"wrapping_public_key_der,omitempty"`
+	// wrapping_algorithm is the scheme to wrap the key material with.
+	WrappingAlgorithm KeyWrapAlgorithm `protobuf:"varint,3,opt,name=wrapping_algorithm,json=wrappingAlgorithm,proto3,enum=vault.v1.KeyWrapAlgorithm" json:"wrapping_algorithm,omitempty"`
+	// expires_at is when the import token and wrapping key are discarded.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImportParametersResponse) Reset() {
+	*x = GetImportParametersResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImportParametersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImportParametersResponse) ProtoMessage() {}
+
+func (x *GetImportParametersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImportParametersResponse.ProtoReflect.Descriptor instead.
+func (*GetImportParametersResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetImportParametersResponse) GetImportToken() string {
+	if x != nil {
+		return x.ImportToken
+	}
+	return ""
+}
+
+func (x *GetImportParametersResponse) GetWrappingPublicKeyDer() []byte {
+	if x != nil {
+		return x.WrappingPublicKeyDer
+	}
+	return nil
+}
+
+func (x *GetImportParametersResponse) GetWrappingAlgorithm() KeyWrapAlgorithm {
+	if x != nil {
+		return x.WrappingAlgorithm
+	}
+	return KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_UNSPECIFIED
+}
+
+func (x *GetImportParametersResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// ImportKeyRequest delivers wrapped key material.
+type ImportKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// import_token is the token GetImportParameters returned.
+	ImportToken string `protobuf:"bytes,1,opt,name=import_token,json=importToken,proto3" json:"import_token,omitempty"`
+	// wrapped_key_material is the key material wrapped with the
+	// wrapping_algorithm under the wrapping public key. The key material
+	// is, by algorithm:
+	//   - symmetric keys: the raw key bytes, of the algorithm's length;
+	//   - ECDSA P-256/P-384 and X25519: the PKCS#8 DER private key;
+	//   - ML-KEM-768 and ML-KEM-1024: the 64-byte d || z seed of FIPS 203;
+	//   - ML-KEM-768+X25519: the 32-byte X-Wing seed.
+	WrappedKeyMaterial []byte `protobuf:"bytes,2,opt,name=wrapped_key_material,json=wrappedKeyMaterial,proto3" json:"wrapped_key_material,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ImportKeyRequest) Reset() {
+	*x = ImportKeyRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportKeyRequest) ProtoMessage() {}
+
+func (x *ImportKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportKeyRequest.ProtoReflect.Descriptor instead.
+func (*ImportKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ImportKeyRequest) GetImportToken() string {
+	if x != nil {
+		return x.ImportToken
+	}
+	return ""
+}
+
+func (x *ImportKeyRequest) GetWrappedKeyMaterial() []byte {
+	if x != nil {
+		return x.WrappedKeyMaterial
+	}
+	return nil
+}
+
+// ImportKeyResponse contains the metadata of the imported key.
+type ImportKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// metadata is the imported key's metadata.
+	Metadata      *KeyMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportKeyResponse) Reset() {
+	*x = ImportKeyResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportKeyResponse) ProtoMessage() {}
+
+func (x *ImportKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportKeyResponse.ProtoReflect.Descriptor instead.
+func (*ImportKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ImportKeyResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// ImportWrappingKeyRequest uploads a trusted wrapping public key.
+type ImportWrappingKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// public_key_der is the PKIX DER encoding of a 2048, 3072 or 4096-bit
+	// RSA public key.
+	PublicKeyDer []byte `protobuf:"bytes,1,opt,name=public_key_der,json=publicKeyDer,proto3" json:"public_key_der,omitempty"`
+	// labels are optional key-value pairs attached to the key.
+	Labels        map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportWrappingKeyRequest) Reset() {
+	*x = ImportWrappingKeyRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWrappingKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWrappingKeyRequest) ProtoMessage() {}
+
+func (x *ImportWrappingKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWrappingKeyRequest.ProtoReflect.Descriptor instead.
+func (*ImportWrappingKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ImportWrappingKeyRequest) GetPublicKeyDer() []byte {
+	if x != nil {
+		return x.PublicKeyDer
+	}
+	return nil
+}
+
+func (x *ImportWrappingKeyRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// ImportWrappingKeyResponse contains the metadata of the uploaded key.
+type ImportWrappingKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// metadata is the wrapping key's metadata. Its purpose is
+	// KEY_PURPOSE_KEY_ENCRYPTION and its mode of use
+	// KEY_MODE_OF_USE_ENCRYPT_ONLY.
+	Metadata      *KeyMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportWrappingKeyResponse) Reset() {
+	*x = ImportWrappingKeyResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWrappingKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWrappingKeyResponse) ProtoMessage() {}
+
+func (x *ImportWrappingKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWrappingKeyResponse.ProtoReflect.Descriptor instead.
+func (*ImportWrappingKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ImportWrappingKeyResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// ExportKeyRequest identifies the key to export and the key to wrap it
+// under.
+type ExportKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the key to export. It must have been created
+	// exportable and must not be deactivated.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// wrapping_key_id identifies the wrapping key: an active AES-128 or
+	// AES-256 key, at least as strong as the exported key, or an RSA key
+	// uploaded with ImportWrappingKey. Its purpose and mode of use must
+	// permit wrapping, and key block protection keys are kept to TR-31.
+	WrappingKeyId string `protobuf:"bytes,2,opt,name=wrapping_key_id,json=wrappingKeyId,proto3" json:"wrapping_key_id,omitempty"`
+	// wrapping_algorithm is the scheme to wrap with:
+	// KEY_WRAP_ALGORITHM_AES_KW or KEY_WRAP_ALGORITHM_AES_KWP under an AES
+	// key, KEY_WRAP_ALGORITHM_RSA_OAEP or KEY_WRAP_ALGORITHM_RSA_AES_KWP
+	// under an RSA key. Defaults to AES_KW and RSA_OAEP respectively.
+	WrappingAlgorithm KeyWrapAlgorithm `protobuf:"varint,3,opt,name=wrapping_algorithm,json=wrappingAlgorithm,proto3,enum=vault.v1.KeyWrapAlgorithm" json:"wrapping_algorithm,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExportKeyRequest) Reset() {
+	*x = ExportKeyRequest{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportKeyRequest) ProtoMessage() {}
+
+func (x *ExportKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportKeyRequest.ProtoReflect.Descriptor instead.
+func (*ExportKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ExportKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *ExportKeyRequest) GetWrappingKeyId() string {
+	if x != nil {
+		return x.WrappingKeyId
+	}
+	return ""
+}
+
+func (x *ExportKeyRequest) GetWrappingAlgorithm() KeyWrapAlgorithm {
+	if x != nil {
+		return x.WrappingAlgorithm
+	}
+	return KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_UNSPECIFIED
+}
+
+// ExportKeyResponse contains the wrapped key material.
+type ExportKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// wrapped_key_material is the key material, in the formats ImportKey
+	// takes, wrapped under the wrapping key.
+	WrappedKeyMaterial []byte `protobuf:"bytes,1,opt,name=wrapped_key_material,json=wrappedKeyMaterial,proto3" json:"wrapped_key_material,omitempty"`
+	// wrapping_algorithm is the scheme the key material was wrapped with.
+	WrappingAlgorithm KeyWrapAlgorithm `protobuf:"varint,2,opt,name=wrapping_algorithm,json=wrappingAlgorithm,proto3,enum=vault.v1.KeyWrapAlgorithm" json:"wrapping_algorithm,omitempty"`
+	// metadata is the exported key's metadata, so the receiver can recreate
+	// its attributes.
+	Metadata      *KeyMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportKeyResponse) Reset() {
+	*x = ExportKeyResponse{}
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportKeyResponse) ProtoMessage() {}
+
+func (x *ExportKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_keymgmt_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportKeyResponse.ProtoReflect.Descriptor instead.
+func (*ExportKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_keymgmt_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ExportKeyResponse) GetWrappedKeyMaterial() []byte {
+	if x != nil {
+		return x.WrappedKeyMaterial
+	}
+	return nil
+}
+
+func (x *ExportKeyResponse) GetWrappingAlgorithm() KeyWrapAlgorithm {
+	if x != nil {
+		return x.WrappingAlgorithm
+	}
+	return KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_UNSPECIFIED
+}
+
+func (x *ExportKeyResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+var File_vault_v1_keymgmt_proto protoreflect.FileDescriptor
+
+const file_vault_v1_keymgmt_proto_rawDesc = "" +
+	"\n" +
+	"\x16vault/v1/keymgmt.proto\x12\bvault.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe7\x05\n" +
+	"\vKeyMetadata\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12+\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x13.vault.v1.KeyStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"rotated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\trotatedAt\x129\n" +
+	"\x06labels\x18\x06 \x03(\v2!.vault.v1.KeyMetadata.LabelsEntryR\x06labels\x12.\n" +
+	"\apurpose\x18\a \x01(\x0e2\x14.vault.v1.KeyPurposeR\apurpose\x126\n" +
+	"\vmode_of_use\x18\b \x01(\x0e2\x16.vault.v1.KeyModeOfUseR\tmodeOfUse\x12\x1e\n" +
+	"\n" +
+	"exportable\x18\t \x01(\bR\n" +
+	"exportable\x12)\n" +
+	"\x10encryption_count\x18\n" +
+	" \x01(\x04R\x0fencryptionCount\x12)\n" +
+	"\x10encryption_limit\x18\v \x01(\x04R\x0fencryptionLimit\x12\"\n" +
+	"\rparent_key_id\x18\f \x01(\tR\vparentKeyId\x12'\n" +
+	"\x0fderivation_path\x18\r \x01(\tR\x0ederivationPath\x12\x1a\n" +
+	"\bprovider\x18\x0e \x01(\tR\bprovider\x12+\n" +
+	"\x06origin\x18\x0f \x01(\x0e2\x13.vault.v1.KeyOriginR\x06origin\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xeb\x02\n" +
+	"\x12GenerateKeyRequest\x124\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12@\n" +
+	"\x06labels\x18\x02 \x03(\v2(.vault.v1.GenerateKeyRequest.LabelsEntryR\x06labels\x12.\n" +
+	"\apurpose\x18\x03 \x01(\x0e2\x14.vault.v1.KeyPurposeR\apurpose\x126\n" +
+	"\vmode_of_use\x18\x04 \x01(\x0e2\x16.vault.v1.KeyModeOfUseR\tmodeOfUse\x12\x1e\n" +
+	"\n" +
+	"exportable\x18\x05 \x01(\bR\n" +
+	"exportable\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"H\n" +
+	"\x13GenerateKeyResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\",\n" +
+	"\x13GetPublicKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"\xb6\x01\n" +
+	"\x14GetPublicKeyResponse\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12$\n" +
+	"\x0epublic_key_der\x18\x02 \x01(\fR\fpublicKeyDer\x124\n" +
+	"\talgorithm\x18\x03 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12+\n" +
+	"\x11encapsulation_key\x18\x04 \x01(\fR\x10encapsulationKey\"\x81\x01\n" +
+	"\x0fListKeysRequest\x128\n" +
+	"\rstatus_filter\x18\x01 \x01(\x0e2\x13.vault.v1.KeyStatusR\fstatusFilter\x124\n" +
+	"\x16derivation_path_prefix\x18\x02 \x01(\tR\x14derivationPathPrefix\"=\n" +
+	"\x10ListKeysResponse\x12)\n" +
+	"\x04keys\x18\x01 \x03(\v2\x15.vault.v1.KeyMetadataR\x04keys\")\n" +
+	"\x10RotateKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"s\n" +
+	"\x11RotateKeyResponse\x12.\n" +
+	"\aold_key\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\x06oldKey\x12.\n" +
+	"\anew_key\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\x06newKey\"-\n" +
+	"\x14DeactivateKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"J\n" +
+	"\x15DeactivateKeyResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"\x17\n" +
+	"\x15WatchKeyEventsRequest\"\xa3\x01\n" +
+	"\bKeyEvent\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.vault.v1.KeyEventTypeR\x04type\x121\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xd4\x01\n" +
+	"\x15ImportKeyBlockRequest\x12\x1e\n" +
+	"\vkbpk_key_id\x18\x01 \x01(\tR\tkbpkKeyId\x12\x1b\n" +
+	"\tkey_block\x18\x02 \x01(\tR\bkeyBlock\x12C\n" +
+	"\x06labels\x18\x03 \x03(\v2+.vault.v1.ImportKeyBlockRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"K\n" +
+	"\x16ImportKeyBlockResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"N\n" +
+	"\x15ExportKeyBlockRequest\x12\x1e\n" +
+	"\vkbpk_key_id\x18\x01 \x01(\tR\tkbpkKeyId\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"5\n" +
+	"\x16ExportKeyBlockResponse\x12\x1b\n" +
+	"\tkey_block\x18\x01 \x01(\tR\bkeyBlock\"\xc5\x03\n" +
+	"\x1bBeginComponentImportRequest\x124\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12'\n" +
+	"\x0fcomponent_count\x18\x02 \x01(\x05R\x0ecomponentCount\x12\x10\n" +
+	"\x03kcv\x18\x03 \x01(\fR\x03kcv\x12.\n" +
+	"\apurpose\x18\x04 \x01(\x0e2\x14.vault.v1.KeyPurposeR\apurpose\x126\n" +
+	"\vmode_of_use\x18\x05 \x01(\x0e2\x16.vault.v1.KeyModeOfUseR\tmodeOfUse\x12\x1e\n" +
+	"\n" +
+	"exportable\x18\x06 \x01(\bR\n" +
+	"exportable\x12I\n" +
+	"\x06labels\x18\a \x03(\v21.vault.v1.BeginComponentImportRequest.LabelsEntryR\x06labels\x12'\n" +
+	"\x0ftimeout_seconds\x18\b \x01(\x05R\x0etimeoutSeconds\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"z\n" +
+	"\x1cBeginComponentImportResponse\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"l\n" +
+	"\x19SubmitKeyComponentRequest\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x12\x1c\n" +
+	"\tcomponent\x18\x02 \x01(\fR\tcomponent\x12\x10\n" +
+	"\x03kcv\x18\x03 \x01(\fR\x03kcv\"\xb1\x01\n" +
+	"\x1aSubmitKeyComponentResponse\x12/\n" +
+	"\x13components_received\x18\x01 \x01(\x05R\x12componentsReceived\x12/\n" +
+	"\x13components_required\x18\x02 \x01(\x05R\x12componentsRequired\x121\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"\x86\x01\n" +
+	"\x1bBeginComponentExportRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12'\n" +
+	"\x0fcomponent_count\x18\x02 \x01(\x05R\x0ecomponentCount\x12'\n" +
+	"\x0ftimeout_seconds\x18\x03 \x01(\x05R\x0etimeoutSeconds\"\x8c\x01\n" +
+	"\x1cBeginComponentExportResponse\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x10\n" +
+	"\x03kcv\x18\x03 \x01(\fR\x03kcv\">\n" +
+	"\x1bRetrieveKeyComponentRequest\x12\x1f\n" +
+	"\vceremony_id\x18\x01 \x01(\tR\n" +
+	"ceremonyId\"\x97\x01\n" +
+	"\x1cRetrieveKeyComponentResponse\x12\x1c\n" +
+	"\tcomponent\x18\x01 \x01(\fR\tcomponent\x12\x10\n" +
+	"\x03kcv\x18\x02 \x01(\fR\x03kcv\x12\x14\n" +
+	"\x05index\x18\x03 \x01(\x05R\x05index\x121\n" +
+	"\x14components_remaining\x18\x04 \x01(\x05R\x13componentsRemaining\"7\n" +
+	"\tKdfParams\x12\x12\n" +
+	"\x04info\x18\x01 \x01(\fR\x04info\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x05R\x06length\"\xe1\x03\n" +
+	"\x19DeriveSharedSecretRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12-\n" +
+	"\x13peer_public_key_der\x18\x02 \x01(\fR\x10peerPublicKeyDer\x122\n" +
+	"\n" +
+	"kdf_params\x18\x03 \x01(\v2\x13.vault.v1.KdfParamsR\tkdfParams\x124\n" +
+	"\x06output\x18\x04 \x01(\x0e2\x1c.vault.v1.SharedSecretOutputR\x06output\x12J\n" +
+	"\x15derived_key_algorithm\x18\x05 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\x13derivedKeyAlgorithm\x12D\n" +
+	"\x13derived_key_purpose\x18\x06 \x01(\x0e2\x14.vault.v1.KeyPurposeR\x11derivedKeyPurpose\x12G\n" +
+	"\x06labels\x18\a \x03(\v2/.vault.v1.DeriveSharedSecretRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"y\n" +
+	"\x1aDeriveSharedSecretResponse\x12#\n" +
+	"\rshared_secret\x18\x01 \x01(\fR\fsharedSecret\x126\n" +
+	"\vderived_key\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\n" +
+	"derivedKey\"\x87\x04\n" +
+	"\x12EncapsulateRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12+\n" +
+	"\x11encapsulation_key\x18\x02 \x01(\fR\x10encapsulationKey\x124\n" +
+	"\talgorithm\x18\x03 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x122\n" +
+	"\n" +
+	"kdf_params\x18\x04 \x01(\v2\x13.vault.v1.KdfParamsR\tkdfParams\x124\n" +
+	"\x06output\x18\x05 \x01(\x0e2\x1c.vault.v1.SharedSecretOutputR\x06output\x12J\n" +
+	"\x15derived_key_algorithm\x18\x06 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\x13derivedKeyAlgorithm\x12D\n" +
+	"\x13derived_key_purpose\x18\a \x01(\x0e2\x14.vault.v1.KeyPurposeR\x11derivedKeyPurpose\x12@\n" +
+	"\x06labels\x18\b \x03(\v2(.vault.v1.EncapsulateRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x92\x01\n" +
+	"\x13EncapsulateResponse\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x01 \x01(\fR\n" +
+	"ciphertext\x12#\n" +
+	"\rshared_secret\x18\x02 \x01(\fR\fsharedSecret\x126\n" +
+	"\vderived_key\x18\x03 \x01(\v2\x15.vault.v1.KeyMetadataR\n" +
+	"derivedKey\"\xc4\x03\n" +
+	"\x12DecapsulateRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\x122\n" +
+	"\n" +
+	"kdf_params\x18\x03 \x01(\v2\x13.vault.v1.KdfParamsR\tkdfParams\x124\n" +
+	"\x06output\x18\x04 \x01(\x0e2\x1c.vault.v1.SharedSecretOutputR\x06output\x12J\n" +
+	"\x15derived_key_algorithm\x18\x05 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\x13derivedKeyAlgorithm\x12D\n" +
+	"\x13derived_key_purpose\x18\x06 \x01(\x0e2\x14.vault.v1.KeyPurposeR\x11derivedKeyPurpose\x12@\n" +
+	"\x06labels\x18\a \x03(\v2(.vault.v1.DecapsulateRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"r\n" +
+	"\x13DecapsulateResponse\x12#\n" +
+	"\rshared_secret\x18\x01 \x01(\fR\fsharedSecret\x126\n" +
+	"\vderived_key\x18\x02 \x01(\v2\x15.vault.v1.KeyMetadataR\n" +
+	"derivedKey\"\x81\x01\n" +
+	"\x15DeriveChildKeyRequest\x12\x1e\n" +
+	"\vroot_key_id\x18\x01 \x01(\tR\trootKeyId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x124\n" +
+	"\talgorithm\x18\x03 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\"K\n" +
+	"\x16DeriveChildKeyResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"\xfb\x02\n" +
+	"\x1aGetImportParametersRequest\x124\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2\x16.vault.v1.KeyAlgorithmR\talgorithm\x12.\n" +
+	"\apurpose\x18\x02 \x01(\x0e2\x14.vault.v1.KeyPurposeR\apurpose\x126\n" +
+	"\vmode_of_use\x18\x03 \x01(\x0e2\x16.vault.v1.KeyModeOfUseR\tmodeOfUse\x12\x1e\n" +
+	"\n" +
+	"exportable\x18\x04 \x01(\bR\n" +
+	"exportable\x12H\n" +
+	"\x06labels\x18\x05 \x03(\v20.vault.v1.GetImportParametersRequest.LabelsEntryR\x06labels\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfd\x01\n" +
+	"\x1bGetImportParametersResponse\x12!\n" +
+	"\fimport_token\x18\x01 \x01(\tR\vimportToken\x125\n" +
+	"\x17wrapping_public_key_der\x18\x02 \x01(\fR\x14wrappingPublicKeyDer\x12I\n" +
+	"\x12wrapping_algorithm\x18\x03 \x01(\x0e2\x1a.vault.v1.KeyWrapAlgorithmR\x11wrappingAlgorithm\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"g\n" +
+	"\x10ImportKeyRequest\x12!\n" +
+	"\fimport_token\x18\x01 \x01(\tR\vimportToken\x120\n" +
+	"\x14wrapped_key_material\x18\x02 \x01(\fR\x12wrappedKeyMaterial\"F\n" +
+	"\x11ImportKeyResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"\xc3\x01\n" +
+	"\x18ImportWrappingKeyRequest\x12$\n" +
+	"\x0epublic_key_der\x18\x01 \x01(\fR\fpublicKeyDer\x12F\n" +
+	"\x06labels\x18\x02 \x03(\v2..vault.v1.ImportWrappingKeyRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"N\n" +
+	"\x19ImportWrappingKeyResponse\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata\"\x9c\x01\n" +
+	"\x10ExportKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12&\n" +
+	"\x0fwrapping_key_id\x18\x02 \x01(\tR\rwrappingKeyId\x12I\n" +
+	"\x12wrapping_algorithm\x18\x03 \x01(\x0e2\x1a.vault.v1.KeyWrapAlgorithmR\x11wrappingAlgorithm\"\xc3\x01\n" +
+	"\x11ExportKeyResponse\x120\n" +
+	"\x14wrapped_key_material\x18\x01 \x01(\fR\x12wrappedKeyMaterial\x12I\n" +
+	"\x12wrapping_algorithm\x18\x02 \x01(\x0e2\x1a.vault.v1.KeyWrapAlgorithmR\x11wrappingAlgorithm\x121\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x15.vault.v1.KeyMetadataR\bmetadata*\xec\x04\n" +
+	"\fKeyAlgorithm\x12\x1d\n" +
+	"\x19KEY_ALGORITHM_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18KEY_ALGORITHM_ECDSA_P256\x10\x01\x12\x1c\n" +
+	"\x18KEY_ALGORITHM_ECDSA_P384\x10\x02\x12\x1b\n" +
+	"\x17KEY_ALGORITHM_TDEA_2KEY\x10\x03\x12\x1b\n" +
+	"\x17KEY_ALGORITHM_TDEA_3KEY\x10\x04\x12\x19\n" +
+	"\x15KEY_ALGORITHM_AES_128\x10\x05\x12\x19\n" +
+	"\x15KEY_ALGORITHM_AES_256\x10\x06\x12\x1d\n" +
+	"\x19KEY_ALGORITHM_HMAC_SHA256\x10\a\x12\x1d\n" +
+	"\x19KEY_ALGORITHM_HMAC_SHA512\x10\b\x12\x1d\n" +
+	"\x19KEY_ALGORITHM_FPE_AES_256\x10\t\x12\x18\n" +
+	"\x14KEY_ALGORITHM_X25519\x10\n" +
+	"\x12\x1c\n" +
+	"\x18KEY_ALGORITHM_ML_KEM_768\x10\v\x12\x1d\n" +
+	"\x19KEY_ALGORITHM_ML_KEM_1024\x10\f\x12#\n" +
+	"\x1fKEY_ALGORITHM_ML_KEM_768_X25519\x10\r\x12#\n" +
+	"\x1fKEY_ALGORITHM_CHACHA20_POLY1305\x10\x0e\x12$\n" +
+	" KEY_ALGORITHM_XCHACHA20_POLY1305\x10\x0f\x12\x19\n" +
+	"\x15KEY_ALGORITHM_AES_SIV\x10\x10\x12\x1a\n" +
+	"\x16KEY_ALGORITHM_RSA_2048\x10\x11\x12\x1a\n" +
+	"\x16KEY_ALGORITHM_RSA_3072\x10\x12\x12\x1a\n" +
+	"\x16KEY_ALGORITHM_RSA_4096\x10\x13*r\n" +
 	"\tKeyStatus\x12\x1a\n" +
 	"\x16KEY_STATUS_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11KEY_STATUS_ACTIVE\x10\x01\x12\x16\n" +
 	"\x12KEY_STATUS_ROTATED\x10\x02\x12\x1a\n" +
-	"\x16KEY_STATUS_DEACTIVATED\x10\x03*\x86\x01\n" +
+	"\x16KEY_STATUS_DEACTIVATED\x10\x03*\xb0\x03\n" +
+	"\n" +
+	"KeyPurpose\x12\x1b\n" +
+	"\x17KEY_PURPOSE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13KEY_PURPOSE_SIGNING\x10\x01\x12\x1f\n" +
+	"\x1bKEY_PURPOSE_DATA_ENCRYPTION\x10\x02\x12\x13\n" +
+	"\x0fKEY_PURPOSE_MAC\x10\x03\x12\x1e\n" +
+	"\x1aKEY_PURPOSE_PIN_ENCRYPTION\x10\x04\x12\x1e\n" +
+	"\x1aKEY_PURPOSE_KEY_ENCRYPTION\x10\x05\x12$\n" +
+	" KEY_PURPOSE_KEY_BLOCK_PROTECTION\x10\x06\x12!\n" +
+	"\x1dKEY_PURPOSE_CARD_VERIFICATION\x10\a\x12 \n" +
+	"\x1cKEY_PURPOSE_PIN_VERIFICATION\x10\b\x12\x1f\n" +
+	"\x1bKEY_PURPOSE_BASE_DERIVATION\x10\t\x12\x1d\n" +
+	"\x19KEY_PURPOSE_KEY_AGREEMENT\x10\n" +
+	"\x12(\n" +
+	"$KEY_PURPOSE_DETERMINISTIC_ENCRYPTION\x10\v\x12!\n" +
+	"\x1dKEY_PURPOSE_TOKEN_FINGERPRINT\x10\f*\xd8\x01\n" +
+	"\fKeyModeOfUse\x12\x1f\n" +
+	"\x1bKEY_MODE_OF_USE_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cKEY_MODE_OF_USE_ENCRYPT_ONLY\x10\x01\x12 \n" +
+	"\x1cKEY_MODE_OF_USE_DECRYPT_ONLY\x10\x02\x12!\n" +
+	"\x1dKEY_MODE_OF_USE_GENERATE_ONLY\x10\x03\x12\x1f\n" +
+	"\x1bKEY_MODE_OF_USE_VERIFY_ONLY\x10\x04\x12\x1f\n" +
+	"\x1bKEY_MODE_OF_USE_DERIVE_ONLY\x10\x05*Z\n" +
+	"\tKeyOrigin\x12\x1a\n" +
+	"\x16KEY_ORIGIN_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14KEY_ORIGIN_GENERATED\x10\x01\x12\x17\n" +
+	"\x13KEY_ORIGIN_IMPORTED\x10\x02*\xba\x01\n" +
+	"\x10KeyWrapAlgorithm\x12\"\n" +
+	"\x1eKEY_WRAP_ALGORITHM_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eKEY_WRAP_ALGORITHM_RSA_AES_KWP\x10\x01\x12\x1d\n" +
+	"\x19KEY_WRAP_ALGORITHM_AES_KW\x10\x02\x12\x1e\n" +
+	"\x1aKEY_WRAP_ALGORITHM_AES_KWP\x10\x03\x12\x1f\n" +
+	"\x1bKEY_WRAP_ALGORITHM_RSA_OAEP\x10\x04*\x86\x01\n" +
 	"\fKeyEventType\x12\x1e\n" +
 	"\x1aKEY_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1a\n" +
 	"\x16KEY_EVENT_TYPE_CREATED\x10\x01\x12\x1a\n" +
 	"\x16KEY_EVENT_TYPE_ROTATED\x10\x02\x12\x1e\n" +
-	"\x1aKEY_EVENT_TYPE_DEACTIVATED\x10\x032\xd5\x03\n" +
+	"\x1aKEY_EVENT_TYPE_DEACTIVATED\x10\x03*\x98\x01\n" +
+	"\x12SharedSecretOutput\x12$\n" +
+	" SHARED_SECRET_OUTPUT_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18SHARED_SECRET_OUTPUT_KEY\x10\x01\x12 \n" +
+	"\x1cSHARED_SECRET_OUTPUT_DERIVED\x10\x02\x12\x1c\n" +
+	"\x18SHARED_SECRET_OUTPUT_RAW\x10\x032\xb1\r\n" +
 	"\x14KeyManagementService\x12J\n" +
 	"\vGenerateKey\x12\x1c.vault.v1.GenerateKeyRequest\x1a\x1d.vault.v1.GenerateKeyResponse\x12M\n" +
 	"\fGetPublicKey\x12\x1d.vault.v1.GetPublicKeyRequest\x1a\x1e.vault.v1.GetPublicKeyResponse\x12A\n" +
 	"\bListKeys\x12\x19.vault.v1.ListKeysRequest\x1a\x1a.vault.v1.ListKeysResponse\x12D\n" +
 	"\tRotateKey\x12\x1a.vault.v1.RotateKeyRequest\x1a\x1b.vault.v1.RotateKeyResponse\x12P\n" +
 	"\rDeactivateKey\x12\x1e.vault.v1.DeactivateKeyRequest\x1a\x1f.vault.v1.DeactivateKeyResponse\x12G\n" +
-	"\x0eWatchKeyEvents\x12\x1f.vault.v1.WatchKeyEventsRequest\x1a\x12.vault.v1.KeyEvent0\x01B5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+	"\x0eWatchKeyEvents\x12\x1f.vault.v1.WatchKeyEventsRequest\x1a\x12.vault.v1.KeyEvent0\x01\x12S\n" +
+	"\x0eImportKeyBlock\x12\x1f.vault.v1.ImportKeyBlockRequest\x1a .vault.v1.ImportKeyBlockResponse\x12S\n" +
+	"\x0eExportKeyBlock\x12\x1f.vault.v1.ExportKeyBlockRequest\x1a .vault.v1.ExportKeyBlockResponse\x12e\n" +
+	"\x14BeginComponentImport\x12%.vault.v1.BeginComponentImportRequest\x1a&.vault.v1.BeginComponentImportResponse\x12_\n" +
+	"\x12SubmitKeyComponent\x12#.vault.v1.SubmitKeyComponentRequest\x1a$.vault.v1.SubmitKeyComponentResponse\x12e\n" +
+	"\x14BeginComponentExport\x12%.vault.v1.BeginComponentExportRequest\x1a&.vault.v1.BeginComponentExportResponse\x12e\n" +
+	"\x14RetrieveKeyComponent\x12%.vault.v1.RetrieveKeyComponentRequest\x1a&.vault.v1.RetrieveKeyComponentResponse\x12_\n" +
+	"\x12DeriveSharedSecret\x12#.vault.v1.DeriveSharedSecretRequest\x1a$.vault.v1.DeriveSharedSecretResponse\x12J\n" +
+	"\vEncapsulate\x12\x1c.vault.v1.EncapsulateRequest\x1a\x1d.vault.v1.EncapsulateResponse\x12J\n" +
+	"\vDecapsulate\x12\x1c.vault.v1.DecapsulateRequest\x1a\x1d.vault.v1.DecapsulateResponse\x12S\n" +
+	"\x0eDeriveChildKey\x12\x1f.vault.v1.DeriveChildKeyRequest\x1a .vault.v1.DeriveChildKeyResponse\x12b\n" +
+	"\x13GetImportParameters\x12$.vault.v1.GetImportParametersRequest\x1a%.vault.v1.GetImportParametersResponse\x12D\n" +
+	"\tImportKey\x12\x1a.vault.v1.ImportKeyRequest\x1a\x1b.vault.v1.ImportKeyResponse\x12\\\n" +
+	"\x11ImportWrappingKey\x12\".vault.v1.ImportWrappingKeyRequest\x1a#.vault.v1.ImportWrappingKeyResponse\x12D\n" +
+	"\tExportKey\x12\x1a.vault.v1.ExportKeyRequest\x1a\x1b.vault.v1.ExportKeyResponseB5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
 
 var (
 	file_vault_v1_keymgmt_proto_rawDescOnce sync.Once
@@ -964,64 +3733,180 @@ func file_vault_v1_keymgmt_proto_rawDescGZIP() []byte {
 	return file_vault_v1_keymgmt_proto_rawDescData
 }
 
-var file_vault_v1_keymgmt_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_vault_v1_keymgmt_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_vault_v1_keymgmt_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_vault_v1_keymgmt_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_vault_v1_keymgmt_proto_goTypes = []any{
-	(KeyAlgorithm)(0),             // 0: vault.v1.KeyAlgorithm
-	(KeyStatus)(0),                // 1: vault.v1.KeyStatus
-	(KeyEventType)(0),             // 2: vault.v1.KeyEventType
-	(*KeyMetadata)(nil),           // 3: vault.v1.KeyMetadata
-	(*GenerateKeyRequest)(nil),    // 4: vault.v1.GenerateKeyRequest
-	(*GenerateKeyResponse)(nil),   // 5: vault.v1.GenerateKeyResponse
-	(*GetPublicKeyRequest)(nil),   // 6: vault.v1.GetPublicKeyRequest
-	(*GetPublicKeyResponse)(nil),  // 7: vault.v1.GetPublicKeyResponse
-	(*ListKeysRequest)(nil),       // 8: vault.v1.ListKeysRequest
-	(*ListKeysResponse)(nil),      // 9: vault.v1.ListKeysResponse
-	(*RotateKeyRequest)(nil),      // 10: vault.v1.RotateKeyRequest
-	(*RotateKeyResponse)(nil),     // 11: vault.v1.RotateKeyResponse
-	(*DeactivateKeyRequest)(nil),  // 12: vault.v1.DeactivateKeyRequest
-	(*DeactivateKeyResponse)(nil), // 13: vault.v1.DeactivateKeyResponse
-	(*WatchKeyEventsRequest)(nil), // 14: vault.v1.WatchKeyEventsRequest
-	(*KeyEvent)(nil),              // 15: vault.v1.KeyEvent
-	nil,                           // 16: vault.v1.KeyMetadata.LabelsEntry
-	nil,                           // 17: vault.v1.GenerateKeyRequest.LabelsEntry
-	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+	(KeyAlgorithm)(0),                    // 0: vault.v1.KeyAlgorithm
+	(KeyStatus)(0),                       // 1: vault.v1.KeyStatus
+	(KeyPurpose)(0),                      // 2: vault.v1.KeyPurpose
+	(KeyModeOfUse)(0),                    // 3: vault.v1.KeyModeOfUse
+	(KeyOrigin)(0),                       // 4: vault.v1.KeyOrigin
+	(KeyWrapAlgorithm)(0),                // 5: vault.v1.KeyWrapAlgorithm
+	(KeyEventType)(0),                    // 6: vault.v1.KeyEventType
+	(SharedSecretOutput)(0),              // 7: vault.v1.SharedSecretOutput
+	(*KeyMetadata)(nil),                  // 8: vault.v1.KeyMetadata
+	(*GenerateKeyRequest)(nil),           // 9: vault.v1.GenerateKeyRequest
+	(*GenerateKeyResponse)(nil),          // 10: vault.v1.GenerateKeyResponse
+	(*GetPublicKeyRequest)(nil),          // 11: vault.v1.GetPublicKeyRequest
+	(*GetPublicKeyResponse)(nil),         // 12: vault.v1.GetPublicKeyResponse
+	(*ListKeysRequest)(nil),              // 13: vault.v1.ListKeysRequest
+	(*ListKeysResponse)(nil),             // 14: vault.v1.ListKeysResponse
+	(*RotateKeyRequest)(nil),             // 15: vault.v1.RotateKeyRequest
+	(*RotateKeyResponse)(nil),            // 16: vault.v1.RotateKeyResponse
+	(*DeactivateKeyRequest)(nil),         // 17: vault.v1.DeactivateKeyRequest
+	(*DeactivateKeyResponse)(nil),        // 18: vault.v1.DeactivateKeyResponse
+	(*WatchKeyEventsRequest)(nil),        // 19: vault.v1.WatchKeyEventsRequest
+	(*KeyEvent)(nil),                     // 20: vault.v1.KeyEvent
+	(*ImportKeyBlockRequest)(nil),        // 21: vault.v1.ImportKeyBlockRequest
+	(*ImportKeyBlockResponse)(nil),       // 22: vault.v1.ImportKeyBlockResponse
+	(*ExportKeyBlockRequest)(nil),        // 23: vault.v1.ExportKeyBlockRequest
+	(*ExportKeyBlockResponse)(nil),       // 24: vault.v1.ExportKeyBlockResponse
+	(*BeginComponentImportRequest)(nil),  // 25: vault.v1.BeginComponentImportRequest
+	(*BeginComponentImportResponse)(nil), // 26: vault.v1.BeginComponentImportResponse
+	(*SubmitKeyComponentRequest)(nil),    // 27: vault.v1.SubmitKeyComponentRequest
+	(*SubmitKeyComponentResponse)(nil),   // 28: vault.v1.SubmitKeyComponentResponse
+	(*BeginComponentExportRequest)(nil),  // 29: vault.v1.BeginComponentExportRequest
+	(*BeginComponentExportResponse)(nil), // 30: vault.v1.BeginComponentExportResponse
+	(*RetrieveKeyComponentRequest)(nil),  // 31: vault.v1.RetrieveKeyComponentRequest
+	(*RetrieveKeyComponentResponse)(nil), // 32: vault.v1.RetrieveKeyComponentResponse
+	(*KdfParams)(nil),                    // 33: vault.v1.KdfParams
+	(*DeriveSharedSecretRequest)(nil),    // 34: vault.v1.DeriveSharedSecretRequest
+	(*DeriveSharedSecretResponse)(nil),   // 35: vault.v1.DeriveSharedSecretResponse
+	(*EncapsulateRequest)(nil),           // 36: vault.v1.EncapsulateRequest
+	(*EncapsulateResponse)(nil),          // 37: vault.v1.EncapsulateResponse
+	(*DecapsulateRequest)(nil),           // 38: vault.v1.DecapsulateRequest
+	(*DecapsulateResponse)(nil),          // 39: vault.v1.DecapsulateResponse
+	(*DeriveChildKeyRequest)(nil),        // 40: vault.v1.DeriveChildKeyRequest
+	(*DeriveChildKeyResponse)(nil),       // 41: vault.v1.DeriveChildKeyResponse
+	(*GetImportParametersRequest)(nil),   // 42: vault.v1.GetImportParametersRequest
+	(*GetImportParametersResponse)(nil),  // 43: vault.v1.GetImportParametersResponse
+	(*ImportKeyRequest)(nil),             // 44: vault.v1.ImportKeyRequest
+	(*ImportKeyResponse)(nil),            // 45: vault.v1.ImportKeyResponse
+	(*ImportWrappingKeyRequest)(nil),     // 46: vault.v1.ImportWrappingKeyRequest
+	(*ImportWrappingKeyResponse)(nil),    // 47: vault.v1.ImportWrappingKeyResponse
+	(*ExportKeyRequest)(nil),             // 48: vault.v1.ExportKeyRequest
+	(*ExportKeyResponse)(nil),            // 49: vault.v1.ExportKeyResponse
+	nil,                                  // 50: vault.v1.KeyMetadata.LabelsEntry
+	nil,                                  // 51: vault.v1.GenerateKeyRequest.LabelsEntry
+	nil,                                  // 52: vault.v1.ImportKeyBlockRequest.LabelsEntry
+	nil,                                  // 53: vault.v1.BeginComponentImportRequest.LabelsEntry
+	nil,                                  // 54: vault.v1.DeriveSharedSecretRequest.LabelsEntry
+	nil,                                  // 55: vault.v1.EncapsulateRequest.LabelsEntry
+	nil,                                  // 56: vault.v1.DecapsulateRequest.LabelsEntry
+	nil,                                  // 57: vault.v1.GetImportParametersRequest.LabelsEntry
+	nil,                                  // 58: vault.v1.ImportWrappingKeyRequest.LabelsEntry
+	(*timestamppb.Timestamp)(nil),        // 59: google.protobuf.Timestamp
 }
 var file_vault_v1_keymgmt_proto_depIdxs = []int32{
 	0,  // 0: vault.v1.KeyMetadata.algorithm:type_name -> vault.v1.KeyAlgorithm
 	1,  // 1: vault.v1.KeyMetadata.status:type_name -> vault.v1.KeyStatus
-	18, // 2: vault.v1.KeyMetadata.created_at:type_name -> google.protobuf.Timestamp
-	18, // 3: vault.v1.KeyMetadata.rotated_at:type_name -> google.protobuf.Timestamp
-	16, // 4: vault.v1.KeyMetadata.labels:type_name -> vault.v1.KeyMetadata.LabelsEntry
-	0,  // 5: vault.v1.GenerateKeyRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
-	17, // 6: vault.v1.GenerateKeyRequest.labels:type_name -> vault.v1.GenerateKeyRequest.LabelsEntry
-	3,  // 7: vault.v1.GenerateKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
-	0,  // 8: vault.v1.GetPublicKeyResponse.algorithm:type_name -> vault.v1.KeyAlgorithm
-	1,  // 9: vault.v1.ListKeysRequest.status_filter:type_name -> vault.v1.KeyStatus
-	3,  // 10: vault.v1.ListKeysResponse.keys:type_name -> vault.v1.KeyMetadata
-	3,  // 11: vault.v1.RotateKeyResponse.old_key:type_name -> vault.v1.KeyMetadata
-	3,  // 12: vault.v1.RotateKeyResponse.new_key:type_name -> vault.v1.KeyMetadata
-	3,  // 13: vault.v1.DeactivateKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
-	2,  // 14: vault.v1.KeyEvent.type:type_name -> vault.v1.KeyEventType
-	3,  // 15: vault.v1.KeyEvent.metadata:type_name -> vault.v1.KeyMetadata
-	18, // 16: vault.v1.KeyEvent.timestamp:type_name -> google.protobuf.Timestamp
-	4,  // 17: vault.v1.KeyManagementService.GenerateKey:input_type -> vault.v1.GenerateKeyRequest
-	6,  // 18: vault.v1.KeyManagementService.GetPublicKey:input_type -> vault.v1.GetPublicKeyRequest
-	8,  // 19: vault.v1.KeyManagementService.ListKeys:input_type -> vault.v1.ListKeysRequest
-	10, // 20: vault.v1.KeyManagementService.RotateKey:input_type -> vault.v1.RotateKeyRequest
-	12, // 21: vault.v1.KeyManagementService.DeactivateKey:input_type -> vault.v1.DeactivateKeyRequest
-	14, // 22: vault.v1.KeyManagementService.WatchKeyEvents:input_type -> vault.v1.WatchKeyEventsRequest
-	5,  // 23: vault.v1.KeyManagementService.GenerateKey:output_type -> vault.v1.GenerateKeyResponse
-	7,  // 24: vault.v1.KeyManagementService.GetPublicKey:output_type -> vault.v1.GetPublicKeyResponse
-	9,  // 25: vault.v1.KeyManagementService.ListKeys:output_type -> vault.v1.ListKeysResponse
-	11, // 26: vault.v1.KeyManagementService.RotateKey:output_type -> vault.v1.RotateKeyResponse
-	13, // 27: vault.v1.KeyManagementService.DeactivateKey:output_type -> vault.v1.DeactivateKeyResponse
-	15, // 28: vault.v1.KeyManagementService.WatchKeyEvents:output_type -> vault.v1.KeyEvent
-	23, // [23:29] is the sub-list for method output_type
-	17, // [17:23] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	59, // 2: vault.v1.KeyMetadata.created_at:type_name -> google.protobuf.Timestamp
+	59, // 3: vault.v1.KeyMetadata.rotated_at:type_name -> google.protobuf.Timestamp
+	50, // 4: vault.v1.KeyMetadata.labels:type_name -> vault.v1.KeyMetadata.LabelsEntry
+	2,  // 5: vault.v1.KeyMetadata.purpose:type_name -> vault.v1.KeyPurpose
+	3,  // 6: vault.v1.KeyMetadata.mode_of_use:type_name -> vault.v1.KeyModeOfUse
+	4,  // 7: vault.v1.KeyMetadata.origin:type_name -> vault.v1.KeyOrigin
+	0,  // 8: vault.v1.GenerateKeyRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
+	51, // 9: vault.v1.GenerateKeyRequest.labels:type_name -> vault.v1.GenerateKeyRequest.LabelsEntry
+	2,  // 10: vault.v1.GenerateKeyRequest.purpose:type_name -> vault.v1.KeyPurpose
+	3,  // 11: vault.v1.GenerateKeyRequest.mode_of_use:type_name -> vault.v1.KeyModeOfUse
+	8,  // 12: vault.v1.GenerateKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
+	0,  // 13: vault.v1.GetPublicKeyResponse.algorithm:type_name -> vault.v1.KeyAlgorithm
+	1,  // 14: vault.v1.ListKeysRequest.status_filter:type_name -> vault.v1.KeyStatus
+	8,  // 15: vault.v1.ListKeysResponse.keys:type_name -> vault.v1.KeyMetadata
+	8,  // 16: vault.v1.RotateKeyResponse.old_key:type_name -> vault.v1.KeyMetadata
+	8,  // 17: vault.v1.RotateKeyResponse.new_key:type_name -> vault.v1.KeyMetadata
+	8,  // 18: vault.v1.DeactivateKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
+	6,  // 19: vault.v1.KeyEvent.type:type_name -> vault.v1.KeyEventType
+	8,  // 20: vault.v1.KeyEvent.metadata:type_name -> vault.v1.KeyMetadata
+	59, // 21: vault.v1.KeyEvent.timestamp:type_name -> google.protobuf.Timestamp
+	52, // 22: vault.v1.ImportKeyBlockRequest.labels:type_name -> vault.v1.ImportKeyBlockRequest.LabelsEntry
+	8,  // 23: vault.v1.ImportKeyBlockResponse.metadata:type_name -> vault.v1.KeyMetadata
+	0,  // 24: vault.v1.BeginComponentImportRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
+	2,  // 25: vault.v1.BeginComponentImportRequest.purpose:type_name -> vault.v1.KeyPurpose
+	3,  // 26: vault.v1.BeginComponentImportRequest.mode_of_use:type_name -> vault.v1.KeyModeOfUse
+	53, // 27: vault.v1.BeginComponentImportRequest.labels:type_name -> vault.v1.BeginComponentImportRequest.LabelsEntry
+	59, // 28: vault.v1.BeginComponentImportResponse.expires_at:type_name -> google.protobuf.Timestamp
+	8,  // 29: vault.v1.SubmitKeyComponentResponse.metadata:type_name -> vault.v1.KeyMetadata
+	59, // 30: vault.v1.BeginComponentExportResponse.expires_at:type_name -> google.protobuf.Timestamp
+	33, // 31: vault.v1.DeriveSharedSecretRequest.kdf_params:type_name -> vault.v1.KdfParams
+	7,  // 32: vault.v1.DeriveSharedSecretRequest.output:type_name -> vault.v1.SharedSecretOutput
+	0,  // 33: vault.v1.DeriveSharedSecretRequest.derived_key_algorithm:type_name -> vault.v1.KeyAlgorithm
+	2,  // 34: vault.v1.DeriveSharedSecretRequest.derived_key_purpose:type_name -> vault.v1.KeyPurpose
+	54, // 35: vault.v1.DeriveSharedSecretRequest.labels:type_name -> vault.v1.DeriveSharedSecretRequest.LabelsEntry
+	8,  // 36: vault.v1.DeriveSharedSecretResponse.derived_key:type_name -> vault.v1.KeyMetadata
+	0,  // 37: vault.v1.EncapsulateRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
+	33, // 38: vault.v1.EncapsulateRequest.kdf_params:type_name -> vault.v1.KdfParams
+	7,  // 39: vault.v1.EncapsulateRequest.output:type_name -> vault.v1.SharedSecretOutput
+	0,  // 40: vault.v1.EncapsulateRequest.derived_key_algorithm:type_name -> vault.v1.KeyAlgorithm
+	2,  // 41: vault.v1.EncapsulateRequest.derived_key_purpose:type_name -> vault.v1.KeyPurpose
+	55, // 42: vault.v1.EncapsulateRequest.labels:type_name -> vault.v1.EncapsulateRequest.LabelsEntry
+	8,  // 43: vault.v1.EncapsulateResponse.derived_key:type_name -> vault.v1.KeyMetadata
+	33, // 44: vault.v1.DecapsulateRequest.kdf_params:type_name -> vault.v1.KdfParams
+	7,  // 45: vault.v1.DecapsulateRequest.output:type_name -> vault.v1.SharedSecretOutput
+	0,  // 46: vault.v1.DecapsulateRequest.derived_key_algorithm:type_name -> vault.v1.KeyAlgorithm
+	2,  // 47: vault.v1.DecapsulateRequest.derived_key_purpose:type_name -> vault.v1.KeyPurpose
+	56, // 48: vault.v1.DecapsulateRequest.labels:type_name -> vault.v1.DecapsulateRequest.LabelsEntry
+	8,  // 49: vault.v1.DecapsulateResponse.derived_key:type_name -> vault.v1.KeyMetadata
+	0,  // 50: vault.v1.DeriveChildKeyRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
+	8,  // 51: vault.v1.DeriveChildKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
+	0,  // 52: vault.v1.GetImportParametersRequest.algorithm:type_name -> vault.v1.KeyAlgorithm
+	2,  // 53: vault.v1.GetImportParametersRequest.purpose:type_name -> vault.v1.KeyPurpose
+	3,  // 54: vault.v1.GetImportParametersRequest.mode_of_use:type_name -> vault.v1.KeyModeOfUse
+	57, // 55: vault.v1.GetImportParametersRequest.labels:type_name -> vault.v1.GetImportParametersRequest.LabelsEntry
+	5,  // 56: vault.v1.GetImportParametersResponse.wrapping_algorithm:type_name -> vault.v1.KeyWrapAlgorithm
+	59, // 57: vault.v1.GetImportParametersResponse.expires_at:type_name -> google.protobuf.Timestamp
+	8,  // 58: vault.v1.ImportKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
+	58, // 59: vault.v1.ImportWrappingKeyRequest.labels:type_name -> vault.v1.ImportWrappingKeyRequest.LabelsEntry
+	8,  // 60: vault.v1.ImportWrappingKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
+	5,  // 61: vault.v1.ExportKeyRequest.wrapping_algorithm:type_name -> vault.v1.KeyWrapAlgorithm
+	5,  // 62: vault.v1.ExportKeyResponse.wrapping_algorithm:type_name -> vault.v1.KeyWrapAlgorithm
+	8,  // 63: vault.v1.ExportKeyResponse.metadata:type_name -> vault.v1.KeyMetadata
+	9,  // 64: vault.v1.KeyManagementService.GenerateKey:input_type -> vault.v1.GenerateKeyRequest
+	11, // 65: vault.v1.KeyManagementService.GetPublicKey:input_type -> vault.v1.GetPublicKeyRequest
+	13, // 66: vault.v1.KeyManagementService.ListKeys:input_type -> vault.v1.ListKeysRequest
+	15, // 67: vault.v1.KeyManagementService.RotateKey:input_type -> vault.v1.RotateKeyRequest
+	17, // 68: vault.v1.KeyManagementService.DeactivateKey:input_type -> vault.v1.DeactivateKeyRequest
+	19, // 69: vault.v1.KeyManagementService.WatchKeyEvents:input_type -> vault.v1.WatchKeyEventsRequest
+	21, // 70: vault.v1.KeyManagementService.ImportKeyBlock:input_type -> vault.v1.ImportKeyBlockRequest
+	23, // 71: vault.v1.KeyManagementService.ExportKeyBlock:input_type -> vault.v1.ExportKeyBlockRequest
+	25, // 72: vault.v1.KeyManagementService.BeginComponentImport:input_type -> vault.v1.BeginComponentImportRequest
+	27, // 73: vault.v1.KeyManagementService.SubmitKeyComponent:input_type -> vault.v1.SubmitKeyComponentRequest
+	29, // 74: vault.v1.KeyManagementService.BeginComponentExport:input_type -> vault.v1.BeginComponentExportRequest
+	31, // 75: vault.v1.KeyManagementService.RetrieveKeyComponent:input_type -> vault.v1.RetrieveKeyComponentRequest
+	34, // 76: vault.v1.KeyManagementService.DeriveSharedSecret:input_type -> vault.v1.DeriveSharedSecretRequest
+	36, // 77: vault.v1.KeyManagementService.Encapsulate:input_type -> vault.v1.EncapsulateRequest
+	38, // 78: vault.v1.KeyManagementService.Decapsulate:input_type -> vault.v1.DecapsulateRequest
+	40, // 79: vault.v1.KeyManagementService.DeriveChildKey:input_type -> vault.v1.DeriveChildKeyRequest
+	42, // 80: vault.v1.KeyManagementService.GetImportParameters:input_type -> vault.v1.GetImportParametersRequest
+	44, // 81: vault.v1.KeyManagementService.ImportKey:input_type -> vault.v1.ImportKeyRequest
+	46, // 82: vault.v1.KeyManagementService.ImportWrappingKey:input_type -> vault.v1.ImportWrappingKeyRequest
+	48, // 83: vault.v1.KeyManagementService.ExportKey:input_type -> vault.v1.ExportKeyRequest
+	10, // 84: vault.v1.KeyManagementService.GenerateKey:output_type -> vault.v1.GenerateKeyResponse
+	12, // 85: vault.v1.KeyManagementService.GetPublicKey:output_type -> vault.v1.GetPublicKeyResponse
+	14, // 86: vault.v1.KeyManagementService.ListKeys:output_type -> vault.v1.ListKeysResponse
+	16, // 87: vault.v1.KeyManagementService.RotateKey:output_type -> vault.v1.RotateKeyResponse
+	18, // 88: vault.v1.KeyManagementService.DeactivateKey:output_type -> vault.v1.DeactivateKeyResponse
+	20, // 89: vault.v1.KeyManagementService.WatchKeyEvents:output_type -> vault.v1.KeyEvent
+	22, // 90: vault.v1.KeyManagementService.ImportKeyBlock:output_type -> vault.v1.ImportKeyBlockResponse
+	24, // 91: vault.v1.KeyManagementService.ExportKeyBlock:output_type -> vault.v1.ExportKeyBlockResponse
+	26, // 92: vault.v1.KeyManagementService.BeginComponentImport:output_type -> vault.v1.BeginComponentImportResponse
+	28, // 93: vault.v1.KeyManagementService.SubmitKeyComponent:output_type -> vault.v1.SubmitKeyComponentResponse
+	30, // 94: vault.v1.KeyManagementService.BeginComponentExport:output_type -> vault.v1.BeginComponentExportResponse
+	32, // 95: vault.v1.KeyManagementService.RetrieveKeyComponent:output_type -> vault.v1.RetrieveKeyComponentResponse
+	35, // 96: vault.v1.KeyManagementService.DeriveSharedSecret:output_type -> vault.v1.DeriveSharedSecretResponse
+	37, // 97: vault.v1.KeyManagementService.Encapsulate:output_type -> vault.v1.EncapsulateResponse
+	39, // 98: vault.v1.KeyManagementService.Decapsulate:output_type -> vault.v1.DecapsulateResponse
+	41, // 99: vault.v1.KeyManagementService.DeriveChildKey:output_type -> vault.v1.DeriveChildKeyResponse
+	43, // 100: vault.v1.KeyManagementService.GetImportParameters:output_type -> vault.v1.GetImportParametersResponse
+	45, // 101: vault.v1.KeyManagementService.ImportKey:output_type -> vault.v1.ImportKeyResponse
+	47, // 102: vault.v1.KeyManagementService.ImportWrappingKey:output_type -> vault.v1.ImportWrappingKeyResponse
+	49, // 103: vault.v1.KeyManagementService.ExportKey:output_type -> vault.v1.ExportKeyResponse
+	84, // [84:104] is the sub-list for method output_type
+	64, // [64:84] is the sub-list for method input_type
+	64, // [64:64] is the sub-list for extension type_name
+	64, // [64:64] is the sub-list for extension extendee
+	0,  // [0:64] is the sub-list for field type_name
 }
 
 func init() { file_vault_v1_keymgmt_proto_init() }
@@ -1034,8 +3919,8 @@ func file_vault_v1_keymgmt_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_keymgmt_proto_rawDesc), len(file_vault_v1_keymgmt_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   15,
+			NumEnums:      8,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
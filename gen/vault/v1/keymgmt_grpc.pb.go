@@ -19,12 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	KeyManagementService_GenerateKey_FullMethodName    = "/vault.v1.KeyManagementService/GenerateKey"
-	KeyManagementService_GetPublicKey_FullMethodName   = "/vault.v1.KeyManagementService/GetPublicKey"
-	KeyManagementService_ListKeys_FullMethodName       = "/vault.v1.KeyManagementService/ListKeys"
-	KeyManagementService_RotateKey_FullMethodName      = "/vault.v1.KeyManagementService/RotateKey"
-	KeyManagementService_DeactivateKey_FullMethodName  = "/vault.v1.KeyManagementService/DeactivateKey"
-	KeyManagementService_WatchKeyEvents_FullMethodName = "/vault.v1.KeyManagementService/WatchKeyEvents"
+	KeyManagementService_GenerateKey_FullMethodName          = "/vault.v1.KeyManagementService/GenerateKey"
+	KeyManagementService_GetPublicKey_FullMethodName         = "/vault.v1.KeyManagementService/GetPublicKey"
+	KeyManagementService_ListKeys_FullMethodName             = "/vault.v1.KeyManagementService/ListKeys"
+	KeyManagementService_RotateKey_FullMethodName            = "/vault.v1.KeyManagementService/RotateKey"
+	KeyManagementService_DeactivateKey_FullMethodName        = "/vault.v1.KeyManagementService/DeactivateKey"
+	KeyManagementService_WatchKeyEvents_FullMethodName       = "/vault.v1.KeyManagementService/WatchKeyEvents"
+	KeyManagementService_ImportKeyBlock_FullMethodName       = "/vault.v1.KeyManagementService/ImportKeyBlock"
+	KeyManagementService_ExportKeyBlock_FullMethodName       = "/vault.v1.KeyManagementService/ExportKeyBlock"
+	KeyManagementService_BeginComponentImport_FullMethodName = "/vault.v1.KeyManagementService/BeginComponentImport"
+	KeyManagementService_SubmitKeyComponent_FullMethodName   = "/vault.v1.KeyManagementService/SubmitKeyComponent"
+	KeyManagementService_BeginComponentExport_FullMethodName = "/vault.v1.KeyManagementService/BeginComponentExport"
+	KeyManagementService_RetrieveKeyComponent_FullMethodName = "/vault.v1.KeyManagementService/RetrieveKeyComponent"
+	KeyManagementService_DeriveSharedSecret_FullMethodName   = "/vault.v1.KeyManagementService/DeriveSharedSecret"
+	KeyManagementService_Encapsulate_FullMethodName          = "/vault.v1.KeyManagementService/Encapsulate"
+	KeyManagementService_Decapsulate_FullMethodName          = "/vault.v1.KeyManagementService/Decapsulate"
+	KeyManagementService_DeriveChildKey_FullMethodName       = "/vault.v1.KeyManagementService/DeriveChildKey"
+	KeyManagementService_GetImportParameters_FullMethodName  = "/vault.v1.KeyManagementService/GetImportParameters"
+	KeyManagementService_ImportKey_FullMethodName            = "/vault.v1.KeyManagementService/ImportKey"
+	KeyManagementService_ImportWrappingKey_FullMethodName    = "/vault.v1.KeyManagementService/ImportWrappingKey"
+	KeyManagementService_ExportKey_FullMethodName            = "/vault.v1.KeyManagementService/ExportKey"
 )
 
 // KeyManagementServiceClient is the client API for KeyManagementService service.
@@ -34,7 +48,8 @@ const (
 // KeyManagementService manages the lifecycle of cryptographic keys,
 // including generation, rotation, deactivation, and event streaming.
 type KeyManagementServiceClient interface {
-	// GenerateKey creates a new ECDSA key pair and stores it in the vault.
+	// GenerateKey creates a new ECDSA key pair or symmetric key and stores it
+	// in the vault.
 	GenerateKey(ctx context.Context, in *GenerateKeyRequest, opts ...grpc.CallOption) (*GenerateKeyResponse, error)
 	// GetPublicKey returns the DER-encoded public key for a given key ID.
 	GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error)
@@ -49,6 +64,63 @@ type KeyManagementServiceClient interface {
 	// WatchKeyEvents opens a server-side stream that emits key lifecycle
 	// events (created, rotated, deactivated) in real time.
 	WatchKeyEvents(ctx context.Context, in *WatchKeyEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[KeyEvent], error)
+	// ImportKeyBlock unwraps an ANSI X9.143 (TR-31) key block under a key
+	// block protection key held in the vault and stores the key. The header's
+	// key usage, algorithm, mode of use and exportability are mapped onto the
+	// new key's purpose, mode of use and exportable attribute.
+	ImportKeyBlock(ctx context.Context, in *ImportKeyBlockRequest, opts ...grpc.CallOption) (*ImportKeyBlockResponse, error)
+	// ExportKeyBlock wraps an exportable symmetric key in a TR-31 key block
+	// under a key block protection key held in the vault. A TDEA protection
+	// key produces a version B block, an AES protection key a version D block.
+	ExportKeyBlock(ctx context.Context, in *ExportKeyBlockRequest, opts ...grpc.CallOption) (*ExportKeyBlockResponse, error)
+	// BeginComponentImport opens a key ceremony that loads a TDEA or AES key
+	// from XOR components. The ceremony expires if not every component
+	// arrives before the timeout.
+	BeginComponentImport(ctx context.Context, in *BeginComponentImportRequest, opts ...grpc.CallOption) (*BeginComponentImportResponse, error)
+	// SubmitKeyComponent delivers one component of an import ceremony. Each
+	// component must come from a different authenticated principal and match
+	// its KCV; the last one creates the key if the combined KCV matches.
+	SubmitKeyComponent(ctx context.Context, in *SubmitKeyComponentRequest, opts ...grpc.CallOption) (*SubmitKeyComponentResponse, error)
+	// BeginComponentExport splits an exportable TDEA or AES key into XOR
+	// components to be retrieved by separate custodians before the timeout.
+	BeginComponentExport(ctx context.Context, in *BeginComponentExportRequest, opts ...grpc.CallOption) (*BeginComponentExportResponse, error)
+	// RetrieveKeyComponent hands the next component of an export ceremony to
+	// the calling principal. Each principal may retrieve only one component.
+	RetrieveKeyComponent(ctx context.Context, in *RetrieveKeyComponentRequest, opts ...grpc.CallOption) (*RetrieveKeyComponentResponse, error)
+	// DeriveSharedSecret performs ECDH between a vault-held private key and a
+	// peer public key. The shared secret is stored as a new vault key by
+	// default, or returned raw or after HKDF.
+	DeriveSharedSecret(ctx context.Context, in *DeriveSharedSecretRequest, opts ...grpc.CallOption) (*DeriveSharedSecretResponse, error)
+	// Encapsulate generates a shared secret and a KEM ciphertext for the
+	// public key of an ML-KEM vault key or a supplied encapsulation key.
+	Encapsulate(ctx context.Context, in *EncapsulateRequest, opts ...grpc.CallOption) (*EncapsulateResponse, error)
+	// Decapsulate recovers the shared secret from a KEM ciphertext with an
+	// ML-KEM vault key.
+	Decapsulate(ctx context.Context, in *DecapsulateRequest, opts ...grpc.CallOption) (*DecapsulateResponse, error)
+	// DeriveChildKey derives a signing or encryption key from a root key and
+	// a path such as "acquirer/merchant/123/terminal/9". The same root, path
+	// and algorithm always give the same key and key_id, so children are not
+	// stored: they are re-derived on use, follow the root's status and can
+	// be used by key_id like any other key.
+	DeriveChildKey(ctx context.Context, in *DeriveChildKeyRequest, opts ...grpc.CallOption) (*DeriveChildKeyResponse, error)
+	// GetImportParameters starts a key import: it returns a one-time RSA
+	// wrapping public key and the import token to pass to ImportKey, bound
+	// to the attributes of the key to import.
+	GetImportParameters(ctx context.Context, in *GetImportParametersRequest, opts ...grpc.CallOption) (*GetImportParametersResponse, error)
+	// ImportKey unwraps key material wrapped under the public key from
+	// GetImportParameters, checks it against the key's algorithm and stores
+	// it as a new key of origin KEY_ORIGIN_IMPORTED. The import token is
+	// used up by the call, whether it succeeds or not.
+	ImportKey(ctx context.Context, in *ImportKeyRequest, opts ...grpc.CallOption) (*ImportKeyResponse, error)
+	// ImportWrappingKey uploads a trusted RSA public key, such as a partner
+	// HSM's, that ExportKey can wrap keys for. It is stored as a key of an
+	// RSA algorithm that can only wrap.
+	ImportWrappingKey(ctx context.Context, in *ImportWrappingKeyRequest, opts ...grpc.CallOption) (*ImportWrappingKeyResponse, error)
+	// ExportKey returns the material of an exportable key wrapped under
+	// another vault key: an AES key encryption key or an uploaded RSA
+	// wrapping key. Key material never leaves the vault in plaintext, and
+	// ECDSA keys never leave their HSM provider.
+	ExportKey(ctx context.Context, in *ExportKeyRequest, opts ...grpc.CallOption) (*ExportKeyResponse, error)
 }
 
 type keyManagementServiceClient struct {
@@ -128,6 +200,146 @@ func (c *keyManagementServiceClient) WatchKeyEvents(ctx context.Context, in *Wat
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type KeyManagementService_WatchKeyEventsClient = grpc.ServerStreamingClient[KeyEvent]
 
+func (c *keyManagementServiceClient) ImportKeyBlock(ctx context.Context, in *ImportKeyBlockRequest, opts ...grpc.CallOption) (*ImportKeyBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportKeyBlockResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_ImportKeyBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) ExportKeyBlock(ctx context.Context, in *ExportKeyBlockRequest, opts ...grpc.CallOption) (*ExportKeyBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportKeyBlockResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_ExportKeyBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) BeginComponentImport(ctx context.Context, in *BeginComponentImportRequest, opts ...grpc.CallOption) (*BeginComponentImportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginComponentImportResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_BeginComponentImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) SubmitKeyComponent(ctx context.Context, in *SubmitKeyComponentRequest, opts ...grpc.CallOption) (*SubmitKeyComponentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitKeyComponentResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_SubmitKeyComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) BeginComponentExport(ctx context.Context, in *BeginComponentExportRequest, opts ...grpc.CallOption) (*BeginComponentExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BeginComponentExportResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_BeginComponentExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) RetrieveKeyComponent(ctx context.Context, in *RetrieveKeyComponentRequest, opts ...grpc.CallOption) (*RetrieveKeyComponentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetrieveKeyComponentResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_RetrieveKeyComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) DeriveSharedSecret(ctx context.Context, in *DeriveSharedSecretRequest, opts ...grpc.CallOption) (*DeriveSharedSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeriveSharedSecretResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_DeriveSharedSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) Encapsulate(ctx context.Context, in *EncapsulateRequest, opts ...grpc.CallOption) (*EncapsulateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EncapsulateResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_Encapsulate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) Decapsulate(ctx context.Context, in *DecapsulateRequest, opts ...grpc.CallOption) (*DecapsulateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecapsulateResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_Decapsulate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) DeriveChildKey(ctx context.Context, in *DeriveChildKeyRequest, opts ...grpc.CallOption) (*DeriveChildKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeriveChildKeyResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_DeriveChildKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) GetImportParameters(ctx context.Context, in *GetImportParametersRequest, opts ...grpc.CallOption) (*GetImportParametersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetImportParametersResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_GetImportParameters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) ImportKey(ctx context.Context, in *ImportKeyRequest, opts ...grpc.CallOption) (*ImportKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportKeyResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_ImportKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) ImportWrappingKey(ctx context.Context, in *ImportWrappingKeyRequest, opts ...grpc.CallOption) (*ImportWrappingKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportWrappingKeyResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_ImportWrappingKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagementServiceClient) ExportKey(ctx context.Context, in *ExportKeyRequest, opts ...grpc.CallOption) (*ExportKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportKeyResponse)
+	err := c.cc.Invoke(ctx, KeyManagementService_ExportKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeyManagementServiceServer is the server API for KeyManagementService service.
 // All implementations must embed UnimplementedKeyManagementServiceServer
 // for forward compatibility.
@@ -135,7 +347,8 @@ type KeyManagementService_WatchKeyEventsClient = grpc.ServerStreamingClient[KeyE
 // KeyManagementService manages the lifecycle of cryptographic keys,
 // including generation, rotation, deactivation, and event streaming.
 type KeyManagementServiceServer interface {
-	// GenerateKey creates a new ECDSA key pair and stores it in the vault.
+	// GenerateKey creates a new ECDSA key pair or symmetric key and stores it
+	// in the vault.
 	GenerateKey(context.Context, *GenerateKeyRequest) (*GenerateKeyResponse, error)
 	// GetPublicKey returns the DER-encoded public key for a given key ID.
 	GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error)
@@ -150,6 +363,63 @@ type KeyManagementServiceServer interface {
 	// WatchKeyEvents opens a server-side stream that emits key lifecycle
 	// events (created, rotated, deactivated) in real time.
 	WatchKeyEvents(*WatchKeyEventsRequest, grpc.ServerStreamingServer[KeyEvent]) error
+	// ImportKeyBlock unwraps an ANSI X9.143 (TR-31) key block under a key
+	// block protection key held in the vault and stores the key. The header's
+	// key usage, algorithm, mode of use and exportability are mapped onto the
+	// new key's purpose, mode of use and exportable attribute.
+	ImportKeyBlock(context.Context, *ImportKeyBlockRequest) (*ImportKeyBlockResponse, error)
+	// ExportKeyBlock wraps an exportable symmetric key in a TR-31 key block
+	// under a key block protection key held in the vault. A TDEA protection
+	// key produces a version B block, an AES protection key a version D block.
+	ExportKeyBlock(context.Context, *ExportKeyBlockRequest) (*ExportKeyBlockResponse, error)
+	// BeginComponentImport opens a key ceremony that loads a TDEA or AES key
+	// from XOR components. The ceremony expires if not every component
+	// arrives before the timeout.
+	BeginComponentImport(context.Context, *BeginComponentImportRequest) (*BeginComponentImportResponse, error)
+	// SubmitKeyComponent delivers one component of an import ceremony. Each
+	// component must come from a different authenticated principal and match
+	// its KCV; the last one creates the key if the combined KCV matches.
+	SubmitKeyComponent(context.Context, *SubmitKeyComponentRequest) (*SubmitKeyComponentResponse, error)
+	// BeginComponentExport splits an exportable TDEA or AES key into XOR
+	// components to be retrieved by separate custodians before the timeout.
+	BeginComponentExport(context.Context, *BeginComponentExportRequest) (*BeginComponentExportResponse, error)
+	// RetrieveKeyComponent hands the next component of an export ceremony to
+	// the calling principal. Each principal may retrieve only one component.
+	RetrieveKeyComponent(context.Context, *RetrieveKeyComponentRequest) (*RetrieveKeyComponentResponse, error)
+	// DeriveSharedSecret performs ECDH between a vault-held private key and a
+	// peer public key. The shared secret is stored as a new vault key by
+	// default, or returned raw or after HKDF.
+	DeriveSharedSecret(context.Context, *DeriveSharedSecretRequest) (*DeriveSharedSecretResponse, error)
+	// Encapsulate generates a shared secret and a KEM ciphertext for the
+	// public key of an ML-KEM vault key or a supplied encapsulation key.
+	Encapsulate(context.Context, *EncapsulateRequest) (*EncapsulateResponse, error)
+	// Decapsulate recovers the shared secret from a KEM ciphertext with an
+	// ML-KEM vault key.
+	Decapsulate(context.Context, *DecapsulateRequest) (*DecapsulateResponse, error)
+	// DeriveChildKey derives a signing or encryption key from a root key and
+	// a path such as "acquirer/merchant/123/terminal/9". The same root, path
+	// and algorithm always give the same key and key_id, so children are not
+	// stored: they are re-derived on use, follow the root's status and can
+	// be used by key_id like any other key.
+	DeriveChildKey(context.Context, *DeriveChildKeyRequest) (*DeriveChildKeyResponse, error)
+	// GetImportParameters starts a key import: it returns a one-time RSA
+	// wrapping public key and the import token to pass to ImportKey, bound
+	// to the attributes of the key to import.
+	GetImportParameters(context.Context, *GetImportParametersRequest) (*GetImportParametersResponse, error)
+	// ImportKey unwraps key material wrapped under the public key from
+	// GetImportParameters, checks it against the key's algorithm and stores
+	// it as a new key of origin KEY_ORIGIN_IMPORTED. The import token is
+	// used up by the call, whether it succeeds or not.
+	ImportKey(context.Context, *ImportKeyRequest) (*ImportKeyResponse, error)
+	// ImportWrappingKey uploads a trusted RSA public key, such as a partner
+	// HSM's, that ExportKey can wrap keys for. It is stored as a key of an
+	// RSA algorithm that can only wrap.
+	ImportWrappingKey(context.Context, *ImportWrappingKeyRequest) (*ImportWrappingKeyResponse, error)
+	// ExportKey returns the material of an exportable key wrapped under
+	// another vault key: an AES key encryption key or an uploaded RSA
+	// wrapping key. Key material never leaves the vault in plaintext, and
+	// ECDSA keys never leave their HSM provider.
+	ExportKey(context.Context, *ExportKeyRequest) (*ExportKeyResponse, error)
 	mustEmbedUnimplementedKeyManagementServiceServer()
 }
 
@@ -178,6 +448,48 @@ func (UnimplementedKeyManagementServiceServer) DeactivateKey(context.Context, *D
 func (UnimplementedKeyManagementServiceServer) WatchKeyEvents(*WatchKeyEventsRequest, grpc.ServerStreamingServer[KeyEvent]) error {
 	return status.Error(codes.Unimplemented, "method WatchKeyEvents not implemented")
 }
+func (UnimplementedKeyManagementServiceServer) ImportKeyBlock(context.Context, *ImportKeyBlockRequest) (*ImportKeyBlockResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportKeyBlock not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) ExportKeyBlock(context.Context, *ExportKeyBlockRequest) (*ExportKeyBlockResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportKeyBlock not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) BeginComponentImport(context.Context, *BeginComponentImportRequest) (*BeginComponentImportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BeginComponentImport not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) SubmitKeyComponent(context.Context, *SubmitKeyComponentRequest) (*SubmitKeyComponentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SubmitKeyComponent not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) BeginComponentExport(context.Context, *BeginComponentExportRequest) (*BeginComponentExportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BeginComponentExport not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) RetrieveKeyComponent(context.Context, *RetrieveKeyComponentRequest) (*RetrieveKeyComponentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RetrieveKeyComponent not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) DeriveSharedSecret(context.Context, *DeriveSharedSecretRequest) (*DeriveSharedSecretResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeriveSharedSecret not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) Encapsulate(context.Context, *EncapsulateRequest) (*EncapsulateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Encapsulate not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) Decapsulate(context.Context, *DecapsulateRequest) (*DecapsulateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Decapsulate not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) DeriveChildKey(context.Context, *DeriveChildKeyRequest) (*DeriveChildKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeriveChildKey not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) GetImportParameters(context.Context, *GetImportParametersRequest) (*GetImportParametersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetImportParameters not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) ImportKey(context.Context, *ImportKeyRequest) (*ImportKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportKey not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) ImportWrappingKey(context.Context, *ImportWrappingKeyRequest) (*ImportWrappingKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportWrappingKey not implemented")
+}
+func (UnimplementedKeyManagementServiceServer) ExportKey(context.Context, *ExportKeyRequest) (*ExportKeyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportKey not implemented")
+}
 func (UnimplementedKeyManagementServiceServer) mustEmbedUnimplementedKeyManagementServiceServer() {}
 func (UnimplementedKeyManagementServiceServer) testEmbeddedByValue()                              {}
 
@@ -300,6 +612,258 @@ func _KeyManagementService_WatchKeyEvents_Handler(srv interface{}, stream grpc.S
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type KeyManagementService_WatchKeyEventsServer = grpc.ServerStreamingServer[KeyEvent]
 
+func _KeyManagementService_ImportKeyBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportKeyBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).ImportKeyBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_ImportKeyBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).ImportKeyBlock(ctx, req.(*ImportKeyBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_ExportKeyBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportKeyBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).ExportKeyBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_ExportKeyBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).ExportKeyBlock(ctx, req.(*ExportKeyBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_BeginComponentImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginComponentImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).BeginComponentImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_BeginComponentImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).BeginComponentImport(ctx, req.(*BeginComponentImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_SubmitKeyComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitKeyComponentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).SubmitKeyComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_SubmitKeyComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).SubmitKeyComponent(ctx, req.(*SubmitKeyComponentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_BeginComponentExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginComponentExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).BeginComponentExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_BeginComponentExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).BeginComponentExport(ctx, req.(*BeginComponentExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_RetrieveKeyComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveKeyComponentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).RetrieveKeyComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_RetrieveKeyComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).RetrieveKeyComponent(ctx, req.(*RetrieveKeyComponentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_DeriveSharedSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeriveSharedSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).DeriveSharedSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_DeriveSharedSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).DeriveSharedSecret(ctx, req.(*DeriveSharedSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_Encapsulate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncapsulateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).Encapsulate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_Encapsulate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).Encapsulate(ctx, req.(*EncapsulateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_Decapsulate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecapsulateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).Decapsulate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_Decapsulate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).Decapsulate(ctx, req.(*DecapsulateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_DeriveChildKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeriveChildKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).DeriveChildKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_DeriveChildKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).DeriveChildKey(ctx, req.(*DeriveChildKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_GetImportParameters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImportParametersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).GetImportParameters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_GetImportParameters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).GetImportParameters(ctx, req.(*GetImportParametersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_ImportKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).ImportKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_ImportKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).ImportKey(ctx, req.(*ImportKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_ImportWrappingKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportWrappingKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).ImportWrappingKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_ImportWrappingKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).ImportWrappingKey(ctx, req.(*ImportWrappingKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManagementService_ExportKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagementServiceServer).ExportKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyManagementService_ExportKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagementServiceServer).ExportKey(ctx, req.(*ExportKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // KeyManagementService_ServiceDesc is the grpc.ServiceDesc for KeyManagementService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -327,6 +891,62 @@ var KeyManagementService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeactivateKey",
 			Handler:    _KeyManagementService_DeactivateKey_Handler,
 		},
+		{
+			MethodName: "ImportKeyBlock",
+			Handler:    _KeyManagementService_ImportKeyBlock_Handler,
+		},
+		{
+			MethodName: "ExportKeyBlock",
+			Handler:    _KeyManagementService_ExportKeyBlock_Handler,
+		},
+		{
+			MethodName: "BeginComponentImport",
+			Handler:    _KeyManagementService_BeginComponentImport_Handler,
+		},
+		{
+			MethodName: "SubmitKeyComponent",
+			Handler:    _KeyManagementService_SubmitKeyComponent_Handler,
+		},
+		{
+			MethodName: "BeginComponentExport",
+			Handler:    _KeyManagementService_BeginComponentExport_Handler,
+		},
+		{
+			MethodName: "RetrieveKeyComponent",
+			Handler:    _KeyManagementService_RetrieveKeyComponent_Handler,
+		},
+		{
+			MethodName: "DeriveSharedSecret",
+			Handler:    _KeyManagementService_DeriveSharedSecret_Handler,
+		},
+		{
+			MethodName: "Encapsulate",
+			Handler:    _KeyManagementService_Encapsulate_Handler,
+		},
+		{
+			MethodName: "Decapsulate",
+			Handler:    _KeyManagementService_Decapsulate_Handler,
+		},
+		{
+			MethodName: "DeriveChildKey",
+			Handler:    _KeyManagementService_DeriveChildKey_Handler,
+		},
+		{
+			MethodName: "GetImportParameters",
+			Handler:    _KeyManagementService_GetImportParameters_Handler,
+		},
+		{
+			MethodName: "ImportKey",
+			Handler:    _KeyManagementService_ImportKey_Handler,
+		},
+		{
+			MethodName: "ImportWrappingKey",
+			Handler:    _KeyManagementService_ImportWrappingKey_Handler,
+		},
+		{
+			MethodName: "ExportKey",
+			Handler:    _KeyManagementService_ExportKey_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
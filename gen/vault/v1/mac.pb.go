@@ -0,0 +1,621 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: vault/v1/mac.proto
+
+package vaultpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MacAlgorithm selects the MAC construction.
+type MacAlgorithm int32
+
+const (
+	// MAC_ALGORITHM_UNSPECIFIED is rejected by the server.
+	MacAlgorithm_MAC_ALGORITHM_UNSPECIFIED MacAlgorithm = 0
+	// MAC_ALGORITHM_ISO9797_ALG1 is CBC-MAC with a TDEA key.
+	MacAlgorithm_MAC_ALGORITHM_ISO9797_ALG1 MacAlgorithm = 1
+	// MAC_ALGORITHM_ISO9797_ALG3 is the ANSI X9.19 retail MAC with a
+	// double-length TDEA key.
+	MacAlgorithm_MAC_ALGORITHM_ISO9797_ALG3 MacAlgorithm = 2
+	// MAC_ALGORITHM_AES_CMAC is AES-CMAC (NIST SP 800-38B) with an AES key.
+	MacAlgorithm_MAC_ALGORITHM_AES_CMAC MacAlgorithm = 3
+	// MAC_ALGORITHM_HMAC_SHA256 is HMAC-SHA256 with an HMAC-SHA256 key.
+	MacAlgorithm_MAC_ALGORITHM_HMAC_SHA256 MacAlgorithm = 4
+	// MAC_ALGORITHM_HMAC_SHA512 is HMAC-SHA512 with an HMAC-SHA512 key.
+	MacAlgorithm_MAC_ALGORITHM_HMAC_SHA512 MacAlgorithm = 5
+)
+
+// Enum value maps for MacAlgorithm.
+var (
+	MacAlgorithm_name = map[int32]string{
+		0: "MAC_ALGORITHM_UNSPECIFIED",
+		1: "MAC_ALGORITHM_ISO9797_ALG1",
+		2: "MAC_ALGORITHM_ISO9797_ALG3",
+		3: "MAC_ALGORITHM_AES_CMAC",
+		4: "MAC_ALGORITHM_HMAC_SHA256",
+		5: "MAC_ALGORITHM_HMAC_SHA512",
+	}
+	MacAlgorithm_value = map[string]int32{
+		"MAC_ALGORITHM_UNSPECIFIED":  0,
+		"MAC_ALGORITHM_ISO9797_ALG1": 1,
+		"MAC_ALGORITHM_ISO9797_ALG3": 2,
+		"MAC_ALGORITHM_AES_CMAC":     3,
+		"MAC_ALGORITHM_HMAC_SHA256":  4,
+		"MAC_ALGORITHM_HMAC_SHA512":  5,
+	}
+)
+
+func (x MacAlgorithm) Enum() *MacAlgorithm {
+	p := new(MacAlgorithm)
+	*p = x
+	return p
+}
+
+func (x MacAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MacAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_mac_proto_enumTypes[0].Descriptor()
+}
+
+func (MacAlgorithm) Type() protoreflect.EnumType {
+	return &file_vault_v1_mac_proto_enumTypes[0]
+}
+
+func (x MacAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MacAlgorithm.Descriptor instead.
+func (MacAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{0}
+}
+
+// MacPadding selects the ISO 9797-1 padding method for the TDEA algorithms.
+// It is ignored for AES-CMAC and HMAC.
+type MacPadding int32
+
+const (
+	// MAC_PADDING_UNSPECIFIED defaults to padding method 1.
+	MacPadding_MAC_PADDING_UNSPECIFIED MacPadding = 0
+	// MAC_PADDING_ISO9797_METHOD_1 pads with zero bytes.
+	MacPadding_MAC_PADDING_ISO9797_METHOD_1 MacPadding = 1
+	// MAC_PADDING_ISO9797_METHOD_2 pads with 0x80 followed by zero bytes.
+	MacPadding_MAC_PADDING_ISO9797_METHOD_2 MacPadding = 2
+)
+
+// Enum value maps for MacPadding.
+var (
+	MacPadding_name = map[int32]string{
+		0: "MAC_PADDING_UNSPECIFIED",
+		1: "MAC_PADDING_ISO9797_METHOD_1",
+		2: "MAC_PADDING_ISO9797_METHOD_2",
+	}
+	MacPadding_value = map[string]int32{
+		"MAC_PADDING_UNSPECIFIED":      0,
+		"MAC_PADDING_ISO9797_METHOD_1": 1,
+		"MAC_PADDING_ISO9797_METHOD_2": 2,
+	}
+)
+
+func (x MacPadding) Enum() *MacPadding {
+	p := new(MacPadding)
+	*p = x
+	return p
+}
+
+func (x MacPadding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MacPadding) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_mac_proto_enumTypes[1].Descriptor()
+}
+
+func (MacPadding) Type() protoreflect.EnumType {
+	return &file_vault_v1_mac_proto_enumTypes[1]
+}
+
+func (x MacPadding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MacPadding.Descriptor instead.
+func (MacPadding) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{1}
+}
+
+// GenerateMacRequest is the request to compute a MAC.
+type GenerateMacRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the MAC key. Must be an active key.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// algorithm selects the MAC construction.
+	Algorithm MacAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.MacAlgorithm" json:"algorithm,omitempty"`
+	// padding selects the ISO 9797-1 padding method.
+	Padding MacPadding `protobuf:"varint,3,opt,name=padding,proto3,enum=vault.v1.MacPadding" json:"padding,omitempty"`
+	// data is the message to authenticate.
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// mac_length truncates the MAC to this many bytes (minimum 4).
+	// When zero, the full MAC is returned.
+	MacLength     int32 `protobuf:"varint,5,opt,name=mac_length,json=macLength,proto3" json:"mac_length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateMacRequest) Reset() {
+	*x = GenerateMacRequest{}
+	mi := &file_vault_v1_mac_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateMacRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateMacRequest) ProtoMessage() {}
+
+func (x *GenerateMacRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_mac_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateMacRequest.ProtoReflect.Descriptor instead.
+func (*GenerateMacRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateMacRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GenerateMacRequest) GetAlgorithm() MacAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return MacAlgorithm_MAC_ALGORITHM_UNSPECIFIED
+}
+
+func (x *GenerateMacRequest) GetPadding() MacPadding {
+	if x != nil {
+		return x.Padding
+	}
+	return MacPadding_MAC_PADDING_UNSPECIFIED
+}
+
+func (x *GenerateMacRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GenerateMacRequest) GetMacLength() int32 {
+	if x != nil {
+		return x.MacLength
+	}
+	return 0
+}
+
+// GenerateMacResponse contains the computed MAC.
+type GenerateMacResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mac is the (possibly truncated) MAC.
+	Mac []byte `protobuf:"bytes,1,opt,name=mac,proto3" json:"mac,omitempty"`
+	// key_id is the identifier of the key that produced the MAC.
+	KeyId         string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateMacResponse) Reset() {
+	*x = GenerateMacResponse{}
+	mi := &file_vault_v1_mac_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateMacResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateMacResponse) ProtoMessage() {}
+
+func (x *GenerateMacResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_mac_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateMacResponse.ProtoReflect.Descriptor instead.
+func (*GenerateMacResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateMacResponse) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+func (x *GenerateMacResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// VerifyMacRequest contains the data, MAC, and key to verify against.
+type VerifyMacRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the MAC key. Accepts keys in any status.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// algorithm selects the MAC construction.
+	Algorithm MacAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.MacAlgorithm" json:"algorithm,omitempty"`
+	// padding selects the ISO 9797-1 padding method.
+	Padding MacPadding `protobuf:"varint,3,opt,name=padding,proto3,enum=vault.v1.MacPadding" json:"padding,omitempty"`
+	// data is the message that was authenticated.
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// mac is the MAC to verify. Truncated MACs of at least 4 bytes are
+	// compared against the leading bytes of the computed MAC.
+	Mac           []byte `protobuf:"bytes,5,opt,name=mac,proto3" json:"mac,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMacRequest) Reset() {
+	*x = VerifyMacRequest{}
+	mi := &file_vault_v1_mac_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMacRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMacRequest) ProtoMessage() {}
+
+func (x *VerifyMacRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_mac_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMacRequest.ProtoReflect.Descriptor instead.
+func (*VerifyMacRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifyMacRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *VerifyMacRequest) GetAlgorithm() MacAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return MacAlgorithm_MAC_ALGORITHM_UNSPECIFIED
+}
+
+func (x *VerifyMacRequest) GetPadding() MacPadding {
+	if x != nil {
+		return x.Padding
+	}
+	return MacPadding_MAC_PADDING_UNSPECIFIED
+}
+
+func (x *VerifyMacRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *VerifyMacRequest) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+// VerifyMacResponse indicates whether the MAC is valid.
+type VerifyMacResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// valid is true when the MAC matches the data and key.
+	Valid         bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyMacResponse) Reset() {
+	*x = VerifyMacResponse{}
+	mi := &file_vault_v1_mac_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyMacResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyMacResponse) ProtoMessage() {}
+
+func (x *VerifyMacResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_mac_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyMacResponse.ProtoReflect.Descriptor instead.
+func (*VerifyMacResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyMacResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+// MacStreamRequest is a single message of a streaming MAC operation.
+type MacStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the MAC key. Read from the first message only.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// algorithm selects the MAC construction. Read from the first message only.
+	Algorithm MacAlgorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=vault.v1.MacAlgorithm" json:"algorithm,omitempty"`
+	// padding selects the ISO 9797-1 padding method. Read from the first
+	// message only.
+	Padding MacPadding `protobuf:"varint,3,opt,name=padding,proto3,enum=vault.v1.MacPadding" json:"padding,omitempty"`
+	// data is the next chunk of the message.
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// mac_length truncates the generated MAC. Read from the first message only.
+	MacLength int32 `protobuf:"varint,5,opt,name=mac_length,json=macLength,proto3" json:"mac_length,omitempty"`
+	// mac is the MAC to verify, used by VerifyMacStream only.
+	Mac           []byte `protobuf:"bytes,6,opt,name=mac,proto3" json:"mac,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MacStreamRequest) Reset() {
+	*x = MacStreamRequest{}
+	mi := &file_vault_v1_mac_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MacStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MacStreamRequest) ProtoMessage() {}
+
+func (x *MacStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_mac_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MacStreamRequest.ProtoReflect.Descriptor instead.
+func (*MacStreamRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_mac_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MacStreamRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *MacStreamRequest) GetAlgorithm() MacAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return MacAlgorithm_MAC_ALGORITHM_UNSPECIFIED
+}
+
+func (x *MacStreamRequest) GetPadding() MacPadding {
+	if x != nil {
+		return x.Padding
+	}
+	return MacPadding_MAC_PADDING_UNSPECIFIED
+}
+
+func (x *MacStreamRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *MacStreamRequest) GetMacLength() int32 {
+	if x != nil {
+		return x.MacLength
+	}
+	return 0
+}
+
+func (x *MacStreamRequest) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+var File_vault_v1_mac_proto protoreflect.FileDescriptor
+
+const file_vault_v1_mac_proto_rawDesc = "" +
+	"\n" +
+	"\x12vault/v1/mac.proto\x12\bvault.v1\"\xc4\x01\n" +
+	"\x12GenerateMacRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.MacAlgorithmR\talgorithm\x12.\n" +
+	"\apadding\x18\x03 \x01(\x0e2\x14.vault.v1.MacPaddingR\apadding\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\x1d\n" +
+	"\n" +
+	"mac_length\x18\x05 \x01(\x05R\tmacLength\">\n" +
+	"\x13GenerateMacResponse\x12\x10\n" +
+	"\x03mac\x18\x01 \x01(\fR\x03mac\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"\xb5\x01\n" +
+	"\x10VerifyMacRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.MacAlgorithmR\talgorithm\x12.\n" +
+	"\apadding\x18\x03 \x01(\x0e2\x14.vault.v1.MacPaddingR\apadding\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\x10\n" +
+	"\x03mac\x18\x05 \x01(\fR\x03mac\")\n" +
+	"\x11VerifyMacResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\"\xd4\x01\n" +
+	"\x10MacStreamRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x124\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x16.vault.v1.MacAlgorithmR\talgorithm\x12.\n" +
+	"\apadding\x18\x03 \x01(\x0e2\x14.vault.v1.MacPaddingR\apadding\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\x1d\n" +
+	"\n" +
+	"mac_length\x18\x05 \x01(\x05R\tmacLength\x12\x10\n" +
+	"\x03mac\x18\x06 \x01(\fR\x03mac*\xc7\x01\n" +
+	"\fMacAlgorithm\x12\x1d\n" +
+	"\x19MAC_ALGORITHM_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aMAC_ALGORITHM_ISO9797_ALG1\x10\x01\x12\x1e\n" +
+	"\x1aMAC_ALGORITHM_ISO9797_ALG3\x10\x02\x12\x1a\n" +
+	"\x16MAC_ALGORITHM_AES_CMAC\x10\x03\x12\x1d\n" +
+	"\x19MAC_ALGORITHM_HMAC_SHA256\x10\x04\x12\x1d\n" +
+	"\x19MAC_ALGORITHM_HMAC_SHA512\x10\x05*m\n" +
+	"\n" +
+	"MacPadding\x12\x1b\n" +
+	"\x17MAC_PADDING_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cMAC_PADDING_ISO9797_METHOD_1\x10\x01\x12 \n" +
+	"\x1cMAC_PADDING_ISO9797_METHOD_2\x10\x022\xbe\x02\n" +
+	"\n" +
+	"MacService\x12J\n" +
+	"\vGenerateMac\x12\x1c.vault.v1.GenerateMacRequest\x1a\x1d.vault.v1.GenerateMacResponse\x12D\n" +
+	"\tVerifyMac\x12\x1a.vault.v1.VerifyMacRequest\x1a\x1b.vault.v1.VerifyMacResponse\x12P\n" +
+	"\x11GenerateMacStream\x12\x1a.vault.v1.MacStreamRequest\x1a\x1d.vault.v1.GenerateMacResponse(\x01\x12L\n" +
+	"\x0fVerifyMacStream\x12\x1a.vault.v1.MacStreamRequest\x1a\x1b.vault.v1.VerifyMacResponse(\x01B5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+
+var (
+	file_vault_v1_mac_proto_rawDescOnce sync.Once
+	file_vault_v1_mac_proto_rawDescData []byte
+)
+
+func file_vault_v1_mac_proto_rawDescGZIP() []byte {
+	file_vault_v1_mac_proto_rawDescOnce.Do(func() {
+		file_vault_v1_mac_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vault_v1_mac_proto_rawDesc), len(file_vault_v1_mac_proto_rawDesc)))
+	})
+	return file_vault_v1_mac_proto_rawDescData
+}
+
+var file_vault_v1_mac_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_vault_v1_mac_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_vault_v1_mac_proto_goTypes = []any{
+	(MacAlgorithm)(0),           // 0: vault.v1.MacAlgorithm
+	(MacPadding)(0),             // 1: vault.v1.MacPadding
+	(*GenerateMacRequest)(nil),  // 2: vault.v1.GenerateMacRequest
+	(*GenerateMacResponse)(nil), // 3: vault.v1.GenerateMacResponse
+	(*VerifyMacRequest)(nil),    // 4: vault.v1.VerifyMacRequest
+	(*VerifyMacResponse)(nil),   // 5: vault.v1.VerifyMacResponse
+	(*MacStreamRequest)(nil),    // 6: vault.v1.MacStreamRequest
+}
+var file_vault_v1_mac_proto_depIdxs = []int32{
+	0,  // 0: vault.v1.GenerateMacRequest.algorithm:type_name -> vault.v1.MacAlgorithm
+	1,  // 1: vault.v1.GenerateMacRequest.padding:type_name -> vault.v1.MacPadding
+	0,  // 2: vault.v1.VerifyMacRequest.algorithm:type_name -> vault.v1.MacAlgorithm
+	1,  // 3: vault.v1.VerifyMacRequest.padding:type_name -> vault.v1.MacPadding
+	0,  // 4: vault.v1.MacStreamRequest.algorithm:type_name -> vault.v1.MacAlgorithm
+	1,  // 5: vault.v1.MacStreamRequest.padding:type_name -> vault.v1.MacPadding
+	2,  // 6: vault.v1.MacService.GenerateMac:input_type -> vault.v1.GenerateMacRequest
+	4,  // 7: vault.v1.MacService.VerifyMac:input_type -> vault.v1.VerifyMacRequest
+	6,  // 8: vault.v1.MacService.GenerateMacStream:input_type -> vault.v1.MacStreamRequest
+	6,  // 9: vault.v1.MacService.VerifyMacStream:input_type -> vault.v1.MacStreamRequest
+	3,  // 10: vault.v1.MacService.GenerateMac:output_type -> vault.v1.GenerateMacResponse
+	5,  // 11: vault.v1.MacService.VerifyMac:output_type -> vault.v1.VerifyMacResponse
+	3,  // 12: vault.v1.MacService.GenerateMacStream:output_type -> vault.v1.GenerateMacResponse
+	5,  // 13: vault.v1.MacService.VerifyMacStream:output_type -> vault.v1.VerifyMacResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_vault_v1_mac_proto_init() }
+func file_vault_v1_mac_proto_init() {
+	if File_vault_v1_mac_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_mac_proto_rawDesc), len(file_vault_v1_mac_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vault_v1_mac_proto_goTypes,
+		DependencyIndexes: file_vault_v1_mac_proto_depIdxs,
+		EnumInfos:         file_vault_v1_mac_proto_enumTypes,
+		MessageInfos:      file_vault_v1_mac_proto_msgTypes,
+	}.Build()
+	File_vault_v1_mac_proto = out.File
+	file_vault_v1_mac_proto_goTypes = nil
+	file_vault_v1_mac_proto_depIdxs = nil
+}
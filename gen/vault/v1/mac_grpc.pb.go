@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v6.33.4
+// source: vault/v1/mac.proto
+
+package vaultpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MacService_GenerateMac_FullMethodName       = "/vault.v1.MacService/GenerateMac"
+	MacService_VerifyMac_FullMethodName         = "/vault.v1.MacService/VerifyMac"
+	MacService_GenerateMacStream_FullMethodName = "/vault.v1.MacService/GenerateMacStream"
+	MacService_VerifyMacStream_FullMethodName   = "/vault.v1.MacService/VerifyMacStream"
+)
+
+// MacServiceClient is the client API for MacService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MacService generates and verifies message authentication codes for
+// host-to-host messages such as ISO 8583, using ISO 9797-1 retail MACs,
+// AES-CMAC, and HMAC.
+type MacServiceClient interface {
+	// GenerateMac computes a MAC over the provided data. The key must be
+	// active and its algorithm must match the requested MAC algorithm.
+	GenerateMac(ctx context.Context, in *GenerateMacRequest, opts ...grpc.CallOption) (*GenerateMacResponse, error)
+	// VerifyMac recomputes the MAC and compares it in constant time.
+	// Like Verify, this accepts keys in any status.
+	VerifyMac(ctx context.Context, in *VerifyMacRequest, opts ...grpc.CallOption) (*VerifyMacResponse, error)
+	// GenerateMacStream computes a MAC over data sent in chunks. Parameters
+	// are taken from the first message; later messages only carry data.
+	GenerateMacStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MacStreamRequest, GenerateMacResponse], error)
+	// VerifyMacStream verifies a MAC over data sent in chunks. The expected
+	// MAC may be sent in any message of the stream.
+	VerifyMacStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MacStreamRequest, VerifyMacResponse], error)
+}
+
+type macServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMacServiceClient(cc grpc.ClientConnInterface) MacServiceClient {
+	return &macServiceClient{cc}
+}
+
+func (c *macServiceClient) GenerateMac(ctx context.Context, in *GenerateMacRequest, opts ...grpc.CallOption) (*GenerateMacResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateMacResponse)
+	err := c.cc.Invoke(ctx, MacService_GenerateMac_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *macServiceClient) VerifyMac(ctx context.Context, in *VerifyMacRequest, opts ...grpc.CallOption) (*VerifyMacResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyMacResponse)
+	err := c.cc.Invoke(ctx, MacService_VerifyMac_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *macServiceClient) GenerateMacStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MacStreamRequest, GenerateMacResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MacService_ServiceDesc.Streams[0], MacService_GenerateMacStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MacStreamRequest, GenerateMacResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MacService_GenerateMacStreamClient = grpc.ClientStreamingClient[MacStreamRequest, GenerateMacResponse]
+
+func (c *macServiceClient) VerifyMacStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MacStreamRequest, VerifyMacResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MacService_ServiceDesc.Streams[1], MacService_VerifyMacStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MacStreamRequest, VerifyMacResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MacService_VerifyMacStreamClient = grpc.ClientStreamingClient[MacStreamRequest, VerifyMacResponse]
+
+// MacServiceServer is the server API for MacService service.
+// All implementations must embed UnimplementedMacServiceServer
+// for forward compatibility.
+//
+// MacService generates and verifies message authentication codes for
+// host-to-host messages such as ISO 8583, using ISO 9797-1 retail MACs,
+// AES-CMAC, and HMAC.
+type MacServiceServer interface {
+	// GenerateMac computes a MAC over the provided data. The key must be
+	// active and its algorithm must match the requested MAC algorithm.
+	GenerateMac(context.Context, *GenerateMacRequest) (*GenerateMacResponse, error)
+	// VerifyMac recomputes the MAC and compares it in constant time.
+	// Like Verify, this accepts keys in any status.
+	VerifyMac(context.Context, *VerifyMacRequest) (*VerifyMacResponse, error)
+	// GenerateMacStream computes a MAC over data sent in chunks. Parameters
+	// are taken from the first message; later messages only carry data.
+	GenerateMacStream(grpc.ClientStreamingServer[MacStreamRequest, GenerateMacResponse]) error
+	// VerifyMacStream verifies a MAC over data sent in chunks. The expected
+	// MAC may be sent in any message of the stream.
+	VerifyMacStream(grpc.ClientStreamingServer[MacStreamRequest, VerifyMacResponse]) error
+	mustEmbedUnimplementedMacServiceServer()
+}
+
+// UnimplementedMacServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMacServiceServer struct{}
+
+func (UnimplementedMacServiceServer) GenerateMac(context.Context, *GenerateMacRequest) (*GenerateMacResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateMac not implemented")
+}
+func (UnimplementedMacServiceServer) VerifyMac(context.Context, *VerifyMacRequest) (*VerifyMacResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyMac not implemented")
+}
+func (UnimplementedMacServiceServer) GenerateMacStream(grpc.ClientStreamingServer[MacStreamRequest, GenerateMacResponse]) error {
+	return status.Error(codes.Unimplemented, "method GenerateMacStream not implemented")
+}
+func (UnimplementedMacServiceServer) VerifyMacStream(grpc.ClientStreamingServer[MacStreamRequest, VerifyMacResponse]) error {
+	return status.Error(codes.Unimplemented, "method VerifyMacStream not implemented")
+}
+func (UnimplementedMacServiceServer) mustEmbedUnimplementedMacServiceServer() {}
+func (UnimplementedMacServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeMacServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MacServiceServer will
+// result in compilation errors.
+type UnsafeMacServiceServer interface {
+	mustEmbedUnimplementedMacServiceServer()
+}
+
+func RegisterMacServiceServer(s grpc.ServiceRegistrar, srv MacServiceServer) {
+	// If the following call panics, it indicates UnimplementedMacServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MacService_ServiceDesc, srv)
+}
+
+func _MacService_GenerateMac_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateMacRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MacServiceServer).GenerateMac(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MacService_GenerateMac_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MacServiceServer).GenerateMac(ctx, req.(*GenerateMacRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MacService_VerifyMac_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMacRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MacServiceServer).VerifyMac(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MacService_VerifyMac_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MacServiceServer).VerifyMac(ctx, req.(*VerifyMacRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MacService_GenerateMacStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MacServiceServer).GenerateMacStream(&grpc.GenericServerStream[MacStreamRequest, GenerateMacResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MacService_GenerateMacStreamServer = grpc.ClientStreamingServer[MacStreamRequest, GenerateMacResponse]
+
+func _MacService_VerifyMacStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MacServiceServer).VerifyMacStream(&grpc.GenericServerStream[MacStreamRequest, VerifyMacResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MacService_VerifyMacStreamServer = grpc.ClientStreamingServer[MacStreamRequest, VerifyMacResponse]
+
+// MacService_ServiceDesc is the grpc.ServiceDesc for MacService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MacService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vault.v1.MacService",
+	HandlerType: (*MacServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateMac",
+			Handler:    _MacService_GenerateMac_Handler,
+		},
+		{
+			MethodName: "VerifyMac",
+			Handler:    _MacService_VerifyMac_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateMacStream",
+			Handler:       _MacService_GenerateMacStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "VerifyMacStream",
+			Handler:       _MacService_VerifyMacStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "vault/v1/mac.proto",
+}
@@ -246,7 +246,7 @@ type BatchSignRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// key_id identifies the signing key. Must be an active key.
 	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
-	// data is the list of payloads to sign.
+	// data is the list of payloads to sign, at most 1024 of them.
 	Data          [][]byte `protobuf:"bytes,2,rep,name=data,proto3" json:"data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
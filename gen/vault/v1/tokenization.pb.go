@@ -0,0 +1,465 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: vault/v1/tokenization.proto
+
+package vaultpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TokenMethod selects how tokens are generated.
+type TokenMethod int32
+
+const (
+	// TOKEN_METHOD_UNSPECIFIED defaults to random tokens.
+	TokenMethod_TOKEN_METHOD_UNSPECIFIED TokenMethod = 0
+	// TOKEN_METHOD_RANDOM draws tokens at random. Requires an AES_256 key,
+	// which encrypts the token table.
+	TokenMethod_TOKEN_METHOD_RANDOM TokenMethod = 1
+	// TOKEN_METHOD_FPE derives tokens with FF1, using the domain as tweak.
+	// Requires an FPE_AES_256 key.
+	TokenMethod_TOKEN_METHOD_FPE TokenMethod = 2
+)
+
+// Enum value maps for TokenMethod.
+var (
+	TokenMethod_name = map[int32]string{
+		0: "TOKEN_METHOD_UNSPECIFIED",
+		1: "TOKEN_METHOD_RANDOM",
+		2: "TOKEN_METHOD_FPE",
+	}
+	TokenMethod_value = map[string]int32{
+		"TOKEN_METHOD_UNSPECIFIED": 0,
+		"TOKEN_METHOD_RANDOM":      1,
+		"TOKEN_METHOD_FPE":         2,
+	}
+)
+
+func (x TokenMethod) Enum() *TokenMethod {
+	p := new(TokenMethod)
+	*p = x
+	return p
+}
+
+func (x TokenMethod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TokenMethod) Descriptor() protoreflect.EnumDescriptor {
+	return file_vault_v1_tokenization_proto_enumTypes[0].Descriptor()
+}
+
+func (TokenMethod) Type() protoreflect.EnumType {
+	return &file_vault_v1_tokenization_proto_enumTypes[0]
+}
+
+func (x TokenMethod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TokenMethod.Descriptor instead.
+func (TokenMethod) EnumDescriptor() ([]byte, []int) {
+	return file_vault_v1_tokenization_proto_rawDescGZIP(), []int{0}
+}
+
+// TokenFormat constrains the shape of issued tokens. Tokens always have the
+// same length as the PAN and contain only digits.
+type TokenFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// luhn_valid makes tokens pass the Luhn check. FPE tokens additionally
+	// require a Luhn-valid PAN.
+	LuhnValid bool `protobuf:"varint,1,opt,name=luhn_valid,json=luhnValid,proto3" json:"luhn_valid,omitempty"`
+	// preserve_last_four keeps the last four PAN digits in the token.
+	PreserveLastFour bool `protobuf:"varint,2,opt,name=preserve_last_four,json=preserveLastFour,proto3" json:"preserve_last_four,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TokenFormat) Reset() {
+	*x = TokenFormat{}
+	mi := &file_vault_v1_tokenization_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenFormat) ProtoMessage() {}
+
+func (x *TokenFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_tokenization_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenFormat.ProtoReflect.Descriptor instead.
+func (*TokenFormat) Descriptor() ([]byte, []int) {
+	return file_vault_v1_tokenization_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TokenFormat) GetLuhnValid() bool {
+	if x != nil {
+		return x.LuhnValid
+	}
+	return false
+}
+
+func (x *TokenFormat) GetPreserveLastFour() bool {
+	if x != nil {
+		return x.PreserveLastFour
+	}
+	return false
+}
+
+// TokenizeRequest is the request to tokenize a PAN.
+type TokenizeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key_id identifies the key protecting the domain's tokens.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// domain scopes tokens; the same PAN gets a different token per domain.
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// pan is the 12-19 digit primary account number.
+	Pan string `protobuf:"bytes,3,opt,name=pan,proto3" json:"pan,omitempty"`
+	// method selects random or FPE-derived tokens.
+	Method TokenMethod `protobuf:"varint,4,opt,name=method,proto3,enum=vault.v1.TokenMethod" json:"method,omitempty"`
+	// format constrains the shape of the token.
+	Format        *TokenFormat `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenizeRequest) Reset() {
+	*x = TokenizeRequest{}
+	mi := &file_vault_v1_tokenization_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenizeRequest) ProtoMessage() {}
+
+func (x *TokenizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_tokenization_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenizeRequest.ProtoReflect.Descriptor instead.
+func (*TokenizeRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_tokenization_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TokenizeRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *TokenizeRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *TokenizeRequest) GetPan() string {
+	if x != nil {
+		return x.Pan
+	}
+	return ""
+}
+
+func (x *TokenizeRequest) GetMethod() TokenMethod {
+	if x != nil {
+		return x.Method
+	}
+	return TokenMethod_TOKEN_METHOD_UNSPECIFIED
+}
+
+func (x *TokenizeRequest) GetFormat() *TokenFormat {
+	if x != nil {
+		return x.Format
+	}
+	return nil
+}
+
+// TokenizeResponse contains the token.
+type TokenizeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// token is the surrogate value for the PAN.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// key_id is the identifier of the key protecting the token.
+	KeyId         string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenizeResponse) Reset() {
+	*x = TokenizeResponse{}
+	mi := &file_vault_v1_tokenization_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenizeResponse) ProtoMessage() {}
+
+func (x *TokenizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_tokenization_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenizeResponse.ProtoReflect.Descriptor instead.
+func (*TokenizeResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_tokenization_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TokenizeResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *TokenizeResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// DetokenizeRequest is the request to recover a PAN.
+type DetokenizeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// domain is the domain the token was issued in.
+	Domain string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// token is the surrogate value returned by Tokenize.
+	Token         string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetokenizeRequest) Reset() {
+	*x = DetokenizeRequest{}
+	mi := &file_vault_v1_tokenization_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetokenizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetokenizeRequest) ProtoMessage() {}
+
+func (x *DetokenizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_tokenization_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetokenizeRequest.ProtoReflect.Descriptor instead.
+func (*DetokenizeRequest) Descriptor() ([]byte, []int) {
+	return file_vault_v1_tokenization_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DetokenizeRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DetokenizeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// DetokenizeResponse contains the recovered PAN.
+type DetokenizeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pan is the primary account number.
+	Pan           string `protobuf:"bytes,1,opt,name=pan,proto3" json:"pan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetokenizeResponse) Reset() {
+	*x = DetokenizeResponse{}
+	mi := &file_vault_v1_tokenization_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetokenizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetokenizeResponse) ProtoMessage() {}
+
+func (x *DetokenizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vault_v1_tokenization_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetokenizeResponse.ProtoReflect.Descriptor instead.
+func (*DetokenizeResponse) Descriptor() ([]byte, []int) {
+	return file_vault_v1_tokenization_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DetokenizeResponse) GetPan() string {
+	if x != nil {
+		return x.Pan
+	}
+	return ""
+}
+
+var File_vault_v1_tokenization_proto protoreflect.FileDescriptor
+
+const file_vault_v1_tokenization_proto_rawDesc = "" +
+	"\n" +
+	"\x1bvault/v1/tokenization.proto\x12\bvault.v1\"Z\n" +
+	"\vTokenFormat\x12\x1d\n" +
+	"\n" +
+	"luhn_valid\x18\x01 \x01(\bR\tluhnValid\x12,\n" +
+	"\x12preserve_last_four\x18\x02 \x01(\bR\x10preserveLastFour\"\xb0\x01\n" +
+	"\x0fTokenizeRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x10\n" +
+	"\x03pan\x18\x03 \x01(\tR\x03pan\x12-\n" +
+	"\x06method\x18\x04 \x01(\x0e2\x15.vault.v1.TokenMethodR\x06method\x12-\n" +
+	"\x06format\x18\x05 \x01(\v2\x15.vault.v1.TokenFormatR\x06format\"?\n" +
+	"\x10TokenizeResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"A\n" +
+	"\x11DetokenizeRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"&\n" +
+	"\x12DetokenizeResponse\x12\x10\n" +
+	"\x03pan\x18\x01 \x01(\tR\x03pan*Z\n" +
+	"\vTokenMethod\x12\x1c\n" +
+	"\x18TOKEN_METHOD_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13TOKEN_METHOD_RANDOM\x10\x01\x12\x14\n" +
+	"\x10TOKEN_METHOD_FPE\x10\x022\xa1\x01\n" +
+	"\x13TokenizationService\x12A\n" +
+	"\bTokenize\x12\x19.vault.v1.TokenizeRequest\x1a\x1a.vault.v1.TokenizeResponse\x12G\n" +
+	"\n" +
+	"Detokenize\x12\x1b.vault.v1.DetokenizeRequest\x1a\x1c.vault.v1.DetokenizeResponseB5Z3github.com/glinharesb/vault-go/gen/vault/v1;vaultpbb\x06proto3"
+
+var (
+	file_vault_v1_tokenization_proto_rawDescOnce sync.Once
+	file_vault_v1_tokenization_proto_rawDescData []byte
+)
+
+func file_vault_v1_tokenization_proto_rawDescGZIP() []byte {
+	file_vault_v1_tokenization_proto_rawDescOnce.Do(func() {
+		file_vault_v1_tokenization_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vault_v1_tokenization_proto_rawDesc), len(file_vault_v1_tokenization_proto_rawDesc)))
+	})
+	return file_vault_v1_tokenization_proto_rawDescData
+}
+
+var file_vault_v1_tokenization_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_vault_v1_tokenization_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_vault_v1_tokenization_proto_goTypes = []any{
+	(TokenMethod)(0),           // 0: vault.v1.TokenMethod
+	(*TokenFormat)(nil),        // 1: vault.v1.TokenFormat
+	(*TokenizeRequest)(nil),    // 2: vault.v1.TokenizeRequest
+	(*TokenizeResponse)(nil),   // 3: vault.v1.TokenizeResponse
+	(*DetokenizeRequest)(nil),  // 4: vault.v1.DetokenizeRequest
+	(*DetokenizeResponse)(nil), // 5: vault.v1.DetokenizeResponse
+}
+var file_vault_v1_tokenization_proto_depIdxs = []int32{
+	0, // 0: vault.v1.TokenizeRequest.method:type_name -> vault.v1.TokenMethod
+	1, // 1: vault.v1.TokenizeRequest.format:type_name -> vault.v1.TokenFormat
+	2, // 2: vault.v1.TokenizationService.Tokenize:input_type -> vault.v1.TokenizeRequest
+	4, // 3: vault.v1.TokenizationService.Detokenize:input_type -> vault.v1.DetokenizeRequest
+	3, // 4: vault.v1.TokenizationService.Tokenize:output_type -> vault.v1.TokenizeResponse
+	5, // 5: vault.v1.TokenizationService.Detokenize:output_type -> vault.v1.DetokenizeResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_vault_v1_tokenization_proto_init() }
+func file_vault_v1_tokenization_proto_init() {
+	if File_vault_v1_tokenization_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vault_v1_tokenization_proto_rawDesc), len(file_vault_v1_tokenization_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vault_v1_tokenization_proto_goTypes,
+		DependencyIndexes: file_vault_v1_tokenization_proto_depIdxs,
+		EnumInfos:         file_vault_v1_tokenization_proto_enumTypes,
+		MessageInfos:      file_vault_v1_tokenization_proto_msgTypes,
+	}.Build()
+	File_vault_v1_tokenization_proto = out.File
+	file_vault_v1_tokenization_proto_goTypes = nil
+	file_vault_v1_tokenization_proto_depIdxs = nil
+}
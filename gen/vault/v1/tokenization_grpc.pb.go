@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v6.33.4
+// source: vault/v1/tokenization.proto
+
+package vaultpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TokenizationService_Tokenize_FullMethodName   = "/vault.v1.TokenizationService/Tokenize"
+	TokenizationService_Detokenize_FullMethodName = "/vault.v1.TokenizationService/Detokenize"
+)
+
+// TokenizationServiceClient is the client API for TokenizationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TokenizationService replaces PANs with surrogate tokens. PANs are kept
+// encrypted in a token table; the same PAN always yields the same token
+// within a domain.
+type TokenizationServiceClient interface {
+	// Tokenize returns the token for a PAN in a domain, issuing one if the PAN
+	// has not been tokenized there yet.
+	Tokenize(ctx context.Context, in *TokenizeRequest, opts ...grpc.CallOption) (*TokenizeResponse, error)
+	// Detokenize returns the PAN for a token. The caller must hold the
+	// "detokenize" permission.
+	Detokenize(ctx context.Context, in *DetokenizeRequest, opts ...grpc.CallOption) (*DetokenizeResponse, error)
+}
+
+type tokenizationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTokenizationServiceClient(cc grpc.ClientConnInterface) TokenizationServiceClient {
+	return &tokenizationServiceClient{cc}
+}
+
+func (c *tokenizationServiceClient) Tokenize(ctx context.Context, in *TokenizeRequest, opts ...grpc.CallOption) (*TokenizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenizeResponse)
+	err := c.cc.Invoke(ctx, TokenizationService_Tokenize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenizationServiceClient) Detokenize(ctx context.Context, in *DetokenizeRequest, opts ...grpc.CallOption) (*DetokenizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DetokenizeResponse)
+	err := c.cc.Invoke(ctx, TokenizationService_Detokenize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TokenizationServiceServer is the server API for TokenizationService service.
+// All implementations must embed UnimplementedTokenizationServiceServer
+// for forward compatibility.
+//
+// TokenizationService replaces PANs with surrogate tokens. PANs are kept
+// encrypted in a token table; the same PAN always yields the same token
+// within a domain.
+type TokenizationServiceServer interface {
+	// Tokenize returns the token for a PAN in a domain, issuing one if the PAN
+	// has not been tokenized there yet.
+	Tokenize(context.Context, *TokenizeRequest) (*TokenizeResponse, error)
+	// Detokenize returns the PAN for a token. The caller must hold the
+	// "detokenize" permission.
+	Detokenize(context.Context, *DetokenizeRequest) (*DetokenizeResponse, error)
+	mustEmbedUnimplementedTokenizationServiceServer()
+}
+
+// UnimplementedTokenizationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTokenizationServiceServer struct{}
+
+func (UnimplementedTokenizationServiceServer) Tokenize(context.Context, *TokenizeRequest) (*TokenizeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Tokenize not implemented")
+}
+func (UnimplementedTokenizationServiceServer) Detokenize(context.Context, *DetokenizeRequest) (*DetokenizeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Detokenize not implemented")
+}
+func (UnimplementedTokenizationServiceServer) mustEmbedUnimplementedTokenizationServiceServer() {}
+func (UnimplementedTokenizationServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeTokenizationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TokenizationServiceServer will
+// result in compilation errors.
+type UnsafeTokenizationServiceServer interface {
+	mustEmbedUnimplementedTokenizationServiceServer()
+}
+
+func RegisterTokenizationServiceServer(s grpc.ServiceRegistrar, srv TokenizationServiceServer) {
+	// If the following call panics, it indicates UnimplementedTokenizationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TokenizationService_ServiceDesc, srv)
+}
+
+func _TokenizationService_Tokenize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenizationServiceServer).Tokenize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenizationService_Tokenize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenizationServiceServer).Tokenize(ctx, req.(*TokenizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenizationService_Detokenize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetokenizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenizationServiceServer).Detokenize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenizationService_Detokenize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenizationServiceServer).Detokenize(ctx, req.(*DetokenizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TokenizationService_ServiceDesc is the grpc.ServiceDesc for TokenizationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TokenizationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vault.v1.TokenizationService",
+	HandlerType: (*TokenizationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Tokenize",
+			Handler:    _TokenizationService_Tokenize_Handler,
+		},
+		{
+			MethodName: "Detokenize",
+			Handler:    _TokenizationService_Detokenize_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vault/v1/tokenization.proto",
+}
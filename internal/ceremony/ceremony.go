@@ -0,0 +1,197 @@
+// Package ceremony tracks key component ceremonies: keys loaded from, or
+// handed out as, XOR components held by separate custodians. Pending state
+// is kept in memory only and wiped when a ceremony completes or expires.
+package ceremony
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+var (
+	// ErrNotFound is returned for unknown, completed or expired ceremonies.
+	ErrNotFound = errors.New("ceremony not found or expired")
+	// ErrCustodian is returned when a custodian handles a second component.
+	ErrCustodian = errors.New("custodian already handled a component of this ceremony")
+	// ErrKind is returned when an import call targets an export ceremony or
+	// vice versa.
+	ErrKind = errors.New("wrong ceremony kind")
+)
+
+// Kind distinguishes loading a key from components and splitting one.
+type Kind int
+
+const (
+	KindImport Kind = iota + 1
+	KindExport
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindImport:
+		return "IMPORT"
+	case KindExport:
+		return "EXPORT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Ceremony is the pending state of a component import or export.
+type Ceremony struct {
+	ID   string
+	Kind Kind
+	// Key is the template of the key being loaded for imports (no secret),
+	// or the key being split for exports.
+	Key *keystore.KeyEntry
+	// Count is the number of components.
+	Count int
+	// KCV is the check value of the combined key.
+	KCV []byte
+	// Components holds the components received so far (imports) or not yet
+	// handed out (exports).
+	Components [][]byte
+	// Custodians lists the principals that submitted or retrieved a
+	// component, in order.
+	Custodians []string
+	ExpiresAt  time.Time
+
+	timer *time.Timer
+}
+
+// Wipe zeroes and drops the ceremony's components.
+func (c *Ceremony) Wipe() {
+	for _, comp := range c.Components {
+		clear(comp)
+	}
+	c.Components = nil
+}
+
+// Manager holds pending ceremonies until they complete or time out.
+type Manager struct {
+	mu         sync.Mutex
+	ceremonies map[string]*Ceremony
+	onExpire   func(*Ceremony)
+}
+
+// NewManager returns a manager that calls onExpire, after wiping the
+// components, for every ceremony that times out.
+func NewManager(onExpire func(*Ceremony)) *Manager {
+	return &Manager{
+		ceremonies: make(map[string]*Ceremony),
+		onExpire:   onExpire,
+	}
+}
+
+// Begin assigns c an ID and registers it until ttl elapses.
+func (m *Manager) Begin(c *Ceremony, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c.ID = uuid.NewString()
+	c.ExpiresAt = time.Now().Add(ttl)
+	c.timer = time.AfterFunc(ttl, func() { m.expire(c.ID) })
+	m.ceremonies[c.ID] = c
+}
+
+// Get returns a pending ceremony.
+func (m *Manager) Get(id string) (*Ceremony, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.ceremonies[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return c, nil
+}
+
+// Submit records an import component from custodian. When the last
+// component arrives the ceremony is removed and returned with complete set;
+// the caller combines the components and must Wipe the ceremony.
+func (m *Manager) Submit(id, custodian string, component []byte) (c *Ceremony, complete bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err = m.pending(id, KindImport, custodian)
+	if err != nil {
+		return nil, false, err
+	}
+	c.Components = append(c.Components, append([]byte(nil), component...))
+	c.Custodians = append(c.Custodians, custodian)
+	if len(c.Components) < c.Count {
+		return c, false, nil
+	}
+	m.remove(c)
+	return c, true, nil
+}
+
+// Take hands the next export component to custodian, returning its 1-based
+// index and the number still to be taken. The ceremony is removed once every
+// component has been taken.
+func (m *Manager) Take(id, custodian string) (component []byte, index, remaining int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, err := m.pending(id, KindExport, custodian)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	component = c.Components[0]
+	c.Components = c.Components[1:]
+	c.Custodians = append(c.Custodians, custodian)
+	if len(c.Components) == 0 {
+		m.remove(c)
+	}
+	return component, len(c.Custodians), len(c.Components), nil
+}
+
+// Abort removes a ceremony and wipes its components.
+func (m *Manager) Abort(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if c, ok := m.ceremonies[id]; ok {
+		m.remove(c)
+		c.Wipe()
+	}
+}
+
+func (m *Manager) pending(id string, kind Kind, custodian string) (*Ceremony, error) {
+	c, ok := m.ceremonies[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if c.Kind != kind {
+		return nil, ErrKind
+	}
+	if slices.Contains(c.Custodians, custodian) {
+		return nil, ErrCustodian
+	}
+	return c, nil
+}
+
+func (m *Manager) remove(c *Ceremony) {
+	c.timer.Stop()
+	delete(m.ceremonies, c.ID)
+}
+
+func (m *Manager) expire(id string) {
+	m.mu.Lock()
+	c, ok := m.ceremonies[id]
+	if ok {
+		delete(m.ceremonies, id)
+		c.Wipe()
+	}
+	m.mu.Unlock()
+
+	if ok && m.onExpire != nil {
+		m.onExpire(c)
+	}
+}
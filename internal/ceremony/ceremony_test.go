@@ -0,0 +1,79 @@
+package ceremony
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+func TestSubmitRequiresDistinctCustodians(t *testing.T) {
+	m := NewManager(nil)
+	c := &Ceremony{Kind: KindImport, Key: &keystore.KeyEntry{}, Count: 2}
+	m.Begin(c, time.Minute)
+
+	if _, complete, err := m.Submit(c.ID, "alice", []byte{1}); err != nil || complete {
+		t.Fatalf("first component: complete=%v err=%v", complete, err)
+	}
+	if _, _, err := m.Submit(c.ID, "alice", []byte{2}); !errors.Is(err, ErrCustodian) {
+		t.Fatalf("expected ErrCustodian, got %v", err)
+	}
+	got, complete, err := m.Submit(c.ID, "bob", []byte{2})
+	if err != nil || !complete || len(got.Components) != 2 {
+		t.Fatalf("second component: complete=%v err=%v", complete, err)
+	}
+	if _, err := m.Get(c.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("completed ceremony should be removed, got %v", err)
+	}
+}
+
+func TestTakeHandsOutEachComponentOnce(t *testing.T) {
+	m := NewManager(nil)
+	c := &Ceremony{Kind: KindExport, Count: 2, Components: [][]byte{{1}, {2}}}
+	m.Begin(c, time.Minute)
+
+	if _, _, err := m.Submit(c.ID, "alice", []byte{1}); !errors.Is(err, ErrKind) {
+		t.Fatalf("expected ErrKind, got %v", err)
+	}
+	first, index, remaining, err := m.Take(c.ID, "alice")
+	if err != nil || index != 1 || remaining != 1 || first[0] != 1 {
+		t.Fatalf("first take: %v %d %d %v", first, index, remaining, err)
+	}
+	second, index, remaining, err := m.Take(c.ID, "bob")
+	if err != nil || index != 2 || remaining != 0 || second[0] != 2 {
+		t.Fatalf("second take: %v %d %d %v", second, index, remaining, err)
+	}
+	if _, _, _, err := m.Take(c.ID, "carol"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestExpiryWipesComponents(t *testing.T) {
+	expired := make(chan *Ceremony, 1)
+	m := NewManager(func(c *Ceremony) { expired <- c })
+	component := []byte{0xAA, 0xBB}
+	c := &Ceremony{Kind: KindImport, Key: &keystore.KeyEntry{}, Count: 2}
+	m.Begin(c, 20*time.Millisecond)
+	if _, _, err := m.Submit(c.ID, "alice", component); err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Lock()
+	stored := c.Components[0]
+	m.mu.Unlock()
+
+	select {
+	case got := <-expired:
+		if got.ID != c.ID || got.Components != nil {
+			t.Fatalf("unexpected expired ceremony: %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ceremony did not expire")
+	}
+	if stored[0] != 0 || stored[1] != 0 {
+		t.Fatalf("component not zeroed: %x", stored)
+	}
+	if _, err := m.Get(c.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -1,18 +1,93 @@
 package config
 
 import (
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	GRPCAddr      string
-	TLSCert       string
-	TLSKey        string
-	AuthToken     string
-	AuditBuffer   int
-	RateLimitRPS  int
-	DataDir       string
+	GRPCAddr     string
+	TLSCert      string
+	TLSKey       string
+	AuthToken    string
+	AuditBuffer  int
+	RateLimitRPS int
+	DataDir      string
+	Principals   []Principal
+	// MaxBatchSignSize is the most payloads a BatchSign request may hold.
+	MaxBatchSignSize int
+
+	// HostCmdAddr enables the payShield host command listener when set.
+	// An address without a host binds to loopback.
+	HostCmdAddr      string
+	HostCmdHeaderLen int
+	// HostCmdTLSCert and HostCmdTLSKey are the listener's certificate;
+	// clients must present a certificate issued by HostCmdClientCA. All
+	// three are required when HostCmdAddr is set.
+	HostCmdTLSCert  string
+	HostCmdTLSKey   string
+	HostCmdClientCA string
+	// EncryptionWarnAt is the per-key encryption count at which keys with
+	// random 96-bit nonces are reported as nearing their 2^32 limit.
+	EncryptionWarnAt uint64
+	// EncryptionLimitAction is "rotate" or "refuse": what happens when a
+	// key reaches the limit.
+	EncryptionLimitAction string
+	// HSMProviders are the HSM providers keys can be held in.
+	HSMProviders []HSMProvider
+	// HSMDefault names the provider keys are generated in when a request
+	// names none; empty selects the first configured.
+	HSMDefault string
+	// HSMProbeInterval is how often providers that can check their own
+	// health are probed.
+	HSMProbeInterval time.Duration
+	// HSMBreakerThreshold is the number of consecutive failures after
+	// which calls to a provider fail fast, for HSMBreakerCooldown.
+	HSMBreakerThreshold int
+	HSMBreakerCooldown  time.Duration
+	// MetricsAddr serves /debug/vars, including HSM provider health, when
+	// set.
+	MetricsAddr string
+}
+
+// HSMProvider is an HSM provider configured by name, as
+// VAULT_HSM_PROVIDERS="name:type,..." with type "software", "pkcs11" or
+// "plugin". A pkcs11 provider named NAME reads its token from
+// VAULT_HSM_NAME_MODULE, VAULT_HSM_NAME_TOKEN, VAULT_HSM_NAME_PIN and
+// VAULT_HSM_NAME_SESSIONS; a plugin provider reads its Unix socket from
+// VAULT_HSM_NAME_SOCKET and its per-call timeout from
+// VAULT_HSM_NAME_TIMEOUT. VAULT_HSM_NAME_FAILOVER names another provider,
+// of the same type and holding replicated keys, that takes NAME's calls
+// while NAME is unavailable. A software provider with
+// VAULT_HSM_NAME_KEYSTORE set keeps its keys in that sealed keystore file,
+// under the hex AES-256 key VAULT_HSM_NAME_MASTER_KEY.
+// VAULT_HSM_NAME_FAULTS=true lets faults be
+// injected into NAME at runtime through HSMAdminService, for resilience
+// testing.
+type HSMProvider struct {
+	Name       string
+	Type       string
+	Module     string
+	TokenLabel string
+	PIN        string
+	Sessions   int
+	Socket     string
+	Timeout    time.Duration
+	Failover   string
+	Faults     bool
+	Keystore   string
+	MasterKey  string
+}
+
+// Principal is an additional bearer token with a name and permissions,
+// configured as VAULT_PRINCIPALS="name:token:perm|perm,...".
+type Principal struct {
+	Name        string
+	Token       string
+	Permissions []string
 }
 
 func Load() Config {
@@ -24,7 +99,78 @@ func Load() Config {
 		AuditBuffer:  envInt("VAULT_AUDIT_BUFFER", 1024),
 		RateLimitRPS: envInt("VAULT_RATE_LIMIT_RPS", 100),
 		DataDir:      envOr("VAULT_DATA_DIR", ""),
+		Principals:   parsePrincipals(os.Getenv("VAULT_PRINCIPALS")),
+
+		MaxBatchSignSize: envInt("VAULT_MAX_BATCH_SIGN_SIZE", 1024),
+
+		HostCmdAddr:      os.Getenv("VAULT_HOSTCMD_ADDR"),
+		HostCmdHeaderLen: envInt("VAULT_HOSTCMD_HEADER_LEN", 4),
+		HostCmdTLSCert:   os.Getenv("VAULT_HOSTCMD_TLS_CERT"),
+		HostCmdTLSKey:    os.Getenv("VAULT_HOSTCMD_TLS_KEY"),
+		HostCmdClientCA:  os.Getenv("VAULT_HOSTCMD_CLIENT_CA"),
+
+		EncryptionWarnAt:      uint64(envInt("VAULT_ENCRYPTION_WARN_AT", 1<<31)),
+		EncryptionLimitAction: envOr("VAULT_ENCRYPTION_LIMIT_ACTION", "rotate"),
+
+		HSMProviders: parseHSMProviders(envOr("VAULT_HSM_PROVIDERS", "software:software")),
+		HSMDefault:   os.Getenv("VAULT_HSM_DEFAULT"),
+
+		HSMProbeInterval:    envDuration("VAULT_HSM_PROBE_INTERVAL", 10*time.Second),
+		HSMBreakerThreshold: envInt("VAULT_HSM_BREAKER_THRESHOLD", 3),
+		HSMBreakerCooldown:  envDuration("VAULT_HSM_BREAKER_COOLDOWN", 10*time.Second),
+
+		MetricsAddr: os.Getenv("VAULT_METRICS_ADDR"),
+	}
+}
+
+func parseHSMProviders(v string) []HSMProvider {
+	var providers []HSMProvider
+	for _, spec := range strings.Split(v, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		name, typ, ok := strings.Cut(spec, ":")
+		if !ok || name == "" || typ == "" {
+			slog.Warn("ignoring malformed hsm provider", "spec", spec)
+			continue
+		}
+		prefix := "VAULT_HSM_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		providers = append(providers, HSMProvider{
+			Name:       name,
+			Type:       typ,
+			Module:     os.Getenv(prefix + "MODULE"),
+			TokenLabel: os.Getenv(prefix + "TOKEN"),
+			PIN:        os.Getenv(prefix + "PIN"),
+			Sessions:   envInt(prefix+"SESSIONS", 0),
+			Socket:     os.Getenv(prefix + "SOCKET"),
+			Timeout:    envDuration(prefix+"TIMEOUT", 0),
+			Failover:   os.Getenv(prefix + "FAILOVER"),
+			Faults:     os.Getenv(prefix+"FAULTS") == "true",
+			Keystore:   os.Getenv(prefix + "KEYSTORE"),
+			MasterKey:  os.Getenv(prefix + "MASTER_KEY"),
+		})
+	}
+	return providers
+}
+
+func parsePrincipals(v string) []Principal {
+	var principals []Principal
+	for _, spec := range strings.Split(v, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			slog.Warn("ignoring malformed principal", "name", parts[0])
+			continue
+		}
+		p := Principal{Name: parts[0], Token: parts[1]}
+		if len(parts) == 3 && parts[2] != "" {
+			p.Permissions = strings.Split(parts[2], "|")
+		}
+		principals = append(principals, p)
 	}
+	return principals
 }
 
 func envOr(key, fallback string) string {
@@ -34,6 +180,18 @@ func envOr(key, fallback string) string {
 	return fallback
 }
 
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
 func envInt(key string, fallback int) int {
 	v := os.Getenv(key)
 	if v == "" {
@@ -0,0 +1,135 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// AEADAlgorithm identifies the cipher of a ciphertext produced by SealAEAD.
+// The values are recorded in the ciphertext and must not change.
+type AEADAlgorithm byte
+
+const (
+	// AEADAESGCM is AES-GCM with a 128- or 256-bit key.
+	AEADAESGCM AEADAlgorithm = iota + 1
+	// AEADChaCha20Poly1305 is ChaCha20-Poly1305 (RFC 8439) with a 96-bit nonce.
+	AEADChaCha20Poly1305
+	// AEADXChaCha20Poly1305 is ChaCha20-Poly1305 with a 192-bit nonce, safe
+	// to pick at random for any number of messages.
+	AEADXChaCha20Poly1305
+)
+
+// RandomNonceLimit is the number of messages NIST SP 800-38D section 8.3
+// allows under one key when 96-bit nonces are drawn at random.
+const RandomNonceLimit = 1 << 32
+
+// ErrUnknownAEAD is returned for a ciphertext recording an algorithm this
+// build does not know.
+var ErrUnknownAEAD = errors.New("unknown aead algorithm")
+
+func (a AEADAlgorithm) String() string {
+	switch a {
+	case AEADAESGCM:
+		return "AES-GCM"
+	case AEADChaCha20Poly1305:
+		return "ChaCha20-Poly1305"
+	case AEADXChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// NonceLimit returns how many messages SealAEAD may encrypt under one key
+// before random nonces risk colliding, or 0 when the nonce is long enough
+// that there is no practical limit.
+func (a AEADAlgorithm) NonceLimit() uint64 {
+	switch a {
+	case AEADAESGCM, AEADChaCha20Poly1305:
+		return RandomNonceLimit
+	default:
+		return 0
+	}
+}
+
+func (a AEADAlgorithm) cipher(key []byte) (cipher.AEAD, error) {
+	switch a {
+	case AEADAESGCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("aes new cipher: %w", err)
+		}
+		return cipher.NewGCM(block)
+	case AEADChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	case AEADXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, ErrUnknownAEAD
+	}
+}
+
+// SealAEAD encrypts plaintext under alg. The returned ciphertext records
+// the algorithm ahead of the nonce: [alg | nonce | encrypted | tag]. The
+// algorithm byte is authenticated along with aad.
+func SealAEAD(alg AEADAlgorithm, key, plaintext, aad []byte) ([]byte, error) {
+	aead, err := alg.cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out[0] = byte(alg)
+	nonce := out[1:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return aead.Seal(out, nonce, plaintext, envelopeAAD(alg, aad)), nil
+}
+
+// OpenAEAD decrypts a ciphertext produced by SealAEAD using the algorithm
+// it records.
+func OpenAEAD(key, ciphertext, aad []byte) ([]byte, error) {
+	alg, err := SealedAlgorithm(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := alg.cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	body := ciphertext[1:]
+	if len(body) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ct := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ct, envelopeAAD(alg, aad))
+	if err != nil {
+		return nil, fmt.Errorf("%v decrypt: %w", alg, err)
+	}
+	return plaintext, nil
+}
+
+// SealedAlgorithm returns the algorithm recorded in a ciphertext produced
+// by SealAEAD.
+func SealedAlgorithm(ciphertext []byte) (AEADAlgorithm, error) {
+	if len(ciphertext) == 0 {
+		return 0, fmt.Errorf("ciphertext too short")
+	}
+	alg := AEADAlgorithm(ciphertext[0])
+	if alg.String() == "UNKNOWN" {
+		return 0, ErrUnknownAEAD
+	}
+	return alg, nil
+}
+
+func envelopeAAD(alg AEADAlgorithm, aad []byte) []byte {
+	return append([]byte{byte(alg)}, aad...)
+}
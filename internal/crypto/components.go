@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"fmt"
+)
+
+// KCV lengths: TDEA check values are the first 3 bytes of E(K, 0^64); AES
+// check values are the first 5 bytes of CMAC(K, 0^128) per ANSI X9.24-1.
+const (
+	TDEAKCVSize = 3
+	AESKCVSize  = 5
+)
+
+// TDEAKCV returns the key check value of a double- or triple-length TDEA key.
+func TDEAKCV(key []byte) ([]byte, error) {
+	block, err := NewTDEACipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, block.BlockSize())
+	block.Encrypt(out, out)
+	return out[:TDEAKCVSize], nil
+}
+
+// AESKCV returns the CMAC-based key check value of an AES key.
+func AESKCV(key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	m, err := NewCMAC(block)
+	if err != nil {
+		return nil, err
+	}
+	m.Write(make([]byte, aes.BlockSize))
+	return m.Sum(nil)[:AESKCVSize], nil
+}
+
+// SplitKey splits key into n XOR components. With desParity every component
+// is given odd parity, as TDEA components are expected to have; CombineKey
+// restores the key's own parity afterwards.
+func SplitKey(key []byte, n int, desParity bool) ([][]byte, error) {
+	if n < 2 {
+		return nil, fmt.Errorf("need at least 2 components, got %d", n)
+	}
+	components := make([][]byte, n)
+	last := append([]byte(nil), key...)
+	for i := range n - 1 {
+		c, err := GenerateSymmetricKey(len(key))
+		if err != nil {
+			return nil, err
+		}
+		if desParity {
+			AdjustDESParity(c)
+		}
+		subtle.XORBytes(last, last, c)
+		components[i] = c
+	}
+	if desParity {
+		AdjustDESParity(last)
+	}
+	components[n-1] = last
+	return components, nil
+}
+
+// CombineKey XORs components into a key. With desParity the result is
+// adjusted to odd parity.
+func CombineKey(components [][]byte, desParity bool) ([]byte, error) {
+	if len(components) < 2 {
+		return nil, fmt.Errorf("need at least 2 components, got %d", len(components))
+	}
+	key := make([]byte, len(components[0]))
+	for _, c := range components {
+		if len(c) != len(key) {
+			return nil, fmt.Errorf("component length mismatch: %d and %d bytes", len(key), len(c))
+		}
+		subtle.XORBytes(key, key, c)
+	}
+	if desParity {
+		AdjustDESParity(key)
+	}
+	return key, nil
+}
@@ -0,0 +1,54 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTDEAKCV(t *testing.T) {
+	kcv, err := TDEAKCV(mustHex(t, "0123456789ABCDEFFEDCBA9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(kcv, mustHex(t, "08D7B4")) {
+		t.Fatalf("kcv: got %X, want 08D7B4", kcv)
+	}
+}
+
+func TestSplitCombineKey(t *testing.T) {
+	tdea, _ := GenerateTDEAKey(16)
+	aesKey, _ := GenerateAESKey()
+	for _, tc := range []struct {
+		key    []byte
+		parity bool
+	}{{tdea, true}, {aesKey, false}} {
+		for n := 2; n <= 3; n++ {
+			components, err := SplitKey(tc.key, n, tc.parity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.parity {
+				for _, c := range components {
+					check := append([]byte(nil), c...)
+					AdjustDESParity(check)
+					if !bytes.Equal(check, c) {
+						t.Fatalf("component %x lacks odd parity", c)
+					}
+				}
+			}
+			combined, err := CombineKey(components, tc.parity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(combined, tc.key) {
+				t.Fatalf("%d components: combined key mismatch", n)
+			}
+		}
+	}
+
+	k1, _ := AESKCV(aesKey)
+	k2, _ := AESKCV(aesKey)
+	if len(k1) != AESKCVSize || !bytes.Equal(k1, k2) {
+		t.Fatalf("aes kcv not stable: %x %x", k1, k2)
+	}
+}
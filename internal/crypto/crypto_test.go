@@ -3,6 +3,7 @@ package crypto
 import (
 	"bytes"
 	"crypto/elliptic"
+	"encoding/hex"
 	"testing"
 )
 
@@ -236,6 +237,61 @@ func TestHKDFInvalidLength(t *testing.T) {
 	}
 }
 
+func TestHKDFRFC5869(t *testing.T) {
+	// RFC 5869 appendix A, test cases 1 and 3.
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	tests := []struct {
+		name    string
+		salt    string
+		info    string
+		length  int
+		wantHex string
+	}{
+		{"basic", "000102030405060708090a0b0c", "f0f1f2f3f4f5f6f7f8f9", 42,
+			"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"},
+		{"zero-length salt and info", "", "", 42,
+			"8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			salt, _ := hex.DecodeString(tt.salt)
+			info, _ := hex.DecodeString(tt.info)
+			okm, err := HKDF(HKDFSHA256, ikm, salt, info, tt.length)
+			if err != nil {
+				t.Fatalf("hkdf: %v", err)
+			}
+			if got := hex.EncodeToString(okm); got != tt.wantHex {
+				t.Fatalf("okm = %s, want %s", got, tt.wantHex)
+			}
+		})
+	}
+}
+
+func TestHKDFHashes(t *testing.T) {
+	secret, _ := GenerateAESKey()
+	seen := map[string]bool{}
+	for _, h := range []HKDFHash{HKDFSHA256, HKDFSHA384, HKDFSHA512} {
+		limit := h.MaxLength()
+		okm, err := HKDF(h, secret, []byte("salt"), []byte("info"), limit)
+		if err != nil {
+			t.Fatalf("%v: derive %d bytes: %v", h, limit, err)
+		}
+		if len(okm) != limit {
+			t.Fatalf("%v: got %d bytes, want %d", h, len(okm), limit)
+		}
+		if _, err := HKDF(h, secret, nil, nil, limit+1); err == nil {
+			t.Fatalf("%v: length %d should fail", h, limit+1)
+		}
+		seen[hex.EncodeToString(okm[:32])] = true
+	}
+	if len(seen) != 3 {
+		t.Fatal("different hashes should produce different keys")
+	}
+	if _, err := HKDF(HKDFHash(9), secret, nil, nil, 32); err == nil {
+		t.Fatal("unknown hash should fail")
+	}
+}
+
 // Benchmarks
 
 func BenchmarkECDSAP256Sign(b *testing.B) {
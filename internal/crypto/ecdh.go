@@ -0,0 +1,80 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+)
+
+// GenerateX25519Key creates a new X25519 key pair.
+func GenerateX25519Key() (*ecdh.PrivateKey, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate x25519 key: %w", err)
+	}
+	return key, nil
+}
+
+// MarshalAgreementKey encodes an ECDH private key in PKCS8 DER format.
+func MarshalAgreementKey(key *ecdh.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal agreement key: %w", err)
+	}
+	return der, nil
+}
+
+// UnmarshalAgreementKey decodes a PKCS8 DER-encoded X25519 private key.
+func UnmarshalAgreementKey(der []byte) (*ecdh.PrivateKey, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse agreement key: %w", err)
+	}
+	key, ok := parsed.(*ecdh.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an ECDH private key")
+	}
+	return key, nil
+}
+
+// ParseAgreementPublicKey decodes a PKIX DER public key for key agreement.
+// ECDSA keys are converted to their ECDH form.
+func ParseAgreementPublicKey(der []byte) (*ecdh.PublicKey, error) {
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	switch pub := parsed.(type) {
+	case *ecdh.PublicKey:
+		return pub, nil
+	case *ecdsa.PublicKey:
+		key, err := pub.ECDH()
+		if err != nil {
+			return nil, fmt.Errorf("convert public key: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("not an ECDH public key")
+	}
+}
+
+// ECDH computes the shared secret between key and a peer public key in PKIX
+// DER format. ECDSA peer keys are accepted for the NIST curves; the peer
+// must use the same curve as key, which may be held in an HSM.
+func ECDH(key ecdh.KeyExchanger, peerDER []byte) ([]byte, error) {
+	peer, err := ParseAgreementPublicKey(peerDER)
+	if err != nil {
+		return nil, fmt.Errorf("peer: %w", err)
+	}
+	if peer.Curve() != key.Curve() {
+		return nil, fmt.Errorf("peer key curve does not match")
+	}
+
+	secret, err := key.ECDH(peer)
+	if err != nil {
+		return nil, fmt.Errorf("ecdh: %w", err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+)
+
+func TestECDHX25519(t *testing.T) {
+	alice, err := GenerateX25519Key()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	bob, _ := GenerateX25519Key()
+
+	alicePub, err := MarshalPublicKey(alice.PublicKey())
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	bobPub, _ := MarshalPublicKey(bob.PublicKey())
+
+	s1, err := ECDH(alice, bobPub)
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	s2, err := ECDH(bob, alicePub)
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	if !bytes.Equal(s1, s2) || len(s1) != 32 {
+		t.Fatal("shared secrets should match")
+	}
+}
+
+func TestECDHWithECDSAKeys(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		a, _ := GenerateECDSAKey(curve)
+		b, _ := GenerateECDSAKey(curve)
+		aKey, err := a.ECDH()
+		if err != nil {
+			t.Fatalf("convert key: %v", err)
+		}
+		bKey, _ := b.ECDH()
+		aPub, _ := MarshalPublicKey(&a.PublicKey)
+		bPub, _ := MarshalPublicKey(&b.PublicKey)
+
+		s1, err := ECDH(aKey, bPub)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Params().Name, err)
+		}
+		s2, _ := ECDH(bKey, aPub)
+		if !bytes.Equal(s1, s2) {
+			t.Fatalf("%s: shared secrets should match", curve.Params().Name)
+		}
+	}
+}
+
+func TestECDHCurveMismatch(t *testing.T) {
+	key, _ := GenerateX25519Key()
+	peer, _ := GenerateECDSAKey(elliptic.P256())
+	der, _ := MarshalPublicKey(&peer.PublicKey)
+	if _, err := ECDH(key, der); err == nil {
+		t.Fatal("expected error for a peer key on another curve")
+	}
+	if _, err := ECDH(key, []byte("not a key")); err == nil {
+		t.Fatal("expected error for a malformed peer key")
+	}
+}
+
+func TestAgreementKeyMarshalRoundTrip(t *testing.T) {
+	key, _ := GenerateX25519Key()
+	der, err := MarshalAgreementKey(key)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	recovered, err := UnmarshalAgreementKey(der)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !key.Equal(recovered) {
+		t.Fatal("roundtrip key mismatch")
+	}
+}
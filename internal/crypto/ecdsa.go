@@ -35,8 +35,8 @@ func VerifyECDSA(pub *ecdsa.PublicKey, data, signature []byte) bool {
 	return ecdsa.VerifyASN1(pub, hash[:], signature)
 }
 
-// MarshalPublicKey encodes an ECDSA public key in PKIX DER format.
-func MarshalPublicKey(pub *ecdsa.PublicKey) ([]byte, error) {
+// MarshalPublicKey encodes an ECDSA or ECDH public key in PKIX DER format.
+func MarshalPublicKey(pub any) ([]byte, error) {
 	der, err := x509.MarshalPKIXPublicKey(pub)
 	if err != nil {
 		return nil, fmt.Errorf("marshal public key: %w", err)
@@ -0,0 +1,356 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// FPEAlgorithm identifies a format-preserving encryption mode from
+// NIST SP 800-38G Rev. 1.
+type FPEAlgorithm int
+
+const (
+	// FPEFF1 is the FF1 mode; tweaks may be any length.
+	FPEFF1 FPEAlgorithm = iota + 1
+	// FPEFF31 is the FF3-1 mode; tweaks must be exactly 7 bytes.
+	FPEFF31
+)
+
+func (a FPEAlgorithm) String() string {
+	switch a {
+	case FPEFF1:
+		return "FF1"
+	case FPEFF31:
+		return "FF3-1"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// DigitsAlphabet is the radix-10 alphabet used for PANs.
+const DigitsAlphabet = "0123456789"
+
+// FF31TweakSize is the FF3-1 tweak length in bytes (56 bits).
+const FF31TweakSize = 7
+
+const (
+	// fpeMinDomain is the smallest domain size (radix^minlen) permitted by
+	// SP 800-38G Rev. 1.
+	fpeMinDomain = 1_000_000
+	maxRadix     = 1 << 16
+	// ff1MaxLen bounds FF1 inputs; the standard allows up to 2^32 numerals
+	// but nothing larger than this is useful for format-preserving data.
+	ff1MaxLen = 4096
+	ff1Rounds = 10
+	ff3Rounds = 8
+)
+
+// FPE encrypts strings over a fixed alphabet so the ciphertext has the same
+// length and character set as the plaintext.
+type FPE struct {
+	alg      FPEAlgorithm
+	block    cipher.Block
+	alphabet []rune
+	index    map[rune]uint16
+	radix    *big.Int
+	minLen   int
+	maxLen   int
+}
+
+// NewFPE returns an FF1 or FF3-1 cipher over alphabet with an AES key.
+// The radix is the number of distinct characters in alphabet.
+func NewFPE(alg FPEAlgorithm, key []byte, alphabet string) (*FPE, error) {
+	runes := []rune(alphabet)
+	radix := len(runes)
+	if radix < 2 || radix > maxRadix {
+		return nil, fmt.Errorf("fpe: radix must be 2-%d, got %d", maxRadix, radix)
+	}
+	index := make(map[rune]uint16, radix)
+	for i, r := range runes {
+		if _, dup := index[r]; dup {
+			return nil, fmt.Errorf("fpe: duplicate character %q in alphabet", r)
+		}
+		index[r] = uint16(i)
+	}
+
+	f := &FPE{
+		alg:      alg,
+		alphabet: runes,
+		index:    index,
+		radix:    big.NewInt(int64(radix)),
+		minLen:   2,
+	}
+	for d := radix * radix; d < fpeMinDomain; d *= radix {
+		f.minLen++
+	}
+
+	var err error
+	switch alg {
+	case FPEFF1:
+		f.block, err = aes.NewCipher(key)
+		f.maxLen = ff1MaxLen
+	case FPEFF31:
+		// FF3-1 uses the AES key with its bytes reversed.
+		f.block, err = aes.NewCipher(reverseBytes(key))
+		f.maxLen = 2 * int(math.Floor(96/math.Log2(float64(radix))))
+	default:
+		return nil, fmt.Errorf("fpe: unsupported algorithm: %v", alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	return f, nil
+}
+
+// MinLength and MaxLength bound the number of characters the cipher accepts.
+func (f *FPE) MinLength() int { return f.minLen }
+func (f *FPE) MaxLength() int { return f.maxLen }
+
+// Encrypt enciphers plaintext under tweak.
+func (f *FPE) Encrypt(tweak []byte, plaintext string) (string, error) {
+	return f.run(tweak, plaintext, true)
+}
+
+// Decrypt deciphers ciphertext under tweak.
+func (f *FPE) Decrypt(tweak []byte, ciphertext string) (string, error) {
+	return f.run(tweak, ciphertext, false)
+}
+
+func (f *FPE) run(tweak []byte, s string, encrypt bool) (string, error) {
+	x, err := f.numerals(s)
+	if err != nil {
+		return "", err
+	}
+	if len(x) < f.minLen || len(x) > f.maxLen {
+		return "", fmt.Errorf("fpe: input must be %d-%d characters, got %d", f.minLen, f.maxLen, len(x))
+	}
+
+	var y []uint16
+	switch f.alg {
+	case FPEFF1:
+		y = f.ff1(tweak, x, encrypt)
+	case FPEFF31:
+		if len(tweak) != FF31TweakSize {
+			return "", fmt.Errorf("fpe: ff3-1 tweak must be %d bytes, got %d", FF31TweakSize, len(tweak))
+		}
+		y = f.ff3(ff31Tweak(tweak), x, encrypt)
+	}
+	return f.str(y), nil
+}
+
+func (f *FPE) numerals(s string) ([]uint16, error) {
+	x := make([]uint16, 0, len(s))
+	for _, r := range s {
+		n, ok := f.index[r]
+		if !ok {
+			return nil, fmt.Errorf("fpe: character %q not in alphabet", r)
+		}
+		x = append(x, n)
+	}
+	return x, nil
+}
+
+func (f *FPE) str(x []uint16) string {
+	out := make([]rune, len(x))
+	for i, n := range x {
+		out[i] = f.alphabet[n]
+	}
+	return string(out)
+}
+
+// ff1 implements FF1.Encrypt and FF1.Decrypt (SP 800-38G §6.2).
+func (f *FPE) ff1(tweak []byte, x []uint16, encrypt bool) []uint16 {
+	n, t := len(x), len(tweak)
+	u := n / 2
+	v := n - u
+	a, b := slices.Clone(x[:u]), slices.Clone(x[u:])
+
+	// b is the byte length of a v-numeral number; d adds room for the
+	// modular reduction to be close to uniform.
+	bl := (new(big.Int).Sub(f.pow(v), big.NewInt(1)).BitLen() + 7) / 8
+	d := 4*((bl+3)/4) + 4
+
+	radix := int(f.radix.Int64())
+	p := []byte{1, 2, 1, byte(radix >> 16), byte(radix >> 8), byte(radix), 10, byte(u),
+		byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n),
+		byte(t >> 24), byte(t >> 16), byte(t >> 8), byte(t)}
+
+	zeros := ((-t-bl-1)%16 + 16) % 16
+	q := make([]byte, t+zeros+1+bl)
+	copy(q, tweak)
+
+	r := make([]byte, 16)
+	s := make([]byte, ((d+15)/16)*16)
+	y := new(big.Int)
+	c := new(big.Int)
+
+	for round := range ff1Rounds {
+		i := round
+		if !encrypt {
+			i = ff1Rounds - 1 - round
+		}
+		m := u
+		if i%2 == 1 {
+			m = v
+		}
+
+		// The round function always takes the half that is not being
+		// updated: B when enciphering, A when deciphering.
+		src, dst := b, a
+		if !encrypt {
+			src, dst = a, b
+		}
+		q[t+zeros] = byte(i)
+		f.num(src).FillBytes(q[t+zeros+1:])
+
+		// R = PRF(P || Q), a CBC-MAC with a zero IV.
+		clear(r)
+		f.cbcmac(r, p)
+		f.cbcmac(r, q)
+
+		// S = R || CIPH(R xor [1]) || CIPH(R xor [2]) ...
+		copy(s, r)
+		for j := 1; j*16 < d; j++ {
+			blk := s[j*16 : (j+1)*16]
+			copy(blk, r)
+			blk[15] ^= byte(j)
+			blk[14] ^= byte(j >> 8)
+			blk[13] ^= byte(j >> 16)
+			blk[12] ^= byte(j >> 24)
+			f.block.Encrypt(blk, blk)
+		}
+		y.SetBytes(s[:d])
+
+		c.Set(f.num(dst))
+		if encrypt {
+			c.Add(c, y)
+		} else {
+			c.Sub(c, y)
+		}
+		c.Mod(c, f.pow(m))
+
+		out := f.strm(c, m)
+		if encrypt {
+			a, b = b, out
+		} else {
+			b, a = a, out
+		}
+	}
+	return append(a, b...)
+}
+
+func (f *FPE) cbcmac(x, data []byte) {
+	for len(data) > 0 {
+		for i := range 16 {
+			x[i] ^= data[i]
+		}
+		f.block.Encrypt(x, x)
+		data = data[16:]
+	}
+}
+
+// ff3 implements FF3-1.Encrypt and FF3-1.Decrypt (SP 800-38G Rev. 1 §6.3)
+// given the two 4-byte tweak halves.
+func (f *FPE) ff3(tweak [2][4]byte, x []uint16, encrypt bool) []uint16 {
+	n := len(x)
+	u := (n + 1) / 2
+	v := n - u
+	a, b := slices.Clone(x[:u]), slices.Clone(x[u:])
+
+	p := make([]byte, 16)
+	y := new(big.Int)
+	c := new(big.Int)
+
+	for round := range ff3Rounds {
+		i := round
+		if !encrypt {
+			i = ff3Rounds - 1 - round
+		}
+		m, w := u, tweak[1]
+		if i%2 == 1 {
+			m, w = v, tweak[0]
+		}
+
+		src, dst := b, a
+		if !encrypt {
+			src, dst = a, b
+		}
+		copy(p, w[:])
+		p[3] ^= byte(i)
+		clear(p[4:])
+		f.num(reversed(src)).FillBytes(p[4:])
+
+		// S = REVB(CIPH_REVB(K)(REVB(P))); the key was reversed in NewFPE.
+		slices.Reverse(p)
+		f.block.Encrypt(p, p)
+		slices.Reverse(p)
+		y.SetBytes(p)
+
+		c.Set(f.num(reversed(dst)))
+		if encrypt {
+			c.Add(c, y)
+		} else {
+			c.Sub(c, y)
+		}
+		c.Mod(c, f.pow(m))
+
+		out := reversed(f.strm(c, m))
+		if encrypt {
+			a, b = b, out
+		} else {
+			b, a = a, out
+		}
+	}
+	return append(a, b...)
+}
+
+// ff31Tweak splits a 56-bit FF3-1 tweak into its left and right halves.
+func ff31Tweak(t []byte) [2][4]byte {
+	return [2][4]byte{
+		{t[0], t[1], t[2], t[3] & 0xf0},
+		{t[4], t[5], t[6], t[3] << 4},
+	}
+}
+
+// num returns NUM_radix(x), most significant numeral first.
+func (f *FPE) num(x []uint16) *big.Int {
+	z := new(big.Int)
+	d := new(big.Int)
+	for _, n := range x {
+		z.Mul(z, f.radix)
+		z.Add(z, d.SetUint64(uint64(n)))
+	}
+	return z
+}
+
+// strm returns STR^m_radix(z).
+func (f *FPE) strm(z *big.Int, m int) []uint16 {
+	out := make([]uint16, m)
+	z = new(big.Int).Set(z)
+	r := new(big.Int)
+	for i := m - 1; i >= 0; i-- {
+		z.QuoRem(z, f.radix, r)
+		out[i] = uint16(r.Uint64())
+	}
+	return out
+}
+
+func (f *FPE) pow(m int) *big.Int {
+	return new(big.Int).Exp(f.radix, big.NewInt(int64(m)), nil)
+}
+
+func reversed(x []uint16) []uint16 {
+	out := slices.Clone(x)
+	slices.Reverse(out)
+	return out
+}
+
+func reverseBytes(b []byte) []byte {
+	out := slices.Clone(b)
+	slices.Reverse(out)
+	return out
+}
@@ -0,0 +1,122 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+const base36 = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+func TestFF1Vectors(t *testing.T) {
+	// NIST SP 800-38G FF1 samples.
+	tests := []struct {
+		key, tweak, alphabet, pt, ct string
+	}{
+		{"2B7E151628AED2A6ABF7158809CF4F3C", "", DigitsAlphabet, "0123456789", "2433477484"},
+		{"2B7E151628AED2A6ABF7158809CF4F3C", "39383736353433323130", DigitsAlphabet, "0123456789", "6124200773"},
+		{"2B7E151628AED2A6ABF7158809CF4F3C", "3737373770717273373737", base36, "0123456789abcdefghi", "a9tv40mll9kdu509eum"},
+		{"2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94", "", DigitsAlphabet, "0123456789", "6657667009"},
+		{"2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94", "3737373770717273373737", base36, "0123456789abcdefghi", "xs8a0azh2avyalyzuwd"},
+	}
+	for _, tt := range tests {
+		key, _ := hex.DecodeString(tt.key)
+		tweak, _ := hex.DecodeString(tt.tweak)
+		f, err := NewFPE(FPEFF1, key, tt.alphabet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, err := f.Encrypt(tweak, tt.pt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct != tt.ct {
+			t.Errorf("encrypt %s: got %s, want %s", tt.pt, ct, tt.ct)
+		}
+		pt, err := f.Decrypt(tweak, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pt != tt.pt {
+			t.Errorf("decrypt %s: got %s, want %s", ct, pt, tt.pt)
+		}
+	}
+}
+
+func TestFF3Vectors(t *testing.T) {
+	// NIST FF3 sample 1, run through the FF3-1 rounds with the equivalent
+	// 64-bit tweak halves.
+	key, _ := hex.DecodeString("EF4359D8D580AA4F7F036D6F04FC6A94")
+	f, err := NewFPE(FPEFF31, key, DigitsAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweak := [2][4]byte{{0xD8, 0xE7, 0x92, 0x0A}, {0xFA, 0x33, 0x0A, 0x73}}
+	x, _ := f.numerals("890121234567890000")
+	if got := f.str(f.ff3(tweak, x, true)); got != "750918814058654607" {
+		t.Fatalf("ff3 encrypt: got %s", got)
+	}
+}
+
+func TestFF31Vector(t *testing.T) {
+	key, _ := hex.DecodeString("AD41EC5D2356DEAE53AE76F50B4BA6D2")
+	tweak, _ := hex.DecodeString("CF29DA1E18D970")
+	f, err := NewFPE(FPEFF31, key, DigitsAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := f.Encrypt(tweak, "6520935496")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != "4716569208" {
+		t.Fatalf("got %s", ct)
+	}
+}
+
+func TestFPERoundTripPAN(t *testing.T) {
+	key, _ := GenerateAESKey()
+	for _, alg := range []FPEAlgorithm{FPEFF1, FPEFF31} {
+		f, err := NewFPE(alg, key, DigitsAlphabet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tweak := make([]byte, FF31TweakSize)
+		pan := "4111111111111111"
+		ct, err := f.Encrypt(tweak, pan)
+		if err != nil {
+			t.Fatalf("%v: %v", alg, err)
+		}
+		if len(ct) != len(pan) || ct == pan {
+			t.Fatalf("%v: unexpected ciphertext %s", alg, ct)
+		}
+		pt, err := f.Decrypt(tweak, ct)
+		if err != nil || pt != pan {
+			t.Fatalf("%v: round trip got %s, %v", alg, pt, err)
+		}
+	}
+}
+
+func TestFPELimits(t *testing.T) {
+	key, _ := GenerateAESKey()
+	f, _ := NewFPE(FPEFF1, key, DigitsAlphabet)
+	if f.MinLength() != 6 {
+		t.Fatalf("radix 10 min length: got %d", f.MinLength())
+	}
+	if _, err := f.Encrypt(nil, "12345"); err == nil {
+		t.Fatal("expected error for a domain below one million")
+	}
+	if _, err := f.Encrypt(nil, "12345a"); err == nil {
+		t.Fatal("expected error for a character outside the alphabet")
+	}
+
+	ff3, _ := NewFPE(FPEFF31, key, DigitsAlphabet)
+	if ff3.MaxLength() != 56 {
+		t.Fatalf("ff3-1 radix 10 max length: got %d", ff3.MaxLength())
+	}
+	if _, err := ff3.Encrypt(make([]byte, 8), "123456"); err == nil {
+		t.Fatal("expected error for a 64-bit ff3-1 tweak")
+	}
+	if _, err := NewFPE(FPEFF1, key, "0012"); err == nil {
+		t.Fatal("expected error for duplicate alphabet characters")
+	}
+}
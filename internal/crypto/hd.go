@@ -0,0 +1,68 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+)
+
+// Hierarchical derivation turns one root key into a tree of keys addressed
+// by path. Each path segment is one HKDF-SHA256 step from its parent's
+// chain key,
+//
+//	chain(root)       = root
+//	chain(p/segment)  = HKDF(chain(p), info = "vault-hd" | 0 | segment)
+//
+// so the key at any path can be recomputed from the root alone, and a
+// chain key only reaches the keys below it. A leaf key is expanded from
+// its chain key with the algorithm name as a label, so the same path gives
+// unrelated keys for different algorithms.
+const hdChainSize = 32
+
+// DerivePath returns the chain key reached by walking path from root.
+func DerivePath(root []byte, path []string) ([]byte, error) {
+	chain := append([]byte(nil), root...)
+	for _, segment := range path {
+		if segment == "" {
+			clear(chain)
+			return nil, errors.New("empty derivation path segment")
+		}
+		next, err := HKDF(HKDFSHA256, chain, nil, hdInfo("vault-hd", segment), hdChainSize)
+		clear(chain)
+		if err != nil {
+			return nil, err
+		}
+		chain = next
+	}
+	return chain, nil
+}
+
+// DeriveLeafKey expands a chain key into size bytes of key material for
+// the algorithm named by label.
+func DeriveLeafKey(chain []byte, label string, size int) ([]byte, error) {
+	return HKDF(HKDFSHA256, chain, nil, hdInfo("vault-hd-key", label), size)
+}
+
+// DeriveECDSAKey expands a chain key into an ECDSA private key on curve.
+// Candidate scalars outside [1, n-1] are rejected and the next counter
+// tried, which for P-256 and P-384 almost never happens.
+func DeriveECDSAKey(curve elliptic.Curve, chain []byte, label string) (*ecdsa.PrivateKey, error) {
+	size := (curve.Params().BitSize + 7) / 8
+	for counter := byte(0); counter < 16; counter++ {
+		d, err := HKDF(HKDFSHA256, chain, nil, append(hdInfo("vault-hd-key", label), counter), size)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ecdsa.ParseRawPrivateKey(curve, d)
+		clear(d)
+		if err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no valid ecdsa scalar derived")
+}
+
+func hdInfo(prefix, value string) []byte {
+	info := append([]byte(prefix), 0)
+	return append(info, value...)
+}
@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+)
+
+func TestDerivePathHierarchy(t *testing.T) {
+	root, _ := GenerateAESKey()
+	rootCopy := append([]byte(nil), root...)
+
+	full, err := DerivePath(root, []string{"acquirer", "merchant", "123", "terminal", "9"})
+	if err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	if !bytes.Equal(root, rootCopy) {
+		t.Fatal("derivation modified the root key")
+	}
+
+	// A chain key reaches everything below it.
+	merchant, _ := DerivePath(root, []string{"acquirer", "merchant", "123"})
+	fromMerchant, _ := DerivePath(merchant, []string{"terminal", "9"})
+	if !bytes.Equal(full, fromMerchant) {
+		t.Fatal("deriving in two steps should match deriving the full path")
+	}
+
+	other, _ := DerivePath(root, []string{"acquirer", "merchant", "123", "terminal", "10"})
+	if bytes.Equal(full, other) {
+		t.Fatal("sibling paths should derive different keys")
+	}
+	// Segments are not concatenated: a/bc and ab/c differ.
+	ab, _ := DerivePath(root, []string{"a", "bc"})
+	ba, _ := DerivePath(root, []string{"ab", "c"})
+	if bytes.Equal(ab, ba) {
+		t.Fatal("segment boundaries should affect the derived key")
+	}
+
+	if _, err := DerivePath(root, []string{"a", "", "b"}); err == nil {
+		t.Fatal("empty segment should fail")
+	}
+}
+
+func TestDeriveLeafKeys(t *testing.T) {
+	root, _ := GenerateAESKey()
+	chain, _ := DerivePath(root, []string{"merchant", "1"})
+
+	aes1, _ := DeriveLeafKey(chain, "AES_256", 32)
+	aes2, _ := DeriveLeafKey(chain, "AES_256", 32)
+	hmac, _ := DeriveLeafKey(chain, "HMAC_SHA256", 32)
+	if !bytes.Equal(aes1, aes2) {
+		t.Fatal("leaf derivation should be deterministic")
+	}
+	if bytes.Equal(aes1, hmac) {
+		t.Fatal("different algorithms should derive different keys")
+	}
+
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		k1, err := DeriveECDSAKey(curve, chain, curve.Params().Name)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Params().Name, err)
+		}
+		k2, _ := DeriveECDSAKey(curve, chain, curve.Params().Name)
+		if !k1.Equal(k2) {
+			t.Fatalf("%s: ecdsa derivation should be deterministic", curve.Params().Name)
+		}
+		sig, err := SignECDSA(k1, []byte("msg"))
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if !VerifyECDSA(&k1.PublicKey, []byte("msg"), sig) {
+			t.Fatalf("%s: derived key should sign", curve.Params().Name)
+		}
+	}
+}
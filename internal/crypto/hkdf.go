@@ -2,24 +2,88 @@ package crypto
 
 import (
 	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
+	"hash"
 	"io"
 
 	"golang.org/x/crypto/hkdf"
 )
 
-// DeriveKey derives a key from the root key material using HKDF-SHA256.
-// context is used as the HKDF info parameter for domain separation.
-// length specifies the output key size in bytes.
-func DeriveKey(rootKey, context []byte, length int) ([]byte, error) {
-	if length <= 0 || length > 64 {
-		return nil, fmt.Errorf("invalid derived key length: %d (must be 1-64)", length)
+// HKDFHash selects the hash function HKDF is instantiated with.
+type HKDFHash int
+
+const (
+	// HKDFSHA256 is HKDF-SHA256, the default.
+	HKDFSHA256 HKDFHash = iota
+	// HKDFSHA384 is HKDF-SHA384.
+	HKDFSHA384
+	// HKDFSHA512 is HKDF-SHA512.
+	HKDFSHA512
+)
+
+func (h HKDFHash) String() string {
+	switch h {
+	case HKDFSHA256:
+		return "SHA-256"
+	case HKDFSHA384:
+		return "SHA-384"
+	case HKDFSHA512:
+		return "SHA-512"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func (h HKDFHash) newHash() (func() hash.Hash, int, error) {
+	switch h {
+	case HKDFSHA256:
+		return sha256.New, sha256.Size, nil
+	case HKDFSHA384:
+		return sha512.New384, sha512.Size384, nil
+	case HKDFSHA512:
+		return sha512.New, sha512.Size, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown hkdf hash %d", int(h))
+	}
+}
+
+// MaxLength returns the most output HKDF can produce with h: 255 hash
+// blocks (RFC 5869 section 2.3).
+func (h HKDFHash) MaxLength() int {
+	_, size, err := h.newHash()
+	if err != nil {
+		return 0
 	}
+	return 255 * size
+}
 
-	r := hkdf.New(sha256.New, rootKey, nil, context)
+// HKDF derives length bytes from secret with HKDF (RFC 5869) over h. salt
+// may be nil, in which case a string of zeros is used; info provides
+// domain separation. length may be up to h.MaxLength().
+func HKDF(h HKDFHash, secret, salt, info []byte, length int) ([]byte, error) {
+	newHash, _, err := h.newHash()
+	if err != nil {
+		return nil, err
+	}
+	if limit := h.MaxLength(); length <= 0 || length > limit {
+		return nil, fmt.Errorf("invalid derived key length: %d (must be 1-%d)", length, limit)
+	}
+
+	r := hkdf.New(newHash, secret, salt, info)
 	derived := make([]byte, length)
 	if _, err := io.ReadFull(r, derived); err != nil {
 		return nil, fmt.Errorf("hkdf derive: %w", err)
 	}
 	return derived, nil
 }
+
+// DeriveKey derives a key from the root key material using HKDF-SHA256.
+// context is used as the HKDF info parameter for domain separation.
+// length specifies the output key size in bytes.
+func DeriveKey(rootKey, context []byte, length int) ([]byte, error) {
+	if length <= 0 || length > 64 {
+		return nil, fmt.Errorf("invalid derived key length: %d (must be 1-64)", length)
+	}
+	return HKDF(HKDFSHA256, rootKey, nil, context, length)
+}
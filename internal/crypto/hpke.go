@@ -0,0 +1,87 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/hpke"
+	"fmt"
+)
+
+// HPKEAEAD identifies an RFC 9180 AEAD. Every suite uses DHKEM on the
+// recipient key's curve and HKDF-SHA256.
+type HPKEAEAD int
+
+const (
+	HPKEAES128GCM HPKEAEAD = iota + 1
+	HPKEAES256GCM
+	HPKEChaCha20Poly1305
+)
+
+func (a HPKEAEAD) String() string {
+	switch a {
+	case HPKEAES128GCM:
+		return "AES_128_GCM"
+	case HPKEAES256GCM:
+		return "AES_256_GCM"
+	case HPKEChaCha20Poly1305:
+		return "CHACHA20_POLY1305"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func (a HPKEAEAD) aead() (hpke.AEAD, error) {
+	switch a {
+	case HPKEAES128GCM:
+		return hpke.AES128GCM(), nil
+	case HPKEAES256GCM:
+		return hpke.AES256GCM(), nil
+	case HPKEChaCha20Poly1305:
+		return hpke.ChaCha20Poly1305(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hpke aead: %v", a)
+	}
+}
+
+// SealHPKE encrypts plaintext to pub in HPKE base mode. It returns the
+// encapsulated key and the ciphertext.
+func SealHPKE(pub *ecdh.PublicKey, alg HPKEAEAD, info, aad, plaintext []byte) (enc, ciphertext []byte, err error) {
+	aead, err := alg.aead()
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, err := hpke.NewDHKEMPublicKey(pub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke public key: %w", err)
+	}
+	enc, sender, err := hpke.NewSender(pk, hpke.HKDFSHA256(), aead, info)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke setup: %w", err)
+	}
+	ciphertext, err = sender.Seal(aad, plaintext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke seal: %w", err)
+	}
+	return enc, ciphertext, nil
+}
+
+// OpenHPKE decrypts a message sealed to key's public key by SealHPKE or any
+// RFC 9180 implementation using the same suite.
+func OpenHPKE(key ecdh.KeyExchanger, alg HPKEAEAD, info, aad, enc, ciphertext []byte) ([]byte, error) {
+	aead, err := alg.aead()
+	if err != nil {
+		return nil, err
+	}
+	sk, err := hpke.NewDHKEMPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("hpke private key: %w", err)
+	}
+	recipient, err := hpke.NewRecipient(enc, sk, hpke.HKDFSHA256(), aead, info)
+	if err != nil {
+		return nil, fmt.Errorf("hpke setup: %w", err)
+	}
+	plaintext, err := recipient.Open(aad, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("hpke open: %w", err)
+	}
+	return plaintext, nil
+}
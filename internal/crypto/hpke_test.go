@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+)
+
+// RFC 9180 Appendix A.1.1: DHKEM(X25519, HKDF-SHA256), HKDF-SHA256,
+// AES-128-GCM, base mode, sequence number 0.
+func TestOpenHPKEVector(t *testing.T) {
+	sk, err := ecdh.X25519().NewPrivateKey(mustHex(t, "4612c550263fc8ad58375df3f557aac531d26850903e55a9f23f21d8534e8ac8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := mustHex(t, "37fda3567bdbd628e88668c3c8d7e97d1d1253b6d4ea6d44c150f741f1bf4431")
+	info := mustHex(t, "4f6465206f6e2061204772656369616e2055726e")
+	aad := mustHex(t, "436f756e742d30")
+	ct := mustHex(t, "f938558b5d72f1a23810b4be2ab4f84331acc02fc97babc53a52ae8218a355a96d8770ac83d07bea87e13c512a")
+
+	pt, err := OpenHPKE(sk, HPKEAES128GCM, info, aad, enc, ct)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if string(pt) != "Beauty is truth, truth beauty" {
+		t.Fatalf("got %q", pt)
+	}
+}
+
+func TestHPKERoundTrip(t *testing.T) {
+	x, _ := GenerateX25519Key()
+	p256, _ := ecdh.P256().GenerateKey(rand.Reader)
+	p384, _ := ecdh.P384().GenerateKey(rand.Reader)
+
+	for _, key := range []*ecdh.PrivateKey{x, p256, p384} {
+		for _, alg := range []HPKEAEAD{HPKEAES128GCM, HPKEAES256GCM, HPKEChaCha20Poly1305} {
+			enc, ct, err := SealHPKE(key.PublicKey(), alg, []byte("info"), []byte("aad"), []byte("secret"))
+			if err != nil {
+				t.Fatalf("%v: seal: %v", alg, err)
+			}
+			pt, err := OpenHPKE(key, alg, []byte("info"), []byte("aad"), enc, ct)
+			if err != nil || !bytes.Equal(pt, []byte("secret")) {
+				t.Fatalf("%v: open: %q, %v", alg, pt, err)
+			}
+			if _, err := OpenHPKE(key, alg, []byte("info"), []byte("other"), enc, ct); err == nil {
+				t.Fatalf("%v: expected error for wrong aad", alg)
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/mlkem"
+	"crypto/rand"
+	"crypto/sha3"
+	"fmt"
+	"slices"
+)
+
+// KEMAlgorithm identifies a key encapsulation mechanism.
+type KEMAlgorithm int
+
+const (
+	KEMMLKEM768 KEMAlgorithm = iota + 1
+	KEMMLKEM1024
+	// KEMXWing is the X-Wing hybrid of ML-KEM-768 and X25519
+	// (draft-connolly-cfrg-xwing-kem).
+	KEMXWing
+)
+
+func (a KEMAlgorithm) String() string {
+	switch a {
+	case KEMMLKEM768:
+		return "ML-KEM-768"
+	case KEMMLKEM1024:
+		return "ML-KEM-1024"
+	case KEMXWing:
+		return "X-Wing"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// SeedSize returns the length of the seed a decapsulation key is derived
+// from: the 64-byte d || z seed of FIPS 203 for ML-KEM and the 32-byte
+// X-Wing seed, which expands to an ML-KEM-768 seed and an X25519 scalar.
+func (a KEMAlgorithm) SeedSize() int {
+	switch a {
+	case KEMMLKEM768, KEMMLKEM1024:
+		return mlkem.SeedSize
+	case KEMXWing:
+		return xwingSeedSize
+	default:
+		return 0
+	}
+}
+
+const (
+	xwingSeedSize = 32
+	x25519Size    = 32
+)
+
+// xwingLabel is the X-Wing combiner label, "\.//^\".
+var xwingLabel = []byte{0x5c, 0x2e, 0x2f, 0x2f, 0x5e, 0x5c}
+
+// KEMPrivateKey is a decapsulation key that is fully determined by its seed,
+// so only the seed needs to be stored.
+type KEMPrivateKey struct {
+	alg    KEMAlgorithm
+	seed   []byte
+	dk768  *mlkem.DecapsulationKey768
+	dk1024 *mlkem.DecapsulationKey1024
+	x25519 *ecdh.PrivateKey
+}
+
+// GenerateKEMKey creates a new decapsulation key from a random seed.
+func GenerateKEMKey(alg KEMAlgorithm) (*KEMPrivateKey, error) {
+	seed := make([]byte, alg.SeedSize())
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("unsupported kem: %v", alg)
+	}
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("generate kem seed: %w", err)
+	}
+	return NewKEMPrivateKey(alg, seed)
+}
+
+// NewKEMPrivateKey reconstructs a decapsulation key from its seed.
+func NewKEMPrivateKey(alg KEMAlgorithm, seed []byte) (*KEMPrivateKey, error) {
+	if n := alg.SeedSize(); n == 0 || len(seed) != n {
+		return nil, fmt.Errorf("%v seed must be %d bytes, got %d", alg, n, len(seed))
+	}
+	k := &KEMPrivateKey{alg: alg, seed: slices.Clone(seed)}
+
+	var err error
+	switch alg {
+	case KEMMLKEM768:
+		k.dk768, err = mlkem.NewDecapsulationKey768(seed)
+	case KEMMLKEM1024:
+		k.dk1024, err = mlkem.NewDecapsulationKey1024(seed)
+	case KEMXWing:
+		expanded := sha3.SumSHAKE256(seed, mlkem.SeedSize+x25519Size)
+		if k.dk768, err = mlkem.NewDecapsulationKey768(expanded[:mlkem.SeedSize]); err == nil {
+			k.x25519, err = ecdh.X25519().NewPrivateKey(expanded[mlkem.SeedSize:])
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v key: %w", alg, err)
+	}
+	return k, nil
+}
+
+// Algorithm returns the key's KEM.
+func (k *KEMPrivateKey) Algorithm() KEMAlgorithm { return k.alg }
+
+// Seed returns a copy of the seed the key was derived from.
+func (k *KEMPrivateKey) Seed() []byte { return slices.Clone(k.seed) }
+
+// EncapsulationKey returns the public encapsulation key: the FIPS 203
+// encoding for ML-KEM, and the ML-KEM-768 key followed by the X25519 public
+// key for X-Wing.
+func (k *KEMPrivateKey) EncapsulationKey() []byte {
+	switch k.alg {
+	case KEMMLKEM1024:
+		return k.dk1024.EncapsulationKey().Bytes()
+	case KEMXWing:
+		return append(k.dk768.EncapsulationKey().Bytes(), k.x25519.PublicKey().Bytes()...)
+	default:
+		return k.dk768.EncapsulationKey().Bytes()
+	}
+}
+
+// Decapsulate recovers the 32-byte shared key from a ciphertext produced by
+// Encapsulate.
+func (k *KEMPrivateKey) Decapsulate(ciphertext []byte) ([]byte, error) {
+	switch k.alg {
+	case KEMMLKEM768:
+		return k.dk768.Decapsulate(ciphertext)
+	case KEMMLKEM1024:
+		return k.dk1024.Decapsulate(ciphertext)
+	}
+
+	if len(ciphertext) != mlkem.CiphertextSize768+x25519Size {
+		return nil, fmt.Errorf("x-wing ciphertext must be %d bytes, got %d", mlkem.CiphertextSize768+x25519Size, len(ciphertext))
+	}
+	ctM, ctX := ciphertext[:mlkem.CiphertextSize768], ciphertext[mlkem.CiphertextSize768:]
+	ssM, err := k.dk768.Decapsulate(ctM)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := ecdh.X25519().NewPublicKey(ctX)
+	if err != nil {
+		return nil, fmt.Errorf("x-wing ciphertext: %w", err)
+	}
+	ssX, err := k.x25519.ECDH(peer)
+	if err != nil {
+		return nil, fmt.Errorf("x25519: %w", err)
+	}
+	return xwingCombine(ssM, ssX, ctX, k.x25519.PublicKey().Bytes()), nil
+}
+
+// Encapsulate generates a shared key and the ciphertext that conveys it to
+// the holder of the decapsulation key for encapsulationKey.
+func Encapsulate(alg KEMAlgorithm, encapsulationKey []byte) (sharedKey, ciphertext []byte, err error) {
+	switch alg {
+	case KEMMLKEM768:
+		ek, err := mlkem.NewEncapsulationKey768(encapsulationKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v encapsulation key: %w", alg, err)
+		}
+		sharedKey, ciphertext = ek.Encapsulate()
+		return sharedKey, ciphertext, nil
+	case KEMMLKEM1024:
+		ek, err := mlkem.NewEncapsulationKey1024(encapsulationKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v encapsulation key: %w", alg, err)
+		}
+		sharedKey, ciphertext = ek.Encapsulate()
+		return sharedKey, ciphertext, nil
+	case KEMXWing:
+	default:
+		return nil, nil, fmt.Errorf("unsupported kem: %v", alg)
+	}
+
+	if len(encapsulationKey) != mlkem.EncapsulationKeySize768+x25519Size {
+		return nil, nil, fmt.Errorf("x-wing encapsulation key must be %d bytes, got %d", mlkem.EncapsulationKeySize768+x25519Size, len(encapsulationKey))
+	}
+	ekM, err := mlkem.NewEncapsulationKey768(encapsulationKey[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, nil, fmt.Errorf("x-wing encapsulation key: %w", err)
+	}
+	pkX := encapsulationKey[mlkem.EncapsulationKeySize768:]
+	peer, err := ecdh.X25519().NewPublicKey(pkX)
+	if err != nil {
+		return nil, nil, fmt.Errorf("x-wing encapsulation key: %w", err)
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate x25519 key: %w", err)
+	}
+	ssX, err := ephemeral.ECDH(peer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("x25519: %w", err)
+	}
+	ssM, ctM := ekM.Encapsulate()
+	ctX := ephemeral.PublicKey().Bytes()
+	return xwingCombine(ssM, ssX, ctX, pkX), append(ctM, ctX...), nil
+}
+
+// xwingCombine is the X-Wing combiner,
+// SHA3-256(ss_M || ss_X || ct_X || pk_X || label).
+func xwingCombine(ssM, ssX, ctX, pkX []byte) []byte {
+	h := sha3.New256()
+	h.Write(ssM)
+	h.Write(ssX)
+	h.Write(ctX)
+	h.Write(pkX)
+	h.Write(xwingLabel)
+	return h.Sum(nil)
+}
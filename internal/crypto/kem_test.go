@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/hpke"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+)
+
+func TestKEMRoundTrip(t *testing.T) {
+	for _, alg := range []KEMAlgorithm{KEMMLKEM768, KEMMLKEM1024, KEMXWing} {
+		key, err := GenerateKEMKey(alg)
+		if err != nil {
+			t.Fatalf("%v: generate: %v", alg, err)
+		}
+		ss, ct, err := Encapsulate(alg, key.EncapsulationKey())
+		if err != nil {
+			t.Fatalf("%v: encapsulate: %v", alg, err)
+		}
+		got, err := key.Decapsulate(ct)
+		if err != nil {
+			t.Fatalf("%v: decapsulate: %v", alg, err)
+		}
+		if !bytes.Equal(got, ss) || len(ss) != 32 {
+			t.Fatalf("%v: shared keys differ", alg)
+		}
+
+		restored, err := NewKEMPrivateKey(alg, key.Seed())
+		if err != nil {
+			t.Fatalf("%v: restore: %v", alg, err)
+		}
+		if !bytes.Equal(restored.EncapsulationKey(), key.EncapsulationKey()) {
+			t.Fatalf("%v: restored key differs", alg)
+		}
+	}
+
+	if _, err := NewKEMPrivateKey(KEMXWing, make([]byte, 64)); err == nil {
+		t.Fatal("expected error for wrong seed size")
+	}
+	key, _ := GenerateKEMKey(KEMXWing)
+	if _, err := key.Decapsulate(make([]byte, 10)); err == nil {
+		t.Fatal("expected error for short ciphertext")
+	}
+}
+
+// TestKEMMatchesHPKE checks the encodings and shared keys against the
+// ML-KEM-768 and MLKEM768-X25519 KEMs of crypto/hpke: the public keys must
+// match and the HPKE exporter computed from Decapsulate's shared key must
+// equal the sender's.
+func TestKEMMatchesHPKE(t *testing.T) {
+	for _, tt := range []struct {
+		alg KEMAlgorithm
+		kem hpke.KEM
+	}{
+		{KEMMLKEM768, hpke.MLKEM768()},
+		{KEMMLKEM1024, hpke.MLKEM1024()},
+		{KEMXWing, hpke.MLKEM768X25519()},
+	} {
+		key, _ := GenerateKEMKey(tt.alg)
+		sk, err := tt.kem.NewPrivateKey(key.Seed())
+		if err != nil {
+			t.Fatalf("%v: hpke private key: %v", tt.alg, err)
+		}
+		if !bytes.Equal(sk.PublicKey().Bytes(), key.EncapsulationKey()) {
+			t.Fatalf("%v: public keys differ", tt.alg)
+		}
+
+		info := []byte("vault")
+		enc, sender, err := hpke.NewSender(sk.PublicKey(), hpke.HKDFSHA256(), hpke.ExportOnly(), info)
+		if err != nil {
+			t.Fatalf("%v: hpke sender: %v", tt.alg, err)
+		}
+		want, _ := sender.Export("test", 32)
+
+		ss, err := key.Decapsulate(enc)
+		if err != nil {
+			t.Fatalf("%v: decapsulate: %v", tt.alg, err)
+		}
+		if got := hpkeExport(t, tt.kem.ID(), ss, info, "test", 32); !bytes.Equal(got, want) {
+			t.Fatalf("%v: exporter secrets differ", tt.alg)
+		}
+	}
+}
+
+// hpkeExport runs the RFC 9180 base mode key schedule with HKDF-SHA256 and
+// the export-only AEAD, then Export.
+func hpkeExport(t *testing.T, kemID uint16, ss, info []byte, exporterContext string, length int) []byte {
+	t.Helper()
+	suite := []byte("HPKE")
+	suite = binary.BigEndian.AppendUint16(suite, kemID)
+	suite = binary.BigEndian.AppendUint16(suite, 0x0001)
+	suite = binary.BigEndian.AppendUint16(suite, 0xffff)
+
+	extract := func(salt []byte, label string, ikm []byte) []byte {
+		prk, err := hkdf.Extract(sha256.New, append(append(append([]byte("HPKE-v1"), suite...), label...), ikm...), salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prk
+	}
+	expand := func(prk []byte, label string, info []byte, n int) []byte {
+		labeled := binary.BigEndian.AppendUint16(nil, uint16(n))
+		labeled = append(append(append(append(labeled, "HPKE-v1"...), suite...), label...), info...)
+		out, err := hkdf.Expand(sha256.New, prk, string(labeled), n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	context := append([]byte{0}, extract(nil, "psk_id_hash", nil)...)
+	context = append(context, extract(nil, "info_hash", info)...)
+	secret := extract(ss, "secret", nil)
+	exporter := expand(secret, "exp", context, sha256.Size)
+	return expand(exporter, "sec", []byte(exporterContext), length)
+}
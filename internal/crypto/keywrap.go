@@ -0,0 +1,235 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// WrappingKeyBits is the size of the RSA keys key material is imported
+// under.
+const WrappingKeyBits = 3072
+
+// ErrUnwrap is returned for wrapped keys that fail their integrity check.
+var ErrUnwrap = errors.New("key unwrap failed")
+
+// kwIV is the default initial value of RFC 3394.
+var kwIV = [8]byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// kwpIV is the alternative initial value of RFC 5649, followed by the
+// 32-bit length of the key material.
+var kwpIV = [4]byte{0xa6, 0x59, 0x59, 0xa6}
+
+// WrapKey wraps key material under an AES key encryption key with AES-KW
+// (RFC 3394). The key material must be a multiple of 8 bytes and at least
+// 16 bytes long; WrapKeyWithPadding takes any length.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, errors.New("key wrap: key material must be a multiple of 8 bytes and at least 16 bytes")
+	}
+	return wrap(block, kwIV, key), nil
+}
+
+// UnwrapKey reverses WrapKey.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, ErrUnwrap
+	}
+	iv, key := unwrap(block, wrapped)
+	if subtle.ConstantTimeCompare(iv[:], kwIV[:]) != 1 {
+		clear(key)
+		return nil, ErrUnwrap
+	}
+	return key, nil
+}
+
+// WrapKeyWithPadding wraps key material of any length under an AES key
+// encryption key with AES-KWP (RFC 5649).
+func WrapKeyWithPadding(kek, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	if len(key) == 0 || uint64(len(key)) > 1<<32-1 {
+		return nil, errors.New("key wrap: key material must be 1 to 2^32-1 bytes")
+	}
+	var iv [8]byte
+	copy(iv[:], kwpIV[:])
+	binary.BigEndian.PutUint32(iv[4:], uint32(len(key)))
+	padded := make([]byte, (len(key)+7)/8*8)
+	copy(padded, key)
+	defer clear(padded)
+
+	if len(padded) == 8 {
+		// A single block is encrypted in one go, with the IV in front.
+		out := make([]byte, 16)
+		copy(out, iv[:])
+		copy(out[8:], padded)
+		block.Encrypt(out, out)
+		return out, nil
+	}
+	return wrap(block, iv, padded), nil
+}
+
+// UnwrapKeyWithPadding reverses WrapKeyWithPadding.
+func UnwrapKeyWithPadding(kek, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	if len(wrapped) < 16 || len(wrapped)%8 != 0 {
+		return nil, ErrUnwrap
+	}
+	var iv [8]byte
+	var padded []byte
+	if len(wrapped) == 16 {
+		out := make([]byte, 16)
+		block.Decrypt(out, wrapped)
+		copy(iv[:], out)
+		padded = out[8:]
+	} else {
+		iv, padded = unwrap(block, wrapped)
+	}
+
+	n := int(binary.BigEndian.Uint32(iv[4:]))
+	ok := subtle.ConstantTimeCompare(iv[:4], kwpIV[:]) == 1 &&
+		n > len(padded)-8 && n <= len(padded)
+	if ok {
+		for _, b := range padded[n:] {
+			ok = ok && b == 0
+		}
+	}
+	if !ok {
+		clear(padded)
+		return nil, ErrUnwrap
+	}
+	return padded[:n], nil
+}
+
+// wrap is the wrapping process W of RFC 3394 over 64-bit blocks.
+func wrap(block cipher.Block, iv [8]byte, plaintext []byte) []byte {
+	n := len(plaintext) / 8
+	out := make([]byte, 8+len(plaintext))
+	copy(out[8:], plaintext)
+	var b [16]byte
+	copy(b[:8], iv[:])
+	for j := range 6 {
+		for i := 1; i <= n; i++ {
+			r := out[8*i : 8*i+8]
+			copy(b[8:], r)
+			block.Encrypt(b[:], b[:])
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:8], binary.BigEndian.Uint64(b[:8])^t)
+			copy(r, b[8:])
+		}
+	}
+	copy(out, b[:8])
+	clear(b[:])
+	return out
+}
+
+// unwrap is the unwrapping process W^-1 of RFC 3394. It returns the
+// recovered initial value, which the caller checks.
+func unwrap(block cipher.Block, ciphertext []byte) ([8]byte, []byte) {
+	n := len(ciphertext)/8 - 1
+	out := make([]byte, 8*n)
+	copy(out, ciphertext[8:])
+	var b [16]byte
+	copy(b[:8], ciphertext[:8])
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			r := out[8*(i-1) : 8*i]
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:8], binary.BigEndian.Uint64(b[:8])^t)
+			copy(b[8:], r)
+			block.Decrypt(b[:], b[:])
+			copy(r, b[8:])
+		}
+	}
+	var iv [8]byte
+	copy(iv[:], b[:8])
+	clear(b[:])
+	return iv, out
+}
+
+// GenerateWrappingKey generates an RSA key for importing key material.
+func GenerateWrappingKey() (*rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, WrappingKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("generate rsa key: %w", err)
+	}
+	return key, nil
+}
+
+// WrapKeyRSAAES wraps key material for the holder of an RSA private key,
+// as PKCS#11 CKM_RSA_AES_KEY_WRAP does: a fresh AES-256 key encrypted with
+// RSA-OAEP (SHA-256 and MGF1-SHA-256, no label), followed by the key
+// material wrapped under that key with AES-KWP.
+func WrapKeyRSAAES(pub *rsa.PublicKey, key []byte) ([]byte, error) {
+	ephemeral, err := GenerateAESKey()
+	if err != nil {
+		return nil, err
+	}
+	defer clear(ephemeral)
+	encrypted, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, ephemeral, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rsa oaep: %w", err)
+	}
+	wrapped, err := WrapKeyWithPadding(ephemeral, key)
+	if err != nil {
+		return nil, err
+	}
+	return append(encrypted, wrapped...), nil
+}
+
+// WrapKeyRSAOAEP encrypts key material directly with RSA-OAEP (SHA-256
+// and MGF1-SHA-256, no label), as PKCS#11 CKM_RSA_PKCS_OAEP does. The key
+// material must fit in one RSA block: up to 190 bytes for a 2048-bit key.
+func WrapKeyRSAOAEP(pub *rsa.PublicKey, key []byte) ([]byte, error) {
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rsa oaep: %w", err)
+	}
+	return wrapped, nil
+}
+
+// UnwrapKeyRSAOAEP reverses WrapKeyRSAOAEP.
+func UnwrapKeyRSAOAEP(priv *rsa.PrivateKey, wrapped []byte) ([]byte, error) {
+	key, err := rsa.DecryptOAEP(sha256.New(), nil, priv, wrapped, nil)
+	if err != nil {
+		return nil, ErrUnwrap
+	}
+	return key, nil
+}
+
+// UnwrapKeyRSAAES reverses WrapKeyRSAAES. Every failure returns ErrUnwrap,
+// so callers cannot tell which step failed.
+func UnwrapKeyRSAAES(priv *rsa.PrivateKey, wrapped []byte) ([]byte, error) {
+	k := priv.Size()
+	if len(wrapped) <= k {
+		return nil, ErrUnwrap
+	}
+	ephemeral, err := rsa.DecryptOAEP(sha256.New(), nil, priv, wrapped[:k], nil)
+	if err != nil {
+		return nil, ErrUnwrap
+	}
+	defer clear(ephemeral)
+	if len(ephemeral) != 16 && len(ephemeral) != 24 && len(ephemeral) != 32 {
+		return nil, ErrUnwrap
+	}
+	return UnwrapKeyWithPadding(ephemeral, wrapped[k:])
+}
@@ -0,0 +1,181 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestKeyWrapVectors(t *testing.T) {
+	// RFC 3394, sections 4.1, 4.3 and 4.6.
+	tests := []struct {
+		kek, key, wrapped string
+	}{
+		{
+			"000102030405060708090a0b0c0d0e0f",
+			"00112233445566778899aabbccddeeff",
+			"1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5",
+		},
+		{
+			"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			"00112233445566778899aabbccddeeff",
+			"64e8c3f9ce0f5ba263e9777905818a2a93c8191e7d6e8ae7",
+		},
+		{
+			"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			"00112233445566778899aabbccddeeff000102030405060708090a0b0c0d0e0f",
+			"28c9f404c4b810f4cbccb35cfb87f8263f5786e2d80ed326cbc7f0e71a99f43bfb988b9b7a02dd21",
+		},
+	}
+	for _, tt := range tests {
+		kek, _ := hex.DecodeString(tt.kek)
+		key, _ := hex.DecodeString(tt.key)
+		want, _ := hex.DecodeString(tt.wrapped)
+		got, err := WrapKey(kek, key)
+		if err != nil {
+			t.Fatalf("wrap: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("wrap %s: got %x, want %x", tt.key, got, want)
+		}
+		unwrapped, err := UnwrapKey(kek, want)
+		if err != nil || !bytes.Equal(unwrapped, key) {
+			t.Fatalf("unwrap %s: got %x, %v", tt.wrapped, unwrapped, err)
+		}
+	}
+}
+
+func TestKeyWrapRejects(t *testing.T) {
+	kek, _ := GenerateAESKey()
+	key, _ := GenerateAESKey()
+	wrapped, err := WrapKey(kek, key)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	for i := range wrapped {
+		bad := bytes.Clone(wrapped)
+		bad[i] ^= 1
+		if _, err := UnwrapKey(kek, bad); !errors.Is(err, ErrUnwrap) {
+			t.Fatalf("flipped byte %d: got %v, want ErrUnwrap", i, err)
+		}
+	}
+	if _, err := UnwrapKey(kek, wrapped[:16]); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("truncated: got %v, want ErrUnwrap", err)
+	}
+	for _, n := range []int{8, 20} {
+		if _, err := WrapKey(kek, make([]byte, n)); err == nil {
+			t.Fatalf("%d bytes of key material should fail", n)
+		}
+	}
+}
+
+func TestKeyWrapWithPaddingVectors(t *testing.T) {
+	// RFC 5649, section 6.
+	kek, _ := hex.DecodeString("5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8")
+	tests := []struct {
+		key, wrapped string
+	}{
+		{"c37b7e6492584340bed12207808941155068f738", "138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a"},
+		{"466f7250617369", "afbeb0f07dfbf5419200f2ccb50bb24f"},
+	}
+	for _, tt := range tests {
+		key, _ := hex.DecodeString(tt.key)
+		want, _ := hex.DecodeString(tt.wrapped)
+		got, err := WrapKeyWithPadding(kek, key)
+		if err != nil {
+			t.Fatalf("wrap: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("wrap %s: got %x, want %x", tt.key, got, want)
+		}
+		unwrapped, err := UnwrapKeyWithPadding(kek, want)
+		if err != nil || !bytes.Equal(unwrapped, key) {
+			t.Fatalf("unwrap %s: got %x, %v", tt.wrapped, unwrapped, err)
+		}
+	}
+}
+
+func TestKeyWrapWithPaddingRejects(t *testing.T) {
+	kek, _ := GenerateAESKey()
+	wrapped, err := WrapKeyWithPadding(kek, []byte("0123456789abcdef0123"))
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	for i := range wrapped {
+		bad := bytes.Clone(wrapped)
+		bad[i] ^= 1
+		if _, err := UnwrapKeyWithPadding(kek, bad); !errors.Is(err, ErrUnwrap) {
+			t.Fatalf("flipped byte %d: got %v, want ErrUnwrap", i, err)
+		}
+	}
+	other, _ := GenerateAESKey()
+	if _, err := UnwrapKeyWithPadding(other, wrapped); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("wrong kek: got %v, want ErrUnwrap", err)
+	}
+	if _, err := UnwrapKeyWithPadding(kek, wrapped[:12]); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("truncated: got %v, want ErrUnwrap", err)
+	}
+	if _, err := WrapKeyWithPadding(kek, nil); err == nil {
+		t.Fatal("empty key material should fail")
+	}
+}
+
+func TestKeyWrapRSAAES(t *testing.T) {
+	// A small key keeps the test fast; the scheme does not depend on it.
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	key, _ := GenerateSymmetricKey(64)
+	wrapped, err := WrapKeyRSAAES(&priv.PublicKey, key)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	if len(wrapped) != priv.Size()+len(key)+8 {
+		t.Fatalf("wrapped length %d", len(wrapped))
+	}
+	got, err := UnwrapKeyRSAAES(priv, wrapped)
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("unwrap: %v", err)
+	}
+
+	bad := bytes.Clone(wrapped)
+	bad[0] ^= 1
+	if _, err := UnwrapKeyRSAAES(priv, bad); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("corrupt rsa part: got %v, want ErrUnwrap", err)
+	}
+	bad = bytes.Clone(wrapped)
+	bad[len(bad)-1] ^= 1
+	if _, err := UnwrapKeyRSAAES(priv, bad); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("corrupt kwp part: got %v, want ErrUnwrap", err)
+	}
+	if _, err := UnwrapKeyRSAAES(priv, wrapped[:priv.Size()]); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("no kwp part: got %v, want ErrUnwrap", err)
+	}
+}
+
+func TestKeyWrapRSAOAEP(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	key, _ := GenerateAESKey()
+	wrapped, err := WrapKeyRSAOAEP(&priv.PublicKey, key)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	got, err := UnwrapKeyRSAOAEP(priv, wrapped)
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("unwrap: %v", err)
+	}
+	wrapped[0] ^= 1
+	if _, err := UnwrapKeyRSAOAEP(priv, wrapped); !errors.Is(err, ErrUnwrap) {
+		t.Fatalf("corrupt: got %v, want ErrUnwrap", err)
+	}
+	if _, err := WrapKeyRSAOAEP(&priv.PublicKey, make([]byte, 191)); err == nil {
+		t.Fatal("key material larger than the rsa key allows should fail")
+	}
+}
@@ -0,0 +1,357 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+)
+
+// MACAlgorithm identifies a message authentication code construction.
+type MACAlgorithm int
+
+const (
+	// MACISO9797Alg1 is ISO 9797-1 MAC Algorithm 1 (CBC-MAC) with TDEA.
+	MACISO9797Alg1 MACAlgorithm = iota + 1
+	// MACISO9797Alg3 is ISO 9797-1 MAC Algorithm 3 (ANSI X9.19 retail MAC)
+	// with a double-length TDEA key.
+	MACISO9797Alg3
+	// MACAESCMAC is AES-CMAC as defined in NIST SP 800-38B / RFC 4493.
+	MACAESCMAC
+	MACHMACSHA256
+	MACHMACSHA512
+)
+
+func (a MACAlgorithm) String() string {
+	switch a {
+	case MACISO9797Alg1:
+		return "ISO9797_ALG1"
+	case MACISO9797Alg3:
+		return "ISO9797_ALG3"
+	case MACAESCMAC:
+		return "AES_CMAC"
+	case MACHMACSHA256:
+		return "HMAC_SHA256"
+	case MACHMACSHA512:
+		return "HMAC_SHA512"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MACPadding selects the ISO 9797-1 padding method for the CBC-MAC algorithms.
+// It is ignored by CMAC and HMAC, which define their own padding.
+type MACPadding int
+
+const (
+	// PaddingMethod1 appends zero bytes up to the block boundary
+	// (no padding when the data is already block aligned).
+	PaddingMethod1 MACPadding = iota + 1
+	// PaddingMethod2 appends a single 0x80 byte followed by zero bytes.
+	PaddingMethod2
+)
+
+// NewMAC returns a streaming MAC for the given algorithm and key.
+// The returned hash.Hash can be written to incrementally; Sum does not
+// change its state, so intermediate values may be taken.
+func NewMAC(alg MACAlgorithm, key []byte, padding MACPadding) (hash.Hash, error) {
+	switch alg {
+	case MACISO9797Alg1:
+		block, err := NewTDEACipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return newCBCMAC(block, nil, padding)
+	case MACISO9797Alg3:
+		if len(key) != 16 {
+			return nil, fmt.Errorf("iso 9797-1 algorithm 3 requires a 16-byte key, got %d", len(key))
+		}
+		k1, err := des.NewCipher(key[:8])
+		if err != nil {
+			return nil, fmt.Errorf("des new cipher: %w", err)
+		}
+		k2, err := des.NewCipher(key[8:])
+		if err != nil {
+			return nil, fmt.Errorf("des new cipher: %w", err)
+		}
+		return newCBCMAC(k1, k2, padding)
+	case MACAESCMAC:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("aes new cipher: %w", err)
+		}
+		return NewCMAC(block)
+	case MACHMACSHA256:
+		return hmac.New(sha256.New, key), nil
+	case MACHMACSHA512:
+		return hmac.New(sha512.New, key), nil
+	default:
+		return nil, fmt.Errorf("unsupported mac algorithm: %v", alg)
+	}
+}
+
+// ComputeMAC returns the full-length MAC of data.
+func ComputeMAC(alg MACAlgorithm, key, data []byte, padding MACPadding) ([]byte, error) {
+	m, err := NewMAC(alg, key, padding)
+	if err != nil {
+		return nil, err
+	}
+	m.Write(data)
+	return m.Sum(nil), nil
+}
+
+// VerifyMAC checks mac against the MAC of data in constant time.
+// mac may be truncated; only its leading bytes are compared.
+func VerifyMAC(alg MACAlgorithm, key, data, mac []byte, padding MACPadding) (bool, error) {
+	expected, err := ComputeMAC(alg, key, data, padding)
+	if err != nil {
+		return false, err
+	}
+	return EqualMAC(expected, mac), nil
+}
+
+// EqualMAC compares a received (possibly truncated) MAC with the full
+// computed value in constant time. An empty or over-long mac never matches.
+func EqualMAC(computed, mac []byte) bool {
+	if len(mac) == 0 || len(mac) > len(computed) {
+		return false
+	}
+	return subtle.ConstantTimeCompare(computed[:len(mac)], mac) == 1
+}
+
+// GenerateTDEAKey generates a random double- (16) or triple-length (24)
+// TDEA key with odd parity on every byte.
+func GenerateTDEAKey(length int) ([]byte, error) {
+	if length != 16 && length != 24 {
+		return nil, fmt.Errorf("invalid tdea key length: %d (must be 16 or 24)", length)
+	}
+	key := make([]byte, length)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate tdea key: %w", err)
+	}
+	AdjustDESParity(key)
+	return key, nil
+}
+
+// GenerateSymmetricKey generates size random bytes of key material.
+func GenerateSymmetricKey(size int) ([]byte, error) {
+	key := make([]byte, size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate symmetric key: %w", err)
+	}
+	return key, nil
+}
+
+// AdjustDESParity sets the low bit of each byte so it has odd parity.
+func AdjustDESParity(key []byte) {
+	for i, b := range key {
+		b &= 0xfe
+		ones := 0
+		for v := b; v != 0; v >>= 1 {
+			ones += int(v & 1)
+		}
+		if ones%2 == 0 {
+			b |= 1
+		}
+		key[i] = b
+	}
+}
+
+// NewTDEACipher builds a TDEA cipher, expanding a double-length key to K1|K2|K1.
+func NewTDEACipher(key []byte) (cipher.Block, error) {
+	var k []byte
+	switch len(key) {
+	case 16:
+		k = make([]byte, 0, 24)
+		k = append(k, key...)
+		k = append(k, key[:8]...)
+	case 24:
+		k = key
+	default:
+		return nil, fmt.Errorf("invalid tdea key length: %d (must be 16 or 24)", len(key))
+	}
+	block, err := des.NewTripleDESCipher(k)
+	if err != nil {
+		return nil, fmt.Errorf("tdea new cipher: %w", err)
+	}
+	return block, nil
+}
+
+// cbcMAC implements ISO 9797-1 MAC Algorithm 1 and, when final is set,
+// Algorithm 3 (the last chaining value is decrypted under final and
+// re-encrypted under block).
+type cbcMAC struct {
+	block   cipher.Block
+	final   cipher.Block
+	padding MACPadding
+	x       []byte // chaining value
+	buf     []byte // pending partial block
+	written bool
+}
+
+func newCBCMAC(block, final cipher.Block, padding MACPadding) (*cbcMAC, error) {
+	if padding == 0 {
+		padding = PaddingMethod1
+	}
+	if padding != PaddingMethod1 && padding != PaddingMethod2 {
+		return nil, fmt.Errorf("unsupported mac padding: %d", padding)
+	}
+	bs := block.BlockSize()
+	return &cbcMAC{
+		block:   block,
+		final:   final,
+		padding: padding,
+		x:       make([]byte, bs),
+		buf:     make([]byte, 0, bs),
+	}, nil
+}
+
+func (m *cbcMAC) Write(p []byte) (int, error) {
+	n := len(p)
+	if n > 0 {
+		m.written = true
+	}
+	bs := m.block.BlockSize()
+	for len(p) > 0 {
+		take := min(bs-len(m.buf), len(p))
+		m.buf = append(m.buf, p[:take]...)
+		p = p[take:]
+		if len(m.buf) == bs {
+			subtle.XORBytes(m.x, m.x, m.buf)
+			m.block.Encrypt(m.x, m.x)
+			m.buf = m.buf[:0]
+		}
+	}
+	return n, nil
+}
+
+func (m *cbcMAC) Sum(b []byte) []byte {
+	bs := m.block.BlockSize()
+	x := append([]byte(nil), m.x...)
+
+	last := make([]byte, bs)
+	copy(last, m.buf)
+	pad := false
+	switch m.padding {
+	case PaddingMethod1:
+		pad = len(m.buf) > 0 || !m.written
+	case PaddingMethod2:
+		last[len(m.buf)] = 0x80
+		pad = true
+	}
+	if pad {
+		subtle.XORBytes(x, x, last)
+		m.block.Encrypt(x, x)
+	}
+
+	if m.final != nil {
+		m.final.Decrypt(x, x)
+		m.block.Encrypt(x, x)
+	}
+	return append(b, x...)
+}
+
+func (m *cbcMAC) Reset() {
+	clear(m.x)
+	m.buf = m.buf[:0]
+	m.written = false
+}
+
+func (m *cbcMAC) Size() int      { return m.block.BlockSize() }
+func (m *cbcMAC) BlockSize() int { return m.block.BlockSize() }
+
+// cmac implements CMAC (NIST SP 800-38B) over a 64- or 128-bit block cipher.
+type cmac struct {
+	block  cipher.Block
+	k1, k2 []byte
+	x      []byte
+	buf    []byte // holds up to one full block until more data arrives
+}
+
+// NewCMAC returns a streaming CMAC over the given block cipher.
+func NewCMAC(block cipher.Block) (hash.Hash, error) {
+	bs := block.BlockSize()
+	var rb byte
+	switch bs {
+	case 8:
+		rb = 0x1b
+	case 16:
+		rb = 0x87
+	default:
+		return nil, fmt.Errorf("cmac: unsupported block size %d", bs)
+	}
+
+	l := make([]byte, bs)
+	block.Encrypt(l, l)
+	k1 := shiftSubkey(l, rb)
+	k2 := shiftSubkey(k1, rb)
+
+	return &cmac{
+		block: block,
+		k1:    k1,
+		k2:    k2,
+		x:     make([]byte, bs),
+		buf:   make([]byte, 0, bs),
+	}, nil
+}
+
+// shiftSubkey doubles in in GF(2^n), as used for CMAC subkey generation.
+func shiftSubkey(in []byte, rb byte) []byte {
+	out := make([]byte, len(in))
+	var carry byte
+	for i := len(in) - 1; i >= 0; i-- {
+		out[i] = in[i]<<1 | carry
+		carry = in[i] >> 7
+	}
+	if carry != 0 {
+		out[len(out)-1] ^= rb
+	}
+	return out
+}
+
+func (c *cmac) Write(p []byte) (int, error) {
+	n := len(p)
+	bs := c.block.BlockSize()
+	for len(p) > 0 {
+		if len(c.buf) == bs {
+			subtle.XORBytes(c.x, c.x, c.buf)
+			c.block.Encrypt(c.x, c.x)
+			c.buf = c.buf[:0]
+		}
+		take := min(bs-len(c.buf), len(p))
+		c.buf = append(c.buf, p[:take]...)
+		p = p[take:]
+	}
+	return n, nil
+}
+
+func (c *cmac) Sum(b []byte) []byte {
+	bs := c.block.BlockSize()
+	last := make([]byte, bs)
+	copy(last, c.buf)
+	if len(c.buf) == bs {
+		subtle.XORBytes(last, last, c.k1)
+	} else {
+		last[len(c.buf)] = 0x80
+		subtle.XORBytes(last, last, c.k2)
+	}
+
+	x := append([]byte(nil), c.x...)
+	subtle.XORBytes(x, x, last)
+	c.block.Encrypt(x, x)
+	return append(b, x...)
+}
+
+func (c *cmac) Reset() {
+	clear(c.x)
+	c.buf = c.buf[:0]
+}
+
+func (c *cmac) Size() int      { return c.block.BlockSize() }
+func (c *cmac) BlockSize() int { return c.block.BlockSize() }
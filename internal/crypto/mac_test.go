@@ -0,0 +1,219 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+	"testing"
+)
+
+func mustHex(t testing.TB, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode hex: %v", err)
+	}
+	return b
+}
+
+func TestAESCMACVectors(t *testing.T) {
+	// RFC 4493, section 4.
+	key := mustHex(t, "2b7e151628aed2a6abf7158809cf4f3c")
+	msg := mustHex(t, "6bc1bee22e409f96e93d7e117393172a"+
+		"ae2d8a571e03ac9c9eb76fac45af8e51"+
+		"30c81c46a35ce411e5fbc1191a0a52ef"+
+		"f69f2445df4f9b17ad2b417be66c3710")
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "bb1d6929e95937287fa37d129b756746"},
+		{16, "070a16b46b4d4144f79bdd9dd04a287c"},
+		{40, "dfa66747de9ae63030ca32611497c827"},
+		{64, "51f0bebf7e3b9d92fc49741779363cfe"},
+	}
+	for _, tt := range tests {
+		mac, err := ComputeMAC(MACAESCMAC, key, msg[:tt.n], 0)
+		if err != nil {
+			t.Fatalf("cmac: %v", err)
+		}
+		if got := hex.EncodeToString(mac); got != tt.want {
+			t.Fatalf("cmac len %d: got %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHMACSHA256Vector(t *testing.T) {
+	// RFC 4231, test case 2.
+	mac, err := ComputeMAC(MACHMACSHA256, []byte("Jefe"), []byte("what do ya want for nothing?"), 0)
+	if err != nil {
+		t.Fatalf("hmac: %v", err)
+	}
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got := hex.EncodeToString(mac); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestISO9797Alg1SingleLengthEquivalent(t *testing.T) {
+	// FIPS 113 / ANSI X9.9 example. A TDEA key with K1 == K2 degrades to
+	// single DES, so Algorithm 1 must reproduce the DES CBC-MAC.
+	key := mustHex(t, "0123456789abcdef0123456789abcdef")
+	data := []byte("7654321 Now is the time for ")
+
+	mac, err := ComputeMAC(MACISO9797Alg1, key, data, PaddingMethod1)
+	if err != nil {
+		t.Fatalf("mac: %v", err)
+	}
+	if got := hex.EncodeToString(mac); got != "f1d30f6849312ca4" {
+		t.Fatalf("got %s, want f1d30f6849312ca4", got)
+	}
+
+	// Algorithm 3 with K1 == K2 is the same computation.
+	mac3, err := ComputeMAC(MACISO9797Alg3, key, data, PaddingMethod1)
+	if err != nil {
+		t.Fatalf("mac alg3: %v", err)
+	}
+	if !bytes.Equal(mac, mac3) {
+		t.Fatalf("alg3 with K1 == K2 should equal alg1: %x != %x", mac3, mac)
+	}
+}
+
+func TestISO9797Alg3Vectors(t *testing.T) {
+	tests := []struct {
+		key, data, want string
+	}{
+		// ANSI X9.19 retail MAC example.
+		{"0123456789abcdeffedcba9876543210", "Now is the time for all ", "a1c72e74ea3fa9b6"},
+		// Bouncy Castle ISO9797Alg3MacTest.
+		{"7ca110454a1a6e570131d9619dc1376e", "Hello World !!!!", "f09b856213bab83b"},
+	}
+	for _, tt := range tests {
+		mac, err := ComputeMAC(MACISO9797Alg3, mustHex(t, tt.key), []byte(tt.data), PaddingMethod1)
+		if err != nil {
+			t.Fatalf("mac: %v", err)
+		}
+		if got := hex.EncodeToString(mac); got != tt.want {
+			t.Fatalf("%q: got %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestISO9797Alg3DiffersFromAlg1(t *testing.T) {
+	key, _ := GenerateTDEAKey(16)
+	data := []byte("0200B238000102C0000000000000001000")
+
+	mac1, _ := ComputeMAC(MACISO9797Alg1, key, data, PaddingMethod1)
+	mac3, _ := ComputeMAC(MACISO9797Alg3, key, data, PaddingMethod1)
+	if bytes.Equal(mac1, mac3) {
+		t.Fatal("algorithm 1 and 3 should differ for distinct key halves")
+	}
+}
+
+func TestISO9797Padding(t *testing.T) {
+	key, _ := GenerateTDEAKey(16)
+	aligned := []byte("12345678")
+
+	m1, _ := ComputeMAC(MACISO9797Alg1, key, aligned, PaddingMethod1)
+	m2, _ := ComputeMAC(MACISO9797Alg1, key, aligned, PaddingMethod2)
+	if bytes.Equal(m1, m2) {
+		t.Fatal("padding method 2 should add a block for aligned data")
+	}
+
+	// Method 1 cannot distinguish trailing zeros; method 2 can.
+	z1, _ := ComputeMAC(MACISO9797Alg1, key, []byte("abc"), PaddingMethod1)
+	z2, _ := ComputeMAC(MACISO9797Alg1, key, []byte("abc\x00"), PaddingMethod1)
+	if !bytes.Equal(z1, z2) {
+		t.Fatal("padding method 1 should zero-fill")
+	}
+	z1, _ = ComputeMAC(MACISO9797Alg1, key, []byte("abc"), PaddingMethod2)
+	z2, _ = ComputeMAC(MACISO9797Alg1, key, []byte("abc\x00"), PaddingMethod2)
+	if bytes.Equal(z1, z2) {
+		t.Fatal("padding method 2 should be unambiguous")
+	}
+}
+
+func TestMACStreamingMatchesOneShot(t *testing.T) {
+	data := bytes.Repeat([]byte("streaming mac data "), 100)
+	tdea, _ := GenerateTDEAKey(24)
+	aesKey, _ := GenerateAESKey()
+
+	cases := []struct {
+		alg MACAlgorithm
+		key []byte
+	}{
+		{MACISO9797Alg1, tdea},
+		{MACISO9797Alg3, tdea[:16]},
+		{MACAESCMAC, aesKey},
+		{MACHMACSHA256, aesKey},
+		{MACHMACSHA512, aesKey},
+	}
+	for _, c := range cases {
+		want, err := ComputeMAC(c.alg, c.key, data, PaddingMethod2)
+		if err != nil {
+			t.Fatalf("%v: %v", c.alg, err)
+		}
+
+		m, _ := NewMAC(c.alg, c.key, PaddingMethod2)
+		for chunk := range slices.Chunk(data, 7) {
+			m.Write(chunk)
+		}
+		if got := m.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("%v: streaming mac mismatch", c.alg)
+		}
+		// Sum must not disturb the running state.
+		if got := m.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("%v: second Sum differs", c.alg)
+		}
+	}
+}
+
+func TestVerifyMACTruncated(t *testing.T) {
+	key, _ := GenerateTDEAKey(16)
+	data := []byte("iso 8583 message")
+
+	mac, _ := ComputeMAC(MACISO9797Alg3, key, data, PaddingMethod1)
+
+	ok, err := VerifyMAC(MACISO9797Alg3, key, data, mac[:4], PaddingMethod1)
+	if err != nil || !ok {
+		t.Fatalf("truncated mac should verify: %v", err)
+	}
+
+	bad := append([]byte(nil), mac[:4]...)
+	bad[0] ^= 1
+	if ok, _ := VerifyMAC(MACISO9797Alg3, key, data, bad, PaddingMethod1); ok {
+		t.Fatal("tampered mac should not verify")
+	}
+	if ok, _ := VerifyMAC(MACISO9797Alg3, key, data, nil, PaddingMethod1); ok {
+		t.Fatal("empty mac should not verify")
+	}
+}
+
+func TestMACInvalidKey(t *testing.T) {
+	if _, err := NewMAC(MACISO9797Alg3, make([]byte, 24), PaddingMethod1); err == nil {
+		t.Fatal("algorithm 3 should reject triple-length keys")
+	}
+	if _, err := NewMAC(MACISO9797Alg1, make([]byte, 8), PaddingMethod1); err == nil {
+		t.Fatal("algorithm 1 should reject single-length keys")
+	}
+	if _, err := NewMAC(MACAESCMAC, make([]byte, 10), 0); err == nil {
+		t.Fatal("cmac should reject invalid aes key sizes")
+	}
+}
+
+func TestGenerateTDEAKeyParity(t *testing.T) {
+	key, err := GenerateTDEAKey(16)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for i, b := range key {
+		ones := 0
+		for v := b; v != 0; v >>= 1 {
+			ones += int(v & 1)
+		}
+		if ones%2 != 1 {
+			t.Fatalf("byte %d (%02x) does not have odd parity", i, b)
+		}
+	}
+}
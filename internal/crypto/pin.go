@@ -0,0 +1,202 @@
+package crypto
+
+import (
+	"crypto/des"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PINBlockFormat identifies an ISO 9564-1 PIN block format.
+type PINBlockFormat int
+
+const (
+	// PINBlockISO0 (ANSI X9.8) XORs the PIN field with the account number.
+	PINBlockISO0 PINBlockFormat = iota + 1
+	// PINBlockISO1 uses random fill and no account number.
+	PINBlockISO1
+	// PINBlockISO3 is format 0 with random fill from A-F.
+	PINBlockISO3
+)
+
+func (f PINBlockFormat) String() string {
+	switch f {
+	case PINBlockISO0:
+		return "ISO0"
+	case PINBlockISO1:
+		return "ISO1"
+	case PINBlockISO3:
+		return "ISO3"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+var (
+	// ErrPINLength is returned for PINs shorter than 4 or longer than 12 digits.
+	ErrPINLength = errors.New("pin must be 4-12 digits")
+	// ErrPINBlock is returned when a PIN block does not decode to a valid PIN.
+	ErrPINBlock = errors.New("invalid pin block")
+)
+
+// EncodePINBlock builds the clear 8-byte PIN block for pin. account is the
+// 12 rightmost PAN digits excluding the check digit; format 1 ignores it.
+func EncodePINBlock(format PINBlockFormat, pin, account string) ([]byte, error) {
+	if len(pin) < 4 || len(pin) > 12 || !isDigits(pin) {
+		return nil, ErrPINLength
+	}
+
+	var control byte
+	switch format {
+	case PINBlockISO0:
+		control = '0'
+	case PINBlockISO1:
+		control = '1'
+	case PINBlockISO3:
+		control = '3'
+	default:
+		return nil, fmt.Errorf("unsupported pin block format: %v", format)
+	}
+
+	field := []byte{control, "0123456789ABC"[len(pin)]}
+	field = append(field, pin...)
+	fill := make([]byte, 16-len(field))
+	if format != PINBlockISO0 {
+		if _, err := rand.Read(fill); err != nil {
+			return nil, fmt.Errorf("pin block fill: %w", err)
+		}
+	}
+	for _, b := range fill {
+		switch format {
+		case PINBlockISO0:
+			field = append(field, 'F')
+		case PINBlockISO1:
+			field = append(field, "0123456789ABCDEF"[b&0x0f])
+		case PINBlockISO3:
+			field = append(field, "ABCDEF"[int(b)%6])
+		}
+	}
+
+	block, _ := hex.DecodeString(string(field))
+	if format == PINBlockISO1 {
+		return block, nil
+	}
+	acct, err := accountField(account)
+	if err != nil {
+		return nil, err
+	}
+	subtle.XORBytes(block, block, acct)
+	return block, nil
+}
+
+// DecodePINBlock recovers the PIN from a clear 8-byte PIN block.
+func DecodePINBlock(format PINBlockFormat, block []byte, account string) (string, error) {
+	if len(block) != 8 {
+		return "", ErrPINBlock
+	}
+	clearBlock := append([]byte(nil), block...)
+	if format != PINBlockISO1 {
+		acct, err := accountField(account)
+		if err != nil {
+			return "", err
+		}
+		subtle.XORBytes(clearBlock, clearBlock, acct)
+	}
+
+	field := strings.ToUpper(hex.EncodeToString(clearBlock))
+	var control byte
+	var fill string
+	switch format {
+	case PINBlockISO0:
+		control, fill = '0', "F"
+	case PINBlockISO1:
+		control, fill = '1', "0123456789ABCDEF"
+	case PINBlockISO3:
+		control, fill = '3', "ABCDEF"
+	default:
+		return "", fmt.Errorf("unsupported pin block format: %v", format)
+	}
+	if field[0] != control {
+		return "", ErrPINBlock
+	}
+	n := strings.IndexByte("0123456789ABC", field[1])
+	if n < 4 {
+		return "", ErrPINBlock
+	}
+	pin := field[2 : 2+n]
+	if !isDigits(pin) {
+		return "", ErrPINBlock
+	}
+	for i := 2 + n; i < len(field); i++ {
+		if !strings.ContainsRune(fill, rune(field[i])) {
+			return "", ErrPINBlock
+		}
+	}
+	return pin, nil
+}
+
+func accountField(account string) ([]byte, error) {
+	if len(account) != 12 || !isDigits(account) {
+		return nil, errors.New("account number must be 12 digits")
+	}
+	field, _ := hex.DecodeString("0000" + account)
+	return field, nil
+}
+
+// ComputeCVV returns the 3-digit Visa CVV (or Mastercard CVC) for a
+// double-length CVK holding the CVK A and CVK B halves.
+func ComputeCVV(cvk []byte, pan, expiry, serviceCode string) (string, error) {
+	if len(cvk) != 16 {
+		return "", fmt.Errorf("cvk must be 16 bytes, got %d", len(cvk))
+	}
+	data := pan + expiry + serviceCode
+	if !isDigits(data) || len(data) > 32 {
+		return "", errors.New("pan, expiry and service code must be at most 32 digits")
+	}
+	data += strings.Repeat("0", 32-len(data))
+	blocks, _ := hex.DecodeString(data)
+
+	ka, err := des.NewCipher(cvk[:8])
+	if err != nil {
+		return "", fmt.Errorf("des new cipher: %w", err)
+	}
+	kab, err := NewTDEACipher(cvk)
+	if err != nil {
+		return "", err
+	}
+
+	x := make([]byte, 8)
+	ka.Encrypt(x, blocks[:8])
+	subtle.XORBytes(x, x, blocks[8:])
+	kab.Encrypt(x, x)
+	return decimalize(strings.ToUpper(hex.EncodeToString(x)), 3), nil
+}
+
+// decimalize extracts n decimal digits from a hex string: first the digits
+// in order, then the letters A-F mapped to 0-5.
+func decimalize(h string, n int) string {
+	out := make([]byte, 0, n)
+	for i := 0; i < len(h) && len(out) < n; i++ {
+		if h[i] <= '9' {
+			out = append(out, h[i])
+		}
+	}
+	for i := 0; i < len(h) && len(out) < n; i++ {
+		if h[i] >= 'A' {
+			out = append(out, h[i]-'A'+'0')
+		}
+	}
+	return string(out)
+}
+
+func isDigits(s string) bool {
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
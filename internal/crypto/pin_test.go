@@ -0,0 +1,55 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPINBlockISO0(t *testing.T) {
+	// PIN field 041234FFFFFFFFFF XOR account field 0000111111111111.
+	block, err := EncodePINBlock(PINBlockISO0, "1234", "111111111111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(block, mustHex(t, "041225EEEEEEEEEE")) {
+		t.Fatalf("got %X", block)
+	}
+	pin, err := DecodePINBlock(PINBlockISO0, block, "111111111111")
+	if err != nil || pin != "1234" {
+		t.Fatalf("decode: %q, %v", pin, err)
+	}
+}
+
+func TestPINBlockRoundTrip(t *testing.T) {
+	for _, f := range []PINBlockFormat{PINBlockISO0, PINBlockISO1, PINBlockISO3} {
+		for _, pin := range []string{"0000", "98765", "123456789012"} {
+			block, err := EncodePINBlock(f, pin, "400000123456")
+			if err != nil {
+				t.Fatalf("%v %s: %v", f, pin, err)
+			}
+			got, err := DecodePINBlock(f, block, "400000123456")
+			if err != nil || got != pin {
+				t.Fatalf("%v %s: got %q, %v", f, pin, got, err)
+			}
+		}
+	}
+
+	if _, err := EncodePINBlock(PINBlockISO0, "123", "111111111111"); err != ErrPINLength {
+		t.Fatalf("expected ErrPINLength, got %v", err)
+	}
+	block, _ := EncodePINBlock(PINBlockISO3, "1234", "111111111111")
+	if _, err := DecodePINBlock(PINBlockISO0, block, "111111111111"); err != ErrPINBlock {
+		t.Fatalf("format mismatch: expected ErrPINBlock, got %v", err)
+	}
+}
+
+func TestComputeCVV(t *testing.T) {
+	cvk := mustHex(t, "0123456789ABCDEFFEDCBA9876543210")
+	cvv, err := ComputeCVV(cvk, "4123456789012345", "8701", "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cvv != "561" {
+		t.Fatalf("got %s, want 561", cvv)
+	}
+}
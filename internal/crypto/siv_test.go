@@ -0,0 +1,97 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestSIVVectors(t *testing.T) {
+	// RFC 5297 appendix A.
+	tests := []struct {
+		name      string
+		key       string
+		ad        []string
+		plaintext string
+		want      string
+	}{
+		{
+			name:      "A.1 deterministic",
+			key:       "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+			ad:        []string{"101112131415161718191a1b1c1d1e1f2021222324252627"},
+			plaintext: "112233445566778899aabbccddee",
+			want:      "85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c",
+		},
+		{
+			name: "A.2 nonce-based",
+			key:  "7f7e7d7c7b7a79787776757473727170404142434445464748494a4b4c4d4e4f",
+			ad: []string{
+				"00112233445566778899aabbccddeeffdeaddadadeaddadaffeeddccbbaa99887766554433221100",
+				"102030405060708090a0",
+				"09f911029d74e35bd84156c5635688c0",
+			},
+			plaintext: "7468697320697320736f6d6520706c61696e7465787420746f20656e6372797074207573696e67205349562d414553",
+			want:      "7bdb6e3b432667eb06f4d14bff2fbd0fcb900f2fddbe404326601965c889bf17dba77ceb094fa663b7a3f748ba8af829ea64ad544a272e9c485b62a3fd5c0d",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _ := hex.DecodeString(tt.key)
+			plaintext, _ := hex.DecodeString(tt.plaintext)
+			var ad [][]byte
+			for _, a := range tt.ad {
+				b, _ := hex.DecodeString(a)
+				ad = append(ad, b)
+			}
+
+			ct, err := SealSIV(key, plaintext, ad...)
+			if err != nil {
+				t.Fatalf("seal: %v", err)
+			}
+			if got := hex.EncodeToString(ct); got != tt.want {
+				t.Fatalf("ciphertext = %s, want %s", got, tt.want)
+			}
+
+			pt, err := OpenSIV(key, ct, ad...)
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			if !bytes.Equal(pt, plaintext) {
+				t.Fatalf("plaintext mismatch: got %x", pt)
+			}
+		})
+	}
+}
+
+func TestSIVDeterministic(t *testing.T) {
+	key, _ := GenerateSymmetricKey(64)
+	ct1, _ := SealSIV(key, []byte("alice@example.com"), []byte("email"))
+	ct2, _ := SealSIV(key, []byte("alice@example.com"), []byte("email"))
+	if !bytes.Equal(ct1, ct2) {
+		t.Fatal("equal inputs should produce equal ciphertexts")
+	}
+	ct3, _ := SealSIV(key, []byte("alice@example.com"), []byte("name"))
+	if bytes.Equal(ct1, ct3) {
+		t.Fatal("different associated data should change the ciphertext")
+	}
+}
+
+func TestSIVRejectsTampering(t *testing.T) {
+	key, _ := GenerateSymmetricKey(64)
+	ct, _ := SealSIV(key, []byte("short"), []byte("aad"))
+
+	if _, err := OpenSIV(key, ct, []byte("other")); !errors.Is(err, ErrSIVAuth) {
+		t.Fatalf("wrong aad: got %v, want ErrSIVAuth", err)
+	}
+	ct[len(ct)-1] ^= 1
+	if _, err := OpenSIV(key, ct, []byte("aad")); !errors.Is(err, ErrSIVAuth) {
+		t.Fatalf("tampered ciphertext: got %v, want ErrSIVAuth", err)
+	}
+	if _, err := OpenSIV(key, ct[:SIVTagSize-1]); err == nil {
+		t.Fatal("short ciphertext should fail")
+	}
+	if _, err := SealSIV(key[:16], []byte("x")); err == nil {
+		t.Fatal("16-byte key should be rejected")
+	}
+}
@@ -0,0 +1,289 @@
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Streaming encryption splits a message into segments sealed with the
+// STREAM construction (Hoang et al., "Online Authenticated-Encryption and
+// its Nonce-Reuse Misuse-Resistance"). The ciphertext is
+//
+//	header | segment 0 | segment 1 | ... | final segment
+//
+// with a header of
+//
+//	version (1) | algorithm (1) | segment size (4) | salt (32) | nonce prefix (7)
+//
+// Each stream is sealed under its own 256-bit key, HKDF-SHA256(key, salt,
+// info) with info binding the version, algorithm and segment size.
+// Segment i is sealed with the nonce prefix | i (4 bytes, big endian) |
+// last (1 byte), so reordered, dropped or truncated segments fail to open.
+// Every segment but the last carries exactly segment size bytes of
+// plaintext.
+const (
+	streamVersion    = 1
+	streamSaltSize   = 32
+	streamPrefixSize = 7
+	// StreamHeaderSize is the length of the header that starts a stream.
+	StreamHeaderSize = 2 + 4 + streamSaltSize + streamPrefixSize
+
+	// DefaultStreamSegmentSize is the plaintext length of a full segment.
+	DefaultStreamSegmentSize = 64 << 10
+	// MinStreamSegmentSize and MaxStreamSegmentSize bound the segment
+	// size a stream may use.
+	MinStreamSegmentSize = 1 << 10
+	MaxStreamSegmentSize = 4 << 20
+)
+
+// ErrStreamTruncated is returned when a stream ends before its final
+// segment.
+var ErrStreamTruncated = errors.New("stream truncated")
+
+type streamState struct {
+	aead    cipher.AEAD
+	prefix  []byte
+	aad     []byte
+	segment int
+	seq     uint64
+}
+
+func newStreamState(key, header, aad []byte) (*streamState, error) {
+	alg := AEADAlgorithm(header[1])
+	segment := int(binary.BigEndian.Uint32(header[2:6]))
+	if segment < MinStreamSegmentSize || segment > MaxStreamSegmentSize {
+		return nil, fmt.Errorf("invalid stream segment size %d", segment)
+	}
+
+	salt := header[6 : 6+streamSaltSize]
+	info := append([]byte("vault-stream"), header[:6]...)
+	streamKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, info), streamKey); err != nil {
+		return nil, fmt.Errorf("hkdf derive: %w", err)
+	}
+	// XChaCha20 keys seal segments with ChaCha20-Poly1305: nonces are unique
+	// under a per-stream key, so the extended nonce buys nothing here.
+	segAlg := alg
+	if alg == AEADXChaCha20Poly1305 {
+		segAlg = AEADChaCha20Poly1305
+	}
+	aead, err := segAlg.cipher(streamKey)
+	clear(streamKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamState{
+		aead:    aead,
+		prefix:  header[6+streamSaltSize : StreamHeaderSize],
+		aad:     aad,
+		segment: segment,
+	}, nil
+}
+
+func (s *streamState) nonce(last bool) ([]byte, error) {
+	if s.seq > math.MaxUint32 {
+		return nil, errors.New("stream has too many segments")
+	}
+	nonce := make([]byte, 0, s.aead.NonceSize())
+	nonce = append(nonce, s.prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, uint32(s.seq))
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+	s.seq++
+	return nonce, nil
+}
+
+// StreamEncrypter seals a message of any length written to it in pieces.
+// Ciphertext goes to the underlying writer one header or segment per
+// Write, so memory use is bounded by the segment size.
+type StreamEncrypter struct {
+	w      io.Writer
+	state  *streamState
+	buf    []byte
+	closed bool
+}
+
+// NewStreamEncrypter starts a stream sealed under alg and key, writing the
+// header to w. aad is authenticated with every segment and must be given
+// again to decrypt. A segmentSize of 0 selects DefaultStreamSegmentSize.
+func NewStreamEncrypter(w io.Writer, alg AEADAlgorithm, key, aad []byte, segmentSize int) (*StreamEncrypter, error) {
+	if segmentSize == 0 {
+		segmentSize = DefaultStreamSegmentSize
+	}
+	if segmentSize < MinStreamSegmentSize || segmentSize > MaxStreamSegmentSize {
+		return nil, fmt.Errorf("invalid stream segment size %d", segmentSize)
+	}
+
+	header := make([]byte, StreamHeaderSize)
+	header[0] = streamVersion
+	header[1] = byte(alg)
+	binary.BigEndian.PutUint32(header[2:6], uint32(segmentSize))
+	if _, err := rand.Read(header[6:]); err != nil {
+		return nil, fmt.Errorf("generate stream salt: %w", err)
+	}
+	state, err := newStreamState(key, header, aad)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &StreamEncrypter{w: w, state: state, buf: make([]byte, 0, segmentSize)}, nil
+}
+
+// Write buffers plaintext and seals every full segment that is followed by
+// more data. The last segment is only sealed by Close.
+func (e *StreamEncrypter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed stream")
+	}
+	n := len(p)
+	for len(p) > 0 {
+		if len(e.buf) == e.state.segment {
+			if err := e.seal(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		k := min(len(p), e.state.segment-len(e.buf))
+		e.buf = append(e.buf, p[:k]...)
+		p = p[k:]
+	}
+	return n, nil
+}
+
+// Close seals the final segment. A stream that is never closed cannot be
+// decrypted.
+func (e *StreamEncrypter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *StreamEncrypter) seal(last bool) error {
+	nonce, err := e.state.nonce(last)
+	if err != nil {
+		return err
+	}
+	ct := e.state.aead.Seal(nil, nonce, e.buf, e.state.aad)
+	clear(e.buf)
+	e.buf = e.buf[:0]
+	_, err = e.w.Write(ct)
+	return err
+}
+
+// StreamDecrypter opens a stream produced by StreamEncrypter. Ciphertext
+// may be written to it in pieces of any size; each segment's plaintext is
+// written to the underlying writer once it authenticates.
+//
+// Plaintext is released segment by segment, before the end of the stream
+// is known. A consumer must discard everything it received if Close
+// reports an error, since the stream may have been truncated.
+type StreamDecrypter struct {
+	w      io.Writer
+	alg    AEADAlgorithm
+	key    []byte
+	aad    []byte
+	header []byte
+	state  *streamState
+	buf    []byte
+	closed bool
+}
+
+// NewStreamDecrypter returns a StreamDecrypter writing plaintext to w. The
+// stream header must record alg.
+func NewStreamDecrypter(w io.Writer, alg AEADAlgorithm, key, aad []byte) *StreamDecrypter {
+	return &StreamDecrypter{w: w, alg: alg, key: key, aad: aad, header: make([]byte, 0, StreamHeaderSize)}
+}
+
+// Write consumes ciphertext, opening every full segment that is followed
+// by more data.
+func (d *StreamDecrypter) Write(p []byte) (int, error) {
+	if d.closed {
+		return 0, errors.New("write to closed stream")
+	}
+	n := len(p)
+	if d.state == nil {
+		k := min(len(p), StreamHeaderSize-len(d.header))
+		d.header = append(d.header, p[:k]...)
+		p = p[k:]
+		if len(d.header) < StreamHeaderSize {
+			return n, nil
+		}
+		if err := d.start(); err != nil {
+			return n - len(p), err
+		}
+	}
+
+	full := d.state.segment + d.state.aead.Overhead()
+	for len(p) > 0 {
+		if len(d.buf) == full {
+			if err := d.open(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		k := min(len(p), full-len(d.buf))
+		d.buf = append(d.buf, p[:k]...)
+		p = p[k:]
+	}
+	return n, nil
+}
+
+// Close opens the final segment. It returns ErrStreamTruncated if the
+// ciphertext ended early.
+func (d *StreamDecrypter) Close() error {
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	if d.state == nil {
+		return ErrStreamTruncated
+	}
+	return d.open(true)
+}
+
+func (d *StreamDecrypter) start() error {
+	if d.header[0] != streamVersion {
+		return fmt.Errorf("unsupported stream version %d", d.header[0])
+	}
+	if alg := AEADAlgorithm(d.header[1]); alg != d.alg {
+		return fmt.Errorf("stream was encrypted with %v, key uses %v", alg, d.alg)
+	}
+	state, err := newStreamState(d.key, d.header, d.aad)
+	if err != nil {
+		return err
+	}
+	d.state = state
+	d.buf = make([]byte, 0, state.segment+state.aead.Overhead())
+	return nil
+}
+
+func (d *StreamDecrypter) open(last bool) error {
+	nonce, err := d.state.nonce(last)
+	if err != nil {
+		return err
+	}
+	pt, err := d.state.aead.Open(nil, nonce, d.buf, d.state.aad)
+	if err != nil {
+		if last {
+			return fmt.Errorf("%w or corrupt final segment", ErrStreamTruncated)
+		}
+		return fmt.Errorf("segment %d: %w", d.state.seq-1, err)
+	}
+	d.buf = d.buf[:0]
+	_, err = d.w.Write(pt)
+	return err
+}
@@ -0,0 +1,136 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+// encryptStream seals plaintext, writing it in pieces of chunk bytes.
+func encryptStream(t *testing.T, alg AEADAlgorithm, key, aad, plaintext []byte, segment, chunk int) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	e, err := NewStreamEncrypter(&out, alg, key, aad, segment)
+	if err != nil {
+		t.Fatalf("new encrypter: %v", err)
+	}
+	for p := plaintext; len(p) > 0; {
+		k := min(chunk, len(p))
+		if _, err := e.Write(p[:k]); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		p = p[k:]
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return out.Bytes()
+}
+
+func decryptStream(alg AEADAlgorithm, key, aad, ciphertext []byte, chunk int) ([]byte, error) {
+	var out bytes.Buffer
+	d := NewStreamDecrypter(&out, alg, key, aad)
+	for p := ciphertext; len(p) > 0; {
+		k := min(chunk, len(p))
+		if _, err := d.Write(p[:k]); err != nil {
+			return nil, err
+		}
+		p = p[k:]
+	}
+	if err := d.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	const segment = 1024
+	sizes := []int{0, 1, segment - 1, segment, segment + 1, 3 * segment, 5*segment + 17}
+	for _, alg := range []AEADAlgorithm{AEADAESGCM, AEADChaCha20Poly1305, AEADXChaCha20Poly1305} {
+		key, _ := GenerateSymmetricKey(32)
+		aad := []byte("recon-2024-06-01.csv")
+		for _, size := range sizes {
+			plaintext := make([]byte, size)
+			rand.Read(plaintext)
+			for _, chunk := range []int{1, 100, segment, 4096} {
+				ct := encryptStream(t, alg, key, aad, plaintext, segment, chunk)
+				segments := max(1, (size+segment-1)/segment)
+				if want := StreamHeaderSize + size + 16*segments; len(ct) != want {
+					t.Fatalf("%v size %d: ciphertext length %d, want %d", alg, size, len(ct), want)
+				}
+				pt, err := decryptStream(alg, key, aad, ct, chunk+7)
+				if err != nil {
+					t.Fatalf("%v size %d chunk %d: decrypt: %v", alg, size, chunk, err)
+				}
+				if !bytes.Equal(pt, plaintext) {
+					t.Fatalf("%v size %d chunk %d: plaintext mismatch", alg, size, chunk)
+				}
+			}
+		}
+	}
+}
+
+func TestStreamRejectsTampering(t *testing.T) {
+	const segment = 1024
+	key, _ := GenerateSymmetricKey(32)
+	plaintext := make([]byte, 3*segment+10)
+	ct := encryptStream(t, AEADAESGCM, key, nil, plaintext, segment, 4096)
+	seg := segment + 16
+	body := ct[StreamHeaderSize:]
+
+	t.Run("truncated at segment boundary", func(t *testing.T) {
+		cut := ct[:StreamHeaderSize+2*seg]
+		if _, err := decryptStream(AEADAESGCM, key, nil, cut, 4096); !errors.Is(err, ErrStreamTruncated) {
+			t.Fatalf("got %v, want ErrStreamTruncated", err)
+		}
+	})
+	t.Run("header only", func(t *testing.T) {
+		if _, err := decryptStream(AEADAESGCM, key, nil, ct[:StreamHeaderSize-1], 4096); !errors.Is(err, ErrStreamTruncated) {
+			t.Fatalf("got %v, want ErrStreamTruncated", err)
+		}
+	})
+	t.Run("reordered segments", func(t *testing.T) {
+		swapped := append([]byte(nil), ct[:StreamHeaderSize]...)
+		swapped = append(swapped, body[seg:2*seg]...)
+		swapped = append(swapped, body[:seg]...)
+		swapped = append(swapped, body[2*seg:]...)
+		if _, err := decryptStream(AEADAESGCM, key, nil, swapped, 4096); err == nil {
+			t.Fatal("reordered stream should fail")
+		}
+	})
+	t.Run("appended segment", func(t *testing.T) {
+		extended := append(append([]byte(nil), ct...), body[:seg]...)
+		if _, err := decryptStream(AEADAESGCM, key, nil, extended, 4096); err == nil {
+			t.Fatal("extended stream should fail")
+		}
+	})
+	t.Run("wrong aad", func(t *testing.T) {
+		if _, err := decryptStream(AEADAESGCM, key, []byte("other"), ct, 4096); err == nil {
+			t.Fatal("wrong aad should fail")
+		}
+	})
+	t.Run("segment size relabelled", func(t *testing.T) {
+		relabelled := append([]byte(nil), ct...)
+		relabelled[4] ^= 0x08
+		if _, err := decryptStream(AEADAESGCM, key, nil, relabelled, 4096); err == nil {
+			t.Fatal("relabelled header should fail")
+		}
+	})
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		if _, err := decryptStream(AEADChaCha20Poly1305, key, nil, ct, 4096); err == nil {
+			t.Fatal("stream from another algorithm should fail")
+		}
+	})
+}
+
+func TestStreamSegmentSizeBounds(t *testing.T) {
+	key, _ := GenerateSymmetricKey(32)
+	var out bytes.Buffer
+	if _, err := NewStreamEncrypter(&out, AEADAESGCM, key, nil, 512); err == nil {
+		t.Fatal("segment size below the minimum should be rejected")
+	}
+	if _, err := NewStreamEncrypter(&out, AEADAESGCM, key, nil, 8<<20); err == nil {
+		t.Fatal("segment size above the maximum should be rejected")
+	}
+}
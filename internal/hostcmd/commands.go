@@ -0,0 +1,457 @@
+package hostcmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+	"github.com/glinharesb/vault-go/internal/server"
+)
+
+// hostError is a two-digit payShield error code.
+type hostError string
+
+func (e hostError) Error() string { return "host command error " + string(e) }
+
+const (
+	errVerification hostError = "01"
+	errKeyType      hostError = "04"
+	// errKey reports a key reference that is unknown or may not be used
+	// for the command.
+	errKey         hostError = "10"
+	errInput       hostError = "15"
+	errPINBlock    hostError = "20"
+	errPINFormat   hostError = "23"
+	errPINLength   hostError = "24"
+	errUnsupported hostError = "68"
+)
+
+// principal identifies host command traffic to the vault services.
+var principal = &interceptor.Principal{Name: "hostcmd"}
+
+// KeyLabel marks the keys host commands may use: only keys labelled
+// hostcmd=true, which A0 sets on the keys it generates. Other vault keys
+// are reported as unknown.
+const KeyLabel = "hostcmd"
+
+// keyTypes maps the A0 key type codes to vault key purposes. Type 002 is
+// shared by TPKs and PVKs on a payShield; the vault treats it as a TPK.
+var keyTypes = map[string]pb.KeyPurpose{
+	"000": pb.KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION,    // ZMK
+	"001": pb.KeyPurpose_KEY_PURPOSE_PIN_ENCRYPTION,    // ZPK
+	"002": pb.KeyPurpose_KEY_PURPOSE_PIN_ENCRYPTION,    // TPK
+	"003": pb.KeyPurpose_KEY_PURPOSE_MAC,               // TAK
+	"008": pb.KeyPurpose_KEY_PURPOSE_MAC,               // ZAK
+	"00A": pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION,   // ZEK
+	"402": pb.KeyPurpose_KEY_PURPOSE_CARD_VERIFICATION, // CVK
+}
+
+// pinFormats maps payShield PIN block format codes to ISO 9564-1 formats.
+var pinFormats = map[string]crypto.PINBlockFormat{
+	"01": crypto.PINBlockISO0,
+	"05": crypto.PINBlockISO1,
+	"47": crypto.PINBlockISO3,
+}
+
+type command func(h *Handler, ctx context.Context, peer string, r *reader) (string, error)
+
+var commands = map[string]command{
+	"A0": (*Handler).generateKey,
+	"CA": (*Handler).translatePIN,
+	"CC": (*Handler).translatePIN,
+	"CW": (*Handler).generateCVV,
+	"CY": (*Handler).verifyCVV,
+	"M6": (*Handler).generateMAC,
+	"M8": (*Handler).verifyMAC,
+	"NC": (*Handler).diagnostics,
+}
+
+// Handler executes host commands. Key generation and MACs are delegated to
+// the vault's gRPC services; PIN translation and CVVs, which have no service
+// of their own, use the key store directly with the same usage checks.
+type Handler struct {
+	store keystore.Store
+	keys  *server.KeyManagementServer
+	macs  *server.MacServer
+	audit *audit.Logger
+}
+
+func NewHandler(store keystore.Store, keys *server.KeyManagementServer, macs *server.MacServer, a *audit.Logger) *Handler {
+	return &Handler{
+		store: store,
+		keys:  keys,
+		macs:  macs,
+		audit: a,
+	}
+}
+
+// Handle executes one command message, without its header, and returns the
+// response message: the response code, a two-digit error code and, on
+// success, the response fields.
+func (h *Handler) Handle(ctx context.Context, peer, msg string) string {
+	if len(msg) < 2 {
+		return "ZZ" + string(errInput)
+	}
+	code := msg[:2]
+	resp := responseCode(code)
+
+	cmd, ok := commands[code]
+	if !ok {
+		return resp + string(errUnsupported)
+	}
+	r := &reader{s: msg[2:]}
+	fields, err := cmd(h, interceptor.ContextWithPrincipal(ctx, principal), peer, r)
+	if err != nil {
+		return resp + string(hostErrorCode(err))
+	}
+	return resp + "00" + fields
+}
+
+// responseCode is the command code with its second character incremented.
+func responseCode(code string) string {
+	return code[:1] + string(code[1]+1)
+}
+
+// hostErrorCode maps errors from the vault services to payShield codes.
+func hostErrorCode(err error) hostError {
+	var he hostError
+	if errors.As(err, &he) {
+		return he
+	}
+	switch status.Code(err) {
+	case codes.NotFound, codes.FailedPrecondition:
+		return errKey
+	default:
+		return errInput
+	}
+}
+
+// diagnostics answers NC with an all-zero LMK check value and the firmware
+// identifier.
+func (h *Handler) diagnostics(ctx context.Context, peer string, r *reader) (string, error) {
+	return "0000000000000000" + "vault-go", nil
+}
+
+// generateKey implements A0 in mode 0: generate a key and return it under
+// the LMK. Mode 1, which also exports the key under a ZMK, is not emulated.
+func (h *Handler) generateKey(ctx context.Context, peer string, r *reader) (string, error) {
+	mode, keyType, scheme := r.take(1), r.take(3), r.take(1)
+	if r.err != nil {
+		return "", r.err
+	}
+	if mode != "0" {
+		return "", errUnsupported
+	}
+	purpose, ok := keyTypes[keyType]
+	if !ok {
+		return "", errKeyType
+	}
+
+	var algo pb.KeyAlgorithm
+	switch scheme {
+	case string(schemeDouble):
+		algo = pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_2KEY
+	case string(schemeTriple):
+		algo = pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_3KEY
+	default:
+		return "", errInput
+	}
+
+	resp, err := h.keys.GenerateKey(ctx, &pb.GenerateKeyRequest{
+		Algorithm: algo,
+		Purpose:   purpose,
+		Labels:    map[string]string{"source": "hostcmd", "key_type": keyType, KeyLabel: "true"},
+	})
+	if err != nil {
+		return "", err
+	}
+	entry, err := h.store.Get(resp.Metadata.KeyId)
+	if err != nil {
+		return "", err
+	}
+	kcv, err := crypto.TDEAKCV(entry.SecretKey)
+	if err != nil {
+		return "", err
+	}
+	return keyRef(entry) + strings.ToUpper(hex.EncodeToString(kcv)), nil
+}
+
+// translatePIN implements CA (TPK to ZPK) and CC (ZPK to ZPK). The PIN is
+// recovered under the source key and re-encrypted in the destination
+// format under the destination key.
+func (h *Handler) translatePIN(ctx context.Context, peer string, r *reader) (string, error) {
+	srcID, dstID := r.key(), r.key()
+	maxLen := r.digits(2)
+	block := r.take(16)
+	srcCode, dstCode := r.take(2), r.take(2)
+	account := r.digits(12)
+	if r.err != nil {
+		return "", r.err
+	}
+	srcFormat, ok := pinFormats[srcCode]
+	if !ok {
+		return "", errPINFormat
+	}
+	dstFormat, ok := pinFormats[dstCode]
+	if !ok {
+		return "", errPINFormat
+	}
+	encrypted, err := hex.DecodeString(block)
+	if err != nil {
+		return "", errInput
+	}
+
+	src, err := h.tdeaKey(srcID, keystore.OpDecrypt, false)
+	if err != nil {
+		return "", err
+	}
+	dst, err := h.tdeaKey(dstID, keystore.OpEncrypt, true)
+	if err != nil {
+		return "", err
+	}
+	meta := map[string]string{"destination_key_id": dst.ID}
+
+	srcCipher, err := crypto.NewTDEACipher(src.SecretKey)
+	if err != nil {
+		return "", err
+	}
+	clearBlock := make([]byte, 8)
+	srcCipher.Decrypt(clearBlock, encrypted)
+	pin, err := crypto.DecodePINBlock(srcFormat, clearBlock, account)
+	if err != nil {
+		h.audit.Log("TranslatePin", src.ID, "ERROR", peer, meta)
+		return "", errPINBlock
+	}
+	if n, _ := strconv.Atoi(maxLen); len(pin) > n {
+		h.audit.Log("TranslatePin", src.ID, "ERROR", peer, meta)
+		return "", errPINLength
+	}
+
+	out, err := crypto.EncodePINBlock(dstFormat, pin, account)
+	if err != nil {
+		return "", err
+	}
+	dstCipher, err := crypto.NewTDEACipher(dst.SecretKey)
+	if err != nil {
+		return "", err
+	}
+	dstCipher.Encrypt(out, out)
+
+	h.audit.Log("TranslatePin", src.ID, "OK", peer, meta)
+	return fmt.Sprintf("%02d%X%s", len(pin), out, dstCode), nil
+}
+
+// generateCVV implements CW: compute the CVV for a card with a CVK pair.
+func (h *Handler) generateCVV(ctx context.Context, peer string, r *reader) (string, error) {
+	id := r.key()
+	pan, expiry, serviceCode := r.until(';'), r.digits(4), r.digits(3)
+	if r.err != nil {
+		return "", r.err
+	}
+	entry, err := h.cvk(id, keystore.OpGenerateMAC, true)
+	if err != nil {
+		return "", err
+	}
+	cvv, err := crypto.ComputeCVV(entry.SecretKey, pan, expiry, serviceCode)
+	if err != nil {
+		return "", errInput
+	}
+	h.audit.Log("GenerateCvv", entry.ID, "OK", peer, nil)
+	return cvv, nil
+}
+
+// verifyCVV implements CY: check a card's CVV with a CVK pair.
+func (h *Handler) verifyCVV(ctx context.Context, peer string, r *reader) (string, error) {
+	id := r.key()
+	cvv := r.digits(3)
+	pan, expiry, serviceCode := r.until(';'), r.digits(4), r.digits(3)
+	if r.err != nil {
+		return "", r.err
+	}
+	entry, err := h.cvk(id, keystore.OpVerifyMAC, false)
+	if err != nil {
+		return "", err
+	}
+	want, err := crypto.ComputeCVV(entry.SecretKey, pan, expiry, serviceCode)
+	if err != nil {
+		return "", errInput
+	}
+	valid := subtle.ConstantTimeCompare([]byte(want), []byte(cvv)) == 1
+	h.audit.Log("VerifyCvv", entry.ID, "OK", peer, map[string]string{"valid": strconv.FormatBool(valid)})
+	if !valid {
+		return "", errVerification
+	}
+	return "", nil
+}
+
+// macRequest holds the fields shared by M6 and M8.
+type macRequest struct {
+	keyID     string
+	algorithm pb.MacAlgorithm
+	padding   pb.MacPadding
+	length    int32
+	data      []byte
+}
+
+// readMacRequest parses the M6/M8 fields up to and including the message.
+// Only mode 0 (the whole message in one command) is supported.
+func readMacRequest(r *reader) (*macRequest, error) {
+	mode, inputFormat, size, alg, padding, _ := r.take(1), r.take(1), r.take(1), r.take(1), r.take(1), r.take(3)
+	id := r.key()
+	msgLen := r.take(4)
+	if r.err != nil {
+		return nil, r.err
+	}
+	if mode != "0" {
+		return nil, errUnsupported
+	}
+
+	req := &macRequest{keyID: id}
+	switch size {
+	case "0":
+		req.length = 4
+	case "1":
+		req.length = 8
+	default:
+		return nil, errInput
+	}
+	switch alg {
+	case "1":
+		req.algorithm = pb.MacAlgorithm_MAC_ALGORITHM_ISO9797_ALG1
+	case "3":
+		req.algorithm = pb.MacAlgorithm_MAC_ALGORITHM_ISO9797_ALG3
+	default:
+		return nil, errInput
+	}
+	switch padding {
+	case "0", "1":
+		req.padding = pb.MacPadding_MAC_PADDING_ISO9797_METHOD_1
+	case "2":
+		req.padding = pb.MacPadding_MAC_PADDING_ISO9797_METHOD_2
+	default:
+		return nil, errInput
+	}
+
+	n, err := strconv.ParseUint(msgLen, 16, 16)
+	if err != nil {
+		return nil, errInput
+	}
+	data := r.take(int(n))
+	if r.err != nil {
+		return nil, r.err
+	}
+	switch inputFormat {
+	case "0", "2":
+		req.data = []byte(data)
+	case "1":
+		if req.data, err = hex.DecodeString(data); err != nil {
+			return nil, errInput
+		}
+	default:
+		return nil, errInput
+	}
+	return req, nil
+}
+
+// generateMAC implements M6 through the MAC service.
+func (h *Handler) generateMAC(ctx context.Context, peer string, r *reader) (string, error) {
+	req, err := readMacRequest(r)
+	if err != nil {
+		return "", err
+	}
+	if _, err := h.key(req.keyID); err != nil {
+		return "", err
+	}
+	resp, err := h.macs.GenerateMac(ctx, &pb.GenerateMacRequest{
+		KeyId:     req.keyID,
+		Algorithm: req.algorithm,
+		Padding:   req.padding,
+		Data:      req.data,
+		MacLength: req.length,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(resp.Mac)), nil
+}
+
+// verifyMAC implements M8 through the MAC service.
+func (h *Handler) verifyMAC(ctx context.Context, peer string, r *reader) (string, error) {
+	req, err := readMacRequest(r)
+	if err != nil {
+		return "", err
+	}
+	mac, err := hex.DecodeString(r.take(2 * int(req.length)))
+	if r.err != nil || err != nil {
+		return "", errInput
+	}
+	if _, err := h.key(req.keyID); err != nil {
+		return "", err
+	}
+	resp, err := h.macs.VerifyMac(ctx, &pb.VerifyMacRequest{
+		KeyId:     req.keyID,
+		Algorithm: req.algorithm,
+		Padding:   req.padding,
+		Data:      req.data,
+		Mac:       mac,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Valid {
+		return "", errVerification
+	}
+	return "", nil
+}
+
+// key loads a key that has opted in to host commands.
+func (h *Handler) key(id string) (*keystore.KeyEntry, error) {
+	entry, err := h.store.Get(id)
+	if err != nil || entry.Labels[KeyLabel] != "true" {
+		return nil, errKey
+	}
+	return entry, nil
+}
+
+// tdeaKey loads a TDEA key permitted for op. Keys that produce new
+// cryptograms must also be active.
+func (h *Handler) tdeaKey(id string, op keystore.KeyOperation, active bool) (*keystore.KeyEntry, error) {
+	entry, err := h.key(id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Algorithm != keystore.AlgorithmTDEA2Key && entry.Algorithm != keystore.AlgorithmTDEA3Key {
+		return nil, errKey
+	}
+	if active && entry.Status != keystore.StatusActive {
+		return nil, errKey
+	}
+	if !entry.Permits(op) {
+		return nil, errKey
+	}
+	return entry, nil
+}
+
+// cvk loads a double-length key holding a CVK A and CVK B pair.
+func (h *Handler) cvk(id string, op keystore.KeyOperation, active bool) (*keystore.KeyEntry, error) {
+	entry, err := h.tdeaKey(id, op, active)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Algorithm != keystore.AlgorithmTDEA2Key {
+		return nil, errKey
+	}
+	return entry, nil
+}
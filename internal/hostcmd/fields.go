@@ -0,0 +1,99 @@
+package hostcmd
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// Key references stand in for keys encrypted under the LMK. They carry the
+// variant scheme tag of the key length followed by the vault key ID's 32 hex
+// digits; triple-length references are padded with zeros to the 48 digits
+// legacy applications expect.
+const (
+	schemeDouble = 'U'
+	schemeTriple = 'T'
+	refPadding   = "0000000000000000"
+)
+
+// keyRef returns the host key reference for a vault key.
+func keyRef(entry *keystore.KeyEntry) string {
+	id := strings.ToUpper(strings.ReplaceAll(entry.ID, "-", ""))
+	if entry.Algorithm == keystore.AlgorithmTDEA3Key {
+		return string(schemeTriple) + id + refPadding
+	}
+	return string(schemeDouble) + id
+}
+
+// reader consumes the fields of a host command. The first malformed field
+// sets err and every later read returns an empty string.
+type reader struct {
+	s   string
+	err error
+}
+
+func (r *reader) take(n int) string {
+	if r.err != nil {
+		return ""
+	}
+	if len(r.s) < n {
+		r.err = errInput
+		return ""
+	}
+	v := r.s[:n]
+	r.s = r.s[n:]
+	return v
+}
+
+// until consumes a variable-length field terminated by delim.
+func (r *reader) until(delim byte) string {
+	if r.err != nil {
+		return ""
+	}
+	i := strings.IndexByte(r.s, delim)
+	if i < 0 {
+		r.err = errInput
+		return ""
+	}
+	v := r.s[:i]
+	r.s = r.s[i+1:]
+	return v
+}
+
+// key consumes a key reference and returns the vault key ID.
+func (r *reader) key() string {
+	var ref string
+	switch r.take(1) {
+	case string(schemeDouble):
+		ref = r.take(32)
+	case string(schemeTriple):
+		if ref = r.take(48); ref != "" && ref[32:] != refPadding {
+			r.err = errKey
+		}
+		ref = ref[:min(len(ref), 32)]
+	default:
+		if r.err == nil {
+			r.err = errKey
+		}
+	}
+	if r.err != nil {
+		return ""
+	}
+	id, err := uuid.Parse(ref)
+	if err != nil {
+		r.err = errKey
+		return ""
+	}
+	return id.String()
+}
+
+// digits consumes an n-digit decimal field.
+func (r *reader) digits(n int) string {
+	v := r.take(n)
+	if r.err == nil && strings.Trim(v, "0123456789") != "" {
+		r.err = errInput
+	}
+	return v
+}
@@ -0,0 +1,306 @@
+package hostcmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/keystore"
+	"github.com/glinharesb/vault-go/internal/server"
+)
+
+func newTestHandler(t *testing.T) (*Handler, keystore.Store) {
+	t.Helper()
+	store := keystore.NewMemoryStore()
+	a := audit.NewLogger(64, io.Discard)
+	t.Cleanup(a.Close)
+	providers := hsm.NewRegistry()
+	if err := providers.Register("software", hsm.NewSoftwareHSM()); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	keys := server.NewKeyManagementServer(store, providers, a)
+	return NewHandler(store, keys, server.NewMacServer(store, a), a), store
+}
+
+// generate runs A0 and returns the key reference.
+func generate(t *testing.T, h *Handler, keyType, scheme string) string {
+	t.Helper()
+	resp := h.Handle(context.Background(), "test", "A00"+keyType+scheme)
+	if !strings.HasPrefix(resp, "A100") {
+		t.Fatalf("A0 %s: %s", keyType, resp)
+	}
+	n := 33
+	if scheme == "T" {
+		n = 49
+	}
+	if len(resp) != 4+n+6 {
+		t.Fatalf("A0 response length %d: %s", len(resp), resp)
+	}
+	return resp[4 : 4+n]
+}
+
+func secret(t *testing.T, store keystore.Store, ref string) []byte {
+	t.Helper()
+	r := &reader{s: ref}
+	entry, err := store.Get(r.key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry.SecretKey
+}
+
+func TestGenerateKeyKCV(t *testing.T) {
+	h, store := newTestHandler(t)
+	resp := h.Handle(context.Background(), "test", "A00001T")
+	ref, kcv := resp[4:53], resp[53:]
+	want, _ := crypto.TDEAKCV(secret(t, store, ref))
+	if kcv != fmt.Sprintf("%X", want) {
+		t.Fatalf("kcv %s, want %X", kcv, want)
+	}
+
+	if resp := h.Handle(context.Background(), "test", "A00999U"); resp != "A104" {
+		t.Fatalf("unknown key type: %s", resp)
+	}
+	if resp := h.Handle(context.Background(), "test", "A01001U"); resp != "A168" {
+		t.Fatalf("mode 1: %s", resp)
+	}
+}
+
+func TestTranslatePIN(t *testing.T) {
+	h, store := newTestHandler(t)
+	tpk := generate(t, h, "002", "U")
+	zpk := generate(t, h, "001", "T")
+
+	const account = "400000123456"
+	clearBlock, _ := crypto.EncodePINBlock(crypto.PINBlockISO0, "1234", account)
+	c, _ := crypto.NewTDEACipher(secret(t, store, tpk))
+	c.Encrypt(clearBlock, clearBlock)
+
+	resp := h.Handle(context.Background(), "test", fmt.Sprintf("CA%s%s12%X0147%s", tpk, zpk, clearBlock, account))
+	if !strings.HasPrefix(resp, "CB0004") || len(resp) != 24 || resp[22:] != "47" {
+		t.Fatalf("CA: %s", resp)
+	}
+
+	out, _ := hex.DecodeString(resp[6:22])
+	c, _ = crypto.NewTDEACipher(secret(t, store, zpk))
+	c.Decrypt(out, out)
+	if pin, err := crypto.DecodePINBlock(crypto.PINBlockISO3, out, account); err != nil || pin != "1234" {
+		t.Fatalf("translated pin %q, %v", pin, err)
+	}
+
+	// A PIN longer than the caller's maximum is rejected.
+	longBlock, _ := crypto.EncodePINBlock(crypto.PINBlockISO0, "123456", account)
+	c, _ = crypto.NewTDEACipher(secret(t, store, tpk))
+	c.Encrypt(longBlock, longBlock)
+	resp = h.Handle(context.Background(), "test", fmt.Sprintf("CA%s%s04%X0147%s", tpk, zpk, longBlock, account))
+	if resp != "CB24" {
+		t.Fatalf("max length: %s", resp)
+	}
+	// A MAC key cannot translate PINs.
+	zak := generate(t, h, "008", "U")
+	if resp := h.Handle(context.Background(), "test", fmt.Sprintf("CC%s%s12%X0101%s", zak, zpk, clearBlock, account)); resp != "CD10" {
+		t.Fatalf("wrong key purpose: %s", resp)
+	}
+}
+
+func TestCVV(t *testing.T) {
+	h, _ := newTestHandler(t)
+	cvk := generate(t, h, "402", "U")
+
+	resp := h.Handle(context.Background(), "test", "CW"+cvk+"4123456789012345;8701101")
+	if !strings.HasPrefix(resp, "CX00") || len(resp) != 7 {
+		t.Fatalf("CW: %s", resp)
+	}
+	cvv := resp[4:]
+
+	if resp := h.Handle(context.Background(), "test", "CY"+cvk+cvv+"4123456789012345;8701101"); resp != "CZ00" {
+		t.Fatalf("CY: %s", resp)
+	}
+	if resp := h.Handle(context.Background(), "test", "CY"+cvk+cvv+"4123456789012345;8702101"); resp != "CZ01" {
+		t.Fatalf("CY wrong expiry: %s", resp)
+	}
+}
+
+func TestMAC(t *testing.T) {
+	h, _ := newTestHandler(t)
+	zak := generate(t, h, "008", "U")
+
+	msg := "0200B23A800128A180180000000014000000"
+	resp := h.Handle(context.Background(), "test", fmt.Sprintf("M602131008%s%04X%s", zak, len(msg), msg))
+	if !strings.HasPrefix(resp, "M700") || len(resp) != 20 {
+		t.Fatalf("M6: %s", resp)
+	}
+	mac := resp[4:]
+
+	verify := fmt.Sprintf("M802131008%s%04X%s", zak, len(msg), msg)
+	if resp := h.Handle(context.Background(), "test", verify+mac); resp != "M900" {
+		t.Fatalf("M8: %s", resp)
+	}
+	if resp := h.Handle(context.Background(), "test", verify+strings.Repeat("0", 16)); resp != "M901" {
+		t.Fatalf("M8 bad mac: %s", resp)
+	}
+}
+
+func TestHandleErrors(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for msg, want := range map[string]string{
+		"XX": "XY68",
+		"A0": "A115",
+		"CY" + "U" + strings.Repeat("0", 32) + "123":                      "CZ15",
+		"CW" + "U" + strings.Repeat("0", 32) + "4123456789012345;8701101": "CX10",
+		"CWQ": "CX10",
+	} {
+		if got := h.Handle(context.Background(), "test", msg); got != want {
+			t.Errorf("%s: got %s, want %s", msg, got, want)
+		}
+	}
+}
+
+func TestKeyOptIn(t *testing.T) {
+	h, store := newTestHandler(t)
+
+	// Keys created through the vault API are unknown to host commands
+	// unless labelled for them.
+	ref := func(purpose pb.KeyPurpose, labels map[string]string) string {
+		resp, err := h.keys.GenerateKey(context.Background(), &pb.GenerateKeyRequest{
+			Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_2KEY,
+			Purpose:   purpose,
+			Labels:    labels,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := store.Get(resp.Metadata.KeyId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return keyRef(entry)
+	}
+
+	msg := "0200B23A800128A180180000000014000000"
+	zak := ref(pb.KeyPurpose_KEY_PURPOSE_MAC, nil)
+	if resp := h.Handle(context.Background(), "test", fmt.Sprintf("M602131008%s%04X%s", zak, len(msg), msg)); resp != "M710" {
+		t.Fatalf("M6 without opt-in: %s", resp)
+	}
+	cvk := ref(pb.KeyPurpose_KEY_PURPOSE_CARD_VERIFICATION, map[string]string{KeyLabel: "false"})
+	if resp := h.Handle(context.Background(), "test", "CW"+cvk+"4123456789012345;8701101"); resp != "CX10" {
+		t.Fatalf("CW without opt-in: %s", resp)
+	}
+	cvk = ref(pb.KeyPurpose_KEY_PURPOSE_CARD_VERIFICATION, map[string]string{KeyLabel: "true"})
+	if resp := h.Handle(context.Background(), "test", "CW"+cvk+"4123456789012345;8701101"); !strings.HasPrefix(resp, "CX00") {
+		t.Fatalf("CW with opt-in: %s", resp)
+	}
+}
+
+// testCertificate issues a certificate for cn, self-signed when parent is
+// nil.
+func testCertificate(t *testing.T, cn string, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	issuer, signer := tmpl, any(key)
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestServer(t *testing.T) {
+	h, _ := newTestHandler(t)
+	ca := testCertificate(t, "test ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := tls.NewListener(tcp, &tls.Config{
+		Certificates: []tls.Certificate{testCertificate(t, "vault", &ca)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- NewServer(h, 4, 100).Serve(ctx, lis) }()
+
+	// A client without a certificate is refused.
+	anon, err := tls.Dial("tcp", lis.Addr().String(), &tls.Config{RootCAs: pool})
+	if err == nil {
+		_, err = anon.Read(make([]byte, 1))
+		anon.Close()
+	}
+	if err == nil {
+		t.Fatal("connection without a client certificate accepted")
+	}
+
+	conn, err := tls.Dial("tcp", lis.Addr().String(), &tls.Config{
+		Certificates: []tls.Certificate{testCertificate(t, "switch", &ca)},
+		RootCAs:      pool,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for range 2 {
+		msg := "0001NC"
+		if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...)); err != nil {
+			t.Fatal(err)
+		}
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			t.Fatal(err)
+		}
+		resp := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(resp); got != "0001ND000000000000000000vault-go" {
+			t.Fatalf("NC: %s", got)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+}
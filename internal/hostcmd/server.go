@@ -0,0 +1,123 @@
+// Package hostcmd emulates a subset of the Thales payShield host command
+// interface so legacy payment applications can run against the vault in
+// test environments. It is not a substitute for a certified payment HSM.
+package hostcmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/glinharesb/vault-go/internal/interceptor"
+)
+
+// Server accepts host command connections. Each message is framed by a
+// two-byte big-endian length and starts with a fixed-length header that is
+// echoed back unchanged in the response.
+//
+// Host commands bypass the gRPC interceptors, so the server applies its own
+// rate limit: commands over rps per second, across all connections, wait
+// for a token rather than fail, as the protocol has no error for it.
+// Authentication is left to the listener; the vault serves host commands
+// only over mutual TLS.
+type Server struct {
+	handler   *Handler
+	headerLen int
+	limit     *interceptor.TokenBucket
+	wait      time.Duration
+}
+
+func NewServer(h *Handler, headerLen, rps int) *Server {
+	return &Server{
+		handler:   h,
+		headerLen: headerLen,
+		limit:     interceptor.NewTokenBucket(rps),
+		wait:      time.Second / time.Duration(max(rps, 1)),
+	}
+}
+
+// Serve accepts connections on lis until ctx is cancelled, then closes the
+// listener and every open connection.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { lis.Close() })
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Go(func() { s.serveConn(ctx, conn) })
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	peer := conn.RemoteAddr().String()
+	if tc, ok := conn.(*tls.Conn); ok {
+		if err := tc.HandshakeContext(ctx); err != nil {
+			slog.Warn("host command handshake", "peer", peer, "error", err)
+			return
+		}
+		// Audit entries name the client certificate as well as the address.
+		if certs := tc.ConnectionState().PeerCertificates; len(certs) > 0 {
+			peer = certs[0].Subject.CommonName + "@" + peer
+		}
+	}
+	r := bufio.NewReader(conn)
+	var size [2]byte
+	for {
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				slog.Warn("host command read", "peer", peer, "error", err)
+			}
+			return
+		}
+		msg := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(r, msg); err != nil {
+			slog.Warn("host command read", "peer", peer, "error", err)
+			return
+		}
+		if len(msg) < s.headerLen {
+			slog.Warn("host command shorter than header", "peer", peer, "length", len(msg))
+			return
+		}
+
+		for !s.limit.Allow() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.wait):
+			}
+		}
+
+		header, cmd := msg[:s.headerLen], string(msg[s.headerLen:])
+		resp := append(header, s.handler.Handle(ctx, peer, cmd)...)
+		if len(resp) > 0xffff {
+			slog.Warn("host command response too long", "peer", peer, "length", len(resp))
+			return
+		}
+
+		out := binary.BigEndian.AppendUint16(nil, uint16(len(resp)))
+		if _, err := conn.Write(append(out, resp...)); err != nil {
+			slog.Warn("host command write", "peer", peer, "error", err)
+			return
+		}
+	}
+}
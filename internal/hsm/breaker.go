@@ -0,0 +1,360 @@
+package hsm
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// Prober is implemented by providers that can check their own health
+// without touching a key, such as by querying a token's session.
+type Prober interface {
+	Probe() error
+}
+
+// UnavailableError is returned while a provider's circuit breaker is open.
+// It wraps ErrUnavailable.
+type UnavailableError struct {
+	Provider string
+	// RetryAfter is how long until the breaker lets a call through again.
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("hsm provider %s unavailable, retry after %s", e.Provider, e.RetryAfter.Round(time.Second))
+}
+
+func (e *UnavailableError) Unwrap() error { return ErrUnavailable }
+
+const (
+	// DefaultBreakerThreshold is the number of consecutive failures that
+	// open a breaker when none is configured.
+	DefaultBreakerThreshold = 3
+	// DefaultBreakerCooldown is how long a breaker stays open when no
+	// cooldown is configured.
+	DefaultBreakerCooldown = 10 * time.Second
+)
+
+// BreakerConfig tunes a Breaker.
+type BreakerConfig struct {
+	// Threshold is the number of consecutive failures, of calls or
+	// probes, that opens the breaker.
+	Threshold int
+	// Cooldown is how long the breaker stays open before it lets a call
+	// through to try the provider again.
+	Cooldown time.Duration
+	// OnChange, if set, is called when the provider becomes healthy or
+	// unhealthy.
+	OnChange func(healthy bool)
+}
+
+// ProviderStats describes the health of a provider for monitoring.
+type ProviderStats struct {
+	Healthy bool   `json:"healthy"`
+	State   string `json:"state"`
+	// ConsecutiveFailures is the current run of failed calls and probes.
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	Failures            uint64 `json:"failures"`
+	// Rejected counts calls refused while the breaker was open.
+	Rejected      uint64 `json:"rejected"`
+	Probes        uint64 `json:"probes"`
+	ProbeFailures uint64 `json:"probe_failures"`
+	// Failovers counts calls a Failover sent to its secondary.
+	Failovers uint64 `json:"failovers"`
+}
+
+// Monitored is implemented by the providers that track their health.
+type Monitored interface {
+	Healthy() bool
+	Stats() ProviderStats
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker is a circuit breaker around a provider. Calls that fail with
+// ErrUnavailable, and failed probes, count against the provider; after
+// Threshold in a row the breaker opens and calls fail at once with an
+// UnavailableError instead of waiting on a provider that is down. Once
+// the cooldown has passed a single trial call is let through, and others
+// are refused until it returns: its success, or a successful probe,
+// closes the breaker again.
+//
+// Errors that say nothing about the provider's health, such as an invalid
+// handle, pass through without counting.
+type Breaker struct {
+	name     string
+	provider Provider
+	cfg      BreakerConfig
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	stats    ProviderStats
+	// trial is set while the half-open breaker's trial call is in flight.
+	trial bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var (
+	_ Provider           = (*Breaker)(nil)
+	_ Monitored          = (*Breaker)(nil)
+	_ KeyLifecycle       = (*Breaker)(nil)
+	_ KeyImporter        = (*Breaker)(nil)
+	_ CapabilityReporter = (*Breaker)(nil)
+)
+
+// NewBreaker wraps p, registered as name, in a circuit breaker.
+func NewBreaker(name string, p Provider, cfg BreakerConfig) *Breaker {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultBreakerThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultBreakerCooldown
+	}
+	return &Breaker{name: name, provider: p, cfg: cfg, now: time.Now}
+}
+
+// Watch probes the provider every interval until Close, if it is a Prober.
+func (b *Breaker) Watch(interval time.Duration) {
+	if _, ok := b.provider.(Prober); !ok || interval <= 0 {
+		return
+	}
+	b.stop, b.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(b.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.stop:
+				return
+			case <-ticker.C:
+				b.Probe()
+			}
+		}
+	}()
+}
+
+// Close stops probing and closes the provider if it holds resources.
+func (b *Breaker) Close() error {
+	if b.stop != nil {
+		close(b.stop)
+		<-b.done
+	}
+	if c, ok := b.provider.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Probe checks the provider's health whatever the breaker's state, so a
+// provider that recovers is put back in service without waiting for a
+// call. Providers that cannot probe are reported healthy.
+func (b *Breaker) Probe() error {
+	p, ok := b.provider.(Prober)
+	if !ok {
+		return nil
+	}
+	err := p.Probe()
+	b.mu.Lock()
+	b.stats.Probes++
+	if err != nil {
+		b.stats.ProbeFailures++
+		err = fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	b.mu.Unlock()
+	b.record(err)
+	return err
+}
+
+// Healthy reports whether the breaker is closed.
+func (b *Breaker) Healthy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breakerClosed
+}
+
+// Stats reports the breaker state and the outcomes it has counted.
+func (b *Breaker) Stats() ProviderStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.stats
+	s.Healthy = b.state == breakerClosed
+	s.State = b.state.String()
+	s.ConsecutiveFailures = b.failures
+	return s
+}
+
+// allow reports whether a call may go to the provider.
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerClosed:
+		return nil
+	case breakerHalfOpen:
+		if b.trial {
+			b.stats.Rejected++
+			return &UnavailableError{Provider: b.name, RetryAfter: b.cfg.Cooldown}
+		}
+	default:
+		if wait := b.openedAt.Add(b.cfg.Cooldown).Sub(b.now()); wait > 0 {
+			b.stats.Rejected++
+			return &UnavailableError{Provider: b.name, RetryAfter: wait}
+		}
+		b.state = breakerHalfOpen
+	}
+	b.trial = true
+	return nil
+}
+
+// record counts the outcome of a call or probe.
+func (b *Breaker) record(err error) {
+	if err != nil && !errors.Is(err, ErrUnavailable) {
+		// A trial call that fails this way says nothing about the
+		// provider's health: let the next call try instead.
+		b.mu.Lock()
+		b.trial = false
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Lock()
+	b.trial = false
+	wasHealthy := b.state == breakerClosed
+	if err == nil {
+		b.failures = 0
+		b.state = breakerClosed
+	} else {
+		b.failures++
+		b.stats.Failures++
+		if b.state == breakerHalfOpen || b.failures >= b.cfg.Threshold {
+			b.state = breakerOpen
+			b.openedAt = b.now()
+		}
+	}
+	healthy := b.state == breakerClosed
+	b.mu.Unlock()
+
+	if healthy != wasHealthy && b.cfg.OnChange != nil {
+		b.cfg.OnChange(healthy)
+	}
+}
+
+func (b *Breaker) GenerateKey(id string, curve elliptic.Curve) (KeyHandle, *ecdsa.PublicKey, error) {
+	if err := b.allow(); err != nil {
+		return "", nil, err
+	}
+	h, pub, err := b.provider.GenerateKey(id, curve)
+	b.record(err)
+	return h, pub, err
+}
+
+func (b *Breaker) Sign(h KeyHandle, data []byte) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	sig, err := b.provider.Sign(h, data)
+	b.record(err)
+	return sig, err
+}
+
+func (b *Breaker) Verify(pub *ecdsa.PublicKey, data, signature []byte) bool {
+	return b.provider.Verify(pub, data, signature)
+}
+
+func (b *Breaker) ECDH(h KeyHandle, peer *ecdh.PublicKey) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	secret, err := b.provider.ECDH(h, peer)
+	b.record(err)
+	return secret, err
+}
+
+func (b *Breaker) Encrypt(h KeyHandle, plaintext, aad []byte) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	ct, err := b.provider.Encrypt(h, plaintext, aad)
+	b.record(err)
+	return ct, err
+}
+
+func (b *Breaker) Decrypt(h KeyHandle, ciphertext, aad []byte) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	pt, err := b.provider.Decrypt(h, ciphertext, aad)
+	b.record(err)
+	return pt, err
+}
+
+func (b *Breaker) Derive(h KeyHandle, hash crypto.HKDFHash, salt, info []byte, length int) ([]byte, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	out, err := b.provider.Derive(h, hash, salt, info, length)
+	b.record(err)
+	return out, err
+}
+
+// Capabilities reports the provider's capabilities. Reading them does not
+// count towards its health.
+func (b *Breaker) Capabilities() (Capabilities, error) {
+	return ProviderCapabilities(b.provider)
+}
+
+func (b *Breaker) ImportKey(id string, key *ecdsa.PrivateKey) (KeyHandle, error) {
+	if err := b.allow(); err != nil {
+		return "", err
+	}
+	h, err := ImportKey(b.provider, id, key)
+	b.record(err)
+	return h, err
+}
+
+func (b *Breaker) DeactivateKey(h KeyHandle) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := DeactivateKey(b.provider, h)
+	b.record(err)
+	return err
+}
+
+func (b *Breaker) DestroyKey(h KeyHandle) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := DestroyKey(b.provider, h)
+	b.record(err)
+	return err
+}
@@ -0,0 +1,173 @@
+package hsm
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flaky is a SoftwareHSM that can be taken down, as a token that is
+// unplugged or a plugin that crashes.
+type flaky struct {
+	*SoftwareHSM
+	down atomic.Bool
+}
+
+func (f *flaky) Probe() error {
+	if f.down.Load() {
+		return errors.New("token not present")
+	}
+	return nil
+}
+
+func (f *flaky) Sign(h KeyHandle, data []byte) ([]byte, error) {
+	if f.down.Load() {
+		return nil, ErrUnavailable
+	}
+	return f.SoftwareHSM.Sign(h, data)
+}
+
+func TestBreaker(t *testing.T) {
+	p := &flaky{SoftwareHSM: NewSoftwareHSM()}
+	var changes []bool
+	b := NewBreaker("dev", p, BreakerConfig{
+		Threshold: 2,
+		Cooldown:  time.Minute,
+		OnChange:  func(healthy bool) { changes = append(changes, healthy) },
+	})
+	now := time.Unix(1000, 0)
+	b.now = func() time.Time { return now }
+
+	h, _, err := b.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := b.Sign("software:bad", nil); !errors.Is(err, ErrInvalidHandle) {
+		t.Fatalf("bad handle: got %v", err)
+	}
+	if b.Stats().ConsecutiveFailures != 0 {
+		t.Fatal("errors unrelated to health should not count")
+	}
+
+	p.down.Store(true)
+	for range 2 {
+		if _, err := b.Sign(h, []byte("data")); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("provider down: got %v", err)
+		}
+	}
+	if b.Healthy() {
+		t.Fatal("breaker should open after the threshold")
+	}
+	_, err = b.Sign(h, []byte("data"))
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) || unavailable.Provider != "dev" || unavailable.RetryAfter != time.Minute {
+		t.Fatalf("open breaker: got %v, want UnavailableError retrying after a minute", err)
+	}
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatal("UnavailableError should wrap ErrUnavailable")
+	}
+
+	// After the cooldown one call is let through; its failure reopens
+	// the breaker.
+	now = now.Add(time.Minute)
+	if _, err := b.Sign(h, []byte("data")); errors.As(err, &unavailable) {
+		t.Fatal("breaker should let a call through after the cooldown")
+	}
+	if b.Healthy() || b.Stats().State != "open" {
+		t.Fatalf("failed trial call should reopen the breaker, state %s", b.Stats().State)
+	}
+
+	// Only one trial call is let through at a time.
+	now = now.Add(time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("trial call: %v", err)
+	}
+	if err := b.allow(); !errors.As(err, &unavailable) {
+		t.Fatalf("second call during the trial: got %v, want UnavailableError", err)
+	}
+	b.record(ErrUnavailable)
+
+	// A successful probe closes it without waiting for the cooldown.
+	p.down.Store(false)
+	if err := b.Probe(); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if !b.Healthy() {
+		t.Fatal("successful probe should close the breaker")
+	}
+	if _, err := b.Sign(h, []byte("data")); err != nil {
+		t.Fatalf("sign after recovery: %v", err)
+	}
+
+	s := b.Stats()
+	if s.Failures != 4 || s.Rejected != 2 || s.Probes != 1 {
+		t.Fatalf("stats: %+v", s)
+	}
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Fatalf("health changes: got %v, want [false true]", changes)
+	}
+}
+
+func TestBreakerProbeOpens(t *testing.T) {
+	p := &flaky{SoftwareHSM: NewSoftwareHSM()}
+	b := NewBreaker("dev", p, BreakerConfig{Threshold: 1})
+	p.down.Store(true)
+	if err := b.Probe(); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("probe: got %v, want ErrUnavailable", err)
+	}
+	if b.Healthy() {
+		t.Fatal("failed probe should open the breaker")
+	}
+	if s := b.Stats(); s.ProbeFailures != 1 || s.State != "open" {
+		t.Fatalf("stats: %+v", s)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	// Both instances front one SoftwareHSM, so they share keys as
+	// replicated tokens would.
+	soft := NewSoftwareHSM()
+	primary := &flaky{SoftwareHSM: soft}
+	b := NewBreaker("primary", primary, BreakerConfig{Threshold: 1, Cooldown: time.Minute})
+	f := NewFailover(b, NewBreaker("secondary", soft, BreakerConfig{}))
+
+	h, pub, err := f.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	primary.down.Store(true)
+	for range 2 {
+		sig, err := f.Sign(h, []byte("data"))
+		if err != nil {
+			t.Fatalf("sign with primary down: %v", err)
+		}
+		if !f.Verify(pub, []byte("data"), sig) {
+			t.Fatal("secondary signature should verify")
+		}
+	}
+	if !f.Healthy() {
+		t.Fatal("failover should be healthy while the secondary is")
+	}
+	s := f.Stats()
+	if s.Failovers != 2 || s.State != "open" {
+		t.Fatalf("stats: %+v", s)
+	}
+
+	if _, err := f.Sign("software:bad", nil); !errors.Is(err, ErrInvalidHandle) {
+		t.Fatalf("bad handle: got %v", err)
+	}
+
+	// Keys are not generated or destroyed on the secondary alone.
+	if _, _, err := f.GenerateKey("key-2", elliptic.P256()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("generate with primary down: got %v, want ErrUnavailable", err)
+	}
+	if err := f.DestroyKey(h); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("destroy with primary down: got %v, want ErrUnavailable", err)
+	}
+	if f.Stats().Failovers != 3 {
+		t.Fatalf("lifecycle writes should not fail over: %+v", f.Stats())
+	}
+}
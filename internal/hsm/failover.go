@@ -0,0 +1,155 @@
+package hsm
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// Failover sends calls to a primary provider and, when it is unavailable,
+// to a secondary instance holding replicated keys, such as a second token
+// of an HSM cluster. Handles must be valid on both: the same provider type
+// issues them, referring to keys replicated under the same reference.
+// Errors other than ErrUnavailable come from the primary as they are.
+//
+// Only operations on existing keys fail over. A key generated, imported or
+// destroyed on the secondary alone would leave the instances out of step,
+// so lifecycle writes go to the primary and fail with ErrUnavailable while
+// it is down.
+//
+// With a Breaker around the primary, calls skip it at once while it is
+// down.
+type Failover struct {
+	primary   Provider
+	secondary Provider
+	failovers atomic.Uint64
+}
+
+var (
+	_ Provider           = (*Failover)(nil)
+	_ Monitored          = (*Failover)(nil)
+	_ KeyLifecycle       = (*Failover)(nil)
+	_ KeyImporter        = (*Failover)(nil)
+	_ CapabilityReporter = (*Failover)(nil)
+)
+
+func NewFailover(primary, secondary Provider) *Failover {
+	return &Failover{primary: primary, secondary: secondary}
+}
+
+// Healthy reports whether either instance can take calls.
+func (f *Failover) Healthy() bool {
+	return healthy(f.primary) || healthy(f.secondary)
+}
+
+// Stats reports the primary's health, and the calls sent to the secondary.
+func (f *Failover) Stats() ProviderStats {
+	var s ProviderStats
+	if m, ok := f.primary.(Monitored); ok {
+		s = m.Stats()
+	}
+	s.Healthy = f.Healthy()
+	s.Failovers = f.failovers.Load()
+	return s
+}
+
+// Close closes the primary. The secondary is a provider in its own right
+// and is closed by its owner.
+func (f *Failover) Close() error {
+	if c, ok := f.primary.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func healthy(p Provider) bool {
+	m, ok := p.(Monitored)
+	return !ok || m.Healthy()
+}
+
+// do runs fn on the primary, then on the secondary if the primary is
+// unavailable.
+func (f *Failover) do(fn func(Provider) error) error {
+	err := fn(f.primary)
+	if !errors.Is(err, ErrUnavailable) {
+		return err
+	}
+	f.failovers.Add(1)
+	return fn(f.secondary)
+}
+
+func (f *Failover) GenerateKey(id string, curve elliptic.Curve) (KeyHandle, *ecdsa.PublicKey, error) {
+	return f.primary.GenerateKey(id, curve)
+}
+
+func (f *Failover) Sign(h KeyHandle, data []byte) (sig []byte, err error) {
+	err = f.do(func(p Provider) error {
+		sig, err = p.Sign(h, data)
+		return err
+	})
+	return sig, err
+}
+
+func (f *Failover) Verify(pub *ecdsa.PublicKey, data, signature []byte) bool {
+	return f.primary.Verify(pub, data, signature)
+}
+
+func (f *Failover) ECDH(h KeyHandle, peer *ecdh.PublicKey) (secret []byte, err error) {
+	err = f.do(func(p Provider) error {
+		secret, err = p.ECDH(h, peer)
+		return err
+	})
+	return secret, err
+}
+
+func (f *Failover) Encrypt(h KeyHandle, plaintext, aad []byte) (ct []byte, err error) {
+	err = f.do(func(p Provider) error {
+		ct, err = p.Encrypt(h, plaintext, aad)
+		return err
+	})
+	return ct, err
+}
+
+func (f *Failover) Decrypt(h KeyHandle, ciphertext, aad []byte) (pt []byte, err error) {
+	err = f.do(func(p Provider) error {
+		pt, err = p.Decrypt(h, ciphertext, aad)
+		return err
+	})
+	return pt, err
+}
+
+func (f *Failover) Derive(h KeyHandle, hash crypto.HKDFHash, salt, info []byte, length int) (out []byte, err error) {
+	err = f.do(func(p Provider) error {
+		out, err = p.Derive(h, hash, salt, info, length)
+		return err
+	})
+	return out, err
+}
+
+// Capabilities reports the capabilities of the primary, or of the
+// secondary while the primary is unavailable; both are the same type of
+// provider.
+func (f *Failover) Capabilities() (c Capabilities, err error) {
+	err = f.do(func(p Provider) error {
+		c, err = ProviderCapabilities(p)
+		return err
+	})
+	return c, err
+}
+
+func (f *Failover) ImportKey(id string, key *ecdsa.PrivateKey) (KeyHandle, error) {
+	return ImportKey(f.primary, id, key)
+}
+
+func (f *Failover) DeactivateKey(h KeyHandle) error {
+	return DeactivateKey(f.primary, h)
+}
+
+func (f *Failover) DestroyKey(h KeyHandle) error {
+	return DestroyKey(f.primary, h)
+}
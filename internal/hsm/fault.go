@@ -0,0 +1,237 @@
+package hsm
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// ErrInjectedFault is wrapped by the errors a FaultHSM injects. They also
+// wrap ErrUnavailable, so callers treat them as a provider outage.
+var ErrInjectedFault = errors.New("hsm: injected fault")
+
+// Operations lists the operations faults can be set on, OpAll aside.
+var Operations = append(slices.Clone(KeyOperations), OpProbe)
+
+// Fault describes how an operation misbehaves.
+type Fault struct {
+	// Latency delays the operation before it runs. Changing the faults
+	// ends the delays in progress.
+	Latency time.Duration
+	// ErrorRate is the probability, from 0 to 1, that the operation fails
+	// with ErrInjectedFault instead of running.
+	ErrorRate float64
+	// CorruptRate is the probability that a signature is returned with a
+	// flipped bit. It only applies to OpSign.
+	CorruptRate float64
+	// Hang blocks the operation until the fault is changed or cleared.
+	Hang bool
+}
+
+// FaultHSM wraps a provider and injects faults into its operations, so
+// integration suites can exercise retries, timeouts and failover against a
+// running server. Faults are set and cleared at runtime; with none set it
+// passes calls through unchanged.
+type FaultHSM struct {
+	provider Provider
+
+	mu     sync.Mutex
+	faults map[Operation]Fault
+	// release is closed, and replaced, whenever the faults change, waking
+	// the operations that hang.
+	release chan struct{}
+	rand    func() float64
+}
+
+var (
+	_ Provider           = (*FaultHSM)(nil)
+	_ Prober             = (*FaultHSM)(nil)
+	_ KeyLifecycle       = (*FaultHSM)(nil)
+	_ KeyImporter        = (*FaultHSM)(nil)
+	_ CapabilityReporter = (*FaultHSM)(nil)
+)
+
+func NewFaultHSM(p Provider) *FaultHSM {
+	return &FaultHSM{
+		provider: p,
+		faults:   make(map[Operation]Fault),
+		release:  make(chan struct{}),
+		rand:     rand.Float64,
+	}
+}
+
+// SetFault sets the fault for op, replacing any it had. OpAll sets the
+// fault for every operation without one of its own.
+func (f *FaultHSM) SetFault(op Operation, fault Fault) error {
+	if op != OpAll && !slices.Contains(Operations, op) {
+		return fmt.Errorf("hsm: unknown operation %q", op)
+	}
+	if fault.ErrorRate < 0 || fault.ErrorRate > 1 || fault.CorruptRate < 0 || fault.CorruptRate > 1 {
+		return errors.New("hsm: fault rates must be between 0 and 1")
+	}
+	if fault.Latency < 0 {
+		return errors.New("hsm: fault latency must not be negative")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if fault == (Fault{}) {
+		delete(f.faults, op)
+	} else {
+		f.faults[op] = fault
+	}
+	f.wake()
+	return nil
+}
+
+// ClearFaults removes every fault and releases hung operations.
+func (f *FaultHSM) ClearFaults() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.faults)
+	f.wake()
+}
+
+// Faults returns the faults currently set, keyed by operation.
+func (f *FaultHSM) Faults() map[Operation]Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.faults)
+}
+
+// Close releases hung operations and closes the provider if it holds
+// resources.
+func (f *FaultHSM) Close() error {
+	f.ClearFaults()
+	if c, ok := f.provider.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// wake releases the operations hanging on the current faults. f.mu must
+// be held.
+func (f *FaultHSM) wake() {
+	close(f.release)
+	f.release = make(chan struct{})
+}
+
+// fault returns the fault in effect for op.
+func (f *FaultHSM) fault(op Operation) (Fault, <-chan struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fault, ok := f.faults[op]
+	if !ok {
+		fault = f.faults[OpAll]
+	}
+	return fault, f.release
+}
+
+// inject applies op's fault before the operation runs. It reports whether
+// a signature should be corrupted.
+func (f *FaultHSM) inject(op Operation) (corrupt bool, err error) {
+	fault, release := f.fault(op)
+	for fault.Hang {
+		<-release
+		fault, release = f.fault(op)
+	}
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-release:
+		}
+	}
+	if fault.ErrorRate > 0 && f.rand() < fault.ErrorRate {
+		return false, fmt.Errorf("%w: %w: %s", ErrUnavailable, ErrInjectedFault, op)
+	}
+	return fault.CorruptRate > 0 && f.rand() < fault.CorruptRate, nil
+}
+
+// Probe fails with the faults set on OpProbe, then probes the provider if
+// it can.
+func (f *FaultHSM) Probe() error {
+	if _, err := f.inject(OpProbe); err != nil {
+		return err
+	}
+	if p, ok := f.provider.(Prober); ok {
+		return p.Probe()
+	}
+	return nil
+}
+
+func (f *FaultHSM) GenerateKey(id string, curve elliptic.Curve) (KeyHandle, *ecdsa.PublicKey, error) {
+	if _, err := f.inject(OpGenerateKey); err != nil {
+		return "", nil, err
+	}
+	return f.provider.GenerateKey(id, curve)
+}
+
+func (f *FaultHSM) Sign(h KeyHandle, data []byte) ([]byte, error) {
+	corrupt, err := f.inject(OpSign)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := f.provider.Sign(h, data)
+	if err == nil && corrupt && len(sig) > 0 {
+		sig[len(sig)-1] ^= 1
+	}
+	return sig, err
+}
+
+func (f *FaultHSM) Verify(pub *ecdsa.PublicKey, data, signature []byte) bool {
+	return f.provider.Verify(pub, data, signature)
+}
+
+func (f *FaultHSM) ECDH(h KeyHandle, peer *ecdh.PublicKey) ([]byte, error) {
+	if _, err := f.inject(OpECDH); err != nil {
+		return nil, err
+	}
+	return f.provider.ECDH(h, peer)
+}
+
+func (f *FaultHSM) Encrypt(h KeyHandle, plaintext, aad []byte) ([]byte, error) {
+	if _, err := f.inject(OpEncrypt); err != nil {
+		return nil, err
+	}
+	return f.provider.Encrypt(h, plaintext, aad)
+}
+
+func (f *FaultHSM) Decrypt(h KeyHandle, ciphertext, aad []byte) ([]byte, error) {
+	if _, err := f.inject(OpDecrypt); err != nil {
+		return nil, err
+	}
+	return f.provider.Decrypt(h, ciphertext, aad)
+}
+
+func (f *FaultHSM) Derive(h KeyHandle, hash crypto.HKDFHash, salt, info []byte, length int) ([]byte, error) {
+	if _, err := f.inject(OpDerive); err != nil {
+		return nil, err
+	}
+	return f.provider.Derive(h, hash, salt, info, length)
+}
+
+// Capabilities reports the provider's capabilities; faults do not apply.
+func (f *FaultHSM) Capabilities() (Capabilities, error) {
+	return ProviderCapabilities(f.provider)
+}
+
+func (f *FaultHSM) ImportKey(id string, key *ecdsa.PrivateKey) (KeyHandle, error) {
+	return ImportKey(f.provider, id, key)
+}
+
+func (f *FaultHSM) DeactivateKey(h KeyHandle) error {
+	return DeactivateKey(f.provider, h)
+}
+
+func (f *FaultHSM) DestroyKey(h KeyHandle) error {
+	return DestroyKey(f.provider, h)
+}
@@ -0,0 +1,124 @@
+package hsm
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+func TestFaultHSM(t *testing.T) {
+	f := NewFaultHSM(NewSoftwareHSM())
+	h, pub, err := f.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	if err := f.SetFault(OpSign, Fault{ErrorRate: 1}); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+	_, err = f.Sign(h, []byte("data"))
+	if !errors.Is(err, ErrInjectedFault) || !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("error rate 1: got %v, want an injected unavailable error", err)
+	}
+	if _, err := f.Encrypt(h, []byte("data"), nil); err != nil {
+		t.Fatalf("other operations should be unaffected: %v", err)
+	}
+
+	if err := f.SetFault(OpSign, Fault{CorruptRate: 1}); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+	sig, err := f.Sign(h, []byte("data"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if f.Verify(pub, []byte("data"), sig) {
+		t.Fatal("corrupted signature should not verify")
+	}
+
+	if err := f.SetFault(OpAll, Fault{Latency: 50 * time.Millisecond}); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+	start := time.Now()
+	if _, err := f.Derive(h, crypto.HKDFSHA256, nil, nil, 32); err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatal("latency should apply to operations without their own fault")
+	}
+	if got := f.Faults(); len(got) != 2 {
+		t.Fatalf("faults: got %v", got)
+	}
+
+	f.ClearFaults()
+	sig, err = f.Sign(h, []byte("data"))
+	if err != nil || !f.Verify(pub, []byte("data"), sig) {
+		t.Fatalf("sign after clearing faults: %v", err)
+	}
+
+	if err := f.SetFault("reboot", Fault{Hang: true}); err == nil {
+		t.Fatal("unknown operation should fail")
+	}
+	if err := f.SetFault(OpSign, Fault{ErrorRate: 2}); err == nil {
+		t.Fatal("rate above 1 should fail")
+	}
+}
+
+func TestFaultHSMHang(t *testing.T) {
+	f := NewFaultHSM(NewSoftwareHSM())
+	h, _, err := f.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := f.SetFault(OpSign, Fault{Hang: true}); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := f.Sign(h, []byte("data"))
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("sign should hang")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Changing another operation's fault leaves the hang in place.
+	if err := f.SetFault(OpDerive, Fault{ErrorRate: 1}); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+	select {
+	case <-done:
+		t.Fatal("sign should still hang")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	f.ClearFaults()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("released sign: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("clearing faults should release the hung sign")
+	}
+}
+
+func TestFaultHSMOpensBreaker(t *testing.T) {
+	f := NewFaultHSM(NewSoftwareHSM())
+	b := NewBreaker("dev", f, BreakerConfig{Threshold: 1})
+	if err := f.SetFault(OpProbe, Fault{ErrorRate: 1}); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+	if err := b.Probe(); err == nil || b.Healthy() {
+		t.Fatal("failing probe should open the breaker")
+	}
+	f.ClearFaults()
+	if err := b.Probe(); err != nil || !b.Healthy() {
+		t.Fatalf("probe after clearing faults: %v", err)
+	}
+}
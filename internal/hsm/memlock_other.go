@@ -0,0 +1,14 @@
+//go:build !unix
+
+package hsm
+
+// lockedAlloc returns n bytes. Memory cannot be locked on this platform,
+// so it is only zeroized when freed.
+func lockedAlloc(n int) ([]byte, error) {
+	return make([]byte, n), nil
+}
+
+// lockedFree zeroizes memory from lockedAlloc.
+func lockedFree(b []byte) {
+	clear(b)
+}
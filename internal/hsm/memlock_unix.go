@@ -0,0 +1,35 @@
+//go:build unix
+
+package hsm
+
+import (
+	"log/slog"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+var warnUnlocked sync.Once
+
+// lockedAlloc returns n bytes outside the Go heap, locked into RAM so they
+// are never written to swap. Where the memory lock limit does not allow
+// it, the memory is still kept off the heap, and a warning is logged once.
+func lockedAlloc(n int) ([]byte, error) {
+	b, err := unix.Mmap(-1, 0, n, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Mlock(b); err != nil {
+		warnUnlocked.Do(func() {
+			slog.Warn("hsm: cannot lock key memory, raise RLIMIT_MEMLOCK", "error", err)
+		})
+	}
+	return b, nil
+}
+
+// lockedFree zeroizes and releases memory from lockedAlloc.
+func lockedFree(b []byte) {
+	clear(b)
+	unix.Munlock(b)
+	unix.Munmap(b)
+}
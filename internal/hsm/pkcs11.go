@@ -0,0 +1,556 @@
+//go:build pkcs11
+
+package hsm
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// pkcs11Provider names the handles PKCS11 issues.
+const pkcs11Provider = "pkcs11"
+
+// PKCS11 keeps ECDSA keys inside a PKCS#11 token. Keys are generated on
+// the token as sensitive, non-extractable objects whose CKA_ID and
+// CKA_LABEL are the vault key ID, and every private key operation runs on
+// the token, so private key bytes never enter this process. Verification
+// only needs the public key and runs in software.
+//
+// Its handles are "pkcs11:<vault key id>". Encrypt, Decrypt and Derive
+// key software primitives with the private key and are not supported.
+type PKCS11 struct {
+	ctx      *pkcs11.Ctx
+	slot     uint
+	pin      string
+	sessions chan pkcs11.SessionHandle
+	// pooled is the number of sessions in the pool, taken or not.
+	pooled int
+	// closed is closed by Close, which then waits for every pooled
+	// session to come back before closing it.
+	closed chan struct{}
+
+	mu       sync.Mutex
+	keys     map[string]pkcs11Key
+	isClosed bool
+}
+
+// pkcs11Key caches the object handle and public key of a token key.
+// Object handles stay valid across sessions of one application.
+type pkcs11Key struct {
+	private pkcs11.ObjectHandle
+	public  *ecdsa.PublicKey
+}
+
+var _ Provider = (*PKCS11)(nil)
+
+// NewPKCS11 loads the module, finds the token and opens a pool of
+// sessions logged in as the normal user.
+func NewPKCS11(cfg PKCS11Config) (*PKCS11, error) {
+	if cfg.Module == "" {
+		return nil, errors.New("pkcs11: module path is required")
+	}
+	ctx := pkcs11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("pkcs11: load module %s", cfg.Module)
+	}
+	if err := ctx.Initialize(); err != nil && !isCKR(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11: initialize: %w", err)
+	}
+
+	h := &PKCS11{ctx: ctx, pin: cfg.PIN, keys: make(map[string]pkcs11Key), closed: make(chan struct{})}
+	slot, err := h.findSlot(cfg.TokenLabel)
+	if err != nil {
+		h.finalize()
+		return nil, err
+	}
+	h.slot = slot
+
+	size := cfg.Sessions
+	if size <= 0 {
+		size = DefaultPKCS11Sessions
+	}
+	h.sessions = make(chan pkcs11.SessionHandle, size)
+	for range size {
+		sh, err := h.openSession()
+		if err != nil {
+			h.Close()
+			return nil, err
+		}
+		h.sessions <- sh
+		h.pooled++
+	}
+	return h, nil
+}
+
+// OpenPKCS11 is NewPKCS11 for callers that configure providers by type
+// and may be built without the pkcs11 tag.
+func OpenPKCS11(cfg PKCS11Config) (Provider, error) {
+	h, err := NewPKCS11(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *PKCS11) findSlot(label string) (uint, error) {
+	slots, err := h.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: list slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := h.ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if label == "" || strings.TrimRight(info.Label, " \x00") == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11: no token labelled %q", label)
+}
+
+// openSession opens a read-write session and logs the application in.
+// Login state is shared by all sessions, so only the first login does
+// anything.
+func (h *PKCS11) openSession() (pkcs11.SessionHandle, error) {
+	sh, err := h.ctx.OpenSession(h.slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: open session: %w", err)
+	}
+	if err := h.ctx.Login(sh, pkcs11.CKU_USER, h.pin); err != nil && !isCKR(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		h.ctx.CloseSession(sh)
+		return 0, fmt.Errorf("pkcs11: login: %w", err)
+	}
+	return sh, nil
+}
+
+// withSession runs fn on a pooled session. A session the token has closed
+// or logged out is replaced and fn retried once. Errors that mean the
+// token cannot be reached wrap ErrUnavailable, as does calling it once the
+// provider is closed.
+func (h *PKCS11) withSession(fn func(pkcs11.SessionHandle) error) error {
+	var sh pkcs11.SessionHandle
+	select {
+	case <-h.closed:
+		return fmt.Errorf("%w: pkcs11 provider closed", ErrUnavailable)
+	default:
+	}
+	select {
+	case sh = <-h.sessions:
+	case <-h.closed:
+		return fmt.Errorf("%w: pkcs11 provider closed", ErrUnavailable)
+	}
+	err := fn(sh)
+	if isCKR(err, pkcs11.CKR_SESSION_HANDLE_INVALID, pkcs11.CKR_SESSION_CLOSED, pkcs11.CKR_USER_NOT_LOGGED_IN) {
+		h.ctx.CloseSession(sh)
+		fresh, openErr := h.openSession()
+		if openErr != nil {
+			// Keep the pool size: the stale handle fails fast and is
+			// replaced again on its next use.
+			h.sessions <- sh
+			return fmt.Errorf("%w: %v", ErrUnavailable, openErr)
+		}
+		sh = fresh
+		err = fn(sh)
+	}
+	h.sessions <- sh
+	if isCKR(err, pkcs11.CKR_DEVICE_ERROR, pkcs11.CKR_DEVICE_REMOVED, pkcs11.CKR_TOKEN_NOT_PRESENT,
+		pkcs11.CKR_SESSION_HANDLE_INVALID, pkcs11.CKR_SESSION_CLOSED) {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return err
+}
+
+// Capabilities reports the operations done on the token, at most one per
+// pooled session.
+func (h *PKCS11) Capabilities() (Capabilities, error) {
+	return Capabilities{
+		Curves:         []elliptic.Curve{elliptic.P256(), elliptic.P384()},
+		Operations:     []Operation{OpGenerateKey, OpSign, OpECDH},
+		MaxConcurrency: cap(h.sessions),
+	}, nil
+}
+
+// Probe checks that a pooled session still reaches the token.
+func (h *PKCS11) Probe() error {
+	return h.withSession(func(sh pkcs11.SessionHandle) error {
+		_, err := h.ctx.GetSessionInfo(sh)
+		return err
+	})
+}
+
+// GenerateKey creates an ECDSA key pair on the token under the vault key
+// ID and returns its handle and public key.
+func (h *PKCS11) GenerateKey(id string, curve elliptic.Curve) (KeyHandle, *ecdsa.PublicKey, error) {
+	params, err := ecParams(curve)
+	if err != nil {
+		return "", nil, err
+	}
+	public := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(id)),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, id),
+	}
+	private := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DERIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(id)),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, id),
+	}
+
+	var key pkcs11Key
+	err = h.withSession(func(sh pkcs11.SessionHandle) error {
+		mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}
+		pubObj, privObj, err := h.ctx.GenerateKeyPair(sh, mech, public, private)
+		if err != nil {
+			return err
+		}
+		pub, err := h.readPublicKey(sh, pubObj, curve)
+		if err != nil {
+			return err
+		}
+		key = pkcs11Key{private: privObj, public: pub}
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("pkcs11: generate key: %w", err)
+	}
+
+	h.mu.Lock()
+	h.keys[id] = key
+	h.mu.Unlock()
+	return NewKeyHandle(pkcs11Provider, id), key.public, nil
+}
+
+// PublicKey returns the public key of a token key.
+func (h *PKCS11) PublicKey(handle KeyHandle) (*ecdsa.PublicKey, error) {
+	id, err := handle.Ref(pkcs11Provider)
+	if err != nil {
+		return nil, err
+	}
+	key, err := h.key(id)
+	if err != nil {
+		return nil, err
+	}
+	return key.public, nil
+}
+
+// Sign signs the SHA-256 digest of data on the token and returns an
+// ASN.1 DER signature, the format SoftwareHSM produces.
+func (h *PKCS11) Sign(handle KeyHandle, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	var raw []byte
+	err := h.withKey(handle, func(sh pkcs11.SessionHandle, key pkcs11Key) error {
+		if err := h.ctx.SignInit(sh, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, key.private); err != nil {
+			return err
+		}
+		var err error
+		raw, err = h.ctx.Sign(sh, digest[:])
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: sign: %w", err)
+	}
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, fmt.Errorf("pkcs11: sign: malformed signature of %d bytes", len(raw))
+	}
+	half := len(raw) / 2
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		new(big.Int).SetBytes(raw[:half]),
+		new(big.Int).SetBytes(raw[half:]),
+	})
+}
+
+// Verify checks a signature in software against the public key.
+func (h *PKCS11) Verify(pub *ecdsa.PublicKey, data, signature []byte) bool {
+	return crypto.VerifyECDSA(pub, data, signature)
+}
+
+// ECDH computes the shared secret between the token key and a peer public
+// key on the same curve. The secret is derived on the token into a
+// temporary session object, read out and destroyed.
+func (h *PKCS11) ECDH(handle KeyHandle, peer *ecdh.PublicKey) ([]byte, error) {
+	var secret []byte
+	err := h.withKey(handle, func(sh pkcs11.SessionHandle, key pkcs11Key) error {
+		params := pkcs11.NewECDH1DeriveParams(pkcs11.CKD_NULL, nil, peer.Bytes())
+		mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDH1_DERIVE, params)}
+		size := (key.public.Curve.Params().BitSize + 7) / 8
+		obj, err := h.ctx.DeriveKey(sh, mech, key.private, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_GENERIC_SECRET),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, size),
+		})
+		if err != nil {
+			return err
+		}
+		defer h.ctx.DestroyObject(sh, obj)
+		attrs, err := h.ctx.GetAttributeValue(sh, obj, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil)})
+		if err != nil {
+			return err
+		}
+		secret = attrs[0].Value
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: ecdh: %w", err)
+	}
+	return secret, nil
+}
+
+func (h *PKCS11) Encrypt(KeyHandle, []byte, []byte) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+func (h *PKCS11) Decrypt(KeyHandle, []byte, []byte) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+func (h *PKCS11) Derive(KeyHandle, crypto.HKDFHash, []byte, []byte, int) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+// DestroyKey removes both halves of the key pair from the token.
+func (h *PKCS11) DestroyKey(handle KeyHandle) error {
+	id, err := handle.Ref(pkcs11Provider)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	delete(h.keys, id)
+	h.mu.Unlock()
+	return h.withSession(func(sh pkcs11.SessionHandle) error {
+		objs, err := h.findObjects(sh, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(id))})
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if err := h.ctx.DestroyObject(sh, obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close logs out, closes every pooled session and unloads the module.
+// Calls waiting for a session fail; calls holding one finish first, and
+// Close waits for their sessions to come back rather than closing the pool
+// under them.
+func (h *PKCS11) Close() error {
+	h.mu.Lock()
+	if h.isClosed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.isClosed = true
+	h.mu.Unlock()
+
+	close(h.closed)
+	for i := range h.pooled {
+		sh := <-h.sessions
+		if i == 0 {
+			h.ctx.Logout(sh)
+		}
+		h.ctx.CloseSession(sh)
+	}
+	h.finalize()
+	return nil
+}
+
+func (h *PKCS11) finalize() {
+	h.ctx.Finalize()
+	h.ctx.Destroy()
+}
+
+// withKey runs fn with the token key a handle names. A cached object
+// handle the token no longer recognises is looked up again.
+func (h *PKCS11) withKey(handle KeyHandle, fn func(pkcs11.SessionHandle, pkcs11Key) error) error {
+	id, err := handle.Ref(pkcs11Provider)
+	if err != nil {
+		return err
+	}
+	key, err := h.key(id)
+	if err != nil {
+		return err
+	}
+	return h.withSession(func(sh pkcs11.SessionHandle) error {
+		err := fn(sh, key)
+		if isCKR(err, pkcs11.CKR_OBJECT_HANDLE_INVALID, pkcs11.CKR_KEY_HANDLE_INVALID) {
+			h.mu.Lock()
+			delete(h.keys, id)
+			h.mu.Unlock()
+			if key, err = h.lookup(sh, id); err != nil {
+				return err
+			}
+			err = fn(sh, key)
+		}
+		return err
+	})
+}
+
+// key returns the cached token key for id, finding it on the token on
+// first use.
+func (h *PKCS11) key(id string) (pkcs11Key, error) {
+	h.mu.Lock()
+	key, ok := h.keys[id]
+	h.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	err := h.withSession(func(sh pkcs11.SessionHandle) error {
+		var err error
+		key, err = h.lookup(sh, id)
+		return err
+	})
+	return key, err
+}
+
+func (h *PKCS11) lookup(sh pkcs11.SessionHandle, id string) (pkcs11Key, error) {
+	find := func(class uint) (pkcs11.ObjectHandle, error) {
+		objs, err := h.findObjects(sh, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, []byte(id)),
+		})
+		if err != nil {
+			return 0, err
+		}
+		if len(objs) == 0 {
+			return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+		}
+		return objs[0], nil
+	}
+	privObj, err := find(pkcs11.CKO_PRIVATE_KEY)
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+	pubObj, err := find(pkcs11.CKO_PUBLIC_KEY)
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+	attrs, err := h.ctx.GetAttributeValue(sh, pubObj, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil)})
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+	curve, err := curveFromParams(attrs[0].Value)
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+	pub, err := h.readPublicKey(sh, pubObj, curve)
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+
+	key := pkcs11Key{private: privObj, public: pub}
+	h.mu.Lock()
+	h.keys[id] = key
+	h.mu.Unlock()
+	return key, nil
+}
+
+func (h *PKCS11) findObjects(sh pkcs11.SessionHandle, template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	if err := h.ctx.FindObjectsInit(sh, template); err != nil {
+		return nil, err
+	}
+	defer h.ctx.FindObjectsFinal(sh)
+	var all []pkcs11.ObjectHandle
+	for {
+		objs, more, err := h.ctx.FindObjects(sh, 16)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, objs...)
+		if !more || len(objs) == 0 {
+			return all, nil
+		}
+	}
+}
+
+func (h *PKCS11) readPublicKey(sh pkcs11.SessionHandle, obj pkcs11.ObjectHandle, curve elliptic.Curve) (*ecdsa.PublicKey, error) {
+	attrs, err := h.ctx.GetAttributeValue(sh, obj, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return nil, err
+	}
+	point := attrs[0].Value
+	// CKA_EC_POINT is a DER OCTET STRING holding the point, though some
+	// tokens return the bare point.
+	size := (curve.Params().BitSize + 7) / 8
+	if len(point) != 1+2*size {
+		var inner []byte
+		if _, err := asn1.Unmarshal(point, &inner); err != nil {
+			return nil, fmt.Errorf("parse ec point: %w", err)
+		}
+		point = inner
+	}
+	return ecdsa.ParseUncompressedPublicKey(curve, point)
+}
+
+var (
+	oidP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+)
+
+// ecParams returns the CKA_EC_PARAMS of a named curve.
+func ecParams(curve elliptic.Curve) ([]byte, error) {
+	switch curve {
+	case elliptic.P256():
+		return asn1.Marshal(oidP256)
+	case elliptic.P384():
+		return asn1.Marshal(oidP384)
+	default:
+		return nil, fmt.Errorf("pkcs11: unsupported curve %s", curve.Params().Name)
+	}
+}
+
+func curveFromParams(params []byte) (elliptic.Curve, error) {
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		return nil, fmt.Errorf("parse ec params: %w", err)
+	}
+	switch {
+	case oid.Equal(oidP256):
+		return elliptic.P256(), nil
+	case oid.Equal(oidP384):
+		return elliptic.P384(), nil
+	default:
+		return nil, fmt.Errorf("pkcs11: unsupported curve %v", oid)
+	}
+}
+
+func isCKR(err error, codes ...uint) bool {
+	var e pkcs11.Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	for _, code := range codes {
+		if uint(e) == code {
+			return true
+		}
+	}
+	return false
+}
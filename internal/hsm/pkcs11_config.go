@@ -0,0 +1,23 @@
+package hsm
+
+import "errors"
+
+// ErrKeyNotFound is returned when a provider holds no key for an ID.
+var ErrKeyNotFound = errors.New("hsm key not found")
+
+// DefaultPKCS11Sessions is the session pool size when none is configured.
+const DefaultPKCS11Sessions = 4
+
+// PKCS11Config locates a PKCS#11 token. The provider itself is only built
+// with the pkcs11 build tag, since the module is loaded through cgo.
+type PKCS11Config struct {
+	// Module is the path of the PKCS#11 library, such as
+	// /usr/lib/softhsm/libsofthsm2.so.
+	Module string
+	// TokenLabel selects the token; empty takes the first token present.
+	TokenLabel string
+	// PIN is the normal user PIN.
+	PIN string
+	// Sessions is the number of pooled sessions.
+	Sessions int
+}
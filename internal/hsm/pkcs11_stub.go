@@ -0,0 +1,11 @@
+//go:build !pkcs11
+
+package hsm
+
+import "errors"
+
+// OpenPKCS11 fails in builds without the pkcs11 tag, which leave out the
+// cgo PKCS#11 provider.
+func OpenPKCS11(PKCS11Config) (Provider, error) {
+	return nil, errors.New("pkcs11: support not built in, rebuild with -tags pkcs11")
+}
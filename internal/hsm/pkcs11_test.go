@@ -0,0 +1,195 @@
+//go:build pkcs11
+
+package hsm
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/miekg/pkcs11"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// newTestPKCS11 connects to the token named by the environment, for
+// example a SoftHSMv2 token initialised with
+//
+//	softhsm2-util --init-token --free --label vault-test --pin 1234 --so-pin 1234
+//	VAULT_TEST_PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so \
+//	VAULT_TEST_PKCS11_TOKEN=vault-test VAULT_TEST_PKCS11_PIN=1234 \
+//	go test -tags pkcs11 ./internal/hsm/
+func newTestPKCS11(t *testing.T) *PKCS11 {
+	t.Helper()
+	module := os.Getenv("VAULT_TEST_PKCS11_MODULE")
+	if module == "" {
+		t.Skip("VAULT_TEST_PKCS11_MODULE not set")
+	}
+	h, err := NewPKCS11(PKCS11Config{
+		Module:     module,
+		TokenLabel: os.Getenv("VAULT_TEST_PKCS11_TOKEN"),
+		PIN:        os.Getenv("VAULT_TEST_PKCS11_PIN"),
+		Sessions:   2,
+	})
+	if err != nil {
+		t.Fatalf("new pkcs11: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func TestPKCS11SignVerify(t *testing.T) {
+	h := newTestPKCS11(t)
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		id := uuid.NewString()
+		handle, pub, err := h.GenerateKey(id, curve)
+		if err != nil {
+			t.Fatalf("%s: generate: %v", curve.Params().Name, err)
+		}
+		t.Cleanup(func() { h.DestroyKey(handle) })
+
+		sig, err := h.Sign(handle, []byte("payload"))
+		if err != nil {
+			t.Fatalf("%s: sign: %v", curve.Params().Name, err)
+		}
+		if !crypto.VerifyECDSA(pub, []byte("payload"), sig) {
+			t.Fatalf("%s: token signature does not verify in software", curve.Params().Name)
+		}
+		if h.Verify(pub, []byte("other"), sig) {
+			t.Fatalf("%s: signature verified for other data", curve.Params().Name)
+		}
+	}
+}
+
+func TestPKCS11KeysAreNotExtractable(t *testing.T) {
+	h := newTestPKCS11(t)
+	id := uuid.NewString()
+	handle, _, err := h.GenerateKey(id, elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	t.Cleanup(func() { h.DestroyKey(handle) })
+
+	key, err := h.key(id)
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	err = h.withSession(func(sh pkcs11.SessionHandle) error {
+		_, err := h.ctx.GetAttributeValue(sh, key.private, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil)})
+		return err
+	})
+	if err == nil {
+		t.Fatal("reading CKA_VALUE of a private key should fail")
+	}
+}
+
+func TestPKCS11ReopensKeysByID(t *testing.T) {
+	h := newTestPKCS11(t)
+	id := uuid.NewString()
+	handle, pub, err := h.GenerateKey(id, elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	t.Cleanup(func() { h.DestroyKey(handle) })
+
+	// Forget the cached handle: the key must be found by CKA_ID.
+	h.mu.Lock()
+	delete(h.keys, id)
+	h.mu.Unlock()
+	got, err := h.PublicKey(handle)
+	if err != nil {
+		t.Fatalf("public key: %v", err)
+	}
+	if !got.Equal(pub) {
+		t.Fatal("public key found by ID does not match")
+	}
+
+	if _, err := h.PublicKey(NewKeyHandle(pkcs11Provider, uuid.NewString())); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("unknown ID: got %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestPKCS11ECDH(t *testing.T) {
+	h := newTestPKCS11(t)
+	id := uuid.NewString()
+	handle, pub, err := h.GenerateKey(id, elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	t.Cleanup(func() { h.DestroyKey(handle) })
+
+	peer, _ := ecdh.P256().GenerateKey(rand.Reader)
+	ours, err := h.ECDH(handle, peer.PublicKey())
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	tokenPub, _ := pub.ECDH()
+	theirs, _ := peer.ECDH(tokenPub)
+	if string(ours) != string(theirs) {
+		t.Fatal("shared secrets differ")
+	}
+}
+
+func TestPKCS11RejectsForeignHandles(t *testing.T) {
+	h := newTestPKCS11(t)
+	soft, _, err := NewSoftwareHSM().GenerateKey("soft", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate software key: %v", err)
+	}
+	if _, err := h.Sign(soft, []byte("data")); !errors.Is(err, ErrInvalidHandle) {
+		t.Fatalf("software handle: got %v, want ErrInvalidHandle", err)
+	}
+}
+
+func TestPKCS11ConcurrentSessions(t *testing.T) {
+	h := newTestPKCS11(t)
+	id := uuid.NewString()
+	handle, pub, err := h.GenerateKey(id, elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	t.Cleanup(func() { h.DestroyKey(handle) })
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			sig, err := h.Sign(handle, []byte("concurrent"))
+			if err != nil || !h.Verify(pub, []byte("concurrent"), sig) {
+				t.Errorf("sign over a pooled session: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestPKCS11CloseUnderLoad(t *testing.T) {
+	h := newTestPKCS11(t)
+	handle, _, err := h.GenerateKey(uuid.NewString(), elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	// Calls racing Close either finish or fail as unavailable; none may
+	// panic on the pool.
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			for range 8 {
+				if _, err := h.Sign(handle, []byte("closing")); err != nil && !errors.Is(err, ErrUnavailable) {
+					t.Errorf("sign while closing: %v", err)
+					return
+				}
+			}
+		})
+	}
+	h.Close()
+	wg.Wait()
+	if _, err := h.Sign(handle, []byte("closed")); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("sign after close: got %v, want ErrUnavailable", err)
+	}
+}
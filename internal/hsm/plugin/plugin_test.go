@@ -0,0 +1,241 @@
+package plugin
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+)
+
+// startPlugin serves p on socket until the test ends or stop is called.
+func startPlugin(t *testing.T, socket string, p hsm.Provider) (stop func()) {
+	t.Helper()
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	Register(srv, p)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return srv.Stop
+}
+
+func dial(t *testing.T, socket string, timeout time.Duration) *Remote {
+	t.Helper()
+	r, err := Dial(Config{Socket: socket, Timeout: timeout, HealthInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestRemoteRoundTrip(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+	local := hsm.NewSoftwareHSM()
+	startPlugin(t, socket, local)
+	r := dial(t, socket, time.Second)
+	if !r.Healthy() {
+		t.Fatal("plugin should be healthy after dial")
+	}
+
+	h, pub, err := r.GenerateKey("key-1", elliptic.P384())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if h.Provider() != "plugin" || pub.Curve != elliptic.P384() {
+		t.Fatalf("handle %q curve %s", h, pub.Curve.Params().Name)
+	}
+
+	sig, err := r.Sign(h, []byte("data"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if !r.Verify(pub, []byte("data"), sig) {
+		t.Fatal("signature should verify")
+	}
+
+	peer, err := ecdh.P384().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("peer key: %v", err)
+	}
+	secret, err := r.ECDH(h, peer.PublicKey())
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	ours, err := pub.ECDH()
+	if err != nil {
+		t.Fatalf("convert public key: %v", err)
+	}
+	want, err := peer.ECDH(ours)
+	if err != nil {
+		t.Fatalf("peer ecdh: %v", err)
+	}
+	if !bytes.Equal(secret, want) {
+		t.Fatal("shared secrets differ")
+	}
+
+	ct, err := r.Encrypt(h, []byte("plaintext"), []byte("aad"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	pt, err := r.Decrypt(h, ct, []byte("aad"))
+	if err != nil || string(pt) != "plaintext" {
+		t.Fatalf("decrypt: %q, %v", pt, err)
+	}
+
+	out, err := r.Derive(h, crypto.HKDFSHA512, []byte("salt"), []byte("info"), 48)
+	if err != nil {
+		t.Fatalf("derive: %v", err)
+	}
+	ref, _ := h.Ref("plugin")
+	direct, err := local.Derive(hsm.KeyHandle(ref), crypto.HKDFSHA512, []byte("salt"), []byte("info"), 48)
+	if err != nil || !bytes.Equal(out, direct) {
+		t.Fatal("derive over the plugin should match the provider")
+	}
+
+	if _, err := r.Sign(hsm.NewKeyHandle("software", "x"), nil); !errors.Is(err, hsm.ErrInvalidHandle) {
+		t.Fatalf("foreign handle: got %v, want ErrInvalidHandle", err)
+	}
+}
+
+// noDerive is a provider that cannot derive, like a PKCS#11 token.
+type noDerive struct{ *hsm.SoftwareHSM }
+
+func (noDerive) Derive(hsm.KeyHandle, crypto.HKDFHash, []byte, []byte, int) ([]byte, error) {
+	return nil, hsm.ErrUnsupported
+}
+
+func (noDerive) Capabilities() (hsm.Capabilities, error) {
+	return hsm.Capabilities{
+		Curves:         []elliptic.Curve{elliptic.P256()},
+		Operations:     []hsm.Operation{hsm.OpGenerateKey, hsm.OpSign, hsm.OpECDH},
+		MaxConcurrency: 4,
+	}, nil
+}
+
+// slowSign is a provider whose signatures outlast the call timeout.
+type slowSign struct{ *hsm.SoftwareHSM }
+
+func (p slowSign) Sign(h hsm.KeyHandle, data []byte) ([]byte, error) {
+	time.Sleep(200 * time.Millisecond)
+	return p.SoftwareHSM.Sign(h, data)
+}
+
+func TestRemoteErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	socket := filepath.Join(dir, "noderive.sock")
+	startPlugin(t, socket, noDerive{hsm.NewSoftwareHSM()})
+	r := dial(t, socket, time.Second)
+	h, _, err := r.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := r.Derive(h, crypto.HKDFSHA256, nil, nil, 32); !errors.Is(err, hsm.ErrUnsupported) {
+		t.Fatalf("unsupported: got %v, want ErrUnsupported", err)
+	}
+
+	socket = filepath.Join(dir, "slow.sock")
+	startPlugin(t, socket, slowSign{hsm.NewSoftwareHSM()})
+	r = dial(t, socket, 50*time.Millisecond)
+	h, _, err = r.GenerateKey("key-2", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := r.Sign(h, []byte("data")); !errors.Is(err, hsm.ErrUnavailable) {
+		t.Fatalf("timeout: got %v, want ErrUnavailable", err)
+	}
+}
+
+func TestRemoteCapabilities(t *testing.T) {
+	dir := t.TempDir()
+
+	socket := filepath.Join(dir, "noderive.sock")
+	startPlugin(t, socket, noDerive{hsm.NewSoftwareHSM()})
+	c, err := dial(t, socket, time.Second).Capabilities()
+	if err != nil {
+		t.Fatalf("capabilities: %v", err)
+	}
+	if len(c.Curves) != 1 || c.Curves[0] != elliptic.P256() || c.MaxConcurrency != 4 {
+		t.Fatalf("capabilities: %+v", c)
+	}
+	if !c.Supports(hsm.OpSign) || c.Supports(hsm.OpDerive) || len(c.DeriveHashes) != 0 {
+		t.Fatalf("operations: %v, hashes %v", c.Operations, c.DeriveHashes)
+	}
+
+	// A plugin built before GetCapabilities is taken to support
+	// everything.
+	socket = filepath.Join(dir, "old.sock")
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterHSMPluginServer(srv, &pb.UnimplementedHSMPluginServer{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	c, err = dial(t, socket, time.Second).Capabilities()
+	if err != nil {
+		t.Fatalf("capabilities of an older plugin: %v", err)
+	}
+	if len(c.Operations) != len(hsm.KeyOperations) || len(c.DeriveHashes) != 3 {
+		t.Fatalf("older plugin: %+v", c)
+	}
+}
+
+func TestRemoteReconnects(t *testing.T) {
+	dir := t.TempDir()
+	socket, keystore := filepath.Join(dir, "plugin.sock"), filepath.Join(dir, "keys.json")
+	p, err := hsm.OpenSoftwareHSM(keystore)
+	if err != nil {
+		t.Fatalf("open keystore: %v", err)
+	}
+	stop := startPlugin(t, socket, p)
+	r := dial(t, socket, time.Second)
+
+	h, _, err := r.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	stop()
+	if _, err := r.Sign(h, []byte("data")); !errors.Is(err, hsm.ErrUnavailable) {
+		t.Fatalf("plugin down: got %v, want ErrUnavailable", err)
+	}
+	waitFor(t, func() bool { return !r.Healthy() })
+
+	// The restarted plugin reloads its keystore, so it can still use the
+	// key generated before the crash.
+	if p, err = hsm.OpenSoftwareHSM(keystore); err != nil {
+		t.Fatalf("reopen keystore: %v", err)
+	}
+	startPlugin(t, socket, p)
+	waitFor(t, r.Healthy)
+	if _, err := r.Sign(h, []byte("data")); err != nil {
+		t.Fatalf("sign after restart: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,394 @@
+package plugin
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+)
+
+// remoteProvider names the handles Remote issues. They wrap the handle
+// the plugin returned.
+const remoteProvider = "plugin"
+
+const (
+	// DefaultTimeout bounds each call to a plugin when none is configured.
+	DefaultTimeout = 5 * time.Second
+	// DefaultHealthInterval is how often a plugin's health is checked
+	// when no interval is configured.
+	DefaultHealthInterval = 5 * time.Second
+)
+
+// Config locates a plugin.
+type Config struct {
+	// Socket is the path of the plugin's Unix socket.
+	Socket string
+	// Timeout bounds each call.
+	Timeout time.Duration
+	// HealthInterval is how often the plugin's health is checked.
+	HealthInterval time.Duration
+}
+
+// Remote is an hsm.Provider served by a plugin process. The connection is
+// re-established whenever the plugin restarts: a failed health check
+// resets the reconnection backoff so the next attempt is immediate. Calls
+// fail with hsm.ErrUnavailable while the plugin is down or when it does
+// not answer within the timeout.
+type Remote struct {
+	conn    *grpc.ClientConn
+	client  pb.HSMPluginClient
+	health  healthpb.HealthClient
+	timeout time.Duration
+
+	healthy atomic.Bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+var (
+	_ hsm.Provider           = (*Remote)(nil)
+	_ hsm.Prober             = (*Remote)(nil)
+	_ hsm.CapabilityReporter = (*Remote)(nil)
+)
+
+// Dial connects to the plugin at cfg.Socket. A plugin that is not up yet
+// does not fail Dial; calls fail until it is.
+func Dial(cfg Config) (*Remote, error) {
+	if cfg.Socket == "" {
+		return nil, errors.New("plugin: socket path is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = DefaultHealthInterval
+	}
+	conn, err := grpc.NewClient("unix:"+cfg.Socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("plugin: %w", err)
+	}
+
+	r := &Remote{
+		conn:    conn,
+		client:  pb.NewHSMPluginClient(conn),
+		health:  healthpb.NewHealthClient(conn),
+		timeout: cfg.Timeout,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	conn.Connect()
+	r.check(cfg.Socket)
+	go r.watch(cfg.Socket, cfg.HealthInterval)
+	return r, nil
+}
+
+// Healthy reports whether the last health check found the plugin serving.
+func (r *Remote) Healthy() bool {
+	return r.healthy.Load()
+}
+
+// Close stops health checking and closes the connection.
+func (r *Remote) Close() error {
+	close(r.stop)
+	<-r.done
+	return r.conn.Close()
+}
+
+func (r *Remote) watch(socket string, interval time.Duration) {
+	defer close(r.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.check(socket)
+		}
+	}
+}
+
+// Probe asks the plugin for its health.
+func (r *Remote) Probe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	resp, err := r.health.Check(ctx, &healthpb.HealthCheckRequest{Service: pb.HSMPlugin_ServiceDesc.ServiceName})
+	if err != nil {
+		return fromStatus(err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: plugin is %v", hsm.ErrUnavailable, resp.Status)
+	}
+	return nil
+}
+
+// check probes the plugin and logs changes.
+func (r *Remote) check(socket string) {
+	err := r.Probe()
+	healthy := err == nil
+	if !healthy {
+		r.conn.ResetConnectBackoff()
+	}
+	if r.healthy.Swap(healthy) != healthy {
+		if healthy {
+			slog.Info("hsm plugin healthy", "socket", socket)
+		} else {
+			slog.Warn("hsm plugin unhealthy", "socket", socket, "error", err)
+		}
+	}
+}
+
+// call runs fn with the per-call timeout and translates its status.
+func (r *Remote) call(fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	return fromStatus(fn(ctx))
+}
+
+func (r *Remote) GenerateKey(id string, curve elliptic.Curve) (hsm.KeyHandle, *ecdsa.PublicKey, error) {
+	algo, ok := curveToProto(curve)
+	if !ok {
+		return "", nil, fmt.Errorf("plugin: unsupported curve %s", curve.Params().Name)
+	}
+
+	var resp *pb.PluginGenerateKeyResponse
+	err := r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.GenerateKey(ctx, &pb.PluginGenerateKeyRequest{KeyId: id, Algorithm: algo})
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(resp.PublicKeyDer)
+	if err != nil {
+		return "", nil, fmt.Errorf("plugin: parse public key: %w", err)
+	}
+	pub, ok := parsed.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != curve {
+		return "", nil, errors.New("plugin: returned a public key on the wrong curve")
+	}
+	return hsm.NewKeyHandle(remoteProvider, resp.Handle), pub, nil
+}
+
+func (r *Remote) Sign(h hsm.KeyHandle, data []byte) ([]byte, error) {
+	ref, err := h.Ref(remoteProvider)
+	if err != nil {
+		return nil, err
+	}
+	var resp *pb.PluginSignResponse
+	err = r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.Sign(ctx, &pb.PluginSignRequest{Handle: ref, Data: data})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+func (r *Remote) Verify(pub *ecdsa.PublicKey, data, signature []byte) bool {
+	return crypto.VerifyECDSA(pub, data, signature)
+}
+
+func (r *Remote) ECDH(h hsm.KeyHandle, peer *ecdh.PublicKey) ([]byte, error) {
+	ref, err := h.Ref(remoteProvider)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(peer)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: marshal peer key: %w", err)
+	}
+	var resp *pb.PluginECDHResponse
+	err = r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.ECDH(ctx, &pb.PluginECDHRequest{Handle: ref, PeerPublicKeyDer: der})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.SharedSecret, nil
+}
+
+func (r *Remote) Encrypt(h hsm.KeyHandle, plaintext, aad []byte) ([]byte, error) {
+	ref, err := h.Ref(remoteProvider)
+	if err != nil {
+		return nil, err
+	}
+	var resp *pb.PluginEncryptResponse
+	err = r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.Encrypt(ctx, &pb.PluginEncryptRequest{Handle: ref, Plaintext: plaintext, Aad: aad})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+func (r *Remote) Decrypt(h hsm.KeyHandle, ciphertext, aad []byte) ([]byte, error) {
+	ref, err := h.Ref(remoteProvider)
+	if err != nil {
+		return nil, err
+	}
+	var resp *pb.PluginDecryptResponse
+	err = r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.Decrypt(ctx, &pb.PluginDecryptRequest{Handle: ref, Ciphertext: ciphertext, Aad: aad})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+func (r *Remote) Derive(h hsm.KeyHandle, hash crypto.HKDFHash, salt, info []byte, length int) ([]byte, error) {
+	ref, err := h.Ref(remoteProvider)
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("plugin: invalid derive length %d", length)
+	}
+	var resp *pb.PluginDeriveResponse
+	err = r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.Derive(ctx, &pb.PluginDeriveRequest{
+			Handle: ref,
+			Hash:   hashToProto(hash),
+			Salt:   salt,
+			Info:   info,
+			Length: uint32(length),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Output, nil
+}
+
+// Capabilities asks the plugin what it supports. Plugins that predate
+// GetCapabilities are taken to support every operation.
+func (r *Remote) Capabilities() (hsm.Capabilities, error) {
+	var resp *pb.PluginGetCapabilitiesResponse
+	err := r.call(func(ctx context.Context) (err error) {
+		resp, err = r.client.GetCapabilities(ctx, &pb.PluginGetCapabilitiesRequest{})
+		return err
+	})
+	if errors.Is(err, hsm.ErrUnsupported) {
+		return hsm.FullCapabilities(), nil
+	}
+	if err != nil {
+		return hsm.Capabilities{}, err
+	}
+	c := hsm.Capabilities{MaxConcurrency: int(resp.MaxConcurrency)}
+	for _, algo := range resp.Algorithms {
+		if curve, ok := curveFromProto(algo); ok {
+			c.Curves = append(c.Curves, curve)
+		}
+	}
+	for _, op := range hsm.KeyOperations {
+		if slices.Contains(resp.Operations, operations[op]) {
+			c.Operations = append(c.Operations, op)
+		}
+	}
+	for _, h := range resp.DeriveHashes {
+		if hash, err := hashFromProto(h); err == nil {
+			c.DeriveHashes = append(c.DeriveHashes, hash)
+		}
+	}
+	return c, nil
+}
+
+// fromStatus translates a plugin's status back into the provider errors
+// the vault server handles.
+func fromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", hsm.ErrKeyNotFound, st.Message())
+	case codes.Unimplemented:
+		return fmt.Errorf("%w: %s", hsm.ErrUnsupported, st.Message())
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", hsm.ErrUnavailable, st.Message())
+	default:
+		return fmt.Errorf("plugin: %s", st.Message())
+	}
+}
+
+// operations maps the key operations to the HsmOperation values that
+// name them in GetCapabilities.
+var operations = map[hsm.Operation]pb.HsmOperation{
+	hsm.OpGenerateKey: pb.HsmOperation_HSM_OPERATION_GENERATE_KEY,
+	hsm.OpSign:        pb.HsmOperation_HSM_OPERATION_SIGN,
+	hsm.OpECDH:        pb.HsmOperation_HSM_OPERATION_ECDH,
+	hsm.OpEncrypt:     pb.HsmOperation_HSM_OPERATION_ENCRYPT,
+	hsm.OpDecrypt:     pb.HsmOperation_HSM_OPERATION_DECRYPT,
+	hsm.OpDerive:      pb.HsmOperation_HSM_OPERATION_DERIVE,
+}
+
+func curveToProto(curve elliptic.Curve) (pb.KeyAlgorithm, bool) {
+	switch curve {
+	case elliptic.P256():
+		return pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256, true
+	case elliptic.P384():
+		return pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P384, true
+	default:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED, false
+	}
+}
+
+func curveFromProto(algo pb.KeyAlgorithm) (elliptic.Curve, bool) {
+	switch algo {
+	case pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256:
+		return elliptic.P256(), true
+	case pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P384:
+		return elliptic.P384(), true
+	default:
+		return nil, false
+	}
+}
+
+func hashToProto(h crypto.HKDFHash) pb.HkdfHash {
+	switch h {
+	case crypto.HKDFSHA384:
+		return pb.HkdfHash_HKDF_HASH_SHA384
+	case crypto.HKDFSHA512:
+		return pb.HkdfHash_HKDF_HASH_SHA512
+	default:
+		return pb.HkdfHash_HKDF_HASH_SHA256
+	}
+}
+
+func hashFromProto(h pb.HkdfHash) (crypto.HKDFHash, error) {
+	switch h {
+	case pb.HkdfHash_HKDF_HASH_UNSPECIFIED, pb.HkdfHash_HKDF_HASH_SHA256:
+		return crypto.HKDFSHA256, nil
+	case pb.HkdfHash_HKDF_HASH_SHA384:
+		return crypto.HKDFSHA384, nil
+	case pb.HkdfHash_HKDF_HASH_SHA512:
+		return crypto.HKDFSHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported hkdf hash: %v", h)
+	}
+}
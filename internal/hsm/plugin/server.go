@@ -0,0 +1,145 @@
+// Package plugin runs HSM providers out of process. A plugin binary serves
+// the HSMPlugin gRPC service over a Unix socket with Register, and the
+// vault server reaches it through Remote, an hsm.Provider, so vendor SDKs
+// are linked into, and crash with, the plugin rather than the server.
+package plugin
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+)
+
+// Server serves an hsm.Provider as the HSMPlugin service.
+type Server struct {
+	pb.UnimplementedHSMPluginServer
+	provider hsm.Provider
+}
+
+func NewServer(p hsm.Provider) *Server {
+	return &Server{provider: p}
+}
+
+// Register registers p as the HSMPlugin service on srv, along with the
+// health service the vault server checks. The returned health server
+// reports SERVING until the plugin marks itself otherwise, for instance
+// when shutting down.
+func Register(srv *grpc.Server, p hsm.Provider) *health.Server {
+	pb.RegisterHSMPluginServer(srv, NewServer(p))
+	hs := health.NewServer()
+	hs.SetServingStatus(pb.HSMPlugin_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	return hs
+}
+
+func (s *Server) GenerateKey(ctx context.Context, req *pb.PluginGenerateKeyRequest) (*pb.PluginGenerateKeyResponse, error) {
+	curve, ok := curveFromProto(req.Algorithm)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported algorithm: %v", req.Algorithm)
+	}
+	h, pub, err := s.provider.GenerateKey(req.KeyId, curve)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	der, err := crypto.MarshalPublicKey(pub)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &pb.PluginGenerateKeyResponse{Handle: string(h), PublicKeyDer: der}, nil
+}
+
+func (s *Server) Sign(ctx context.Context, req *pb.PluginSignRequest) (*pb.PluginSignResponse, error) {
+	sig, err := s.provider.Sign(hsm.KeyHandle(req.Handle), req.Data)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.PluginSignResponse{Signature: sig}, nil
+}
+
+func (s *Server) ECDH(ctx context.Context, req *pb.PluginECDHRequest) (*pb.PluginECDHResponse, error) {
+	peer, err := crypto.ParseAgreementPublicKey(req.PeerPublicKeyDer)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "peer: %v", err)
+	}
+	secret, err := s.provider.ECDH(hsm.KeyHandle(req.Handle), peer)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.PluginECDHResponse{SharedSecret: secret}, nil
+}
+
+func (s *Server) Encrypt(ctx context.Context, req *pb.PluginEncryptRequest) (*pb.PluginEncryptResponse, error) {
+	ct, err := s.provider.Encrypt(hsm.KeyHandle(req.Handle), req.Plaintext, req.Aad)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.PluginEncryptResponse{Ciphertext: ct}, nil
+}
+
+func (s *Server) Decrypt(ctx context.Context, req *pb.PluginDecryptRequest) (*pb.PluginDecryptResponse, error) {
+	pt, err := s.provider.Decrypt(hsm.KeyHandle(req.Handle), req.Ciphertext, req.Aad)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.PluginDecryptResponse{Plaintext: pt}, nil
+}
+
+func (s *Server) Derive(ctx context.Context, req *pb.PluginDeriveRequest) (*pb.PluginDeriveResponse, error) {
+	hash, err := hashFromProto(req.Hash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	out, err := s.provider.Derive(hsm.KeyHandle(req.Handle), hash, req.Salt, req.Info, int(req.Length))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.PluginDeriveResponse{Output: out}, nil
+}
+
+func (s *Server) GetCapabilities(ctx context.Context, req *pb.PluginGetCapabilitiesRequest) (*pb.PluginGetCapabilitiesResponse, error) {
+	c, err := hsm.ProviderCapabilities(s.provider)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.PluginGetCapabilitiesResponse{MaxConcurrency: uint32(c.MaxConcurrency)}
+	for _, curve := range c.Curves {
+		if algo, ok := curveToProto(curve); ok {
+			resp.Algorithms = append(resp.Algorithms, algo)
+		}
+	}
+	for _, op := range c.Operations {
+		if o, ok := operations[op]; ok {
+			resp.Operations = append(resp.Operations, o)
+		}
+	}
+	for _, h := range c.DeriveHashes {
+		resp.DeriveHashes = append(resp.DeriveHashes, hashToProto(h))
+	}
+	return resp, nil
+}
+
+// toStatus reports a provider error with the status code the HSMPlugin
+// service documents for it.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, hsm.ErrInvalidHandle):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, hsm.ErrKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, hsm.ErrUnsupported):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, hsm.ErrUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -1,14 +1,189 @@
 package hsm
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+var (
+	// ErrInvalidHandle is returned for a handle the provider did not issue.
+	ErrInvalidHandle = errors.New("invalid hsm key handle")
+	// ErrUnsupported is returned for an operation the provider cannot
+	// perform without exporting key material.
+	ErrUnsupported = errors.New("operation not supported by hsm provider")
+	// ErrUnavailable is returned when a provider cannot be reached, such
+	// as an HSM plugin that is down or did not answer in time.
+	ErrUnavailable = errors.New("hsm provider unavailable")
 )
 
-// Provider abstracts hardware security module operations.
-// Real implementations would delegate to PKCS#11 or cloud KMS.
+// KeyHandle is an opaque reference to a private key held by a Provider,
+// stored with the key in place of its material. It has the form
+// "<provider>:<reference>"; what the reference holds is up to the provider
+// that issued it.
+type KeyHandle string
+
+// NewKeyHandle returns the handle for ref issued by the named provider.
+func NewKeyHandle(provider, ref string) KeyHandle {
+	return KeyHandle(provider + ":" + ref)
+}
+
+// Provider returns the name of the provider that issued the handle.
+func (h KeyHandle) Provider() string {
+	name, _, _ := strings.Cut(string(h), ":")
+	return name
+}
+
+// Ref returns the reference part of a handle issued by provider, or
+// ErrInvalidHandle when another provider issued it.
+func (h KeyHandle) Ref(provider string) (string, error) {
+	name, ref, ok := strings.Cut(string(h), ":")
+	if !ok || name != provider || ref == "" {
+		return "", ErrInvalidHandle
+	}
+	return ref, nil
+}
+
+// Operation names a provider operation.
+type Operation string
+
+const (
+	// OpAll stands for every operation when setting faults.
+	OpAll         Operation = ""
+	OpGenerateKey Operation = "generate_key"
+	OpSign        Operation = "sign"
+	OpECDH        Operation = "ecdh"
+	OpEncrypt     Operation = "encrypt"
+	OpDecrypt     Operation = "decrypt"
+	OpDerive      Operation = "derive"
+	// OpProbe is the health probe a Breaker runs.
+	OpProbe Operation = "probe"
+)
+
+// KeyOperations lists the Provider operations on keys.
+var KeyOperations = []Operation{OpGenerateKey, OpSign, OpECDH, OpEncrypt, OpDecrypt, OpDerive}
+
+// Provider abstracts hardware security module operations. Private keys
+// never leave the provider: GenerateKey returns a handle and the public
+// key, and every private key operation takes the handle.
 type Provider interface {
-	GenerateKey(curve elliptic.Curve) (*ecdsa.PrivateKey, error)
-	Sign(key *ecdsa.PrivateKey, data []byte) ([]byte, error)
+	// GenerateKey creates an ECDSA key pair for the vault key id.
+	GenerateKey(id string, curve elliptic.Curve) (KeyHandle, *ecdsa.PublicKey, error)
+	// Sign signs the SHA-256 digest of data and returns an ASN.1 DER
+	// signature.
+	Sign(h KeyHandle, data []byte) ([]byte, error)
 	Verify(pub *ecdsa.PublicKey, data, signature []byte) bool
+	// ECDH computes the shared secret with a peer public key on the key's
+	// curve.
+	ECDH(h KeyHandle, peer *ecdh.PublicKey) ([]byte, error)
+	// Encrypt and Decrypt seal data with AES-256-GCM under a key derived
+	// from the private key, the data encryption ECDSA keys have always
+	// offered: [nonce | ciphertext | tag].
+	Encrypt(h KeyHandle, plaintext, aad []byte) ([]byte, error)
+	Decrypt(h KeyHandle, ciphertext, aad []byte) ([]byte, error)
+	// Derive returns HKDF output keyed by the private key.
+	Derive(h KeyHandle, hash crypto.HKDFHash, salt, info []byte, length int) ([]byte, error)
+}
+
+// KeyLifecycle is implemented by providers that hold key material and
+// follow the lifecycle of vault keys.
+type KeyLifecycle interface {
+	// DeactivateKey leaves a key able to decrypt only, as the vault does
+	// with deactivated keys.
+	DeactivateKey(h KeyHandle) error
+	// DestroyKey zeroizes a key. Its handle is invalid afterwards.
+	DestroyKey(h KeyHandle) error
+}
+
+// DeactivateKey deactivates h in p, or returns ErrUnsupported if p does
+// not follow key lifecycle.
+func DeactivateKey(p Provider, h KeyHandle) error {
+	if l, ok := p.(KeyLifecycle); ok {
+		return l.DeactivateKey(h)
+	}
+	return ErrUnsupported
+}
+
+// DestroyKey destroys h in p, or returns ErrUnsupported if p does not
+// follow key lifecycle.
+func DestroyKey(p Provider, h KeyHandle) error {
+	if l, ok := p.(KeyLifecycle); ok {
+		return l.DestroyKey(h)
+	}
+	return ErrUnsupported
+}
+
+// KeyImporter is implemented by providers that take in private keys
+// generated elsewhere.
+type KeyImporter interface {
+	// ImportKey stores key for the vault key id and returns its handle.
+	ImportKey(id string, key *ecdsa.PrivateKey) (KeyHandle, error)
+}
+
+// ImportKey imports key into p, or returns ErrUnsupported if p does not
+// import keys.
+func ImportKey(p Provider, id string, key *ecdsa.PrivateKey) (KeyHandle, error) {
+	if i, ok := p.(KeyImporter); ok {
+		return i.ImportKey(id, key)
+	}
+	return "", ErrUnsupported
+}
+
+// Capabilities describes what a provider supports.
+type Capabilities struct {
+	// Curves are the curves GenerateKey accepts.
+	Curves []elliptic.Curve
+	// Operations are the key operations the provider performs; the others
+	// fail with ErrUnsupported.
+	Operations []Operation
+	// DeriveHashes are the hashes Derive accepts.
+	DeriveHashes []crypto.HKDFHash
+	// KeyLifecycle reports whether the provider deactivates and destroys
+	// keys.
+	KeyLifecycle bool
+	// KeyImport reports whether the provider imports keys.
+	KeyImport bool
+	// MaxConcurrency is the most operations the provider runs at once,
+	// such as a token's session pool size; 0 means no fixed limit.
+	MaxConcurrency int
+}
+
+// CapabilityReporter is implemented by providers that support less than
+// the whole Provider interface, or whose support is only known at run
+// time.
+type CapabilityReporter interface {
+	Capabilities() (Capabilities, error)
+}
+
+// FullCapabilities are those of a provider that supports every operation
+// on every curve and hash, as the SoftwareHSM does.
+func FullCapabilities() Capabilities {
+	return Capabilities{
+		Curves:       []elliptic.Curve{elliptic.P256(), elliptic.P384()},
+		Operations:   slices.Clone(KeyOperations),
+		DeriveHashes: []crypto.HKDFHash{crypto.HKDFSHA256, crypto.HKDFSHA384, crypto.HKDFSHA512},
+	}
+}
+
+// ProviderCapabilities returns the capabilities of p: those it reports,
+// or else the full set. KeyLifecycle and KeyImport are set from the
+// interfaces p implements when it does not report capabilities.
+func ProviderCapabilities(p Provider) (Capabilities, error) {
+	if r, ok := p.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	c := FullCapabilities()
+	_, c.KeyLifecycle = p.(KeyLifecycle)
+	_, c.KeyImport = p.(KeyImporter)
+	return c, nil
+}
+
+// Supports reports whether op is among the capabilities' operations.
+func (c Capabilities) Supports(op Operation) bool {
+	return slices.Contains(c.Operations, op)
 }
@@ -0,0 +1,74 @@
+package hsm
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"testing"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+func TestProviderCapabilities(t *testing.T) {
+	c, err := ProviderCapabilities(NewSoftwareHSM())
+	if err != nil {
+		t.Fatalf("software: %v", err)
+	}
+	if len(c.Curves) != 2 || len(c.Operations) != len(KeyOperations) || len(c.DeriveHashes) != 3 {
+		t.Fatalf("software: %+v", c)
+	}
+	if c.KeyLifecycle {
+		t.Fatal("software keys without a keystore do not follow key lifecycle")
+	}
+
+	sealed, err := OpenSealedSoftwareHSM(SealedConfig{MasterKey: bytes.Repeat([]byte{7}, 32)})
+	if err != nil {
+		t.Fatalf("open sealed hsm: %v", err)
+	}
+	defer sealed.Close()
+	// Wrappers report the capabilities of the provider they wrap.
+	c, err = ProviderCapabilities(NewBreaker("dev", NewFaultHSM(sealed), BreakerConfig{}))
+	if err != nil {
+		t.Fatalf("sealed: %v", err)
+	}
+	if !c.KeyLifecycle || !c.KeyImport || !c.Supports(OpDecrypt) {
+		t.Fatalf("sealed: %+v", c)
+	}
+
+	// A provider that reports nothing is taken to support everything.
+	c, err = ProviderCapabilities(struct{ Provider }{NewSoftwareHSM()})
+	if err != nil {
+		t.Fatalf("plain provider: %v", err)
+	}
+	if len(c.Operations) != len(KeyOperations) || c.KeyLifecycle || c.KeyImport {
+		t.Fatalf("plain provider: %+v", c)
+	}
+}
+
+func TestImportKey(t *testing.T) {
+	key, err := crypto.GenerateECDSAKey(elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	sealed, err := OpenSealedSoftwareHSM(SealedConfig{MasterKey: bytes.Repeat([]byte{7}, 32)})
+	if err != nil {
+		t.Fatalf("open sealed hsm: %v", err)
+	}
+	defer sealed.Close()
+
+	h, err := ImportKey(NewBreaker("dev", sealed, BreakerConfig{}), "key-1", key)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if h.Provider() != "sealed" {
+		t.Fatalf("handle %q should refer to the sealed key", h)
+	}
+	sig, err := sealed.Sign(h, []byte("data"))
+	if err != nil || !sealed.Verify(&key.PublicKey, []byte("data"), sig) {
+		t.Fatalf("sign with imported key: %v", err)
+	}
+
+	if _, err := ImportKey(struct{ Provider }{NewSoftwareHSM()}, "key-2", key); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("provider without import: got %v, want ErrUnsupported", err)
+	}
+}
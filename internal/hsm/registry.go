@@ -0,0 +1,119 @@
+package hsm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownProvider is returned for a provider name that is not registered.
+var ErrUnknownProvider = errors.New("unknown hsm provider")
+
+// Registry holds the providers a server can keep keys in, by configured
+// name. Each key records the name of the provider holding it, so keys
+// generated in different providers can be used side by side.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	def       string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]Provider)}
+}
+
+// Register adds a provider under name. The first provider registered is
+// the default until SetDefault chooses another.
+func (r *Registry) Register(name string, p Provider) error {
+	if name == "" || strings.ContainsAny(name, ":/") {
+		return fmt.Errorf("invalid hsm provider name %q", name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.providers[name]; ok {
+		return fmt.Errorf("hsm provider %q already registered", name)
+	}
+	r.providers[name] = p
+	if r.def == "" {
+		r.def = name
+	}
+	return nil
+}
+
+// SetDefault selects the provider keys are generated in when a request
+// names none.
+func (r *Registry) SetDefault(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.providers[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+	r.def = name
+	return nil
+}
+
+// Default returns the name of the default provider.
+func (r *Registry) Default() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.def
+}
+
+// Get returns the provider registered under name, or the default provider
+// when name is empty.
+func (r *Registry) Get(name string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.def
+	}
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Close closes every registered provider that holds resources, such as
+// the sessions of a PKCS#11 token.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for name, p := range r.providers {
+		if c, ok := p.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stats returns the health of each registered provider that tracks it.
+func (r *Registry) Stats() map[string]ProviderStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := make(map[string]ProviderStats)
+	for name, p := range r.providers {
+		if m, ok := p.(Monitored); ok {
+			stats[name] = m.Stats()
+		}
+	}
+	return stats
+}
@@ -0,0 +1,46 @@
+package hsm
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	dev, sim := NewSoftwareHSM(), NewSoftwareHSM()
+	if err := r.Register("dev", dev); err != nil {
+		t.Fatalf("register dev: %v", err)
+	}
+	if err := r.Register("sim", sim); err != nil {
+		t.Fatalf("register sim: %v", err)
+	}
+	if err := r.Register("dev", sim); err == nil {
+		t.Fatal("duplicate name should fail")
+	}
+	if err := r.Register("a:b", sim); err == nil {
+		t.Fatal("name with ':' should fail")
+	}
+
+	if r.Default() != "dev" {
+		t.Fatalf("default: got %q, want first registered", r.Default())
+	}
+	if p, err := r.Get(""); err != nil || p != Provider(dev) {
+		t.Fatalf("empty name should give the default provider: %v", err)
+	}
+	if err := r.SetDefault("sim"); err != nil {
+		t.Fatalf("set default: %v", err)
+	}
+	if p, err := r.Get(""); err != nil || p != Provider(sim) {
+		t.Fatalf("empty name should give the new default: %v", err)
+	}
+	if _, err := r.Get("missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("missing provider: got %v, want ErrUnknownProvider", err)
+	}
+	if err := r.SetDefault("missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("missing default: got %v, want ErrUnknownProvider", err)
+	}
+	if names := r.Names(); !slices.Equal(names, []string{"dev", "sim"}) {
+		t.Fatalf("names: %v", names)
+	}
+}
@@ -0,0 +1,340 @@
+package hsm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// sealedProvider names the handles of keys held in a sealed keystore.
+const sealedProvider = "sealed"
+
+var (
+	// ErrTampered is returned by a sealed SoftwareHSM once tamper has
+	// erased its keys. It wraps ErrUnavailable.
+	ErrTampered = fmt.Errorf("%w: tamper detected, keys erased", ErrUnavailable)
+	// ErrKeyDeactivated is returned for an operation other than Decrypt
+	// on a deactivated key.
+	ErrKeyDeactivated = errors.New("hsm key deactivated")
+)
+
+// SealedConfig configures the sealed keystore of a SoftwareHSM.
+type SealedConfig struct {
+	// Path is the keystore file, created if missing. Empty keeps the
+	// keystore in memory only.
+	Path string
+	// MasterKey is the AES-256 key the key material is sealed under.
+	MasterKey []byte
+}
+
+// sealedCheck is sealed under the master key when a keystore is created,
+// so opening it with another key fails instead of treating every key as
+// tampered with.
+const sealedCheck = "vault-go sealed keystore"
+
+// errUnseal is returned for sealed data that fails authentication.
+var errUnseal = errors.New("sealed data failed authentication")
+
+// errErased is returned once the master key has been erased, by tamper or
+// by closing the provider.
+var errErased = fmt.Errorf("%w: sealed keystore master key erased", ErrUnavailable)
+
+type sealedFile struct {
+	// Tampered survives restarts: a tampered keystore stays unusable
+	// until it is removed and the HSM starts over.
+	Tampered bool                  `json:"tampered,omitempty"`
+	Check    []byte                `json:"check,omitempty"`
+	Keys     map[string]*sealedKey `json:"keys,omitempty"`
+}
+
+type sealedKey struct {
+	KeyID string `json:"key_id"`
+	// Sealed is the PKCS8 private key under AES-256-GCM with the master
+	// key: [nonce | ciphertext | tag].
+	Sealed      []byte `json:"sealed"`
+	Deactivated bool   `json:"deactivated,omitempty"`
+}
+
+// sealedKeys holds private keys sealed under a master key. The master key
+// is kept in locked memory, and key material is only ever unsealed into
+// locked memory, which is zeroized as soon as the operation using it
+// returns. The Go runtime gives no such control over the AES key schedule
+// or the parsed private key an operation works with; they are left to the
+// garbage collector.
+type sealedKeys struct {
+	path string
+
+	mu     sync.Mutex
+	master []byte
+	aead   cipher.AEAD
+	file   sealedFile
+}
+
+func openSealedKeys(cfg SealedConfig) (*sealedKeys, error) {
+	if len(cfg.MasterKey) != 32 {
+		return nil, fmt.Errorf("sealed keystore: master key must be 32 bytes, got %d", len(cfg.MasterKey))
+	}
+	master, err := lockedAlloc(len(cfg.MasterKey))
+	if err != nil {
+		return nil, fmt.Errorf("sealed keystore: allocate locked memory: %w", err)
+	}
+	copy(master, cfg.MasterKey)
+	block, err := aes.NewCipher(master)
+	if err != nil {
+		lockedFree(master)
+		return nil, fmt.Errorf("sealed keystore: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		lockedFree(master)
+		return nil, fmt.Errorf("sealed keystore: %w", err)
+	}
+	k := &sealedKeys{path: cfg.Path, master: master, aead: aead}
+
+	if err := k.load(); err != nil {
+		lockedFree(master)
+		return nil, fmt.Errorf("sealed keystore: %w", err)
+	}
+	return k, nil
+}
+
+// load reads the keystore file, creating it if missing, and unseals every
+// key once so tampering shows at startup.
+func (k *sealedKeys) load() error {
+	data, err := os.ReadFile(k.path)
+	switch {
+	case k.path == "" || errors.Is(err, os.ErrNotExist):
+		k.file = sealedFile{Keys: make(map[string]*sealedKey)}
+		if k.file.Check, err = k.seal([]byte(sealedCheck), []byte("check")); err != nil {
+			return err
+		}
+		return k.persist()
+	case err != nil:
+		return err
+	}
+	if err := json.Unmarshal(data, &k.file); err != nil {
+		return fmt.Errorf("parse %s: %w", k.path, err)
+	}
+	if k.file.Keys == nil {
+		k.file.Keys = make(map[string]*sealedKey)
+	}
+	if k.file.Tampered {
+		return nil
+	}
+	if err := k.unseal(k.file.Check, []byte("check"), func([]byte) error { return nil }); err != nil {
+		return errors.New("wrong master key")
+	}
+	for ref, rec := range k.file.Keys {
+		err := k.unseal(rec.Sealed, recordAAD(ref, rec), func([]byte) error { return nil })
+		if errors.Is(err, errUnseal) {
+			// Open the keystore tampered: the provider reports itself
+			// unavailable rather than stopping the server.
+			k.tamper()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persist writes the keystore file. k.mu must be held, or k not yet
+// shared.
+func (k *sealedKeys) persist() error {
+	if k.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(k.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+	tmpPath := k.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, k.path); err != nil {
+		return fmt.Errorf("atomic rename: %w", err)
+	}
+	return nil
+}
+
+// recordAAD binds a sealed key to its reference and vault key, so records
+// cannot be swapped in the file.
+func recordAAD(ref string, rec *sealedKey) []byte {
+	return []byte(ref + "/" + rec.KeyID)
+}
+
+func (k *sealedKeys) seal(plaintext, aad []byte) ([]byte, error) {
+	if k.aead == nil {
+		return nil, errErased
+	}
+	nonce := make([]byte, k.aead.NonceSize(), k.aead.NonceSize()+len(plaintext)+k.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// unseal decrypts sealed into locked memory for the duration of fn.
+func (k *sealedKeys) unseal(sealed, aad []byte, fn func(plaintext []byte) error) error {
+	if k.aead == nil {
+		return errErased
+	}
+	n := k.aead.NonceSize()
+	if len(sealed) < n+k.aead.Overhead() {
+		return errUnseal
+	}
+	buf, err := lockedAlloc(len(sealed))
+	if err != nil {
+		return fmt.Errorf("allocate locked memory: %w", err)
+	}
+	defer lockedFree(buf)
+	plaintext, err := k.aead.Open(buf[:0], sealed[:n], sealed[n:], aad)
+	if err != nil {
+		return errUnseal
+	}
+	return fn(plaintext)
+}
+
+// put seals a PKCS8 private key for the vault key id.
+func (k *sealedKeys) put(id string, der []byte) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.file.Tampered {
+		return "", ErrTampered
+	}
+	var r [16]byte
+	if _, err := rand.Read(r[:]); err != nil {
+		return "", fmt.Errorf("generate key reference: %w", err)
+	}
+	ref := hex.EncodeToString(r[:])
+	rec := &sealedKey{KeyID: id}
+	sealed, err := k.seal(der, recordAAD(ref, rec))
+	if err != nil {
+		return "", err
+	}
+	rec.Sealed = sealed
+	k.file.Keys[ref] = rec
+	if err := k.persist(); err != nil {
+		delete(k.file.Keys, ref)
+		return "", err
+	}
+	return ref, nil
+}
+
+// use unseals the key ref for the duration of fn. Deactivated keys can
+// only decrypt. A key that fails to unseal has been tampered with, and
+// erases every key.
+func (k *sealedKeys) use(ref string, decrypting bool, fn func(der []byte) error) error {
+	k.mu.Lock()
+	if k.file.Tampered {
+		k.mu.Unlock()
+		return ErrTampered
+	}
+	rec, ok := k.file.Keys[ref]
+	if !ok {
+		k.mu.Unlock()
+		return ErrKeyNotFound
+	}
+	if rec.Deactivated && !decrypting {
+		k.mu.Unlock()
+		return ErrKeyDeactivated
+	}
+	// Copy the sealed key: destroying it clears the record in place.
+	sealed, aad := append([]byte(nil), rec.Sealed...), recordAAD(ref, rec)
+	k.mu.Unlock()
+
+	var opErr error
+	err := k.unseal(sealed, aad, func(der []byte) error {
+		opErr = fn(der)
+		return nil
+	})
+	if errors.Is(err, errUnseal) {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		// A key destroyed meanwhile is not tampering.
+		if _, ok := k.file.Keys[ref]; !ok {
+			return ErrKeyNotFound
+		}
+		return k.tamper()
+	}
+	if err != nil {
+		return err
+	}
+	return opErr
+}
+
+func (k *sealedKeys) deactivate(ref string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.file.Tampered {
+		return ErrTampered
+	}
+	rec, ok := k.file.Keys[ref]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	rec.Deactivated = true
+	return k.persist()
+}
+
+// destroy zeroizes and removes the key ref.
+func (k *sealedKeys) destroy(ref string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.file.Tampered {
+		return ErrTampered
+	}
+	rec, ok := k.file.Keys[ref]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	clear(rec.Sealed)
+	delete(k.file.Keys, ref)
+	return k.persist()
+}
+
+func (k *sealedKeys) tampered() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.file.Tampered
+}
+
+// tamper zeroizes every key and the master key and records the tamper in
+// the keystore file. It returns ErrTampered. k.mu must be held.
+func (k *sealedKeys) tamper() error {
+	if !k.file.Tampered {
+		for _, rec := range k.file.Keys {
+			clear(rec.Sealed)
+		}
+		k.file = sealedFile{Tampered: true}
+		k.erase()
+		if err := k.persist(); err != nil {
+			return fmt.Errorf("%w (record tamper: %v)", ErrTampered, err)
+		}
+	}
+	return ErrTampered
+}
+
+// erase zeroizes the master key and drops the cipher expanded from it,
+// so nothing can be sealed or unsealed afterwards. k.mu must be held.
+func (k *sealedKeys) erase() {
+	if k.master != nil {
+		lockedFree(k.master)
+		k.master = nil
+	}
+	k.aead = nil
+}
+
+func (k *sealedKeys) close() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.erase()
+}
@@ -0,0 +1,197 @@
+package hsm
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+func sealedConfig(t *testing.T) SealedConfig {
+	t.Helper()
+	master, err := crypto.GenerateAESKey()
+	if err != nil {
+		t.Fatalf("master key: %v", err)
+	}
+	return SealedConfig{Path: filepath.Join(t.TempDir(), "hsm.json"), MasterKey: master}
+}
+
+func openSealed(t *testing.T, cfg SealedConfig) *SoftwareHSM {
+	t.Helper()
+	s, err := OpenSealedSoftwareHSM(cfg)
+	if err != nil {
+		t.Fatalf("open sealed hsm: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSealedSoftwareHSM(t *testing.T) {
+	cfg := sealedConfig(t)
+	s := openSealed(t, cfg)
+
+	h, pub, err := s.GenerateKey("key-1", elliptic.P384())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if h.Provider() != "sealed" || len(h) > 64 {
+		t.Fatalf("handle %q should only refer to the sealed key", h)
+	}
+	sig, err := s.Sign(h, []byte("data"))
+	if err != nil || !s.Verify(pub, []byte("data"), sig) {
+		t.Fatalf("sign: %v", err)
+	}
+	ct, err := s.Encrypt(h, []byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// The keys survive a restart, and only under the same master key.
+	s.Close()
+	s = openSealed(t, cfg)
+	pt, err := s.Decrypt(h, ct, nil)
+	if err != nil || string(pt) != "plaintext" {
+		t.Fatalf("decrypt after reopening: %q, %v", pt, err)
+	}
+	other := cfg
+	other.MasterKey = bytes.Repeat([]byte{1}, 32)
+	if _, err := OpenSealedSoftwareHSM(other); err == nil {
+		t.Fatal("opening with another master key should fail")
+	}
+
+	// Keys held in memory, as derived keys are, work alongside.
+	key, err := crypto.GenerateECDSAKey(elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate software key: %v", err)
+	}
+	soft, err := s.HoldKey(key)
+	if err != nil {
+		t.Fatalf("hold key: %v", err)
+	}
+	if _, err := s.Sign(soft, []byte("data")); err != nil {
+		t.Fatalf("sign with software handle: %v", err)
+	}
+	if err := s.DestroyKey(soft); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("destroy software handle: got %v, want ErrUnsupported", err)
+	}
+}
+
+func TestSealedSoftwareHSMLifecycle(t *testing.T) {
+	s := openSealed(t, sealedConfig(t))
+	h, _, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	ct, err := s.Encrypt(h, []byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	if err := s.DeactivateKey(h); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if _, err := s.Sign(h, []byte("data")); !errors.Is(err, ErrKeyDeactivated) {
+		t.Fatalf("sign with deactivated key: got %v", err)
+	}
+	if _, err := s.Derive(h, crypto.HKDFSHA256, nil, nil, 32); !errors.Is(err, ErrKeyDeactivated) {
+		t.Fatalf("derive with deactivated key: got %v", err)
+	}
+	if pt, err := s.Decrypt(h, ct, nil); err != nil || string(pt) != "plaintext" {
+		t.Fatalf("deactivated key should still decrypt: %v", err)
+	}
+
+	if err := s.DestroyKey(h); err != nil {
+		t.Fatalf("destroy: %v", err)
+	}
+	if _, err := s.Decrypt(h, ct, nil); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("decrypt with destroyed key: got %v", err)
+	}
+	if err := s.Probe(); err != nil {
+		t.Fatalf("destroying a key is not tampering: %v", err)
+	}
+}
+
+func TestSealedSoftwareHSMTamper(t *testing.T) {
+	cfg := sealedConfig(t)
+	s := openSealed(t, cfg)
+	h, _, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	s.Close()
+
+	// Flip a bit of the sealed key in the file.
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		t.Fatalf("read keystore: %v", err)
+	}
+	var file sealedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("parse keystore: %v", err)
+	}
+	for _, rec := range file.Keys {
+		rec.Sealed[len(rec.Sealed)-1] ^= 1
+	}
+	data, _ = json.Marshal(file)
+	if err := os.WriteFile(cfg.Path, data, 0o600); err != nil {
+		t.Fatalf("write keystore: %v", err)
+	}
+
+	s = openSealed(t, cfg)
+	if err := s.Probe(); !errors.Is(err, ErrTampered) {
+		t.Fatalf("probe: got %v, want ErrTampered", err)
+	}
+	if _, err := s.Sign(h, []byte("data")); !errors.Is(err, ErrTampered) || !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("sign after tamper: got %v", err)
+	}
+	if _, _, err := s.GenerateKey("key-2", elliptic.P256()); !errors.Is(err, ErrTampered) {
+		t.Fatalf("generate after tamper: got %v", err)
+	}
+
+	data, err = os.ReadFile(cfg.Path)
+	if err != nil {
+		t.Fatalf("read keystore: %v", err)
+	}
+	if strings.Contains(string(data), "sealed") {
+		t.Fatalf("tamper should erase every key: %s", data)
+	}
+}
+
+func TestSoftwareHSMTamper(t *testing.T) {
+	s := openSealed(t, SealedConfig{MasterKey: bytes.Repeat([]byte{7}, 32)})
+	h, _, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	s.Tamper()
+	if _, err := s.Sign(h, []byte("data")); !errors.Is(err, ErrTampered) {
+		t.Fatalf("sign after tamper: got %v", err)
+	}
+	if err := s.Probe(); !errors.Is(err, ErrTampered) {
+		t.Fatalf("probe after tamper: got %v", err)
+	}
+	if s.sealed.master != nil || s.sealed.aead != nil {
+		t.Fatal("tamper should erase the master key and its cipher")
+	}
+}
+
+func TestSealedSoftwareHSMClose(t *testing.T) {
+	s := openSealed(t, sealedConfig(t))
+	h, _, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	s.Close()
+	if s.sealed.master != nil || s.sealed.aead != nil {
+		t.Fatal("close should erase the master key and its cipher")
+	}
+	if _, err := s.Sign(h, []byte("data")); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("sign after close: got %v, want ErrUnavailable", err)
+	}
+}
@@ -1,28 +1,423 @@
 package hsm
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
 
 	"github.com/glinharesb/vault-go/internal/crypto"
 )
 
+// softwareProvider names the handles of keys a SoftwareHSM holds in memory.
+const softwareProvider = "software"
+
 // SoftwareHSM is a software-only HSM implementation for development and testing.
 // In production, this would be replaced by a hardware-backed provider.
-type SoftwareHSM struct{}
+//
+// Its handles are opaque references to keys it holds, never the keys
+// themselves. By default it holds them in memory, and with
+// OpenSoftwareHSM also in a plain keystore file so they survive restarts.
+// Opened with OpenSealedSoftwareHSM it behaves more like a real HSM: it
+// keeps the keys it generates sealed in its own keystore, and only
+// unseals them for an operation. Keys computed in the process, such as
+// derived keys, are held in memory by HoldKey either way.
+type SoftwareHSM struct {
+	mu   sync.RWMutex
+	keys map[string]*softwareKey
+	// path is the plain keystore file, if any.
+	path string
+
+	sealed *sealedKeys
+}
+
+// softwareKey is a key a SoftwareHSM holds in memory.
+type softwareKey struct {
+	id  string
+	key *ecdsa.PrivateKey
+	// held keys came from HoldKey and are never written to the keystore
+	// file.
+	held bool
+}
+
+// softwareFile is the plain keystore file of OpenSoftwareHSM, by key
+// reference.
+type softwareFile struct {
+	Keys map[string]softwareRecord `json:"keys"`
+}
+
+type softwareRecord struct {
+	KeyID string `json:"key_id"`
+	// PrivateKeyDER is the PKCS8 encoding of the key.
+	PrivateKeyDER []byte `json:"private_key_der"`
+}
+
+var (
+	_ Provider           = (*SoftwareHSM)(nil)
+	_ Prober             = (*SoftwareHSM)(nil)
+	_ KeyLifecycle       = (*SoftwareHSM)(nil)
+	_ KeyImporter        = (*SoftwareHSM)(nil)
+	_ CapabilityReporter = (*SoftwareHSM)(nil)
+)
 
 func NewSoftwareHSM() *SoftwareHSM {
-	return &SoftwareHSM{}
+	return &SoftwareHSM{keys: make(map[string]*softwareKey)}
+}
+
+// OpenSoftwareHSM returns a SoftwareHSM that also writes the keys it
+// generates or imports to the keystore file at path, unencrypted, and
+// loads them from it. It is meant for development; OpenSealedSoftwareHSM
+// seals the keys instead.
+func OpenSoftwareHSM(path string) (*SoftwareHSM, error) {
+	s := NewSoftwareHSM()
+	s.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, s.persist()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("software keystore: %w", err)
+	}
+	var file softwareFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("software keystore: parse %s: %w", path, err)
+	}
+	for ref, rec := range file.Keys {
+		key, err := crypto.UnmarshalPrivateKey(rec.PrivateKeyDER)
+		if err != nil {
+			return nil, fmt.Errorf("software keystore: key %s: %w", ref, err)
+		}
+		s.keys[ref] = &softwareKey{id: rec.KeyID, key: key}
+	}
+	return s, nil
+}
+
+// OpenSealedSoftwareHSM returns a SoftwareHSM whose keys are sealed under
+// cfg.MasterKey in the keystore at cfg.Path. The key material is only
+// unsealed, into locked memory, for the duration of an operation.
+// Destroying a key zeroizes it, and a keystore found tampered with, or
+// the Tamper method, erases every key: the provider then fails with
+// ErrTampered until the keystore is removed.
+func OpenSealedSoftwareHSM(cfg SealedConfig) (*SoftwareHSM, error) {
+	sealed, err := openSealedKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := NewSoftwareHSM()
+	s.sealed = sealed
+	return s, nil
 }
 
-func (s *SoftwareHSM) GenerateKey(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
-	return crypto.GenerateECDSAKey(curve)
+// LegacySoftwareKey returns the private key carried by a handle that a
+// SoftwareHSM issued when its handles were the base64 PKCS8 encoding of
+// the key, so stores holding such handles can move the key into a
+// provider.
+func LegacySoftwareKey(h KeyHandle) (*ecdsa.PrivateKey, bool) {
+	ref, err := h.Ref(softwareProvider)
+	if err != nil {
+		return nil, false
+	}
+	der, err := base64.RawURLEncoding.DecodeString(ref)
+	if err != nil {
+		return nil, false
+	}
+	defer clear(der)
+	key, err := crypto.UnmarshalPrivateKey(der)
+	if err != nil {
+		return nil, false
+	}
+	return key, true
 }
 
-func (s *SoftwareHSM) Sign(key *ecdsa.PrivateKey, data []byte) ([]byte, error) {
-	return crypto.SignECDSA(key, data)
+func (s *SoftwareHSM) GenerateKey(id string, curve elliptic.Curve) (KeyHandle, *ecdsa.PublicKey, error) {
+	key, err := crypto.GenerateECDSAKey(curve)
+	if err != nil {
+		return "", nil, err
+	}
+	h, err := s.ImportKey(id, key)
+	if err != nil {
+		return "", nil, err
+	}
+	return h, &key.PublicKey, nil
+}
+
+// ImportKey holds key as if it had been generated here: sealed in the
+// keystore if there is one, and written to the plain keystore file if
+// there is one.
+func (s *SoftwareHSM) ImportKey(id string, key *ecdsa.PrivateKey) (KeyHandle, error) {
+	if s.sealed != nil {
+		der, err := crypto.MarshalPrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+		defer clear(der)
+		ref, err := s.sealed.put(id, der)
+		if err != nil {
+			return "", err
+		}
+		return NewKeyHandle(sealedProvider, ref), nil
+	}
+	return s.hold(&softwareKey{id: id, key: key})
+}
+
+// HoldKey holds a key computed in this process, such as one derived from
+// a root key, in memory for the life of the provider. Unlike imported
+// keys, it is never written to a keystore.
+func (s *SoftwareHSM) HoldKey(key *ecdsa.PrivateKey) (KeyHandle, error) {
+	return s.hold(&softwareKey{key: key, held: true})
+}
+
+// ReleaseKey drops a key held with HoldKey. Handles of other keys are
+// ignored: their lifecycle is DestroyKey's.
+func (s *SoftwareHSM) ReleaseKey(h KeyHandle) {
+	ref, err := h.Ref(softwareProvider)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k, ok := s.keys[ref]; ok && k.held {
+		delete(s.keys, ref)
+	}
+}
+
+func (s *SoftwareHSM) hold(k *softwareKey) (KeyHandle, error) {
+	ref, err := newKeyRef()
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[ref] = k
+	if !k.held {
+		if err := s.persist(); err != nil {
+			delete(s.keys, ref)
+			return "", err
+		}
+	}
+	return NewKeyHandle(softwareProvider, ref), nil
+}
+
+// persist writes the plain keystore file, if there is one. s.mu must be
+// held, or s not yet shared.
+func (s *SoftwareHSM) persist() error {
+	if s.path == "" {
+		return nil
+	}
+	file := softwareFile{Keys: make(map[string]softwareRecord)}
+	for ref, k := range s.keys {
+		if k.held {
+			continue
+		}
+		der, err := crypto.MarshalPrivateKey(k.key)
+		if err != nil {
+			return fmt.Errorf("software keystore: %w", err)
+		}
+		file.Keys[ref] = softwareRecord{KeyID: k.id, PrivateKeyDER: der}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("software keystore: marshal json: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("software keystore: write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("software keystore: atomic rename: %w", err)
+	}
+	return nil
+}
+
+// newKeyRef returns a random key reference.
+func newKeyRef() (string, error) {
+	var r [16]byte
+	if _, err := rand.Read(r[:]); err != nil {
+		return "", fmt.Errorf("generate key reference: %w", err)
+	}
+	return hex.EncodeToString(r[:]), nil
+}
+
+func (s *SoftwareHSM) Sign(h KeyHandle, data []byte) (sig []byte, err error) {
+	err = s.withKey(h, false, func(key *ecdsa.PrivateKey) error {
+		sig, err = crypto.SignECDSA(key, data)
+		return err
+	})
+	return sig, err
 }
 
 func (s *SoftwareHSM) Verify(pub *ecdsa.PublicKey, data, signature []byte) bool {
 	return crypto.VerifyECDSA(pub, data, signature)
 }
+
+func (s *SoftwareHSM) ECDH(h KeyHandle, peer *ecdh.PublicKey) (secret []byte, err error) {
+	err = s.withKey(h, false, func(key *ecdsa.PrivateKey) error {
+		priv, err := key.ECDH()
+		if err != nil {
+			return fmt.Errorf("convert key: %w", err)
+		}
+		secret, err = priv.ECDH(peer)
+		return err
+	})
+	return secret, err
+}
+
+func (s *SoftwareHSM) Encrypt(h KeyHandle, plaintext, aad []byte) ([]byte, error) {
+	symKey, err := s.derive(h, false, crypto.HKDFSHA256, nil, []byte("vault-aes-gcm"), 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(symKey)
+	return crypto.EncryptAESGCM(symKey, plaintext, aad)
+}
+
+// Decrypt still works with a deactivated key, so data sealed before the
+// key was deactivated can be recovered.
+func (s *SoftwareHSM) Decrypt(h KeyHandle, ciphertext, aad []byte) ([]byte, error) {
+	symKey, err := s.derive(h, true, crypto.HKDFSHA256, nil, []byte("vault-aes-gcm"), 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(symKey)
+	return crypto.DecryptAESGCM(symKey, ciphertext, aad)
+}
+
+// Derive keys HKDF with the PKCS8 encoding of the private key.
+func (s *SoftwareHSM) Derive(h KeyHandle, hash crypto.HKDFHash, salt, info []byte, length int) ([]byte, error) {
+	return s.derive(h, false, hash, salt, info, length)
+}
+
+func (s *SoftwareHSM) derive(h KeyHandle, decrypting bool, hash crypto.HKDFHash, salt, info []byte, length int) (out []byte, err error) {
+	err = s.withDER(h, decrypting, func(der []byte) error {
+		out, err = crypto.HKDF(hash, der, salt, info, length)
+		return err
+	})
+	return out, err
+}
+
+// Capabilities reports every operation. Only sealed keys follow key
+// lifecycle.
+func (s *SoftwareHSM) Capabilities() (Capabilities, error) {
+	c := FullCapabilities()
+	c.KeyLifecycle = s.sealed != nil
+	c.KeyImport = true
+	return c, nil
+}
+
+// Probe fails once tamper has erased the sealed keystore.
+func (s *SoftwareHSM) Probe() error {
+	if s.sealed != nil && s.sealed.tampered() {
+		return ErrTampered
+	}
+	return nil
+}
+
+// DeactivateKey leaves a sealed key able to decrypt only.
+func (s *SoftwareHSM) DeactivateKey(h KeyHandle) error {
+	ref, err := s.sealedRef(h)
+	if err != nil {
+		return err
+	}
+	return s.sealed.deactivate(ref)
+}
+
+// DestroyKey zeroizes a sealed key.
+func (s *SoftwareHSM) DestroyKey(h KeyHandle) error {
+	ref, err := s.sealedRef(h)
+	if err != nil {
+		return err
+	}
+	return s.sealed.destroy(ref)
+}
+
+// Tamper erases every key, as a real HSM does when its tamper sensors
+// trip. It is a no-op without a sealed keystore.
+func (s *SoftwareHSM) Tamper() {
+	if s.sealed == nil {
+		return
+	}
+	s.sealed.mu.Lock()
+	defer s.sealed.mu.Unlock()
+	s.sealed.tamper()
+}
+
+// Close zeroizes the master key of the sealed keystore.
+func (s *SoftwareHSM) Close() error {
+	if s.sealed != nil {
+		s.sealed.close()
+	}
+	return nil
+}
+
+// sealedRef returns the reference of a key in the sealed keystore. Keys
+// held in memory are not in it, so their lifecycle is not the provider's.
+func (s *SoftwareHSM) sealedRef(h KeyHandle) (string, error) {
+	if h.Provider() == softwareProvider {
+		return "", ErrUnsupported
+	}
+	if s.sealed == nil {
+		return "", ErrInvalidHandle
+	}
+	return h.Ref(sealedProvider)
+}
+
+// withKey runs fn with the private key behind h.
+func (s *SoftwareHSM) withKey(h KeyHandle, decrypting bool, fn func(*ecdsa.PrivateKey) error) error {
+	if h.Provider() != sealedProvider {
+		key, err := s.key(h)
+		if err != nil {
+			return err
+		}
+		return fn(key)
+	}
+	return s.withDER(h, decrypting, func(der []byte) error {
+		key, err := crypto.UnmarshalPrivateKey(der)
+		if err != nil {
+			return err
+		}
+		return fn(key)
+	})
+}
+
+// withDER runs fn with the PKCS8 encoding of the private key behind h.
+func (s *SoftwareHSM) withDER(h KeyHandle, decrypting bool, fn func(der []byte) error) error {
+	if h.Provider() != sealedProvider {
+		key, err := s.key(h)
+		if err != nil {
+			return err
+		}
+		der, err := crypto.MarshalPrivateKey(key)
+		if err != nil {
+			return err
+		}
+		defer clear(der)
+		return fn(der)
+	}
+	ref, err := s.sealedRef(h)
+	if err != nil {
+		return err
+	}
+	return s.sealed.use(ref, decrypting, fn)
+}
+
+// key returns the in-memory key a handle refers to.
+func (s *SoftwareHSM) key(h KeyHandle) (*ecdsa.PrivateKey, error) {
+	ref, err := h.Ref(softwareProvider)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[ref]
+	if !ok {
+		return nil, ErrInvalidHandle
+	}
+	return k.key, nil
+}
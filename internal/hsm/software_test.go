@@ -0,0 +1,116 @@
+package hsm
+
+import (
+	"crypto/elliptic"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+func TestSoftwareHSMHandlesAreReferences(t *testing.T) {
+	s := NewSoftwareHSM()
+	h, pub, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(h) > 64 {
+		t.Fatalf("handle %q should be a reference, not the key", h)
+	}
+	sig, err := s.Sign(h, []byte("data"))
+	if err != nil || !s.Verify(pub, []byte("data"), sig) {
+		t.Fatalf("sign: %v", err)
+	}
+
+	// Another instance does not hold the key.
+	if _, err := NewSoftwareHSM().Sign(h, []byte("data")); !errors.Is(err, ErrInvalidHandle) {
+		t.Fatalf("sign on another instance: got %v, want ErrInvalidHandle", err)
+	}
+}
+
+func TestSoftwareHSMReleaseKey(t *testing.T) {
+	s := NewSoftwareHSM()
+	generated, _, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	derived, err := crypto.GenerateECDSAKey(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	held, err := s.HoldKey(derived)
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+
+	s.ReleaseKey(held)
+	if _, err := s.Sign(held, []byte("data")); !errors.Is(err, ErrInvalidHandle) {
+		t.Fatalf("sign with a released key: got %v, want ErrInvalidHandle", err)
+	}
+	s.ReleaseKey(generated)
+	if _, err := s.Sign(generated, []byte("data")); err != nil {
+		t.Fatalf("generated keys should survive ReleaseKey: %v", err)
+	}
+}
+
+func TestSoftwareHSMKeystore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	s, err := OpenSoftwareHSM(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	h, pub, err := s.GenerateKey("key-1", elliptic.P256())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	derived, err := crypto.GenerateECDSAKey(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	held, err := s.HoldKey(derived)
+	if err != nil {
+		t.Fatalf("hold: %v", err)
+	}
+
+	s, err = OpenSoftwareHSM(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	sig, err := s.Sign(h, []byte("data"))
+	if err != nil || !s.Verify(pub, []byte("data"), sig) {
+		t.Fatalf("sign after reopening: %v", err)
+	}
+	if _, err := s.Sign(held, []byte("data")); !errors.Is(err, ErrInvalidHandle) {
+		t.Fatalf("held keys should not be written to the keystore: got %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("keystore file: %v, %v", info, err)
+	}
+}
+
+func TestLegacySoftwareKey(t *testing.T) {
+	key, err := crypto.GenerateECDSAKey(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := LegacySoftwareKey(NewKeyHandle(softwareProvider, base64.RawURLEncoding.EncodeToString(der)))
+	if !ok || !got.Equal(key) {
+		t.Fatal("legacy handle should carry its key")
+	}
+
+	h, err := NewSoftwareHSM().HoldKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := LegacySoftwareKey(h); ok || strings.Contains(string(h), base64.RawURLEncoding.EncodeToString(der)[:16]) {
+		t.Fatalf("reference handle %q should not carry a key", h)
+	}
+}
@@ -2,49 +2,143 @@ package interceptor
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-// AuthUnary returns a unary interceptor that validates bearer tokens.
-func AuthUnary(token string) grpc.UnaryServerInterceptor {
+const (
+	// PermissionDetokenize allows a principal to recover PANs from tokens.
+	PermissionDetokenize = "detokenize"
+	// PermissionHSMFaults allows a principal to inject faults into HSM
+	// providers.
+	PermissionHSMFaults = "hsm-faults"
+	// PermissionWrappingKeys allows a principal to upload RSA public keys
+	// that keys can be exported under.
+	PermissionWrappingKeys = "wrapping-keys"
+	// PermissionExportKeys allows a principal to export keys: wrapped, as
+	// TR-31 key blocks or as components.
+	PermissionExportKeys = "export-keys"
+	// PermissionImportKeys allows a principal to import its own key
+	// material with GetImportParameters and ImportKey.
+	PermissionImportKeys = "import-keys"
+)
+
+// Principal is an authenticated caller and the permissions granted to it.
+type Principal struct {
+	Name        string
+	Permissions []string
+}
+
+// HasPermission reports whether the principal was granted perm.
+func (p *Principal) HasPermission(perm string) bool {
+	return p != nil && slices.Contains(p.Permissions, perm)
+}
+
+// healthService reports the server's overall status without
+// authentication, so load balancers and orchestrators can check it. The
+// status of named services, which are the HSM providers, needs a token.
+const healthService = "/grpc.health.v1.Health/"
+
+// overallHealth reports whether req asks for the server's overall status.
+func overallHealth(req any) bool {
+	check, ok := req.(*healthpb.HealthCheckRequest)
+	return ok && check.Service == ""
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal authenticated by AuthUnary or
+// AuthStream.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// ContextWithPrincipal attaches p to ctx, as the auth interceptors do.
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// AuthUnary returns a unary interceptor that validates bearer tokens against
+// the principals keyed by token. Checking the server's overall health needs
+// no token.
+func AuthUnary(principals map[string]*Principal) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		if err := validateToken(ctx, token); err != nil {
+		if strings.HasPrefix(info.FullMethod, healthService) && overallHealth(req) {
+			return handler(ctx, req)
+		}
+		p, err := authenticate(ctx, principals)
+		if err != nil {
 			return nil, err
 		}
-		return handler(ctx, req)
+		return handler(ContextWithPrincipal(ctx, p), req)
 	}
 }
 
-// AuthStream returns a stream interceptor that validates bearer tokens.
-func AuthStream(token string) grpc.StreamServerInterceptor {
+// AuthStream returns a stream interceptor that validates bearer tokens
+// against the principals keyed by token. Watching the server's overall
+// health needs no token.
+func AuthStream(principals map[string]*Principal) grpc.StreamServerInterceptor {
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if err := validateToken(ss.Context(), token); err != nil {
+		p, err := authenticate(ss.Context(), principals)
+		if err != nil {
+			if strings.HasPrefix(info.FullMethod, healthService) {
+				// The request names the service only once it is received.
+				return handler(srv, &overallHealthStream{ServerStream: ss, err: err})
+			}
 			return err
 		}
-		return handler(srv, ss)
+		return handler(srv, &principalStream{ServerStream: ss, ctx: ContextWithPrincipal(ss.Context(), p)})
 	}
 }
 
-func validateToken(ctx context.Context, expected string) error {
+// principalStream carries the authenticated principal in its context.
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context { return s.ctx }
+
+// overallHealthStream serves an unauthenticated health stream, failing with
+// err unless it asks for the server's overall status.
+type overallHealthStream struct {
+	grpc.ServerStream
+	err error
+}
+
+func (s *overallHealthStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !overallHealth(m) {
+		return s.err
+	}
+	return nil
+}
+
+func authenticate(ctx context.Context, principals map[string]*Principal) (*Principal, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return status.Error(codes.Unauthenticated, "missing metadata")
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
 	}
 
 	values := md.Get("authorization")
 	if len(values) == 0 {
-		return status.Error(codes.Unauthenticated, "missing authorization header")
+		return nil, status.Error(codes.Unauthenticated, "missing authorization header")
 	}
 
 	token := strings.TrimPrefix(values[0], "Bearer ")
-	if token != expected {
-		return status.Error(codes.Unauthenticated, "invalid token")
+	p, ok := principals[token]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
-	return nil
+	return p, nil
 }
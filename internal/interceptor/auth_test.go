@@ -0,0 +1,112 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthStream receives a single health request.
+type healthStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	service string
+}
+
+func (s *healthStream) Context() context.Context { return s.ctx }
+
+func (s *healthStream) RecvMsg(m any) error {
+	*m.(*healthpb.HealthCheckRequest) = healthpb.HealthCheckRequest{Service: s.service}
+	return nil
+}
+
+func TestAuthStreamHealth(t *testing.T) {
+	auth := AuthStream(map[string]*Principal{"dev-token": {Name: "default"}})
+	watch := func(service string, md metadata.MD) error {
+		ctx := context.Background()
+		if md != nil {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		ss := &healthStream{ctx: ctx, service: service}
+		return auth(nil, ss, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, func(_ any, ss grpc.ServerStream) error {
+			return ss.RecvMsg(new(healthpb.HealthCheckRequest))
+		})
+	}
+
+	if err := watch("", nil); err != nil {
+		t.Fatalf("overall health: %v", err)
+	}
+	if err := watch("vault.hsm.hsm-a", nil); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("provider health without a token: got %v, want Unauthenticated", err)
+	}
+	if err := watch("vault.hsm.hsm-a", metadata.Pairs("authorization", "Bearer dev-token")); err != nil {
+		t.Fatalf("provider health: %v", err)
+	}
+}
+
+func TestAuthUnary(t *testing.T) {
+	auth := AuthUnary(map[string]*Principal{
+		"dev-token": {Name: "default"},
+		"ops-token": {Name: "ops", Permissions: []string{PermissionDetokenize}},
+	})
+	var got *Principal
+	handler := func(ctx context.Context, _ any) (any, error) {
+		got, _ = PrincipalFromContext(ctx)
+		return nil, nil
+	}
+	callWith := func(method string, req any, md metadata.MD) error {
+		got = nil
+		ctx := context.Background()
+		if md != nil {
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		_, err := auth(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+	call := func(method string, md metadata.MD) error { return callWith(method, nil, md) }
+
+	const method = "/vault.v1.TokenizationService/Detokenize"
+	for name, md := range map[string]metadata.MD{
+		"no metadata":   nil,
+		"no token":      metadata.Pairs(),
+		"unknown token": metadata.Pairs("authorization", "Bearer nope"),
+	} {
+		if err := call(method, md); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("%s: got %v, want Unauthenticated", name, err)
+		}
+	}
+
+	if err := call(method, metadata.Pairs("authorization", "Bearer ops-token")); err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	if got.Name != "ops" || !got.HasPermission(PermissionDetokenize) || got.HasPermission(PermissionExportKeys) {
+		t.Fatalf("principal: got %+v", got)
+	}
+
+	const check = "/grpc.health.v1.Health/Check"
+	if err := callWith(check, &healthpb.HealthCheckRequest{}, nil); err != nil {
+		t.Fatalf("health check: %v", err)
+	}
+	if got != nil {
+		t.Fatal("health checks should carry no principal")
+	}
+	provider := &healthpb.HealthCheckRequest{Service: "vault.hsm.hsm-a"}
+	if err := callWith(check, provider, nil); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("provider health without a token: got %v, want Unauthenticated", err)
+	}
+	if err := callWith(check, provider, metadata.Pairs("authorization", "Bearer dev-token")); err != nil {
+		t.Fatalf("provider health: %v", err)
+	}
+	if err := callWith("/grpc.health.v1.Health/List", &healthpb.HealthListRequest{}, nil); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("health list without a token: got %v, want Unauthenticated", err)
+	}
+	var none *Principal
+	if none.HasPermission(PermissionDetokenize) {
+		t.Fatal("no principal has no permissions")
+	}
+}
@@ -10,8 +10,8 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// tokenBucket implements a simple token bucket rate limiter.
-type tokenBucket struct {
+// TokenBucket implements a simple token bucket rate limiter.
+type TokenBucket struct {
 	mu       sync.Mutex
 	tokens   float64
 	max      float64
@@ -19,8 +19,8 @@ type tokenBucket struct {
 	lastTime time.Time
 }
 
-func newTokenBucket(rps int) *tokenBucket {
-	return &tokenBucket{
+func NewTokenBucket(rps int) *TokenBucket {
+	return &TokenBucket{
 		tokens:   float64(rps),
 		max:      float64(rps),
 		rate:     float64(rps),
@@ -28,7 +28,8 @@ func newTokenBucket(rps int) *tokenBucket {
 	}
 }
 
-func (tb *tokenBucket) allow() bool {
+// Allow takes a token if one is available.
+func (tb *TokenBucket) Allow() bool {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
 
@@ -50,9 +51,9 @@ func (tb *tokenBucket) allow() bool {
 
 // RateLimitUnary returns a unary interceptor that enforces requests per second.
 func RateLimitUnary(rps int) grpc.UnaryServerInterceptor {
-	bucket := newTokenBucket(rps)
+	bucket := NewTokenBucket(rps)
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		if !bucket.allow() {
+		if !bucket.Allow() {
 			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
 		}
 		return handler(ctx, req)
@@ -61,9 +62,9 @@ func RateLimitUnary(rps int) grpc.UnaryServerInterceptor {
 
 // RateLimitStream returns a stream interceptor that enforces requests per second.
 func RateLimitStream(rps int) grpc.StreamServerInterceptor {
-	bucket := newTokenBucket(rps)
+	bucket := NewTokenBucket(rps)
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if !bucket.allow() {
+		if !bucket.Allow() {
 			return status.Error(codes.ResourceExhausted, "rate limit exceeded")
 		}
 		return handler(srv, ss)
@@ -0,0 +1,209 @@
+package keyblock
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// Attributes are the vault key attributes that a key block header maps onto.
+type Attributes struct {
+	Algorithm  keystore.KeyAlgorithm
+	Purpose    keystore.KeyPurpose
+	Mode       keystore.KeyMode
+	Exportable bool
+}
+
+// usage describes a supported TR-31 key usage.
+type usage struct {
+	purpose    keystore.KeyPurpose
+	algorithms string // permitted algorithm letters
+	modes      string // permitted mode of use letters, besides 'N'
+}
+
+var usages = map[string]usage{
+	"B0": {keystore.PurposeBaseDerivation, "TA", "X"},
+	"C0": {keystore.PurposeCardVerification, "T", "CGV"},
+	"D0": {keystore.PurposeDataEncryption, "TA", "BDE"},
+	"K0": {keystore.PurposeKeyEncryption, "TA", "BDE"},
+	"K1": {keystore.PurposeKeyBlockProtection, "TA", "BDE"},
+	"M0": {keystore.PurposeMAC, "T", "CGV"},
+	"M1": {keystore.PurposeMAC, "T", "CGV"},
+	"M3": {keystore.PurposeMAC, "T", "CGV"},
+	"M6": {keystore.PurposeMAC, "A", "CGV"},
+	"M7": {keystore.PurposeMAC, "H", "CGV"},
+	"P0": {keystore.PurposePINEncryption, "TA", "BDE"},
+	"V0": {keystore.PurposePINVerification, "T", "CGV"},
+	"V1": {keystore.PurposePINVerification, "T", "CGV"},
+	"V2": {keystore.PurposePINVerification, "T", "CGV"},
+}
+
+var modes = map[byte]keystore.KeyMode{
+	'B': keystore.ModeAny,
+	'C': keystore.ModeAny,
+	'N': keystore.ModeAny,
+	'E': keystore.ModeEncryptOnly,
+	'D': keystore.ModeDecryptOnly,
+	'G': keystore.ModeGenerateOnly,
+	'S': keystore.ModeGenerateOnly,
+	'V': keystore.ModeVerifyOnly,
+	'X': keystore.ModeDeriveOnly,
+}
+
+// HMAC hash identifiers used in the "HM" optional block.
+const (
+	hmacSHA256 = "21"
+	hmacSHA512 = "23"
+)
+
+// Attributes validates the header's key usage, algorithm, mode of use and
+// exportability against the unwrapped key length and maps them onto vault
+// key attributes. Only exportability 'E' makes the key exportable; 'S'
+// (exportable under untrusted keys) is treated as non-exportable.
+func (h Header) Attributes(keyLen int) (Attributes, error) {
+	u, ok := usages[h.KeyUsage]
+	if !ok {
+		return Attributes{}, fmt.Errorf("unsupported key usage %q", h.KeyUsage)
+	}
+	if !strings.ContainsRune(u.algorithms, rune(h.Algorithm)) {
+		return Attributes{}, fmt.Errorf("algorithm %q not valid for key usage %s", h.Algorithm, h.KeyUsage)
+	}
+	if h.ModeOfUse != 'N' && !strings.ContainsRune(u.modes, rune(h.ModeOfUse)) {
+		return Attributes{}, fmt.Errorf("mode of use %q not valid for key usage %s", h.ModeOfUse, h.KeyUsage)
+	}
+
+	attrs := Attributes{
+		Purpose: u.purpose,
+		Mode:    modes[h.ModeOfUse],
+	}
+
+	switch h.Exportability {
+	case 'E':
+		attrs.Exportable = true
+	case 'N', 'S':
+	default:
+		return Attributes{}, fmt.Errorf("invalid exportability %q", h.Exportability)
+	}
+
+	var err error
+	if attrs.Algorithm, err = h.keyAlgorithm(keyLen); err != nil {
+		return Attributes{}, err
+	}
+	if h.KeyUsage == "M3" && attrs.Algorithm != keystore.AlgorithmTDEA2Key {
+		return Attributes{}, fmt.Errorf("key usage M3 requires a double-length TDEA key")
+	}
+	return attrs, nil
+}
+
+func (h Header) keyAlgorithm(keyLen int) (keystore.KeyAlgorithm, error) {
+	switch h.Algorithm {
+	case 'T':
+		switch keyLen {
+		case 16:
+			return keystore.AlgorithmTDEA2Key, nil
+		case 24:
+			return keystore.AlgorithmTDEA3Key, nil
+		}
+	case 'A':
+		switch keyLen {
+		case 16:
+			return keystore.AlgorithmAES128, nil
+		case 32:
+			return keystore.AlgorithmAES256, nil
+		}
+	case 'H':
+		hm, ok := h.Optional("HM")
+		switch {
+		case hm == hmacSHA256 || (!ok && keyLen == 32):
+			return keystore.AlgorithmHMACSHA256, nil
+		case hm == hmacSHA512 || (!ok && keyLen == 64):
+			return keystore.AlgorithmHMACSHA512, nil
+		case ok:
+			return 0, fmt.Errorf("unsupported HMAC hash %q", hm)
+		}
+	}
+	return 0, fmt.Errorf("unsupported %q key of %d bytes", h.Algorithm, keyLen)
+}
+
+// HeaderFor builds a key block header describing entry. Keys without a
+// purpose cannot be expressed as a TR-31 key usage and are rejected.
+func HeaderFor(entry *keystore.KeyEntry, version byte) (Header, error) {
+	h := Header{
+		Version:       version,
+		KeyVersion:    "00",
+		Exportability: 'N',
+	}
+	if entry.Exportable {
+		h.Exportability = 'E'
+	}
+
+	switch entry.Algorithm {
+	case keystore.AlgorithmTDEA2Key, keystore.AlgorithmTDEA3Key:
+		h.Algorithm = 'T'
+	case keystore.AlgorithmAES128, keystore.AlgorithmAES256:
+		h.Algorithm = 'A'
+	case keystore.AlgorithmHMACSHA256:
+		h.Algorithm = 'H'
+		h.OptionalBlocks = []OptionalBlock{{ID: "HM", Data: hmacSHA256}}
+	case keystore.AlgorithmHMACSHA512:
+		h.Algorithm = 'H'
+		h.OptionalBlocks = []OptionalBlock{{ID: "HM", Data: hmacSHA512}}
+	default:
+		return Header{}, fmt.Errorf("%v keys cannot be carried in a key block", entry.Algorithm)
+	}
+
+	var defaultMode byte
+	switch entry.Purpose {
+	case keystore.PurposeBaseDerivation:
+		h.KeyUsage, defaultMode = "B0", 'X'
+	case keystore.PurposeCardVerification:
+		h.KeyUsage, defaultMode = "C0", 'C'
+	case keystore.PurposeDataEncryption:
+		h.KeyUsage, defaultMode = "D0", 'B'
+	case keystore.PurposeKeyEncryption:
+		h.KeyUsage, defaultMode = "K0", 'B'
+	case keystore.PurposeKeyBlockProtection:
+		h.KeyUsage, defaultMode = "K1", 'B'
+	case keystore.PurposePINEncryption:
+		h.KeyUsage, defaultMode = "P0", 'B'
+	case keystore.PurposePINVerification:
+		h.KeyUsage, defaultMode = "V2", 'C'
+	case keystore.PurposeMAC:
+		defaultMode = 'C'
+		switch h.Algorithm {
+		case 'A':
+			h.KeyUsage = "M6"
+		case 'H':
+			h.KeyUsage = "M7"
+		default:
+			h.KeyUsage = "M1"
+			if entry.Algorithm == keystore.AlgorithmTDEA2Key {
+				h.KeyUsage = "M3"
+			}
+		}
+	default:
+		return Header{}, fmt.Errorf("key purpose %v has no key block usage", entry.Purpose)
+	}
+
+	switch entry.Mode {
+	case keystore.ModeAny:
+		h.ModeOfUse = defaultMode
+	case keystore.ModeEncryptOnly:
+		h.ModeOfUse = 'E'
+	case keystore.ModeDecryptOnly:
+		h.ModeOfUse = 'D'
+	case keystore.ModeGenerateOnly:
+		h.ModeOfUse = 'G'
+	case keystore.ModeVerifyOnly:
+		h.ModeOfUse = 'V'
+	case keystore.ModeDeriveOnly:
+		h.ModeOfUse = 'X'
+	}
+
+	// Validate the combination the same way an importer would.
+	if _, err := h.Attributes(len(entry.SecretKey)); err != nil {
+		return Header{}, err
+	}
+	return h, nil
+}
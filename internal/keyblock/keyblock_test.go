@@ -0,0 +1,203 @@
+package keyblock
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+func TestUnwrapX9143Example(t *testing.T) {
+	// ANSI X9.143 version D example: AES-256 KBPK, AES-128 PIN encryption key.
+	kbpk, _ := hex.DecodeString("88E1AB2A2E3DD38C1FA039A536500CC8A87AB9D62DC92C01058FA79F44657DE6")
+	block := "D0112P0AE00E0000B82679114F470F540165EDFBF7E250FCEA43F810D215F8D207E2E417C07156A27E8E31DA05F7425509593D03A457DC34"
+
+	h, key, err := Unwrap(kbpk, block)
+	if err != nil {
+		t.Fatalf("unwrap: %v", err)
+	}
+	if got := strings.ToUpper(hex.EncodeToString(key)); got != "3F419E1CB7079442AA37474C2EFBF8B8" {
+		t.Fatalf("key mismatch: %s", got)
+	}
+	if h.KeyUsage != "P0" || h.Algorithm != 'A' || h.ModeOfUse != 'E' || h.Exportability != 'E' {
+		t.Fatalf("unexpected header: %+v", h)
+	}
+}
+
+func TestWrapUnwrapRoundTrip(t *testing.T) {
+	tdeaKBPK, _ := crypto.GenerateTDEAKey(16)
+	aesKBPK, _ := crypto.GenerateAESKey()
+	key, _ := crypto.GenerateTDEAKey(24)
+
+	for _, tc := range []struct {
+		version byte
+		kbpk    []byte
+	}{
+		{VersionB, tdeaKBPK},
+		{VersionD, aesKBPK},
+	} {
+		h := Header{Version: tc.version, KeyUsage: "K0", Algorithm: 'T', ModeOfUse: 'B', KeyVersion: "00", Exportability: 'N'}
+		block, err := Wrap(tc.kbpk, h, key)
+		if err != nil {
+			t.Fatalf("%c wrap: %v", tc.version, err)
+		}
+		if block[0] != tc.version {
+			t.Fatalf("%c: unexpected version in %s", tc.version, block)
+		}
+
+		got, unwrapped, err := Unwrap(tc.kbpk, block)
+		if err != nil {
+			t.Fatalf("%c unwrap: %v", tc.version, err)
+		}
+		if !bytes.Equal(unwrapped, key) {
+			t.Fatalf("%c: key mismatch", tc.version)
+		}
+		if got.KeyUsage != "K0" || got.ModeOfUse != 'B' {
+			t.Fatalf("%c: header mismatch: %+v", tc.version, got)
+		}
+	}
+}
+
+func TestWrapAddsPaddingBlock(t *testing.T) {
+	kbpk, _ := crypto.GenerateAESKey()
+	key, _ := crypto.GenerateSymmetricKey(32)
+
+	h := Header{
+		Version: VersionD, KeyUsage: "M7", Algorithm: 'H', ModeOfUse: 'C', KeyVersion: "00", Exportability: 'N',
+		OptionalBlocks: []OptionalBlock{{ID: "HM", Data: "21"}},
+	}
+	block, err := Wrap(kbpk, h, key)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+
+	got, hdrLen, err := ParseHeader(block)
+	if err != nil {
+		t.Fatalf("parse header: %v", err)
+	}
+	if hdrLen%16 != 0 {
+		t.Fatalf("header length %d not aligned to the AES block size", hdrLen)
+	}
+	if hm, ok := got.Optional("HM"); !ok || hm != "21" {
+		t.Fatalf("HM block lost: %+v", got.OptionalBlocks)
+	}
+	if _, ok := got.Optional("PB"); !ok {
+		t.Fatal("expected a PB padding block")
+	}
+	if _, _, err := Unwrap(kbpk, block); err != nil {
+		t.Fatalf("unwrap: %v", err)
+	}
+}
+
+func TestUnwrapTampered(t *testing.T) {
+	kbpk, _ := crypto.GenerateAESKey()
+	key, _ := crypto.GenerateSymmetricKey(16)
+	h := Header{Version: VersionD, KeyUsage: "D0", Algorithm: 'A', ModeOfUse: 'E', KeyVersion: "00", Exportability: 'N'}
+	block, _ := Wrap(kbpk, h, key)
+
+	// Flip the mode of use from E to B: the header is authenticated.
+	tampered := block[:8] + "B" + block[9:]
+	if _, _, err := Unwrap(kbpk, tampered); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("expected ErrAuthentication, got %v", err)
+	}
+
+	other, _ := crypto.GenerateAESKey()
+	if _, _, err := Unwrap(other, block); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("wrong kbpk: expected ErrAuthentication, got %v", err)
+	}
+
+	if _, _, err := Unwrap(kbpk, block[:len(block)-2]); err == nil {
+		t.Fatal("truncated block should fail")
+	}
+}
+
+func TestUnwrapWrongKBPKType(t *testing.T) {
+	kbpk, _ := crypto.GenerateAESKey()
+	key, _ := crypto.GenerateSymmetricKey(16)
+	h := Header{Version: VersionD, KeyUsage: "D0", Algorithm: 'A', ModeOfUse: 'B', KeyVersion: "00", Exportability: 'N'}
+	block, _ := Wrap(kbpk, h, key)
+
+	tdea, _ := crypto.GenerateTDEAKey(24)
+	if _, _, err := Unwrap(tdea, "B"+block[1:]); err == nil {
+		t.Fatal("version B block must not unwrap AES-wrapped data")
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	tests := []struct {
+		header  string
+		keyLen  int
+		want    Attributes
+		wantErr bool
+	}{
+		{"P0AE00E", 16, Attributes{keystore.AlgorithmAES128, keystore.PurposePINEncryption, keystore.ModeEncryptOnly, true}, false},
+		{"M3TV00N", 16, Attributes{keystore.AlgorithmTDEA2Key, keystore.PurposeMAC, keystore.ModeVerifyOnly, false}, false},
+		{"K1TB00S", 24, Attributes{keystore.AlgorithmTDEA3Key, keystore.PurposeKeyBlockProtection, keystore.ModeAny, false}, false},
+		{"B0TX00N", 16, Attributes{keystore.AlgorithmTDEA2Key, keystore.PurposeBaseDerivation, keystore.ModeDeriveOnly, false}, false},
+		{"M3TC00N", 24, Attributes{}, true}, // M3 needs a double-length key
+		{"M6TC00N", 16, Attributes{}, true}, // CMAC usage with TDEA
+		{"P0AG00N", 16, Attributes{}, true}, // generate-only PIN key
+		{"S0AN00N", 16, Attributes{}, true}, // asymmetric usage
+		{"D0AB00E", 24, Attributes{}, true}, // AES-192 not supported
+	}
+	for _, tt := range tests {
+		h := Header{
+			KeyUsage:      tt.header[0:2],
+			Algorithm:     tt.header[2],
+			ModeOfUse:     tt.header[3],
+			KeyVersion:    tt.header[4:6],
+			Exportability: tt.header[6],
+		}
+		got, err := h.Attributes(tt.keyLen)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.header)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.header, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestHeaderForRoundTrip(t *testing.T) {
+	secret, _ := crypto.GenerateTDEAKey(16)
+	entry := &keystore.KeyEntry{
+		Algorithm:  keystore.AlgorithmTDEA2Key,
+		SecretKey:  secret,
+		Purpose:    keystore.PurposeMAC,
+		Mode:       keystore.ModeGenerateOnly,
+		Exportable: true,
+	}
+
+	h, err := HeaderFor(entry, VersionB)
+	if err != nil {
+		t.Fatalf("header for: %v", err)
+	}
+	if h.KeyUsage != "M3" || h.ModeOfUse != 'G' || h.Exportability != 'E' {
+		t.Fatalf("unexpected header: %+v", h)
+	}
+
+	attrs, err := h.Attributes(len(secret))
+	if err != nil {
+		t.Fatalf("attributes: %v", err)
+	}
+	want := Attributes{keystore.AlgorithmTDEA2Key, keystore.PurposeMAC, keystore.ModeGenerateOnly, true}
+	if attrs != want {
+		t.Fatalf("got %+v, want %+v", attrs, want)
+	}
+
+	entry.Purpose = keystore.PurposeAny
+	if _, err := HeaderFor(entry, VersionB); err == nil {
+		t.Fatal("keys without a purpose should be rejected")
+	}
+}
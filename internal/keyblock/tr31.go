@@ -0,0 +1,396 @@
+// Package keyblock implements ANSI X9.143 (TR-31) key blocks, used to
+// exchange symmetric keys with processors and payment HSMs.
+//
+// Versions B (TDEA key block protection key) and D (AES key block
+// protection key) are supported. Both use the key derivation binding
+// method: encryption and authentication keys are derived from the key
+// block protection key (KBPK) with CMAC, the header and cleartext key
+// payload are authenticated with CMAC, and the payload is encrypted in
+// CBC mode using the MAC as IV.
+package keyblock
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+const (
+	// VersionB protects keys under a TDEA KBPK.
+	VersionB byte = 'B'
+	// VersionD protects keys under an AES KBPK.
+	VersionD byte = 'D'
+)
+
+// headerLen is the length of the fixed part of a key block header.
+const headerLen = 16
+
+var ErrAuthentication = errors.New("key block authentication failed")
+
+// Header is the cleartext, authenticated header of a key block.
+type Header struct {
+	Version        byte
+	KeyUsage       string // two characters, e.g. "P0", "M3", "K1"
+	Algorithm      byte   // 'T' TDEA, 'A' AES, 'H' HMAC
+	ModeOfUse      byte   // e.g. 'B', 'E', 'D', 'C', 'G', 'V', 'X', 'N'
+	KeyVersion     string // two characters, "00" when unused
+	Exportability  byte   // 'E' exportable, 'N' non-exportable, 'S' sensitive
+	OptionalBlocks []OptionalBlock
+}
+
+// OptionalBlock is a single optional header block such as "HM" or "KS".
+type OptionalBlock struct {
+	ID   string
+	Data string
+}
+
+// Optional returns the data of the optional block with the given ID.
+func (h Header) Optional(id string) (string, bool) {
+	for _, ob := range h.OptionalBlocks {
+		if ob.ID == id {
+			return ob.Data, true
+		}
+	}
+	return "", false
+}
+
+// Wrap builds a key block protecting key under kbpk. The header's Version
+// selects the protection method; the length field and any padding optional
+// block are filled in automatically.
+func Wrap(kbpk []byte, h Header, key []byte) (string, error) {
+	p, err := paramsFor(h.Version, kbpk)
+	if err != nil {
+		return "", err
+	}
+	if len(key) == 0 || len(key) > 0xffff/8 {
+		return "", fmt.Errorf("invalid key length: %d", len(key))
+	}
+	if err := h.validate(); err != nil {
+		return "", err
+	}
+
+	// Payload: 16-bit key length in bits, key, random padding to the block size.
+	payloadLen := 2 + len(key)
+	if r := payloadLen % p.blockSize; r != 0 {
+		payloadLen += p.blockSize - r
+	}
+	payload := make([]byte, payloadLen)
+	payload[0] = byte(len(key) * 8 >> 8)
+	payload[1] = byte(len(key) * 8)
+	copy(payload[2:], key)
+	if _, err := rand.Read(payload[2+len(key):]); err != nil {
+		return "", fmt.Errorf("generate padding: %w", err)
+	}
+
+	opt := h.encodeOptional(p.blockSize)
+	total := headerLen + len(opt) + 2*payloadLen + 2*p.macLen
+	if total > 9999 {
+		return "", fmt.Errorf("key block too long: %d", total)
+	}
+	header := h.encodeFixed(total, len(h.OptionalBlocks)+padBlocks(h, p.blockSize)) + opt
+
+	kbek, kbak, err := p.deriveKeys(kbpk)
+	if err != nil {
+		return "", err
+	}
+
+	mac, err := p.mac(kbak, []byte(header), payload)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := p.newCipher(kbek)
+	if err != nil {
+		return "", err
+	}
+	enc := make([]byte, len(payload))
+	cipher.NewCBCEncrypter(block, mac[:p.blockSize]).CryptBlocks(enc, payload)
+
+	return header + strings.ToUpper(hex.EncodeToString(enc)) + strings.ToUpper(hex.EncodeToString(mac)), nil
+}
+
+// Unwrap authenticates and decrypts a key block under kbpk, returning the
+// parsed header and the cleartext key.
+func Unwrap(kbpk []byte, block string) (Header, []byte, error) {
+	h, hdrLen, err := ParseHeader(block)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	p, err := paramsFor(h.Version, kbpk)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	body := block[hdrLen:]
+	if len(body) < 2*(p.macLen+p.blockSize) {
+		return Header{}, nil, fmt.Errorf("key block too short")
+	}
+	encHex, macHex := body[:len(body)-2*p.macLen], body[len(body)-2*p.macLen:]
+	enc, err := hex.DecodeString(encHex)
+	if err != nil {
+		return Header{}, nil, fmt.Errorf("decode encrypted key data: %w", err)
+	}
+	mac, err := hex.DecodeString(macHex)
+	if err != nil {
+		return Header{}, nil, fmt.Errorf("decode key block mac: %w", err)
+	}
+	if len(enc)%p.blockSize != 0 {
+		return Header{}, nil, fmt.Errorf("encrypted key data is not a multiple of the block size")
+	}
+
+	kbek, kbak, err := p.deriveKeys(kbpk)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	c, err := p.newCipher(kbek)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	payload := make([]byte, len(enc))
+	cipher.NewCBCDecrypter(c, mac[:p.blockSize]).CryptBlocks(payload, enc)
+
+	expected, err := p.mac(kbak, []byte(block[:hdrLen]), payload)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	if subtle.ConstantTimeCompare(expected, mac) != 1 {
+		return Header{}, nil, ErrAuthentication
+	}
+
+	bits := int(payload[0])<<8 | int(payload[1])
+	if bits == 0 || bits%8 != 0 || 2+bits/8 > len(payload) {
+		return Header{}, nil, fmt.Errorf("invalid key length in payload: %d bits", bits)
+	}
+	return h, payload[2 : 2+bits/8], nil
+}
+
+// ParseHeader parses the header of a key block without verifying it,
+// returning the header and its length including optional blocks.
+func ParseHeader(block string) (Header, int, error) {
+	if len(block) < headerLen {
+		return Header{}, 0, fmt.Errorf("key block too short")
+	}
+	total, err := strconv.Atoi(block[1:5])
+	if err != nil {
+		return Header{}, 0, fmt.Errorf("invalid key block length field %q", block[1:5])
+	}
+	if total != len(block) {
+		return Header{}, 0, fmt.Errorf("key block length field %d does not match actual length %d", total, len(block))
+	}
+	count, err := strconv.Atoi(block[12:14])
+	if err != nil {
+		return Header{}, 0, fmt.Errorf("invalid optional block count %q", block[12:14])
+	}
+
+	h := Header{
+		Version:       block[0],
+		KeyUsage:      block[5:7],
+		Algorithm:     block[7],
+		ModeOfUse:     block[8],
+		KeyVersion:    block[9:11],
+		Exportability: block[11],
+	}
+
+	pos := headerLen
+	for range count {
+		if pos+4 > len(block) {
+			return Header{}, 0, fmt.Errorf("truncated optional block")
+		}
+		id := block[pos : pos+2]
+		n, err := strconv.ParseUint(block[pos+2:pos+4], 16, 8)
+		if err != nil {
+			return Header{}, 0, fmt.Errorf("invalid optional block length for %s", id)
+		}
+		dataStart := pos + 4
+		if n == 0 {
+			// Extended length: two hex digits give the size of the length field.
+			if pos+6 > len(block) {
+				return Header{}, 0, fmt.Errorf("truncated optional block %s", id)
+			}
+			ll, err := strconv.ParseUint(block[pos+4:pos+6], 16, 8)
+			if err != nil || pos+6+int(ll) > len(block) {
+				return Header{}, 0, fmt.Errorf("invalid extended length for optional block %s", id)
+			}
+			n, err = strconv.ParseUint(block[pos+6:pos+6+int(ll)], 16, 32)
+			if err != nil {
+				return Header{}, 0, fmt.Errorf("invalid extended length for optional block %s", id)
+			}
+			dataStart = pos + 6 + int(ll)
+		}
+		end := pos + int(n)
+		if end < dataStart || end > len(block) {
+			return Header{}, 0, fmt.Errorf("invalid optional block length for %s", id)
+		}
+		h.OptionalBlocks = append(h.OptionalBlocks, OptionalBlock{ID: id, Data: block[dataStart:end]})
+		pos = end
+	}
+
+	if err := h.validate(); err != nil {
+		return Header{}, 0, err
+	}
+	return h, pos, nil
+}
+
+func (h Header) validate() error {
+	if h.Version != VersionB && h.Version != VersionD {
+		return fmt.Errorf("unsupported key block version %q", h.Version)
+	}
+	if len(h.KeyUsage) != 2 || len(h.KeyVersion) != 2 {
+		return fmt.Errorf("malformed key block header")
+	}
+	for _, ob := range h.OptionalBlocks {
+		if len(ob.ID) != 2 || len(ob.Data) > 0xff-4 {
+			return fmt.Errorf("malformed optional block %q", ob.ID)
+		}
+	}
+	return nil
+}
+
+// encodeFixed renders the 16-character fixed header.
+func (h Header) encodeFixed(total, optCount int) string {
+	return fmt.Sprintf("%c%04d%s%c%c%s%c%02d00",
+		h.Version, total, h.KeyUsage, h.Algorithm, h.ModeOfUse, h.KeyVersion, h.Exportability, optCount)
+}
+
+// encodeOptional renders the optional blocks, appending a "PB" padding block
+// when needed so that the full header is a multiple of the cipher block size.
+func (h Header) encodeOptional(blockSize int) string {
+	var b strings.Builder
+	for _, ob := range h.OptionalBlocks {
+		fmt.Fprintf(&b, "%s%02X%s", ob.ID, 4+len(ob.Data), ob.Data)
+	}
+	if padBlocks(h, blockSize) == 1 {
+		n := padLen(b.Len(), blockSize)
+		fmt.Fprintf(&b, "PB%02X%s", n, strings.Repeat("0", n-4))
+	}
+	return b.String()
+}
+
+// padBlocks reports whether a padding block is needed (1) or not (0).
+func padBlocks(h Header, blockSize int) int {
+	n := 0
+	for _, ob := range h.OptionalBlocks {
+		n += 4 + len(ob.Data)
+	}
+	if (headerLen+n)%blockSize == 0 {
+		return 0
+	}
+	return 1
+}
+
+// padLen returns the size of the padding block that aligns a header with
+// optLen bytes of optional blocks. A block is at least four characters.
+func padLen(optLen, blockSize int) int {
+	n := blockSize - (headerLen+optLen)%blockSize
+	for n < 4 {
+		n += blockSize
+	}
+	return n
+}
+
+// params captures the per-version cipher choices.
+type params struct {
+	blockSize int
+	macLen    int
+	newCipher func(key []byte) (cipher.Block, error)
+	algorithm uint16 // KDF algorithm indicator
+	keyBits   uint16 // KDF derived key length
+	keyLen    int
+}
+
+func paramsFor(version byte, kbpk []byte) (params, error) {
+	switch version {
+	case VersionB:
+		p := params{blockSize: 8, macLen: 8, newCipher: crypto.NewTDEACipher, keyLen: len(kbpk)}
+		switch len(kbpk) {
+		case 16:
+			p.algorithm, p.keyBits = 0x0000, 128
+		case 24:
+			p.algorithm, p.keyBits = 0x0001, 192
+		default:
+			return params{}, fmt.Errorf("version B requires a 16- or 24-byte TDEA KBPK, got %d bytes", len(kbpk))
+		}
+		return p, nil
+	case VersionD:
+		p := params{blockSize: 16, macLen: 16, newCipher: newAES, keyLen: len(kbpk)}
+		switch len(kbpk) {
+		case 16:
+			p.algorithm, p.keyBits = 0x0002, 128
+		case 24:
+			p.algorithm, p.keyBits = 0x0003, 192
+		case 32:
+			p.algorithm, p.keyBits = 0x0004, 256
+		default:
+			return params{}, fmt.Errorf("version D requires an AES KBPK, got %d bytes", len(kbpk))
+		}
+		return p, nil
+	default:
+		return params{}, fmt.Errorf("unsupported key block version %q", version)
+	}
+}
+
+func newAES(key []byte) (cipher.Block, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	return block, nil
+}
+
+// deriveKeys derives the key block encryption key (KBEK) and key block
+// authentication key (KBAK) from the KBPK using CMAC in counter mode.
+func (p params) deriveKeys(kbpk []byte) (kbek, kbak []byte, err error) {
+	if kbek, err = p.derive(kbpk, 0x0000); err != nil {
+		return nil, nil, err
+	}
+	if kbak, err = p.derive(kbpk, 0x0001); err != nil {
+		return nil, nil, err
+	}
+	return kbek, kbak, nil
+}
+
+func (p params) derive(kbpk []byte, usage uint16) ([]byte, error) {
+	block, err := p.newCipher(kbpk)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	for counter := byte(1); len(out) < p.keyLen; counter++ {
+		m, err := crypto.NewCMAC(block)
+		if err != nil {
+			return nil, err
+		}
+		m.Write([]byte{
+			counter,
+			byte(usage >> 8), byte(usage),
+			0x00,
+			byte(p.algorithm >> 8), byte(p.algorithm),
+			byte(p.keyBits >> 8), byte(p.keyBits),
+		})
+		out = m.Sum(out)
+	}
+	return out[:p.keyLen], nil
+}
+
+func (p params) mac(kbak, header, payload []byte) ([]byte, error) {
+	block, err := p.newCipher(kbak)
+	if err != nil {
+		return nil, err
+	}
+	m, err := crypto.NewCMAC(block)
+	if err != nil {
+		return nil, err
+	}
+	m.Write(header)
+	m.Write(payload)
+	return m.Sum(nil)[:p.macLen], nil
+}
@@ -0,0 +1,342 @@
+package keystore
+
+import (
+	"container/list"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+var (
+	// ErrDerivedKey is returned for writes to a key derived by path. Such
+	// keys follow their root: they cannot be stored, rotated or deleted on
+	// their own.
+	ErrDerivedKey = errors.New("derived keys follow their root key")
+	// ErrNotDerivable is returned when a key cannot act as a derivation
+	// root: it must be a symmetric key whose purpose allows derivation.
+	ErrNotDerivable = errors.New("key cannot be a derivation root")
+	// ErrNoSoftwareProvider is returned for ECDSA keys derived by path
+	// when no software HSM provider is registered to hold them.
+	ErrNoSoftwareProvider = errors.New("no software hsm provider to hold derived ECDSA keys")
+)
+
+const (
+	maxPathDepth      = 16
+	maxPathSegmentLen = 64
+)
+
+// derivedPurposes lists the algorithms that can be derived by path and the
+// purpose their keys are given.
+var derivedPurposes = map[KeyAlgorithm]KeyPurpose{
+	AlgorithmECDSAP256:         PurposeSigning,
+	AlgorithmECDSAP384:         PurposeSigning,
+	AlgorithmAES128:            PurposeDataEncryption,
+	AlgorithmAES256:            PurposeDataEncryption,
+	AlgorithmChaCha20Poly1305:  PurposeDataEncryption,
+	AlgorithmXChaCha20Poly1305: PurposeDataEncryption,
+	AlgorithmAESSIV:            PurposeDeterministicEncryption,
+	AlgorithmHMACSHA256:        PurposeMAC,
+	AlgorithmHMACSHA512:        PurposeMAC,
+}
+
+// derivedSizes gives the key length of the symmetric derivable algorithms.
+var derivedSizes = map[KeyAlgorithm]int{
+	AlgorithmAES128:            16,
+	AlgorithmAES256:            32,
+	AlgorithmChaCha20Poly1305:  32,
+	AlgorithmXChaCha20Poly1305: 32,
+	AlgorithmAESSIV:            64,
+	AlgorithmHMACSHA256:        32,
+	AlgorithmHMACSHA512:        64,
+}
+
+// Derivable reports whether keys of this algorithm can be derived from a
+// root key by path.
+func (a KeyAlgorithm) Derivable() bool {
+	_, ok := derivedPurposes[a]
+	return ok
+}
+
+// ParseDerivationPath splits a path such as "acquirer/merchant/123" into
+// its segments. Segments are 1-64 characters of letters, digits, '.', '_'
+// and '-', at most 16 deep.
+func ParseDerivationPath(path string) ([]string, error) {
+	segments := strings.Split(path, "/")
+	if path == "" || len(segments) > maxPathDepth {
+		return nil, fmt.Errorf("derivation path must have 1-%d segments", maxPathDepth)
+	}
+	for _, s := range segments {
+		if s == "" || len(s) > maxPathSegmentLen {
+			return nil, fmt.Errorf("derivation path segment %q must be 1-%d characters", s, maxPathSegmentLen)
+		}
+		for _, c := range s {
+			if !isPathChar(c) {
+				return nil, fmt.Errorf("derivation path segment %q contains %q", s, c)
+			}
+		}
+	}
+	return segments, nil
+}
+
+func isPathChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-'
+}
+
+// ChildKeyID returns the ID of the alg key at path below the root key. The
+// ID names everything needed to derive the key again, so a derived key can
+// be used by ID after a restart without having been stored.
+func ChildKeyID(rootID string, alg KeyAlgorithm, path string) string {
+	return rootID + "/" + alg.String() + "/" + path
+}
+
+// parseChildKeyID splits an ID produced by ChildKeyID.
+func parseChildKeyID(id string) (rootID string, alg KeyAlgorithm, path string, ok bool) {
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 {
+		return "", 0, "", false
+	}
+	for a := range derivedPurposes {
+		if a.String() == parts[1] {
+			return parts[0], a, parts[2], true
+		}
+	}
+	return "", 0, "", false
+}
+
+// maxDerivedKeys bounds how many derived keys a DerivedStore caches.
+const maxDerivedKeys = 4096
+
+// DerivedStore wraps a Store and resolves the IDs of keys derived by path
+// from a root key held in it. Derived keys are computed on first use and
+// cached; nothing about them is written to the underlying store. They take
+// their status from their root, and the encryptions made under them count
+// against the root. List only reports the derived keys in the cache.
+//
+// The cache keeps the most recently used keys, up to maxDerivedKeys; a key
+// dropped from it is derived again on its next use. Deleting a root drops
+// its keys.
+//
+// ECDSA children are held in memory by software, which may be nil when
+// no software provider is registered; deriving one then fails with
+// ErrNoSoftwareProvider. The software provider lets go of a child's key
+// when the child leaves the cache.
+type DerivedStore struct {
+	Store
+	software *SoftwareKeys
+
+	mu    sync.Mutex
+	limit int
+	// children holds the cached keys by ID, as elements of lru, which
+	// orders them from most to least recently used.
+	children map[string]*list.Element
+	lru      *list.List
+}
+
+func NewDerivedStore(base Store, software *SoftwareKeys) *DerivedStore {
+	return &DerivedStore{
+		Store:    base,
+		software: software,
+		limit:    maxDerivedKeys,
+		children: make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+func (d *DerivedStore) Get(id string) (*KeyEntry, error) {
+	rootID, alg, path, ok := parseChildKeyID(id)
+	if !ok {
+		return d.Store.Get(id)
+	}
+	root, err := d.Store.Get(rootID)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			d.mu.Lock()
+			d.forget(id)
+			d.mu.Unlock()
+		}
+		return nil, err
+	}
+	child, err := d.derive(root, id, alg, path)
+	if err != nil {
+		return nil, err
+	}
+	return withRoot(child, root), nil
+}
+
+func (d *DerivedStore) List(filter KeyStatus) ([]*KeyEntry, error) {
+	result, err := d.Store.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	children := make([]*KeyEntry, 0, len(d.children))
+	for _, el := range d.children {
+		children = append(children, el.Value.(*KeyEntry))
+	}
+	d.mu.Unlock()
+
+	for _, child := range children {
+		root, err := d.Store.Get(child.ParentID)
+		if err != nil {
+			continue
+		}
+		if filter == 0 || root.Status == filter {
+			result = append(result, withRoot(child, root))
+		}
+	}
+	return result, nil
+}
+
+func (d *DerivedStore) Put(entry *KeyEntry) error {
+	if _, _, _, ok := parseChildKeyID(entry.ID); ok {
+		return ErrDerivedKey
+	}
+	return d.Store.Put(entry)
+}
+
+func (d *DerivedStore) UpdateStatus(id string, status KeyStatus) error {
+	if _, _, _, ok := parseChildKeyID(id); ok {
+		return ErrDerivedKey
+	}
+	return d.Store.UpdateStatus(id, status)
+}
+
+func (d *DerivedStore) Delete(id string) error {
+	if _, _, _, ok := parseChildKeyID(id); ok {
+		return ErrDerivedKey
+	}
+	if err := d.Store.Delete(id); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for childID, el := range d.children {
+		if el.Value.(*KeyEntry).ParentID == id {
+			d.forget(childID)
+		}
+	}
+	return nil
+}
+
+// RecordEncryption counts encryptions under a derived key against its
+// root, which overstates each child's usage but never loses it across a
+// restart.
+func (d *DerivedStore) RecordEncryption(id string) (uint64, error) {
+	if rootID, _, _, ok := parseChildKeyID(id); ok {
+		id = rootID
+	}
+	return d.Store.RecordEncryption(id)
+}
+
+// derive returns the cached key for id, deriving it from root on first use.
+func (d *DerivedStore) derive(root *KeyEntry, id string, alg KeyAlgorithm, path string) (*KeyEntry, error) {
+	d.mu.Lock()
+	el, ok := d.children[id]
+	if ok {
+		d.lru.MoveToFront(el)
+	}
+	d.mu.Unlock()
+	if ok {
+		return el.Value.(*KeyEntry), nil
+	}
+
+	if !root.Algorithm.IsSymmetric() || len(root.SecretKey) == 0 || !root.Permits(OpDerive) {
+		return nil, ErrNotDerivable
+	}
+	segments, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := crypto.DerivePath(root.SecretKey, segments)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(chain)
+
+	child := &KeyEntry{
+		ID:             id,
+		Algorithm:      alg,
+		Purpose:        derivedPurposes[alg],
+		CreatedAt:      root.CreatedAt,
+		ParentID:       root.ID,
+		DerivationPath: path,
+	}
+	switch alg {
+	case AlgorithmECDSAP256, AlgorithmECDSAP384:
+		err = d.deriveSigningKey(child, chain)
+	default:
+		child.SecretKey, err = crypto.DeriveLeafKey(chain, alg.String(), derivedSizes[alg])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("derive %v key: %w", alg, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Another call may have derived the same key meanwhile.
+	if el, ok := d.children[id]; ok {
+		d.release(child)
+		d.lru.MoveToFront(el)
+		return el.Value.(*KeyEntry), nil
+	}
+	d.children[id] = d.lru.PushFront(child)
+	for d.lru.Len() > d.limit {
+		d.forget(d.lru.Back().Value.(*KeyEntry).ID)
+	}
+	return child, nil
+}
+
+// forget drops the cached key id, if any. d.mu must be held.
+func (d *DerivedStore) forget(id string) {
+	el, ok := d.children[id]
+	if !ok {
+		return
+	}
+	delete(d.children, id)
+	d.lru.Remove(el)
+	d.release(el.Value.(*KeyEntry))
+}
+
+// release lets go of the software provider's hold on a derived key.
+func (d *DerivedStore) release(child *KeyEntry) {
+	if child.Handle != "" {
+		d.software.HSM.ReleaseKey(child.Handle)
+	}
+}
+
+// deriveSigningKey derives an ECDSA child. The key is computed here, from
+// a root this process already holds, and held by the software provider.
+func (d *DerivedStore) deriveSigningKey(child *KeyEntry, chain []byte) error {
+	if d.software == nil {
+		return ErrNoSoftwareProvider
+	}
+	curve := elliptic.P256()
+	if child.Algorithm == AlgorithmECDSAP384 {
+		curve = elliptic.P384()
+	}
+	key, err := crypto.DeriveECDSAKey(curve, chain, child.Algorithm.String())
+	if err != nil {
+		return err
+	}
+	if child.Handle, err = d.software.HSM.HoldKey(key); err != nil {
+		return err
+	}
+	child.Provider = d.software.Provider
+	child.PublicKey = &key.PublicKey
+	return nil
+}
+
+// withRoot returns a copy of a cached derived key carrying its root's
+// current status and encryption count.
+func withRoot(child, root *KeyEntry) *KeyEntry {
+	e := *child
+	e.Status = root.Status
+	e.RotatedAt = root.RotatedAt
+	e.Encryptions = root.Encryptions
+	return &e
+}
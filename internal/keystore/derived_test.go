@@ -0,0 +1,231 @@
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+)
+
+func testSoftware() *SoftwareKeys {
+	return &SoftwareKeys{Provider: "software", HSM: hsm.NewSoftwareHSM()}
+}
+
+func putRoot(t *testing.T, store Store, id string) *KeyEntry {
+	t.Helper()
+	return putSymmetric(t, store, id, PurposeBaseDerivation)
+}
+
+func putSymmetric(t *testing.T, store Store, id string, purpose KeyPurpose) *KeyEntry {
+	t.Helper()
+	secret, err := crypto.GenerateAESKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	root := &KeyEntry{
+		ID:        id,
+		Algorithm: AlgorithmAES256,
+		Status:    StatusActive,
+		SecretKey: secret,
+		Purpose:   purpose,
+		CreatedAt: time.Now(),
+	}
+	if err := store.Put(root); err != nil {
+		t.Fatalf("put root: %v", err)
+	}
+	return root
+}
+
+func TestDerivedStoreGet(t *testing.T) {
+	store := NewDerivedStore(NewMemoryStore(), testSoftware())
+	putRoot(t, store, "root")
+
+	id := ChildKeyID("root", AlgorithmAES256, "acquirer/merchant/123/terminal/9")
+	child, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+	if child.ParentID != "root" || child.DerivationPath != "acquirer/merchant/123/terminal/9" {
+		t.Fatalf("lineage: parent %q path %q", child.ParentID, child.DerivationPath)
+	}
+	if child.Purpose != PurposeDataEncryption || child.Status != StatusActive || len(child.SecretKey) != 32 {
+		t.Fatalf("unexpected child %+v", child)
+	}
+
+	signer, err := store.Get(ChildKeyID("root", AlgorithmECDSAP256, "acquirer/merchant/123/terminal/9"))
+	if err != nil {
+		t.Fatalf("get signing child: %v", err)
+	}
+	if signer.Handle == "" || signer.PublicKey == nil || signer.Purpose != PurposeSigning || signer.Provider != "software" {
+		t.Fatal("ECDSA child should carry a signing key handle")
+	}
+	unheld := NewDerivedStore(NewMemoryStore(), nil)
+	putRoot(t, unheld, "root")
+	if _, err := unheld.Get(ChildKeyID("root", AlgorithmECDSAP256, "a")); !errors.Is(err, ErrNoSoftwareProvider) {
+		t.Fatal("ECDSA child without a software provider should fail")
+	}
+
+	if _, err := store.Get(ChildKeyID("missing", AlgorithmAES256, "a")); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("child of missing root: got %v, want ErrKeyNotFound", err)
+	}
+	if _, err := store.Get(ChildKeyID("root", AlgorithmAES256, "a//b")); err == nil {
+		t.Fatal("invalid path should fail")
+	}
+}
+
+func TestDerivedStoreReproducible(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	ps, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	store := NewDerivedStore(ps, testSoftware())
+	putRoot(t, store, "root")
+	id := ChildKeyID("root", AlgorithmHMACSHA256, "merchant/42")
+	before, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+
+	ps2, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	if _, err := ps2.Get(id); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal("derived keys should not be persisted")
+	}
+	after, err := NewDerivedStore(ps2, testSoftware()).Get(id)
+	if err != nil {
+		t.Fatalf("get child after reload: %v", err)
+	}
+	if !bytes.Equal(before.SecretKey, after.SecretKey) {
+		t.Fatal("derived key should be reproduced after reload")
+	}
+}
+
+func TestDerivedStoreFollowsRoot(t *testing.T) {
+	store := NewDerivedStore(NewMemoryStore(), testSoftware())
+	putRoot(t, store, "root")
+	other := putRoot(t, store, "other")
+	id := ChildKeyID("root", AlgorithmAES256, "terminal/1")
+	if _, err := store.Get(id); err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+
+	if n, _ := store.RecordEncryption(id); n != 1 {
+		t.Fatalf("encryption count: got %d, want 1", n)
+	}
+	if root, _ := store.Get("root"); root.Encryptions != 1 {
+		t.Fatal("child encryptions should count against the root")
+	}
+
+	if err := store.UpdateStatus("root", StatusDeactivated); err != nil {
+		t.Fatalf("deactivate root: %v", err)
+	}
+	child, _ := store.Get(id)
+	if child.Status != StatusDeactivated {
+		t.Fatalf("child status: got %v, want DEACTIVATED", child.Status)
+	}
+	deactivated, _ := store.List(StatusDeactivated)
+	if len(deactivated) != 2 {
+		t.Fatalf("deactivated keys: got %d, want root and child", len(deactivated))
+	}
+	active, _ := store.List(StatusActive)
+	if len(active) != 1 || active[0].ID != other.ID {
+		t.Fatalf("active keys: got %d, want only the other root", len(active))
+	}
+
+	for name, err := range map[string]error{
+		"put":    store.Put(&KeyEntry{ID: id}),
+		"status": store.UpdateStatus(id, StatusActive),
+		"delete": store.Delete(id),
+	} {
+		if !errors.Is(err, ErrDerivedKey) {
+			t.Fatalf("%s: got %v, want ErrDerivedKey", name, err)
+		}
+	}
+}
+
+func TestDerivedStoreRootChecks(t *testing.T) {
+	store := NewDerivedStore(NewMemoryStore(), testSoftware())
+	putSymmetric(t, store, "mac-only", PurposeMAC)
+	if _, err := store.Get(ChildKeyID("mac-only", AlgorithmAES256, "a")); !errors.Is(err, ErrNotDerivable) {
+		t.Fatalf("root without the derive operation: got %v, want ErrNotDerivable", err)
+	}
+	if err := store.Put(makeEntry(t, "ecdsa")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := store.Get(ChildKeyID("ecdsa", AlgorithmAES256, "a")); !errors.Is(err, ErrNotDerivable) {
+		t.Fatalf("asymmetric root: got %v, want ErrNotDerivable", err)
+	}
+}
+
+func TestDerivedStoreReleasesKeys(t *testing.T) {
+	software := testSoftware()
+	store := NewDerivedStore(NewMemoryStore(), software)
+	store.limit = 2
+	putRoot(t, store, "root")
+	signer := func(path string) *KeyEntry {
+		t.Helper()
+		child, err := store.Get(ChildKeyID("root", AlgorithmECDSAP256, path))
+		if err != nil {
+			t.Fatalf("get %s: %v", path, err)
+		}
+		return child
+	}
+
+	first := signer("a")
+	evicted := signer("b")
+	signer("a")
+	signer("c")
+	if _, err := software.HSM.Sign(evicted.Handle, []byte("data")); !errors.Is(err, hsm.ErrInvalidHandle) {
+		t.Fatalf("sign with an evicted key: got %v, want ErrInvalidHandle", err)
+	}
+	if _, err := software.HSM.Sign(first.Handle, []byte("data")); err != nil {
+		t.Fatalf("recently used key should stay cached: %v", err)
+	}
+	if again := signer("b"); again.Handle == evicted.Handle || !again.PublicKey.Equal(evicted.PublicKey) {
+		t.Fatal("evicted key should be derived again")
+	}
+
+	var wg sync.WaitGroup
+	handles := make([]hsm.KeyHandle, 8)
+	for i := range handles {
+		wg.Go(func() { handles[i] = signer("d").Handle })
+	}
+	wg.Wait()
+	for _, h := range handles {
+		if h != handles[0] {
+			t.Fatal("concurrent derivations should share one key")
+		}
+	}
+
+	if err := store.Delete("root"); err != nil {
+		t.Fatalf("delete root: %v", err)
+	}
+	if _, err := software.HSM.Sign(handles[0], []byte("data")); !errors.Is(err, hsm.ErrInvalidHandle) {
+		t.Fatalf("sign after deleting the root: got %v, want ErrInvalidHandle", err)
+	}
+	if children, _ := store.List(StatusActive); len(children) != 0 {
+		t.Fatalf("children of a deleted root: got %d, want none", len(children))
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	if got, err := ParseDerivationPath("acquirer/merchant/123"); err != nil || len(got) != 3 {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	for _, bad := range []string{"", "/a", "a/", "a//b", "a/b c", "a/ü", string(bytes.Repeat([]byte("x"), 65))} {
+		if _, err := ParseDerivationPath(bad); err == nil {
+			t.Fatalf("%q should be rejected", bad)
+		}
+	}
+	if _, err := ParseDerivationPath("a/b/c/d/e/f/g/h/i/j/k/l/m/n/o/p/q"); err == nil {
+		t.Fatal("path deeper than 16 segments should be rejected")
+	}
+}
@@ -7,22 +7,23 @@ import (
 	"testing"
 	"time"
 
-	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
 )
 
 func makeEntry(t *testing.T, id string) *KeyEntry {
 	t.Helper()
-	key, err := crypto.GenerateECDSAKey(elliptic.P256())
+	handle, pub, err := hsm.NewSoftwareHSM().GenerateKey(id, elliptic.P256())
 	if err != nil {
 		t.Fatalf("generate key: %v", err)
 	}
 	return &KeyEntry{
-		ID:         id,
-		Algorithm:  AlgorithmECDSAP256,
-		Status:     StatusActive,
-		PrivateKey: key,
-		CreatedAt:  time.Now(),
-		Labels:     map[string]string{"env": "test"},
+		ID:        id,
+		Algorithm: AlgorithmECDSAP256,
+		Status:    StatusActive,
+		Handle:    handle,
+		PublicKey: pub,
+		CreatedAt: time.Now(),
+		Labels:    map[string]string{"env": "test"},
 	}
 }
 
@@ -110,6 +111,9 @@ func TestUpdateStatus(t *testing.T) {
 	if got.Status != StatusRotated {
 		t.Fatalf("expected StatusRotated, got %v", got.Status)
 	}
+	if got.RotatedAt.IsZero() {
+		t.Fatal("rotated key should record when it was rotated")
+	}
 }
 
 func TestUpdateStatusNotFound(t *testing.T) {
@@ -140,6 +144,66 @@ func TestDeleteNotFound(t *testing.T) {
 	}
 }
 
+func TestRecordEncryption(t *testing.T) {
+	store := NewMemoryStore()
+	store.Put(makeEntry(t, "key-1"))
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.RecordEncryption("key-1")
+		}()
+	}
+	wg.Wait()
+
+	n, err := store.RecordEncryption("key-1")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if n != 101 {
+		t.Fatalf("count = %d, want 101", n)
+	}
+	if _, err := store.RecordEncryption("nonexistent"); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestEntriesAreCopies(t *testing.T) {
+	store := NewMemoryStore()
+	entry := makeEntry(t, "key-1")
+	store.Put(entry)
+	entry.Status = StatusDeactivated
+
+	got, _ := store.Get("key-1")
+	if got.Status != StatusActive {
+		t.Fatal("changing a stored entry should not change the store")
+	}
+
+	// Readers of an entry never race the store's updates to it.
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			store.RecordEncryption("key-1")
+			store.UpdateStatus("key-1", StatusActive)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = got.Encryptions + uint64(got.Status)
+			if list, _ := store.List(0); len(list) == 1 {
+				_ = list[0].Encryptions + uint64(list[0].Status)
+			}
+		}()
+	}
+	wg.Wait()
+	if got.Encryptions != 0 {
+		t.Fatal("counting encryptions should not change an entry already read")
+	}
+}
+
 func TestConcurrentReadWrite(t *testing.T) {
 	store := NewMemoryStore()
 	const numKeys = 50
@@ -201,3 +265,38 @@ func TestConcurrentReadWrite(t *testing.T) {
 		}
 	}
 }
+
+func TestPermits(t *testing.T) {
+	tests := []struct {
+		purpose KeyPurpose
+		mode    KeyMode
+		op      KeyOperation
+		want    bool
+	}{
+		{PurposeAny, ModeAny, OpSign, true},
+		{PurposeAny, ModeAny, OpUnwrap, true},
+		{PurposeMAC, ModeAny, OpGenerateMAC, true},
+		{PurposeMAC, ModeAny, OpEncrypt, false},
+		{PurposeMAC, ModeVerifyOnly, OpGenerateMAC, false},
+		{PurposeMAC, ModeVerifyOnly, OpVerifyMAC, true},
+		{PurposeKeyBlockProtection, ModeAny, OpWrap, true},
+		{PurposeKeyBlockProtection, ModeDecryptOnly, OpWrap, false},
+		{PurposeSigning, ModeAny, OpDecrypt, false},
+		{PurposeAny, ModeDeriveOnly, OpDerive, true},
+		{PurposeAny, ModeDeriveOnly, OpEncrypt, false},
+		{PurposeKeyAgreement, ModeAny, OpAgree, true},
+		{PurposeKeyAgreement, ModeDeriveOnly, OpAgree, true},
+		{PurposeKeyAgreement, ModeAny, OpSign, false},
+		{PurposeSigning, ModeAny, OpAgree, false},
+		{PurposeDeterministicEncryption, ModeAny, OpEncryptDeterministic, true},
+		{PurposeDeterministicEncryption, ModeAny, OpEncrypt, false},
+		{PurposeDeterministicEncryption, ModeDecryptOnly, OpEncryptDeterministic, false},
+		{PurposeDataEncryption, ModeAny, OpEncryptDeterministic, false},
+	}
+	for _, tt := range tests {
+		e := &KeyEntry{Purpose: tt.purpose, Mode: tt.mode}
+		if got := e.Permits(tt.op); got != tt.want {
+			t.Errorf("%v/%v op %d: got %v, want %v", tt.purpose, tt.mode, tt.op, got, tt.want)
+		}
+	}
+}
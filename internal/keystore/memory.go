@@ -3,9 +3,12 @@ package keystore
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 // MemoryStore is a thread-safe in-memory key store backed by sync.RWMutex.
+// Entries are copied in and out, so a caller never reads an entry that
+// the store is updating.
 type MemoryStore struct {
 	mu   sync.RWMutex
 	keys map[string]*KeyEntry
@@ -24,7 +27,8 @@ func (m *MemoryStore) Put(entry *KeyEntry) error {
 	if _, exists := m.keys[entry.ID]; exists {
 		return fmt.Errorf("key %s already exists", entry.ID)
 	}
-	m.keys[entry.ID] = entry
+	e := *entry
+	m.keys[entry.ID] = &e
 	return nil
 }
 
@@ -36,7 +40,8 @@ func (m *MemoryStore) Get(id string) (*KeyEntry, error) {
 	if !ok {
 		return nil, ErrKeyNotFound
 	}
-	return entry, nil
+	e := *entry
+	return &e, nil
 }
 
 func (m *MemoryStore) List(filter KeyStatus) ([]*KeyEntry, error) {
@@ -46,7 +51,8 @@ func (m *MemoryStore) List(filter KeyStatus) ([]*KeyEntry, error) {
 	var result []*KeyEntry
 	for _, entry := range m.keys {
 		if filter == 0 || entry.Status == filter {
-			result = append(result, entry)
+			e := *entry
+			result = append(result, &e)
 		}
 	}
 	return result, nil
@@ -61,9 +67,24 @@ func (m *MemoryStore) UpdateStatus(id string, status KeyStatus) error {
 		return ErrKeyNotFound
 	}
 	entry.Status = status
+	if status == StatusRotated {
+		entry.RotatedAt = time.Now()
+	}
 	return nil
 }
 
+func (m *MemoryStore) RecordEncryption(id string) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.keys[id]
+	if !ok {
+		return 0, ErrKeyNotFound
+	}
+	entry.Encryptions++
+	return entry.Encryptions, nil
+}
+
 func (m *MemoryStore) Delete(id string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
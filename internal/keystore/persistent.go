@@ -1,31 +1,75 @@
 package keystore
 
 import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
 )
 
-// persistedKey is the JSON-serializable form of a KeyEntry.
+// persistedKey is the JSON-serializable form of a KeyEntry. Like secret
+// keys, X25519 and KEM private keys are written as they are.
+//
+// KEM keys are stored as their seed (KEMSeed), from which the whole key pair
+// is re-derived on load: the 64-byte d || z seed of FIPS 203 for ML-KEM-768
+// and ML-KEM-1024, and the 32-byte X-Wing seed for ML-KEM-768+X25519, which
+// SHAKE256 expands into an ML-KEM-768 seed and an X25519 scalar.
 type persistedKey struct {
-	ID            string            `json:"id"`
-	Algorithm     KeyAlgorithm      `json:"algorithm"`
-	Status        KeyStatus         `json:"status"`
-	PrivateKeyDER []byte            `json:"private_key_der"`
-	CreatedAt     time.Time         `json:"created_at"`
-	RotatedAt     time.Time         `json:"rotated_at,omitempty"`
-	Labels        map[string]string `json:"labels,omitempty"`
+	ID        string       `json:"id"`
+	Algorithm KeyAlgorithm `json:"algorithm"`
+	Status    KeyStatus    `json:"status"`
+	// PrivateKeyDER is only read, from files written before ECDSA keys
+	// were held by handle. Such keys, and those whose software handle
+	// carried the key, are moved into the software provider by
+	// AdoptSoftwareKeys.
+	PrivateKeyDER []byte        `json:"private_key_der,omitempty"`
+	Handle        hsm.KeyHandle `json:"handle,omitempty"`
+	Provider      string        `json:"provider,omitempty"`
+	PublicKeyDER  []byte        `json:"public_key_der,omitempty"`
+	// AgreementKeyDER is the PKCS8 encoding of an X25519 key.
+	AgreementKeyDER []byte `json:"agreement_key_der,omitempty"`
+	KEMSeed         []byte `json:"kem_seed,omitempty"`
+	// WrappingKeyDER is the PKIX encoding of an uploaded RSA public key.
+	WrappingKeyDER []byte            `json:"wrapping_key_der,omitempty"`
+	SecretKey      []byte            `json:"secret_key,omitempty"`
+	Purpose        KeyPurpose        `json:"purpose,omitempty"`
+	Mode           KeyMode           `json:"mode,omitempty"`
+	Exportable     bool              `json:"exportable,omitempty"`
+	Origin         KeyOrigin         `json:"origin,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	RotatedAt      time.Time         `json:"rotated_at,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	ParentID       string            `json:"parent_id,omitempty"`
+	// Encryptions is a reservation: at least the number of encryptions
+	// performed, up to encryptionReserve more.
+	Encryptions uint64 `json:"encryptions,omitempty"`
 }
 
+// encryptionReserve is how far ahead of the live encryption count the
+// persisted count runs. The file is rewritten once per reserve rather than
+// on every encryption, and a crash can only overstate a key's usage.
+const encryptionReserve = 4096
+
 // PersistentStore wraps MemoryStore and persists to a JSON file using atomic rename.
 type PersistentStore struct {
 	*MemoryStore
 	path string
+	// saveMu serializes saves, so each writes a snapshot no older than the
+	// one before it.
+	saveMu sync.Mutex
+	// legacy holds the ECDSA keys read from the file itself, until
+	// AdoptSoftwareKeys moves them into a provider.
+	legacy map[string]*ecdsa.PrivateKey
 }
 
 // NewPersistentStore creates a store that persists to the given file path.
@@ -34,6 +78,7 @@ func NewPersistentStore(path string) (*PersistentStore, error) {
 	ps := &PersistentStore{
 		MemoryStore: NewMemoryStore(),
 		path:        path,
+		legacy:      make(map[string]*ecdsa.PrivateKey),
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
@@ -64,6 +109,57 @@ func (ps *PersistentStore) UpdateStatus(id string, status KeyStatus) error {
 	return ps.save()
 }
 
+func (ps *PersistentStore) RecordEncryption(id string) (uint64, error) {
+	ps.mu.Lock()
+	entry, ok := ps.keys[id]
+	if !ok {
+		ps.mu.Unlock()
+		return 0, ErrKeyNotFound
+	}
+	entry.Encryptions++
+	n, persisted := entry.Encryptions, entry.Encryptions <= entry.reservedEncryptions
+	ps.mu.Unlock()
+
+	if persisted {
+		return n, nil
+	}
+	// The count is only returned once a reservation covering it is on
+	// disk, whether written here or by a save this call waits for.
+	if err := ps.saveReserving(entry, n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// AdoptSoftwareKeys imports the ECDSA keys the file held itself into the
+// software provider, and saves the store with their new handles so the
+// file no longer holds them. It fails if there are such keys and software
+// is nil.
+func (ps *PersistentStore) AdoptSoftwareKeys(software *SoftwareKeys) error {
+	if len(ps.legacy) == 0 {
+		return nil
+	}
+	if software == nil {
+		return fmt.Errorf("%d ECDSA keys are stored with their private key and need a software hsm provider", len(ps.legacy))
+	}
+	for id, key := range ps.legacy {
+		h, err := software.HSM.ImportKey(id, key)
+		if err != nil {
+			return fmt.Errorf("import key %s: %w", id, err)
+		}
+		ps.mu.Lock()
+		if entry, ok := ps.keys[id]; ok {
+			entry.Handle, entry.Provider = h, software.Provider
+		}
+		ps.mu.Unlock()
+	}
+	if err := ps.save(); err != nil {
+		return err
+	}
+	clear(ps.legacy)
+	return nil
+}
+
 func (ps *PersistentStore) Delete(id string) error {
 	if err := ps.MemoryStore.Delete(id); err != nil {
 		return err
@@ -73,24 +169,26 @@ func (ps *PersistentStore) Delete(id string) error {
 
 // save writes all keys to a temp file then atomically renames it.
 func (ps *PersistentStore) save() error {
-	ps.mu.RLock()
-	defer ps.mu.RUnlock()
+	return ps.saveReserving(nil, 0)
+}
 
-	var keys []persistedKey
-	for _, e := range ps.keys {
-		der, err := crypto.MarshalPrivateKey(e.PrivateKey)
-		if err != nil {
-			return fmt.Errorf("marshal key %s: %w", e.ID, err)
-		}
-		keys = append(keys, persistedKey{
-			ID:            e.ID,
-			Algorithm:     e.Algorithm,
-			Status:        e.Status,
-			PrivateKeyDER: der,
-			CreatedAt:     e.CreatedAt,
-			RotatedAt:     e.RotatedAt,
-			Labels:        e.Labels,
-		})
+// saveReserving saves the keys with encryptionReserve encryptions reserved
+// ahead of count n for entry, unless a reservation covering n is already
+// on disk. Once the file is written, every key's reservedEncryptions is
+// the count saved for it.
+func (ps *PersistentStore) saveReserving(entry *KeyEntry, n uint64) error {
+	ps.saveMu.Lock()
+	defer ps.saveMu.Unlock()
+
+	ps.mu.RLock()
+	covered := entry != nil && n <= entry.reservedEncryptions
+	ps.mu.RUnlock()
+	if covered {
+		return nil
+	}
+	keys, saved, err := ps.snapshot(entry, n)
+	if err != nil {
+		return err
 	}
 
 	data, err := json.MarshalIndent(keys, "", "  ")
@@ -107,9 +205,80 @@ func (ps *PersistentStore) save() error {
 		return fmt.Errorf("atomic rename: %w", err)
 	}
 
+	ps.mu.Lock()
+	for e, count := range saved {
+		e.reservedEncryptions = count
+	}
+	ps.mu.Unlock()
 	return nil
 }
 
+// snapshot converts the keys to their persisted form, and reports the
+// encryption count saved for each.
+func (ps *PersistentStore) snapshot(entry *KeyEntry, n uint64) ([]persistedKey, map[*KeyEntry]uint64, error) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	var keys []persistedKey
+	saved := make(map[*KeyEntry]uint64, len(ps.keys))
+	for _, e := range ps.keys {
+		var pubDER []byte
+		if e.PublicKey != nil {
+			var err error
+			pubDER, err = crypto.MarshalPublicKey(e.PublicKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("marshal key %s: %w", e.ID, err)
+			}
+		}
+		var agreementDER []byte
+		if e.AgreementKey != nil {
+			var err error
+			agreementDER, err = crypto.MarshalAgreementKey(e.AgreementKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("marshal key %s: %w", e.ID, err)
+			}
+		}
+		var kemSeed []byte
+		if e.KEMKey != nil {
+			kemSeed = e.KEMKey.Seed()
+		}
+		var wrappingDER []byte
+		if e.WrappingKey != nil {
+			var err error
+			wrappingDER, err = crypto.MarshalPublicKey(e.WrappingKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("marshal key %s: %w", e.ID, err)
+			}
+		}
+		saved[e] = max(e.Encryptions, e.reservedEncryptions)
+		if e == entry {
+			saved[e] = max(saved[e], n+encryptionReserve-1)
+		}
+		keys = append(keys, persistedKey{
+			ID:              e.ID,
+			Algorithm:       e.Algorithm,
+			Status:          e.Status,
+			Handle:          e.Handle,
+			Provider:        e.Provider,
+			PublicKeyDER:    pubDER,
+			AgreementKeyDER: agreementDER,
+			KEMSeed:         kemSeed,
+			WrappingKeyDER:  wrappingDER,
+			SecretKey:       e.SecretKey,
+			Purpose:         e.Purpose,
+			Mode:            e.Mode,
+			Exportable:      e.Exportable,
+			Origin:          e.Origin,
+			CreatedAt:       e.CreatedAt,
+			RotatedAt:       e.RotatedAt,
+			Labels:          e.Labels,
+			ParentID:        e.ParentID,
+			Encryptions:     saved[e],
+		})
+	}
+	return keys, saved, nil
+}
+
 // load reads keys from the persisted file.
 func (ps *PersistentStore) load() error {
 	data, err := os.ReadFile(ps.path)
@@ -123,20 +292,108 @@ func (ps *PersistentStore) load() error {
 	}
 
 	for _, pk := range keys {
-		privKey, err := crypto.UnmarshalPrivateKey(pk.PrivateKeyDER)
+		handle, pub, legacy, err := loadHandle(pk)
 		if err != nil {
 			return fmt.Errorf("unmarshal key %s: %w", pk.ID, err)
 		}
+		// Keys saved before providers were named belong to the provider
+		// named for the type that issued their handle.
+		provider := pk.Provider
+		if provider == "" {
+			provider = handle.Provider()
+		}
+		if legacy != nil {
+			ps.legacy[pk.ID] = legacy
+			handle, provider = "", ""
+		}
+		var agreementKey *ecdh.PrivateKey
+		if len(pk.AgreementKeyDER) > 0 {
+			var err error
+			agreementKey, err = crypto.UnmarshalAgreementKey(pk.AgreementKeyDER)
+			if err != nil {
+				return fmt.Errorf("unmarshal key %s: %w", pk.ID, err)
+			}
+		}
+		var kemKey *crypto.KEMPrivateKey
+		if len(pk.KEMSeed) > 0 {
+			var err error
+			kemKey, err = crypto.NewKEMPrivateKey(pk.Algorithm.KEM(), pk.KEMSeed)
+			if err != nil {
+				return fmt.Errorf("unmarshal key %s: %w", pk.ID, err)
+			}
+		}
+		var wrappingKey *rsa.PublicKey
+		if len(pk.WrappingKeyDER) > 0 {
+			var err error
+			wrappingKey, err = parseWrappingKey(pk.WrappingKeyDER)
+			if err != nil {
+				return fmt.Errorf("unmarshal key %s: %w", pk.ID, err)
+			}
+		}
 		ps.keys[pk.ID] = &KeyEntry{
-			ID:         pk.ID,
-			Algorithm:  pk.Algorithm,
-			Status:     pk.Status,
-			PrivateKey: privKey,
-			CreatedAt:  pk.CreatedAt,
-			RotatedAt:  pk.RotatedAt,
-			Labels:     pk.Labels,
+			ID:           pk.ID,
+			Algorithm:    pk.Algorithm,
+			Status:       pk.Status,
+			Handle:       handle,
+			Provider:     provider,
+			PublicKey:    pub,
+			AgreementKey: agreementKey,
+			KEMKey:       kemKey,
+			WrappingKey:  wrappingKey,
+			SecretKey:    pk.SecretKey,
+			Purpose:      pk.Purpose,
+			Mode:         pk.Mode,
+			Exportable:   pk.Exportable,
+			Origin:       pk.Origin,
+			CreatedAt:    pk.CreatedAt,
+			RotatedAt:    pk.RotatedAt,
+			Labels:       pk.Labels,
+			ParentID:     pk.ParentID,
+
+			Encryptions:         pk.Encryptions,
+			reservedEncryptions: pk.Encryptions,
 		}
 	}
 
 	return nil
 }
+
+// loadHandle returns the provider handle and public key of a persisted
+// ECDSA key, or the private key itself if the file holds it.
+func loadHandle(pk persistedKey) (hsm.KeyHandle, *ecdsa.PublicKey, *ecdsa.PrivateKey, error) {
+	if len(pk.PrivateKeyDER) > 0 {
+		key, err := crypto.UnmarshalPrivateKey(pk.PrivateKeyDER)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return "", &key.PublicKey, key, nil
+	}
+	if pk.Handle == "" {
+		return "", nil, nil, nil
+	}
+	if key, ok := hsm.LegacySoftwareKey(pk.Handle); ok {
+		return "", &key.PublicKey, key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(pk.PublicKeyDER)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("parse public key: %w", err)
+	}
+	pub, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("public key is not ECDSA")
+	}
+	return pk.Handle, pub, nil, nil
+}
+
+// parseWrappingKey parses the PKIX encoding of an RSA public key.
+func parseWrappingKey(der []byte) (*rsa.PublicKey, error) {
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	pub, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not RSA")
+	}
+	return pub, nil
+}
@@ -1,28 +1,43 @@
 package keystore
 
 import (
+	"bytes"
+	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
 )
 
+// testHSM holds the keys of persistent test entries, so they can still
+// sign after the store is reloaded.
+var testHSM = hsm.NewSoftwareHSM()
+
 func makePersistentEntry(t *testing.T, id string) *KeyEntry {
 	t.Helper()
-	key, err := crypto.GenerateECDSAKey(elliptic.P256())
+	handle, pub, err := testHSM.GenerateKey(id, elliptic.P256())
 	if err != nil {
 		t.Fatalf("generate key: %v", err)
 	}
 	return &KeyEntry{
-		ID:         id,
-		Algorithm:  AlgorithmECDSAP256,
-		Status:     StatusActive,
-		PrivateKey: key,
-		CreatedAt:  time.Now(),
-		Labels:     map[string]string{"env": "test"},
+		ID:        id,
+		Algorithm: AlgorithmECDSAP256,
+		Status:    StatusActive,
+		Handle:    handle,
+		Provider:  "dev",
+		PublicKey: pub,
+		CreatedAt: time.Now(),
+		Labels:    map[string]string{"env": "test"},
 	}
 }
 
@@ -62,14 +77,17 @@ func TestPersistentStorePutAndReload(t *testing.T) {
 	if got.Algorithm != AlgorithmECDSAP256 {
 		t.Fatalf("algorithm mismatch: %v", got.Algorithm)
 	}
+	if got.Provider != "dev" {
+		t.Fatalf("provider mismatch: %q", got.Provider)
+	}
 
 	// Verify the reloaded key can sign
 	data := []byte("test signing after reload")
-	sig, err := crypto.SignECDSA(got.PrivateKey, data)
+	sig, err := testHSM.Sign(got.Handle, data)
 	if err != nil {
 		t.Fatalf("sign: %v", err)
 	}
-	if !crypto.VerifyECDSA(&entry.PrivateKey.PublicKey, data, sig) {
+	if !crypto.VerifyECDSA(entry.PublicKey, data, sig) {
 		t.Fatal("signature from reloaded key should verify against original")
 	}
 }
@@ -109,6 +127,83 @@ func TestPersistentStoreDeletePersists(t *testing.T) {
 	}
 }
 
+func TestPersistentStoreEncryptionCount(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	store, _ := NewPersistentStore(path)
+	store.Put(makePersistentEntry(t, "key-1"))
+	for range 10 {
+		if _, err := store.RecordEncryption("key-1"); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	// A crash loses the live count; the reload must not understate it.
+	store2, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	got, _ := store2.Get("key-1")
+	if got.Encryptions < 10 || got.Encryptions > 10+encryptionReserve {
+		t.Fatalf("reloaded count = %d, want 10..%d", got.Encryptions, 10+encryptionReserve)
+	}
+
+	// Counting past the reservation writes a new one.
+	for range encryptionReserve {
+		store2.RecordEncryption("key-1")
+	}
+	store3, _ := NewPersistentStore(path)
+	got3, _ := store3.Get("key-1")
+	if live, _ := store2.Get("key-1"); got3.Encryptions < live.Encryptions {
+		t.Fatalf("reloaded count %d is below live count %d", got3.Encryptions, live.Encryptions)
+	}
+}
+
+func TestPersistentStoreEncryptionCountConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, _ := NewPersistentStore(path)
+	store.Put(makePersistentEntry(t, "key-1"))
+
+	// Counts returned just past a reservation must already be on disk,
+	// whichever caller wrote the new reservation.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for range 8 {
+		wg.Go(func() {
+			for range encryptionReserve / 2 {
+				n, err := store.RecordEncryption("key-1")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if n%encryptionReserve > 64 {
+					continue
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					errs <- err
+					return
+				}
+				var keys []persistedKey
+				if err := json.Unmarshal(data, &keys); err != nil {
+					errs <- err
+					return
+				}
+				if keys[0].Encryptions < n {
+					errs <- fmt.Errorf("count %d returned with %d on disk", n, keys[0].Encryptions)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
 func TestPersistentStoreAtomicWrite(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "keys.json")
@@ -138,3 +233,241 @@ func TestPersistentStoreEmptyReload(t *testing.T) {
 		t.Fatal("new store should be empty")
 	}
 }
+
+func TestPersistentStoreSymmetricKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	secret, err := crypto.GenerateTDEAKey(16)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	store, _ := NewPersistentStore(path)
+	if err := store.Put(&KeyEntry{
+		ID:        "mac-1",
+		Algorithm: AlgorithmTDEA2Key,
+		Status:    StatusActive,
+		SecretKey: secret,
+		CreatedAt: time.Now(),
+		ParentID:  "root-1",
+	}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	store2, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	got, err := store2.Get("mac-1")
+	if err != nil {
+		t.Fatalf("get after reload: %v", err)
+	}
+	if got.Handle != "" || got.PublicKey != nil {
+		t.Fatal("symmetric key should not have a key handle")
+	}
+	if !bytes.Equal(got.SecretKey, secret) {
+		t.Fatal("secret key mismatch after reload")
+	}
+	if got.ParentID != "root-1" {
+		t.Fatalf("parent id: got %q, want root-1", got.ParentID)
+	}
+}
+
+func TestPersistentStoreAgreementKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	key, err := crypto.GenerateX25519Key()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	store, _ := NewPersistentStore(path)
+	if err := store.Put(&KeyEntry{
+		ID:           "ecdh-1",
+		Algorithm:    AlgorithmX25519,
+		Status:       StatusActive,
+		AgreementKey: key,
+		Purpose:      PurposeKeyAgreement,
+		Origin:       OriginImported,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	store2, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	got, err := store2.Get("ecdh-1")
+	if err != nil {
+		t.Fatalf("get after reload: %v", err)
+	}
+	if got.AgreementKey == nil || !got.AgreementKey.Equal(key) {
+		t.Fatal("agreement key mismatch after reload")
+	}
+	if got.Purpose != PurposeKeyAgreement {
+		t.Fatalf("purpose: got %v", got.Purpose)
+	}
+	if got.Origin != OriginImported {
+		t.Fatalf("origin: got %v", got.Origin)
+	}
+}
+
+func TestPersistentStoreKEMKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	key, err := crypto.GenerateKEMKey(crypto.KEMXWing)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	store, _ := NewPersistentStore(path)
+	if err := store.Put(&KeyEntry{
+		ID:        "kem-1",
+		Algorithm: AlgorithmMLKEM768X25519,
+		Status:    StatusActive,
+		KEMKey:    key,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	store2, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	got, err := store2.Get("kem-1")
+	if err != nil {
+		t.Fatalf("get after reload: %v", err)
+	}
+	if got.KEMKey == nil || !bytes.Equal(got.KEMKey.EncapsulationKey(), key.EncapsulationKey()) {
+		t.Fatal("kem key mismatch after reload")
+	}
+}
+
+func TestPersistentStoreWrappingKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.json")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	store, _ := NewPersistentStore(path)
+	if err := store.Put(&KeyEntry{
+		ID:          "wrap-1",
+		Algorithm:   AlgorithmRSA2048,
+		Status:      StatusActive,
+		WrappingKey: &key.PublicKey,
+		Purpose:     PurposeKeyEncryption,
+		Mode:        ModeEncryptOnly,
+		Origin:      OriginImported,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	store2, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	got, err := store2.Get("wrap-1")
+	if err != nil {
+		t.Fatalf("get after reload: %v", err)
+	}
+	if got.WrappingKey == nil || !got.WrappingKey.Equal(&key.PublicKey) {
+		t.Fatal("wrapping key mismatch after reload")
+	}
+	if got.Mode != ModeEncryptOnly {
+		t.Fatalf("mode: got %v", got.Mode)
+	}
+}
+
+func TestPersistentStoreAdoptsLegacyPrivateKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	var keys []*ecdsa.PrivateKey
+	var file []persistedKey
+	for i := range 2 {
+		key, err := crypto.GenerateECDSAKey(elliptic.P256())
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		der, err := crypto.MarshalPrivateKey(key)
+		if err != nil {
+			t.Fatalf("marshal key: %v", err)
+		}
+		pk := persistedKey{
+			ID:        fmt.Sprintf("legacy-%d", i),
+			Algorithm: AlgorithmECDSAP256,
+			Status:    StatusActive,
+			CreatedAt: time.Now(),
+		}
+		// The first key is stored as such, the second by a handle that
+		// carries it.
+		if i == 0 {
+			pk.PrivateKeyDER = der
+		} else {
+			pk.Handle = hsm.NewKeyHandle("software", base64.RawURLEncoding.EncodeToString(der))
+		}
+		keys, file = append(keys, key), append(file, pk)
+	}
+	legacy, err := json.Marshal(file)
+	if err != nil {
+		t.Fatalf("marshal file: %v", err)
+	}
+	if err := os.WriteFile(path, legacy, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	store, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("load store: %v", err)
+	}
+	if err := store.AdoptSoftwareKeys(nil); err == nil {
+		t.Fatal("adopting keys without a software provider should fail")
+	}
+	software := &SoftwareKeys{Provider: "dev", HSM: hsm.NewSoftwareHSM()}
+	if err := store.AdoptSoftwareKeys(software); err != nil {
+		t.Fatalf("adopt keys: %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	for i, key := range keys {
+		der, _ := crypto.MarshalPrivateKey(key)
+		if bytes.Contains(saved, []byte(base64.StdEncoding.EncodeToString(der))) ||
+			bytes.Contains(saved, []byte(base64.RawURLEncoding.EncodeToString(der))) {
+			t.Fatalf("legacy-%d: file still holds the private key", i)
+		}
+	}
+
+	reloaded, err := NewPersistentStore(path)
+	if err != nil {
+		t.Fatalf("reload store: %v", err)
+	}
+	for i, key := range keys {
+		got, err := reloaded.Get(fmt.Sprintf("legacy-%d", i))
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if got.Provider != "dev" || !got.PublicKey.Equal(&key.PublicKey) {
+			t.Fatalf("legacy-%d not adopted: provider %q", i, got.Provider)
+		}
+		sig, err := software.HSM.Sign(got.Handle, []byte("legacy"))
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if !crypto.VerifyECDSA(&key.PublicKey, []byte("legacy"), sig) {
+			t.Fatal("signature from adopted key should verify")
+		}
+	}
+	if err := reloaded.AdoptSoftwareKeys(nil); err != nil {
+		t.Fatalf("nothing left to adopt: %v", err)
+	}
+}
@@ -1,9 +1,15 @@
 package keystore
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/rsa"
 	"errors"
+	"slices"
 	"time"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
 )
 
 var (
@@ -17,6 +23,35 @@ type KeyAlgorithm int
 const (
 	AlgorithmECDSAP256 KeyAlgorithm = iota + 1
 	AlgorithmECDSAP384
+	AlgorithmTDEA2Key
+	AlgorithmTDEA3Key
+	AlgorithmAES128
+	AlgorithmAES256
+	AlgorithmHMACSHA256
+	AlgorithmHMACSHA512
+	// AlgorithmFPEAES256 is an AES-256 key reserved for FF1/FF3-1
+	// format-preserving encryption.
+	AlgorithmFPEAES256
+	// AlgorithmX25519 is an X25519 key agreement key pair.
+	AlgorithmX25519
+	// AlgorithmMLKEM768 and AlgorithmMLKEM1024 are ML-KEM (FIPS 203) key
+	// encapsulation key pairs.
+	AlgorithmMLKEM768
+	AlgorithmMLKEM1024
+	// AlgorithmMLKEM768X25519 is the X-Wing hybrid of ML-KEM-768 and X25519.
+	AlgorithmMLKEM768X25519
+	// AlgorithmChaCha20Poly1305 and AlgorithmXChaCha20Poly1305 are 256-bit
+	// data encryption keys for hosts without AES acceleration.
+	AlgorithmChaCha20Poly1305
+	AlgorithmXChaCha20Poly1305
+	// AlgorithmAESSIV is a 512-bit AES-SIV key for deterministic encryption.
+	AlgorithmAESSIV
+	// AlgorithmRSA2048, AlgorithmRSA3072 and AlgorithmRSA4096 are trusted
+	// RSA public keys uploaded to wrap exported keys for. Their private
+	// halves stay with their owner; the vault cannot generate them.
+	AlgorithmRSA2048
+	AlgorithmRSA3072
+	AlgorithmRSA4096
 )
 
 func (a KeyAlgorithm) String() string {
@@ -25,11 +60,141 @@ func (a KeyAlgorithm) String() string {
 		return "ECDSA_P256"
 	case AlgorithmECDSAP384:
 		return "ECDSA_P384"
+	case AlgorithmTDEA2Key:
+		return "TDEA_2KEY"
+	case AlgorithmTDEA3Key:
+		return "TDEA_3KEY"
+	case AlgorithmAES128:
+		return "AES_128"
+	case AlgorithmAES256:
+		return "AES_256"
+	case AlgorithmHMACSHA256:
+		return "HMAC_SHA256"
+	case AlgorithmHMACSHA512:
+		return "HMAC_SHA512"
+	case AlgorithmFPEAES256:
+		return "FPE_AES_256"
+	case AlgorithmX25519:
+		return "X25519"
+	case AlgorithmMLKEM768:
+		return "ML_KEM_768"
+	case AlgorithmMLKEM1024:
+		return "ML_KEM_1024"
+	case AlgorithmMLKEM768X25519:
+		return "ML_KEM_768_X25519"
+	case AlgorithmChaCha20Poly1305:
+		return "CHACHA20_POLY1305"
+	case AlgorithmXChaCha20Poly1305:
+		return "XCHACHA20_POLY1305"
+	case AlgorithmAESSIV:
+		return "AES_SIV"
+	case AlgorithmRSA2048:
+		return "RSA_2048"
+	case AlgorithmRSA3072:
+		return "RSA_3072"
+	case AlgorithmRSA4096:
+		return "RSA_4096"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// Strength returns the security strength of the algorithm in bits,
+// following NIST SP 800-57 Part 1.
+func (a KeyAlgorithm) Strength() int {
+	switch a {
+	case AlgorithmTDEA2Key:
+		return 80
+	case AlgorithmTDEA3Key, AlgorithmRSA2048:
+		return 112
+	case AlgorithmECDSAP256, AlgorithmAES128, AlgorithmX25519, AlgorithmRSA3072, AlgorithmRSA4096:
+		return 128
+	case AlgorithmECDSAP384, AlgorithmMLKEM768, AlgorithmMLKEM768X25519:
+		return 192
+	case AlgorithmAES256, AlgorithmHMACSHA256, AlgorithmHMACSHA512, AlgorithmFPEAES256, AlgorithmMLKEM1024,
+		AlgorithmChaCha20Poly1305, AlgorithmXChaCha20Poly1305, AlgorithmAESSIV:
+		return 256
+	default:
+		return 0
+	}
+}
+
+// IsSymmetric reports whether keys of this algorithm are secret keys
+// held in KeyEntry.SecretKey rather than key pairs.
+func (a KeyAlgorithm) IsSymmetric() bool {
+	switch a {
+	case AlgorithmTDEA2Key, AlgorithmTDEA3Key, AlgorithmAES128, AlgorithmAES256,
+		AlgorithmHMACSHA256, AlgorithmHMACSHA512, AlgorithmFPEAES256,
+		AlgorithmChaCha20Poly1305, AlgorithmXChaCha20Poly1305, AlgorithmAESSIV:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsECDSA reports whether keys of this algorithm are ECDSA key pairs held
+// by the HSM provider behind KeyEntry.Handle. ECDSA keys can sign and also
+// agree keys.
+func (a KeyAlgorithm) IsECDSA() bool {
+	return a == AlgorithmECDSAP256 || a == AlgorithmECDSAP384
+}
+
+// IsRSA reports whether keys of this algorithm are uploaded RSA public keys
+// held in KeyEntry.WrappingKey.
+func (a KeyAlgorithm) IsRSA() bool {
+	switch a {
+	case AlgorithmRSA2048, AlgorithmRSA3072, AlgorithmRSA4096:
+		return true
+	default:
+		return false
+	}
+}
+
+// RSAAlgorithm returns the algorithm of RSA public keys of the given size
+// in bits, or false for sizes the vault does not accept.
+func RSAAlgorithm(bits int) (KeyAlgorithm, bool) {
+	switch bits {
+	case 2048:
+		return AlgorithmRSA2048, true
+	case 3072:
+		return AlgorithmRSA3072, true
+	case 4096:
+		return AlgorithmRSA4096, true
+	default:
+		return 0, false
+	}
+}
+
+// KEM returns the key encapsulation mechanism of a KEM key pair, or 0 for
+// other algorithms.
+func (a KeyAlgorithm) KEM() crypto.KEMAlgorithm {
+	switch a {
+	case AlgorithmMLKEM768:
+		return crypto.KEMMLKEM768
+	case AlgorithmMLKEM1024:
+		return crypto.KEMMLKEM1024
+	case AlgorithmMLKEM768X25519:
+		return crypto.KEMXWing
+	default:
+		return 0
+	}
+}
+
+// AEAD returns the cipher the Encrypt and Decrypt operations use with a
+// symmetric key, or 0 for keys that do not encrypt data directly.
+func (a KeyAlgorithm) AEAD() crypto.AEADAlgorithm {
+	switch a {
+	case AlgorithmAES128, AlgorithmAES256:
+		return crypto.AEADAESGCM
+	case AlgorithmChaCha20Poly1305:
+		return crypto.AEADChaCha20Poly1305
+	case AlgorithmXChaCha20Poly1305:
+		return crypto.AEADXChaCha20Poly1305
+	default:
+		return 0
+	}
+}
+
 // KeyStatus represents the lifecycle state of a key.
 type KeyStatus int
 
@@ -52,15 +217,220 @@ func (s KeyStatus) String() string {
 	}
 }
 
+// KeyPurpose restricts the family of operations a key may be used for.
+// The zero value places no restriction, which is how keys created before
+// purposes existed behave.
+type KeyPurpose int
+
+const (
+	PurposeAny KeyPurpose = iota
+	PurposeSigning
+	PurposeDataEncryption
+	PurposeMAC
+	PurposePINEncryption
+	PurposeKeyEncryption
+	PurposeKeyBlockProtection
+	PurposeCardVerification
+	PurposePINVerification
+	PurposeBaseDerivation
+	PurposeKeyAgreement
+	// PurposeDeterministicEncryption keeps an AES-SIV key away from the
+	// randomized encryption operations.
+	PurposeDeterministicEncryption
+	// PurposeTokenFingerprint marks the key the tokenization service
+	// fingerprints a domain's PANs with. It permits no operation, so no
+	// RPC can compute fingerprints with it.
+	PurposeTokenFingerprint
+)
+
+func (p KeyPurpose) String() string {
+	switch p {
+	case PurposeAny:
+		return "ANY"
+	case PurposeSigning:
+		return "SIGNING"
+	case PurposeDataEncryption:
+		return "DATA_ENCRYPTION"
+	case PurposeMAC:
+		return "MAC"
+	case PurposePINEncryption:
+		return "PIN_ENCRYPTION"
+	case PurposeKeyEncryption:
+		return "KEY_ENCRYPTION"
+	case PurposeKeyBlockProtection:
+		return "KEY_BLOCK_PROTECTION"
+	case PurposeCardVerification:
+		return "CARD_VERIFICATION"
+	case PurposePINVerification:
+		return "PIN_VERIFICATION"
+	case PurposeBaseDerivation:
+		return "BASE_DERIVATION"
+	case PurposeKeyAgreement:
+		return "KEY_AGREEMENT"
+	case PurposeDeterministicEncryption:
+		return "DETERMINISTIC_ENCRYPTION"
+	case PurposeTokenFingerprint:
+		return "TOKEN_FINGERPRINT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// KeyMode further restricts a key to one direction of its purpose,
+// mirroring the TR-31 mode of use field. The zero value allows both.
+type KeyMode int
+
+const (
+	ModeAny KeyMode = iota
+	ModeEncryptOnly
+	ModeDecryptOnly
+	ModeGenerateOnly
+	ModeVerifyOnly
+	ModeDeriveOnly
+)
+
+func (m KeyMode) String() string {
+	switch m {
+	case ModeAny:
+		return "ANY"
+	case ModeEncryptOnly:
+		return "ENCRYPT_ONLY"
+	case ModeDecryptOnly:
+		return "DECRYPT_ONLY"
+	case ModeGenerateOnly:
+		return "GENERATE_ONLY"
+	case ModeVerifyOnly:
+		return "VERIFY_ONLY"
+	case ModeDeriveOnly:
+		return "DERIVE_ONLY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// KeyOrigin records where a key's material came from. The zero value is a
+// key generated in the vault, which is how keys stored before origins
+// existed are read.
+type KeyOrigin int
+
+const (
+	OriginGenerated KeyOrigin = iota
+	// OriginImported is key material brought in from outside the vault:
+	// unwrapped from a key block or import transport, or combined from
+	// components.
+	OriginImported
+)
+
+func (o KeyOrigin) String() string {
+	switch o {
+	case OriginGenerated:
+		return "GENERATED"
+	case OriginImported:
+		return "IMPORTED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// KeyOperation is a single use of a key, checked against its purpose and mode.
+type KeyOperation int
+
+const (
+	OpSign KeyOperation = iota + 1
+	OpVerify
+	OpEncrypt
+	OpDecrypt
+	OpGenerateMAC
+	OpVerifyMAC
+	OpWrap
+	OpUnwrap
+	OpDerive
+	OpAgree
+	OpEncryptDeterministic
+	OpDecryptDeterministic
+)
+
+// purposeOps lists the operations each restricted purpose allows.
+var purposeOps = map[KeyPurpose][]KeyOperation{
+	PurposeSigning:                 {OpSign, OpVerify},
+	PurposeDataEncryption:          {OpEncrypt, OpDecrypt},
+	PurposeMAC:                     {OpGenerateMAC, OpVerifyMAC},
+	PurposePINEncryption:           {OpEncrypt, OpDecrypt},
+	PurposeKeyEncryption:           {OpWrap, OpUnwrap},
+	PurposeKeyBlockProtection:      {OpWrap, OpUnwrap},
+	PurposeCardVerification:        {OpGenerateMAC, OpVerifyMAC},
+	PurposePINVerification:         {OpGenerateMAC, OpVerifyMAC},
+	PurposeBaseDerivation:          {OpDerive},
+	PurposeKeyAgreement:            {OpAgree},
+	PurposeDeterministicEncryption: {OpEncryptDeterministic, OpDecryptDeterministic},
+}
+
+// modeOps lists the operations each restricted mode allows.
+var modeOps = map[KeyMode][]KeyOperation{
+	ModeEncryptOnly:  {OpEncrypt, OpWrap, OpEncryptDeterministic},
+	ModeDecryptOnly:  {OpDecrypt, OpUnwrap, OpDecryptDeterministic},
+	ModeGenerateOnly: {OpSign, OpGenerateMAC},
+	ModeVerifyOnly:   {OpVerify, OpVerifyMAC},
+	ModeDeriveOnly:   {OpDerive, OpAgree},
+}
+
 // KeyEntry holds a key and its metadata.
+// ECDSA keys populate Handle and PublicKey, X25519 keys populate
+// AgreementKey, KEM keys populate KEMKey, symmetric keys populate
+// SecretKey and RSA keys populate WrappingKey. Only ECDSA keys are held by
+// a provider. The X25519 and KEM private keys are held here, and a
+// PersistentStore writes them to its file unencrypted, as it does secret
+// keys.
 type KeyEntry struct {
-	ID         string
-	Algorithm  KeyAlgorithm
-	Status     KeyStatus
-	PrivateKey *ecdsa.PrivateKey
+	ID        string
+	Algorithm KeyAlgorithm
+	Status    KeyStatus
+	// Handle refers to the private key inside the HSM provider; the
+	// private key itself is never held here.
+	Handle hsm.KeyHandle
+	// Provider is the name of the registered HSM provider holding Handle.
+	Provider     string
+	PublicKey    *ecdsa.PublicKey
+	AgreementKey *ecdh.PrivateKey
+	KEMKey       *crypto.KEMPrivateKey
+	SecretKey    []byte
+	// WrappingKey is an uploaded public key that ExportKey can wrap keys
+	// for.
+	WrappingKey *rsa.PublicKey
+	Purpose     KeyPurpose
+	Mode        KeyMode
+	// Exportable allows the key material to leave the vault wrapped under
+	// another key or as split-knowledge components. It never permits
+	// plaintext export.
+	Exportable bool
+	Origin     KeyOrigin
 	CreatedAt  time.Time
 	RotatedAt  time.Time
 	Labels     map[string]string
+	// ParentID is the key this key was derived from with DeriveKey, or
+	// empty for keys generated, imported or agreed.
+	ParentID string
+	// DerivationPath is the path below ParentID a DerivedStore key was
+	// derived at. Such keys are never persisted.
+	DerivationPath string
+	// Encryptions counts the messages encrypted under the key with random
+	// nonces, maintained by Store.RecordEncryption. After a restart a
+	// persistent store may overstate it, but never understates it.
+	Encryptions uint64
+
+	// reservedEncryptions is the count PersistentStore last wrote to disk.
+	reservedEncryptions uint64
+}
+
+// Permits reports whether the key's purpose and mode allow op.
+func (e *KeyEntry) Permits(op KeyOperation) bool {
+	if e.Purpose != PurposeAny && !slices.Contains(purposeOps[e.Purpose], op) {
+		return false
+	}
+	if e.Mode != ModeAny && !slices.Contains(modeOps[e.Mode], op) {
+		return false
+	}
+	return true
 }
 
 // Store defines the key storage interface.
@@ -70,4 +440,16 @@ type Store interface {
 	List(filter KeyStatus) ([]*KeyEntry, error)
 	UpdateStatus(id string, status KeyStatus) error
 	Delete(id string) error
+	// RecordEncryption adds one to the key's encryption count and returns
+	// the new count.
+	RecordEncryption(id string) (uint64, error)
+}
+
+// SoftwareKeys is a registered software HSM provider. It holds the ECDSA
+// keys the key store itself comes by: keys derived from a root, and keys
+// read from a store written when they were kept in it.
+type SoftwareKeys struct {
+	// Provider is the name the HSM is registered under.
+	Provider string
+	HSM      *hsm.SoftwareHSM
 }
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdh"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// defaultSharedSecretLength is the HKDF output length for the DERIVED
+// output when kdf_params.length is unset.
+const defaultSharedSecretLength = 32
+
+// secretOutput describes how an agreed or encapsulated shared secret is
+// returned to the caller.
+type secretOutput struct {
+	output    pb.SharedSecretOutput
+	kdf       *pb.KdfParams
+	algorithm pb.KeyAlgorithm
+	purpose   pb.KeyPurpose
+	labels    map[string]string
+}
+
+func (s *KeyManagementServer) DeriveSharedSecret(ctx context.Context, req *pb.DeriveSharedSecretRequest) (*pb.DeriveSharedSecretResponse, error) {
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if entry.Status != keystore.StatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "key is not active")
+	}
+	if err := checkPermits(entry, keystore.OpAgree); err != nil {
+		return nil, err
+	}
+	key, err := s.agreementKey(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := crypto.ECDH(key, req.PeerPublicKeyDer)
+	if err != nil {
+		s.audit.Log("DeriveSharedSecret", req.KeyId, "ERROR", "", nil)
+		return nil, hsmError(codes.InvalidArgument, "agree", err)
+	}
+	defer clear(secret)
+
+	out, derived, meta, err := s.returnSecret(secret, secretOutput{
+		output:    req.Output,
+		kdf:       req.KdfParams,
+		algorithm: req.DerivedKeyAlgorithm,
+		purpose:   req.DerivedKeyPurpose,
+		labels:    req.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Log("DeriveSharedSecret", req.KeyId, "OK", "", meta)
+	return &pb.DeriveSharedSecretResponse{SharedSecret: out, DerivedKey: derived}, nil
+}
+
+// returnSecret applies the requested output to a shared secret: the raw
+// secret, an HKDF output, or a new vault key derived with HKDF. It returns
+// the audit metadata describing the output.
+func (s *KeyManagementServer) returnSecret(secret []byte, o secretOutput) ([]byte, *pb.KeyMetadata, map[string]string, error) {
+	info := o.kdf.GetInfo()
+	switch o.output {
+	case pb.SharedSecretOutput_SHARED_SECRET_OUTPUT_RAW:
+		return append([]byte(nil), secret...), nil, map[string]string{"output": "RAW"}, nil
+
+	case pb.SharedSecretOutput_SHARED_SECRET_OUTPUT_DERIVED:
+		length := int(o.kdf.GetLength())
+		if length == 0 {
+			length = defaultSharedSecretLength
+		}
+		if length < 0 || length > 64 {
+			return nil, nil, nil, status.Error(codes.InvalidArgument, "length must be 1-64 bytes")
+		}
+		derived, err := crypto.DeriveKey(secret, info, length)
+		if err != nil {
+			return nil, nil, nil, status.Errorf(codes.Internal, "derive key: %v", err)
+		}
+		return derived, nil, map[string]string{"output": "DERIVED"}, nil
+
+	case pb.SharedSecretOutput_SHARED_SECRET_OUTPUT_KEY, pb.SharedSecretOutput_SHARED_SECRET_OUTPUT_UNSPECIFIED:
+		entry, err := s.storeDerivedKey(o, "", func(size int) ([]byte, error) {
+			return crypto.DeriveKey(secret, info, size)
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		meta := entryToProto(entry)
+		s.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_CREATED, meta)
+		return nil, meta, map[string]string{"output": "KEY", "derived_key_id": entry.ID}, nil
+
+	default:
+		return nil, nil, nil, status.Errorf(codes.InvalidArgument, "unsupported output: %v", o.output)
+	}
+}
+
+// storeDerivedKey stores a new vault key of the requested symmetric
+// algorithm, taking its material from derive. parentID records the key it
+// was derived from, if any.
+func (s *KeyManagementServer) storeDerivedKey(o secretOutput, parentID string, derive func(size int) ([]byte, error)) (*keystore.KeyEntry, error) {
+	algo := keystore.AlgorithmAES256
+	if o.algorithm != pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED {
+		var err error
+		if algo, err = algoFromProto(o.algorithm); err != nil {
+			return nil, err
+		}
+	}
+	size := secretSize(algo)
+	if size == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "derived key algorithm must be symmetric, got %v", algo)
+	}
+	purpose := purposeFromProto(o.purpose)
+	if err := checkPurpose(algo, purpose); err != nil {
+		return nil, err
+	}
+
+	key, err := derive(size)
+	if err != nil {
+		return nil, hsmError(codes.Internal, "derive key", err)
+	}
+	if isTDEA(algo) {
+		crypto.AdjustDESParity(key)
+	}
+
+	entry := &keystore.KeyEntry{
+		ID:        uuid.NewString(),
+		Algorithm: algo,
+		Status:    keystore.StatusActive,
+		SecretKey: key,
+		Purpose:   purpose,
+		CreatedAt: time.Now(),
+		Labels:    o.labels,
+		ParentID:  parentID,
+	}
+	if err := s.store.Put(entry); err != nil {
+		return nil, status.Errorf(codes.Internal, "store key: %v", err)
+	}
+	return entry, nil
+}
+
+// agreementKey returns the ECDH private key of an ECDSA or X25519 entry.
+// ECDSA keys stay in the HSM provider, which computes the agreement.
+func (s *KeyManagementServer) agreementKey(entry *keystore.KeyEntry) (ecdh.KeyExchanger, error) {
+	switch {
+	case entry.AgreementKey != nil:
+		return entry.AgreementKey, nil
+	case entry.Algorithm.IsECDSA():
+		pub, err := entry.PublicKey.ECDH()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "convert key: %v", err)
+		}
+		p, err := keyProvider(s.providers, entry)
+		if err != nil {
+			return nil, err
+		}
+		return &hsmExchanger{hsm: p, handle: entry.Handle, pub: pub}, nil
+	default:
+		return nil, status.Error(codes.FailedPrecondition, "key does not support key agreement")
+	}
+}
+
+// hsmExchanger is an ecdh.KeyExchanger for a key held by an HSM provider.
+type hsmExchanger struct {
+	hsm    hsm.Provider
+	handle hsm.KeyHandle
+	pub    *ecdh.PublicKey
+}
+
+func (x *hsmExchanger) PublicKey() *ecdh.PublicKey { return x.pub }
+
+func (x *hsmExchanger) Curve() ecdh.Curve { return x.pub.Curve() }
+
+func (x *hsmExchanger) ECDH(peer *ecdh.PublicKey) ([]byte, error) {
+	return x.hsm.ECDH(x.handle, peer)
+}
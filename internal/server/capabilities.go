@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"crypto/elliptic"
+	"maps"
+	"slices"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+)
+
+// Limits are the request limits the server runs with.
+type Limits struct {
+	// MaxMessageSize is the largest request message, in bytes.
+	MaxMessageSize int
+	// RateLimitRPS is the requests per second admitted across all clients.
+	RateLimitRPS int
+	// MaxBatchSignSize is the most payloads a BatchSign request may hold.
+	MaxBatchSignSize int
+}
+
+type CapabilitiesServer struct {
+	pb.UnimplementedCapabilitiesServiceServer
+	providers *hsm.Registry
+	limits    Limits
+}
+
+func NewCapabilitiesServer(providers *hsm.Registry, limits Limits) *CapabilitiesServer {
+	return &CapabilitiesServer{providers: providers, limits: limits}
+}
+
+func (s *CapabilitiesServer) GetCapabilities(ctx context.Context, req *pb.GetCapabilitiesRequest) (*pb.GetCapabilitiesResponse, error) {
+	resp := &pb.GetCapabilitiesResponse{
+		SoftwareAlgorithms: softwareAlgorithms(),
+		Limits: &pb.Limits{
+			MaxBatchSignSize:         uint32(s.limits.MaxBatchSignSize),
+			MaxMessageSize:           uint32(s.limits.MaxMessageSize),
+			RateLimitRps:             uint32(s.limits.RateLimitRPS),
+			MinStreamSegmentSize:     crypto.MinStreamSegmentSize,
+			MaxStreamSegmentSize:     crypto.MaxStreamSegmentSize,
+			DefaultStreamSegmentSize: crypto.DefaultStreamSegmentSize,
+		},
+	}
+	for _, name := range s.providers.Names() {
+		p, err := s.providers.Get(name)
+		if err != nil {
+			continue
+		}
+		resp.Providers = append(resp.Providers, providerCapabilities(name, name == s.providers.Default(), p))
+	}
+	return resp, nil
+}
+
+func providerCapabilities(name string, isDefault bool, p hsm.Provider) *pb.ProviderCapabilities {
+	pc := &pb.ProviderCapabilities{Name: name, Default: isDefault, Healthy: true}
+	if m, ok := p.(hsm.Monitored); ok {
+		pc.Healthy = m.Healthy()
+	}
+	c, err := hsm.ProviderCapabilities(p)
+	if err != nil {
+		pc.Error = err.Error()
+		return pc
+	}
+	for _, curve := range c.Curves {
+		switch curve {
+		case elliptic.P256():
+			pc.Algorithms = append(pc.Algorithms, pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256)
+		case elliptic.P384():
+			pc.Algorithms = append(pc.Algorithms, pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P384)
+		}
+	}
+	for _, op := range c.Operations {
+		pc.Operations = append(pc.Operations, operationToProto(op))
+	}
+	if c.Supports(hsm.OpSign) {
+		// Every provider signs the SHA-256 digest of the data.
+		pc.SignatureHashes = []pb.HashFunction{pb.HashFunction_HASH_FUNCTION_SHA256}
+		pc.SignatureFormats = []pb.SignatureFormat{pb.SignatureFormat_SIGNATURE_FORMAT_ASN1_DER}
+	}
+	for _, h := range c.DeriveHashes {
+		pc.DeriveHashes = append(pc.DeriveHashes, hkdfHashToProto(h))
+	}
+	pc.KeyLifecycle = c.KeyLifecycle
+	pc.KeyImport = c.KeyImport
+	pc.MaxConcurrency = uint32(c.MaxConcurrency)
+	return pc
+}
+
+// softwareAlgorithms lists the key algorithms generated in software: all
+// but the ECDSA ones, which HSM providers hold, and the RSA ones, which are
+// only uploaded.
+func softwareAlgorithms() []pb.KeyAlgorithm {
+	var algos []pb.KeyAlgorithm
+	for _, n := range slices.Sorted(maps.Keys(pb.KeyAlgorithm_name)) {
+		a := pb.KeyAlgorithm(n)
+		if a == pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED {
+			continue
+		}
+		if algo, err := algoFromProto(a); err == nil && !algo.IsECDSA() && !algo.IsRSA() {
+			algos = append(algos, a)
+		}
+	}
+	return algos
+}
+
+func hkdfHashToProto(h crypto.HKDFHash) pb.HkdfHash {
+	switch h {
+	case crypto.HKDFSHA384:
+		return pb.HkdfHash_HKDF_HASH_SHA384
+	case crypto.HKDFSHA512:
+		return pb.HkdfHash_HKDF_HASH_SHA512
+	default:
+		return pb.HkdfHash_HKDF_HASH_SHA256
+	}
+}
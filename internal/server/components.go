@@ -0,0 +1,333 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/ceremony"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+const (
+	minComponents          = 2
+	maxComponents          = 9
+	defaultCeremonyTimeout = 10 * time.Minute
+	maxCeremonyTimeout     = 24 * time.Hour
+)
+
+func (s *KeyManagementServer) BeginComponentImport(ctx context.Context, req *pb.BeginComponentImportRequest) (*pb.BeginComponentImportResponse, error) {
+	principal, err := custodian(ctx)
+	if err != nil {
+		return nil, err
+	}
+	algo, err := algoFromProto(req.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if componentKeySize(algo) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "key components require a TDEA or AES key")
+	}
+	count, ttl, err := ceremonyParams(req.ComponentCount, req.TimeoutSeconds)
+	if err != nil {
+		return nil, err
+	}
+	if want := kcvSize(algo); len(req.Kcv) != want {
+		return nil, status.Errorf(codes.InvalidArgument, "kcv must be %d bytes for %v keys", want, algo)
+	}
+	purpose := purposeFromProto(req.Purpose)
+	if err := checkPurpose(algo, purpose); err != nil {
+		return nil, err
+	}
+
+	c := &ceremony.Ceremony{
+		Kind: ceremony.KindImport,
+		Key: &keystore.KeyEntry{
+			Algorithm:  algo,
+			Purpose:    purpose,
+			Mode:       modeFromProto(req.ModeOfUse),
+			Exportable: req.Exportable,
+			Labels:     req.Labels,
+		},
+		Count: count,
+		KCV:   req.Kcv,
+	}
+	s.ceremonies.Begin(c, ttl)
+
+	s.audit.Log("BeginComponentImport", "", "OK", "", map[string]string{
+		"ceremony_id": c.ID,
+		"algorithm":   algo.String(),
+		"components":  strconv.Itoa(count),
+		"principal":   principal,
+	})
+	return &pb.BeginComponentImportResponse{CeremonyId: c.ID, ExpiresAt: timestamppb.New(c.ExpiresAt)}, nil
+}
+
+func (s *KeyManagementServer) SubmitKeyComponent(ctx context.Context, req *pb.SubmitKeyComponentRequest) (*pb.SubmitKeyComponentResponse, error) {
+	principal, err := custodian(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.ceremonies.Get(req.CeremonyId)
+	if err != nil {
+		return nil, ceremonyError(err)
+	}
+	if c.Kind != ceremony.KindImport {
+		return nil, ceremonyError(ceremony.ErrKind)
+	}
+
+	algo := c.Key.Algorithm
+	if len(req.Component) != componentKeySize(algo) {
+		return nil, status.Errorf(codes.InvalidArgument, "component must be %d bytes for %v keys", componentKeySize(algo), algo)
+	}
+	kcv, err := componentKCV(algo, req.Component)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "compute kcv: %v", err)
+	}
+	if subtle.ConstantTimeCompare(kcv, req.Kcv) != 1 {
+		s.audit.Log("SubmitKeyComponent", "", "ERROR", "", map[string]string{
+			"ceremony_id": req.CeremonyId,
+			"principal":   principal,
+			"reason":      "component kcv mismatch",
+		})
+		return nil, status.Error(codes.InvalidArgument, "component kcv does not match")
+	}
+
+	c, complete, err := s.ceremonies.Submit(req.CeremonyId, principal, req.Component)
+	if err != nil {
+		s.audit.Log("SubmitKeyComponent", "", "ERROR", "", map[string]string{
+			"ceremony_id": req.CeremonyId,
+			"principal":   principal,
+			"reason":      err.Error(),
+		})
+		return nil, ceremonyError(err)
+	}
+	received := len(c.Custodians)
+	s.audit.Log("SubmitKeyComponent", "", "OK", "", map[string]string{
+		"ceremony_id": c.ID,
+		"principal":   principal,
+		"component":   strconv.Itoa(received),
+	})
+	resp := &pb.SubmitKeyComponentResponse{
+		ComponentsReceived: int32(received),
+		ComponentsRequired: int32(c.Count),
+	}
+	if !complete {
+		return resp, nil
+	}
+
+	// Last component: combine, check and store the key.
+	defer c.Wipe()
+	key, err := crypto.CombineKey(c.Components, isTDEA(algo))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "combine components: %v", err)
+	}
+	kcv, err = componentKCV(algo, key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "compute kcv: %v", err)
+	}
+	meta := map[string]string{"ceremony_id": c.ID, "custodians": strings.Join(c.Custodians, ",")}
+	if subtle.ConstantTimeCompare(kcv, c.KCV) != 1 {
+		clear(key)
+		s.audit.Log("ImportKeyComponents", "", "ERROR", "", meta)
+		return nil, status.Error(codes.FailedPrecondition, "combined key kcv does not match; ceremony aborted")
+	}
+
+	entry := *c.Key
+	entry.ID = uuid.NewString()
+	entry.Status = keystore.StatusActive
+	entry.SecretKey = key
+	entry.Origin = keystore.OriginImported
+	entry.CreatedAt = time.Now()
+	if err := s.store.Put(&entry); err != nil {
+		return nil, status.Errorf(codes.Internal, "store key: %v", err)
+	}
+
+	resp.Metadata = entryToProto(&entry)
+	s.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_CREATED, resp.Metadata)
+	s.audit.Log("ImportKeyComponents", entry.ID, "OK", "", meta)
+	return resp, nil
+}
+
+func (s *KeyManagementServer) BeginComponentExport(ctx context.Context, req *pb.BeginComponentExportRequest) (*pb.BeginComponentExportResponse, error) {
+	principal, err := custodian(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, "BeginComponentExport", req.KeyId, interceptor.PermissionExportKeys, map[string]string{}); err != nil {
+		return nil, err
+	}
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if entry.Status == keystore.StatusDeactivated {
+		return nil, status.Error(codes.FailedPrecondition, "key is deactivated")
+	}
+	if !entry.Exportable {
+		return nil, status.Error(codes.FailedPrecondition, "key is not exportable")
+	}
+	if componentKeySize(entry.Algorithm) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "only TDEA and AES keys can be exported as components")
+	}
+	count, ttl, err := ceremonyParams(req.ComponentCount, req.TimeoutSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := crypto.SplitKey(entry.SecretKey, count, isTDEA(entry.Algorithm))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "split key: %v", err)
+	}
+	kcv, err := componentKCV(entry.Algorithm, entry.SecretKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "compute kcv: %v", err)
+	}
+
+	c := &ceremony.Ceremony{
+		Kind:       ceremony.KindExport,
+		Key:        entry,
+		Count:      count,
+		KCV:        kcv,
+		Components: components,
+	}
+	s.ceremonies.Begin(c, ttl)
+
+	s.audit.Log("BeginComponentExport", entry.ID, "OK", "", map[string]string{
+		"ceremony_id": c.ID,
+		"components":  strconv.Itoa(count),
+		"principal":   principal,
+	})
+	return &pb.BeginComponentExportResponse{CeremonyId: c.ID, ExpiresAt: timestamppb.New(c.ExpiresAt), Kcv: kcv}, nil
+}
+
+func (s *KeyManagementServer) RetrieveKeyComponent(ctx context.Context, req *pb.RetrieveKeyComponentRequest) (*pb.RetrieveKeyComponentResponse, error) {
+	principal, err := custodian(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.ceremonies.Get(req.CeremonyId)
+	if err != nil {
+		return nil, ceremonyError(err)
+	}
+
+	component, index, remaining, err := s.ceremonies.Take(req.CeremonyId, principal)
+	if err != nil {
+		s.audit.Log("RetrieveKeyComponent", c.Key.ID, "ERROR", "", map[string]string{
+			"ceremony_id": req.CeremonyId,
+			"principal":   principal,
+			"reason":      err.Error(),
+		})
+		return nil, ceremonyError(err)
+	}
+	kcv, err := componentKCV(c.Key.Algorithm, component)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "compute kcv: %v", err)
+	}
+
+	s.audit.Log("RetrieveKeyComponent", c.Key.ID, "OK", "", map[string]string{
+		"ceremony_id": req.CeremonyId,
+		"principal":   principal,
+		"component":   strconv.Itoa(index),
+	})
+	return &pb.RetrieveKeyComponentResponse{
+		Component:           component,
+		Kcv:                 kcv,
+		Index:               int32(index),
+		ComponentsRemaining: int32(remaining),
+	}, nil
+}
+
+// ceremonyExpired audits a ceremony that timed out before completing.
+func (s *KeyManagementServer) ceremonyExpired(c *ceremony.Ceremony) {
+	keyID := ""
+	if c.Kind == ceremony.KindExport {
+		keyID = c.Key.ID
+	}
+	s.audit.Log("KeyCeremonyExpired", keyID, "ERROR", "", map[string]string{
+		"ceremony_id": c.ID,
+		"kind":        c.Kind.String(),
+		"custodians":  strings.Join(c.Custodians, ","),
+	})
+}
+
+// custodian returns the name of the authenticated caller. Ceremonies rely
+// on it to keep components with separate custodians.
+func custodian(ctx context.Context) (string, error) {
+	p, ok := interceptor.PrincipalFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "key ceremonies require an authenticated principal")
+	}
+	return p.Name, nil
+}
+
+func ceremonyParams(count, timeoutSeconds int32) (int, time.Duration, error) {
+	if count < minComponents || count > maxComponents {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "component_count must be %d-%d", minComponents, maxComponents)
+	}
+	ttl := defaultCeremonyTimeout
+	if timeoutSeconds != 0 {
+		ttl = time.Duration(timeoutSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > maxCeremonyTimeout {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "timeout_seconds must be 1-%d", int(maxCeremonyTimeout.Seconds()))
+	}
+	return int(count), ttl, nil
+}
+
+func ceremonyError(err error) error {
+	switch {
+	case errors.Is(err, ceremony.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ceremony.ErrCustodian):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ceremony.ErrKind):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "ceremony: %v", err)
+	}
+}
+
+// componentKeySize returns the key length for algorithms that can be
+// handled as components, or 0.
+func componentKeySize(algo keystore.KeyAlgorithm) int {
+	switch algo {
+	case keystore.AlgorithmTDEA2Key, keystore.AlgorithmAES128:
+		return 16
+	case keystore.AlgorithmTDEA3Key:
+		return 24
+	case keystore.AlgorithmAES256:
+		return 32
+	default:
+		return 0
+	}
+}
+
+func isTDEA(algo keystore.KeyAlgorithm) bool {
+	return algo == keystore.AlgorithmTDEA2Key || algo == keystore.AlgorithmTDEA3Key
+}
+
+func kcvSize(algo keystore.KeyAlgorithm) int {
+	if isTDEA(algo) {
+		return crypto.TDEAKCVSize
+	}
+	return crypto.AESKCVSize
+}
+
+func componentKCV(algo keystore.KeyAlgorithm, key []byte) ([]byte, error) {
+	if isTDEA(algo) {
+		return crypto.TDEAKCV(key)
+	}
+	return crypto.AESKCV(key)
+}
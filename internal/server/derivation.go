@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// DeriveChildKey derives the key at a path below a root key. The key is
+// resolved by the store's DerivedStore, which caches it and derives it
+// again whenever its key_id is used after a restart.
+func (s *KeyManagementServer) DeriveChildKey(ctx context.Context, req *pb.DeriveChildKeyRequest) (*pb.DeriveChildKeyResponse, error) {
+	if _, ok := s.store.(*keystore.DerivedStore); !ok {
+		return nil, status.Error(codes.FailedPrecondition, "path derivation is not enabled on this store")
+	}
+
+	algo := keystore.AlgorithmAES256
+	if req.Algorithm != pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED {
+		var err error
+		if algo, err = algoFromProto(req.Algorithm); err != nil {
+			return nil, err
+		}
+	}
+	if !algo.Derivable() {
+		return nil, status.Errorf(codes.InvalidArgument, "%v keys cannot be derived by path", algo)
+	}
+	if _, err := keystore.ParseDerivationPath(req.Path); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	root, err := s.store.Get(req.RootKeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if root.Status != keystore.StatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "root key is not active")
+	}
+	if err := checkPermits(root, keystore.OpDerive); err != nil {
+		return nil, err
+	}
+
+	child, err := s.store.Get(keystore.ChildKeyID(root.ID, algo, req.Path))
+	if err != nil {
+		s.audit.Log("DeriveChildKey", req.RootKeyId, "ERROR", "", map[string]string{"path": req.Path})
+		return nil, keyError(err)
+	}
+
+	s.audit.Log("DeriveChildKey", req.RootKeyId, "OK", "", map[string]string{
+		"path":         req.Path,
+		"algorithm":    algo.String(),
+		"child_key_id": child.ID,
+	})
+	return &pb.DeriveChildKeyResponse{Metadata: entryToProto(child)}, nil
+}
+
+// hasPathPrefix reports whether path starts with the segments of prefix.
+func hasPathPrefix(path, prefix string) bool {
+	if prefix == "" || path == prefix {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
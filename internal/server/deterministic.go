@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// EncryptDeterministic encrypts with AES-SIV. Equal plaintexts give equal
+// ciphertexts under the same key and aad, which is what makes the output
+// searchable and also what it leaks.
+func (s *EncryptionServer) EncryptDeterministic(ctx context.Context, req *pb.EncryptDeterministicRequest) (*pb.EncryptDeterministicResponse, error) {
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if entry.Status != keystore.StatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "key is not active")
+	}
+	if err := checkSIVKey(entry, keystore.OpEncryptDeterministic); err != nil {
+		return nil, err
+	}
+
+	ct, err := crypto.SealSIV(entry.SecretKey, req.Plaintext, req.Aad)
+	if err != nil {
+		s.audit.Log("EncryptDeterministic", req.KeyId, "ERROR", "", nil)
+		return nil, status.Errorf(codes.Internal, "encrypt: %v", err)
+	}
+
+	s.audit.Log("EncryptDeterministic", req.KeyId, "OK", "", nil)
+	return &pb.EncryptDeterministicResponse{Ciphertext: ct, KeyId: req.KeyId}, nil
+}
+
+func (s *EncryptionServer) DecryptDeterministic(ctx context.Context, req *pb.DecryptDeterministicRequest) (*pb.DecryptDeterministicResponse, error) {
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if err := checkSIVKey(entry, keystore.OpDecryptDeterministic); err != nil {
+		return nil, err
+	}
+
+	pt, err := crypto.OpenSIV(entry.SecretKey, req.Ciphertext, req.Aad)
+	if err != nil {
+		s.audit.Log("DecryptDeterministic", req.KeyId, "ERROR", "", nil)
+		return nil, status.Errorf(codes.InvalidArgument, "decrypt: %v", err)
+	}
+
+	s.audit.Log("DecryptDeterministic", req.KeyId, "OK", "", nil)
+	return &pb.DecryptDeterministicResponse{Plaintext: pt}, nil
+}
+
+// checkSIVKey verifies entry is an AES-SIV key whose purpose allows op.
+func checkSIVKey(entry *keystore.KeyEntry, op keystore.KeyOperation) error {
+	if entry.Algorithm != keystore.AlgorithmAESSIV {
+		return status.Errorf(codes.FailedPrecondition, "deterministic encryption requires an %v key", keystore.AlgorithmAESSIV)
+	}
+	return checkPermits(entry, op)
+}
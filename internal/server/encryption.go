@@ -2,26 +2,50 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	pb "github.com/glinharesb/vault-go/gen/vault/v1"
 	"github.com/glinharesb/vault-go/internal/audit"
 	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
 	"github.com/glinharesb/vault-go/internal/keystore"
 )
 
 type EncryptionServer struct {
 	pb.UnimplementedEncryptionServiceServer
-	store keystore.Store
-	audit *audit.Logger
+	store  keystore.Store
+	keys   *KeyManagementServer
+	policy NoncePolicy
+	audit  *audit.Logger
 }
 
-func NewEncryptionServer(store keystore.Store, a *audit.Logger) *EncryptionServer {
+// NoncePolicy governs keys that encrypt with random 96-bit nonces as their
+// use approaches the limit NIST SP 800-38D sets for them.
+type NoncePolicy struct {
+	// WarnAt is the encryption count at which a key is logged and audited
+	// as nearing its limit. Zero disables the warning.
+	WarnAt uint64
+	// Rotate replaces a key that reaches its limit with a new version and
+	// encrypts under that. Otherwise further encryptions are refused.
+	Rotate bool
+}
+
+// NewEncryptionServer returns an EncryptionServer. keys performs the
+// rotations the nonce policy calls for.
+func NewEncryptionServer(store keystore.Store, keys *KeyManagementServer, policy NoncePolicy, a *audit.Logger) *EncryptionServer {
 	return &EncryptionServer{
-		store: store,
-		audit: a,
+		store:  store,
+		keys:   keys,
+		policy: policy,
+		audit:  a,
 	}
 }
 
@@ -33,21 +57,29 @@ func (s *EncryptionServer) Encrypt(ctx context.Context, req *pb.EncryptRequest)
 	if entry.Status != keystore.StatusActive {
 		return nil, status.Error(codes.FailedPrecondition, "key is not active")
 	}
-
-	// Derive a symmetric key from the ECDSA private key bytes for AES-GCM.
-	symKey, err := deriveSymmetricKey(entry)
+	if err := checkEncryptionKey(entry); err != nil {
+		return nil, err
+	}
+	if err := checkPermits(entry, keystore.OpEncrypt); err != nil {
+		return nil, err
+	}
+	entry, err = s.countEncryption(ctx, entry)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "derive symmetric key: %v", err)
+		return nil, err
 	}
 
-	ct, err := crypto.EncryptAESGCM(symKey, req.Plaintext, req.Aad)
+	var meta map[string]string
+	if entry.ID != req.KeyId {
+		meta = map[string]string{"rotated_from": req.KeyId}
+	}
+	ct, err := s.sealData(entry, req.Plaintext, req.Aad)
 	if err != nil {
-		s.audit.Log("Encrypt", req.KeyId, "ERROR", "", nil)
-		return nil, status.Errorf(codes.Internal, "encrypt: %v", err)
+		s.audit.Log("Encrypt", entry.ID, "ERROR", "", meta)
+		return nil, hsmError(codes.Internal, "encrypt", err)
 	}
 
-	s.audit.Log("Encrypt", req.KeyId, "OK", "", nil)
-	return &pb.EncryptResponse{Ciphertext: ct, KeyId: req.KeyId}, nil
+	s.audit.Log("Encrypt", entry.ID, "OK", "", meta)
+	return &pb.EncryptResponse{Ciphertext: ct, KeyId: entry.ID}, nil
 }
 
 func (s *EncryptionServer) Decrypt(ctx context.Context, req *pb.DecryptRequest) (*pb.DecryptResponse, error) {
@@ -55,16 +87,26 @@ func (s *EncryptionServer) Decrypt(ctx context.Context, req *pb.DecryptRequest)
 	if err != nil {
 		return nil, keyError(err)
 	}
-
-	symKey, err := deriveSymmetricKey(entry)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "derive symmetric key: %v", err)
+	if err := checkEncryptionKey(entry); err != nil {
+		return nil, err
+	}
+	if err := checkPermits(entry, keystore.OpDecrypt); err != nil {
+		return nil, err
+	}
+	if alg := entry.Algorithm.AEAD(); alg != 0 {
+		sealed, err := crypto.SealedAlgorithm(req.Ciphertext)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "decrypt: %v", err)
+		}
+		if sealed != alg {
+			return nil, status.Errorf(codes.InvalidArgument, "ciphertext was encrypted with %v, key uses %v", sealed, alg)
+		}
 	}
 
-	pt, err := crypto.DecryptAESGCM(symKey, req.Ciphertext, req.Aad)
+	pt, err := s.openData(entry, req.Ciphertext, req.Aad)
 	if err != nil {
 		s.audit.Log("Decrypt", req.KeyId, "ERROR", "", nil)
-		return nil, status.Errorf(codes.InvalidArgument, "decrypt: %v", err)
+		return nil, hsmError(codes.InvalidArgument, "decrypt", err)
 	}
 
 	s.audit.Log("Decrypt", req.KeyId, "OK", "", nil)
@@ -79,31 +121,230 @@ func (s *EncryptionServer) DeriveKey(ctx context.Context, req *pb.DeriveKeyReque
 	if entry.Status != keystore.StatusActive {
 		return nil, status.Error(codes.FailedPrecondition, "root key is not active")
 	}
+	if !hkdfRoot(entry) {
+		return nil, status.Errorf(codes.FailedPrecondition, "keys cannot be derived from a %v key", entry.Algorithm)
+	}
+	if err := checkPermits(entry, keystore.OpDerive); err != nil {
+		return nil, err
+	}
 
+	hash, err := hkdfHashFromProto(req.Hash)
+	if err != nil {
+		return nil, err
+	}
 	length := int(req.Length)
-	if length <= 0 || length > 64 {
-		return nil, status.Error(codes.InvalidArgument, "length must be 1-64 bytes")
+	if !req.StoreKey && (length <= 0 || length > hash.MaxLength()) {
+		return nil, status.Errorf(codes.InvalidArgument, "length must be 1-%d bytes with %v", hash.MaxLength(), hash)
 	}
 
-	rootBytes, err := crypto.MarshalPrivateKey(entry.PrivateKey)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "marshal root key: %v", err)
+	var derive func(size int) ([]byte, error)
+	if entry.Algorithm.IsECDSA() {
+		p, err := keyProvider(s.keys.providers, entry)
+		if err != nil {
+			return nil, err
+		}
+		derive = func(size int) ([]byte, error) {
+			return p.Derive(entry.Handle, hash, req.Salt, req.Context, size)
+		}
+	} else {
+		rootBytes, err := keyMaterial(entry)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "marshal root key: %v", err)
+		}
+		derive = func(size int) ([]byte, error) {
+			return crypto.HKDF(hash, rootBytes, req.Salt, req.Context, size)
+		}
 	}
+	meta := map[string]string{"hash": hash.String()}
 
-	derived, err := crypto.DeriveKey(rootBytes, req.Context, length)
+	if req.StoreKey {
+		child, err := s.keys.storeDerivedKey(secretOutput{
+			algorithm: req.DerivedKeyAlgorithm,
+			purpose:   req.DerivedKeyPurpose,
+			labels:    req.Labels,
+		}, entry.ID, derive)
+		if err != nil {
+			s.audit.Log("DeriveKey", req.RootKeyId, "ERROR", "", meta)
+			return nil, err
+		}
+		key := entryToProto(child)
+		s.keys.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_CREATED, key)
+		meta["derived_key_id"] = child.ID
+		s.audit.Log("DeriveKey", req.RootKeyId, "OK", "", meta)
+		return &pb.DeriveKeyResponse{Key: key}, nil
+	}
+
+	derived, err := derive(length)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "derive key: %v", err)
+		return nil, hsmError(codes.Internal, "derive key", err)
 	}
 
-	s.audit.Log("DeriveKey", req.RootKeyId, "OK", "", nil)
+	s.audit.Log("DeriveKey", req.RootKeyId, "OK", "", meta)
 	return &pb.DeriveKeyResponse{DerivedKey: derived}, nil
 }
 
-// deriveSymmetricKey produces a 32-byte AES key from an ECDSA key via HKDF.
-func deriveSymmetricKey(entry *keystore.KeyEntry) ([]byte, error) {
-	privBytes, err := crypto.MarshalPrivateKey(entry.PrivateKey)
+func hkdfHashFromProto(h pb.HkdfHash) (crypto.HKDFHash, error) {
+	switch h {
+	case pb.HkdfHash_HKDF_HASH_UNSPECIFIED, pb.HkdfHash_HKDF_HASH_SHA256:
+		return crypto.HKDFSHA256, nil
+	case pb.HkdfHash_HKDF_HASH_SHA384:
+		return crypto.HKDFSHA384, nil
+	case pb.HkdfHash_HKDF_HASH_SHA512:
+		return crypto.HKDFSHA512, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported hkdf hash: %v", h)
+	}
+}
+
+// countEncryption records an encryption under entry and applies the nonce
+// policy. It returns the key to encrypt with: entry itself, or its new
+// version when the policy rotated it. A key derived by path counts against
+// its root, so it is the root that is rotated, and the same path below the
+// new root that is returned.
+func (s *EncryptionServer) countEncryption(ctx context.Context, entry *keystore.KeyEntry) (*keystore.KeyEntry, error) {
+	limit := nonceLimit(entry)
+	if limit == 0 {
+		return entry, nil
+	}
+	n, err := s.store.RecordEncryption(entry.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "record encryption: %v", err)
+	}
+	meta := map[string]string{
+		"encryptions": strconv.FormatUint(n, 10),
+		"limit":       strconv.FormatUint(limit, 10),
+	}
+	if n == s.policy.WarnAt {
+		slog.Warn("key nearing its encryption limit", "key_id", entry.ID, "encryptions", n, "limit", limit)
+		s.audit.Log("EncryptionLimitWarning", entry.ID, "OK", "", meta)
+	}
+	if n <= limit {
+		return entry, nil
+	}
+
+	// Only the encryption that crosses the limit rotates; any racing it
+	// are refused like every later one.
+	if !s.policy.Rotate || n != limit+1 {
+		s.audit.Log("Encrypt", entry.ID, "DENIED", "", meta)
+		return nil, status.Errorf(codes.ResourceExhausted, "key has reached its limit of %d encryptions and must be rotated", limit)
+	}
+	rotate := entry.ID
+	if entry.DerivationPath != "" {
+		rotate = entry.ParentID
+	}
+	resp, err := s.keys.RotateKey(ctx, &pb.RotateKeyRequest{KeyId: rotate})
+	if err != nil {
+		return nil, err
+	}
+	nextID := resp.NewKey.KeyId
+	if entry.DerivationPath != "" {
+		nextID = keystore.ChildKeyID(nextID, entry.Algorithm, entry.DerivationPath)
+	}
+	next, err := s.store.Get(nextID)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	return s.countEncryption(ctx, next)
+}
+
+// nonceLimit returns how many messages Encrypt may seal under the key, or
+// 0 when its nonces are long enough not to need counting.
+func nonceLimit(entry *keystore.KeyEntry) uint64 {
+	if entry.Algorithm.IsECDSA() {
+		return crypto.AEADAESGCM.NonceLimit()
+	}
+	return entry.Algorithm.AEAD().NonceLimit()
+}
+
+// hsmError reports a failed operation with code, FailedPrecondition when
+// the key's HSM provider does not support the operation, or Unavailable
+// when the provider cannot be reached. Errors that already carry a status
+// pass through.
+func hsmError(code codes.Code, op string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, hsm.ErrUnsupported):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", op, err)
+	case errors.Is(err, hsm.ErrUnavailable):
+		st := status.Newf(codes.Unavailable, "%s: %v", op, err)
+		// An open circuit breaker knows when the provider is worth trying
+		// again.
+		var unavailable *hsm.UnavailableError
+		if errors.As(err, &unavailable) {
+			if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{
+				RetryDelay: durationpb.New(unavailable.RetryAfter),
+			}); detailErr == nil {
+				st = detailed
+			}
+		}
+		return st.Err()
+	}
+	return status.Errorf(code, "%s: %v", op, err)
+}
+
+// checkEncryptionKey rejects keys that Encrypt and Decrypt cannot use.
+func checkEncryptionKey(entry *keystore.KeyEntry) error {
+	if !entry.Algorithm.IsECDSA() && entry.Algorithm.AEAD() == 0 {
+		return status.Error(codes.FailedPrecondition, "key does not support encryption")
+	}
+	return nil
+}
+
+// sealData encrypts with the key's cipher, recorded in the ciphertext.
+// ECDSA keys keep the original unlabelled AES-256-GCM format under a key
+// derived from the private key.
+func (s *EncryptionServer) sealData(entry *keystore.KeyEntry, plaintext, aad []byte) ([]byte, error) {
+	if alg := entry.Algorithm.AEAD(); alg != 0 {
+		return crypto.SealAEAD(alg, entry.SecretKey, plaintext, aad)
+	}
+	p, err := keyProvider(s.keys.providers, entry)
+	if err != nil {
+		return nil, err
+	}
+	return p.Encrypt(entry.Handle, plaintext, aad)
+}
+
+// openData reverses sealData.
+func (s *EncryptionServer) openData(entry *keystore.KeyEntry, ciphertext, aad []byte) ([]byte, error) {
+	if entry.Algorithm.AEAD() != 0 {
+		return crypto.OpenAEAD(entry.SecretKey, ciphertext, aad)
+	}
+	p, err := keyProvider(s.keys.providers, entry)
 	if err != nil {
 		return nil, err
 	}
-	return crypto.DeriveKey(privBytes, []byte("vault-aes-gcm"), 32)
+	return p.Decrypt(entry.Handle, ciphertext, aad)
+}
+
+// hkdfRoot reports whether DeriveKey can derive from entry: its private
+// or secret key is held by the vault or by an HSM provider that can
+// derive with it. RSA wrapping keys only hold a public key.
+func hkdfRoot(entry *keystore.KeyEntry) bool {
+	switch {
+	case entry.Algorithm.IsECDSA():
+		return entry.Handle != ""
+	case entry.Algorithm.IsSymmetric():
+		return len(entry.SecretKey) > 0
+	default:
+		return entry.AgreementKey != nil || entry.KEMKey != nil
+	}
+}
+
+// keyMaterial returns the raw secret of a key for use as HKDF input:
+// the secret itself for symmetric keys, the seed for KEM keys and the
+// PKCS8 encoding for X25519 keys. ECDSA keys derive inside the HSM
+// provider instead.
+func keyMaterial(entry *keystore.KeyEntry) ([]byte, error) {
+	if entry.Algorithm.IsSymmetric() {
+		return entry.SecretKey, nil
+	}
+	if entry.AgreementKey != nil {
+		return crypto.MarshalAgreementKey(entry.AgreementKey)
+	}
+	if entry.KEMKey != nil {
+		return entry.KEMKey.Seed(), nil
+	}
+	return nil, fmt.Errorf("%v key material is held by the hsm", entry.Algorithm)
 }
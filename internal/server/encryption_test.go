@@ -0,0 +1,136 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// putWornKey stores an AES-256 key that has reached its encryption limit.
+func putWornKey(t *testing.T, store keystore.Store, purpose keystore.KeyPurpose) *keystore.KeyEntry {
+	t.Helper()
+	secret, err := crypto.GenerateAESKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	entry := &keystore.KeyEntry{
+		ID:          purpose.String(),
+		Algorithm:   keystore.AlgorithmAES256,
+		Status:      keystore.StatusActive,
+		SecretKey:   secret,
+		Purpose:     purpose,
+		Encryptions: crypto.AEADAESGCM.NonceLimit(),
+		CreatedAt:   time.Now(),
+	}
+	if err := store.Put(entry); err != nil {
+		t.Fatalf("put key: %v", err)
+	}
+	return entry
+}
+
+func TestEncryptionLimitRotates(t *testing.T) {
+	ts := newTestServer(t)
+	enc := NewEncryptionServer(ts.store, ts.keys, NoncePolicy{Rotate: true}, ts.audit)
+	key := putWornKey(t, ts.store, keystore.PurposeDataEncryption)
+
+	resp, err := enc.Encrypt(asPrincipal("default"), &pb.EncryptRequest{KeyId: key.ID, Plaintext: []byte("data")})
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if resp.KeyId == key.ID {
+		t.Fatal("key at its limit should have been rotated")
+	}
+	waitAudit(t, ts.sub, "RotateKey", "OK")
+}
+
+func TestEncryptionLimitRotatesDerivedRoot(t *testing.T) {
+	ts := newTestServer(t)
+	enc := NewEncryptionServer(ts.store, ts.keys, NoncePolicy{Rotate: true}, ts.audit)
+	root := putWornKey(t, ts.store, keystore.PurposeBaseDerivation)
+	child := keystore.ChildKeyID(root.ID, keystore.AlgorithmAES256, "merchant/1")
+
+	ctx := asPrincipal("default")
+	resp, err := enc.Encrypt(ctx, &pb.EncryptRequest{KeyId: child, Plaintext: []byte("data")})
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if e := waitAudit(t, ts.sub, "RotateKey", "OK"); e.KeyID != root.ID {
+		t.Fatalf("rotated %q, want the root %q", e.KeyID, root.ID)
+	}
+	next, err := ts.store.Get(resp.KeyId)
+	if err != nil {
+		t.Fatalf("get new child: %v", err)
+	}
+	if next.ParentID == root.ID || next.DerivationPath != "merchant/1" {
+		t.Fatalf("encrypted under %q, want the same path below the new root", resp.KeyId)
+	}
+	if next.Encryptions != 1 {
+		t.Fatalf("new root encryptions: got %d, want 1", next.Encryptions)
+	}
+
+	// The old child follows its root out of use, while its new version
+	// keeps encrypting.
+	_, err = enc.Encrypt(ctx, &pb.EncryptRequest{KeyId: child, Plaintext: []byte("data")})
+	wantCode(t, err, codes.FailedPrecondition)
+	if _, err := enc.Encrypt(ctx, &pb.EncryptRequest{KeyId: resp.KeyId, Plaintext: []byte("data")}); err != nil {
+		t.Fatalf("encrypt under the new child: %v", err)
+	}
+	if _, err := enc.Decrypt(ctx, &pb.DecryptRequest{KeyId: resp.KeyId, Ciphertext: resp.Ciphertext}); err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+}
+
+func TestEncryptionLimitRefuses(t *testing.T) {
+	ts := newTestServer(t)
+	enc := NewEncryptionServer(ts.store, ts.keys, NoncePolicy{}, ts.audit)
+	root := putWornKey(t, ts.store, keystore.PurposeBaseDerivation)
+	child := keystore.ChildKeyID(root.ID, keystore.AlgorithmAES256, "merchant/1")
+
+	_, err := enc.Encrypt(asPrincipal("default"), &pb.EncryptRequest{KeyId: child, Plaintext: []byte("data")})
+	wantCode(t, err, codes.ResourceExhausted)
+	waitAudit(t, ts.sub, "Encrypt", "DENIED")
+}
+
+func TestDeriveKeyRoots(t *testing.T) {
+	ts := newTestServer(t)
+	enc := NewEncryptionServer(ts.store, ts.keys, NoncePolicy{}, ts.audit)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	publicOnly := &keystore.KeyEntry{
+		ID:          "public-only",
+		Algorithm:   keystore.AlgorithmRSA2048,
+		Status:      keystore.StatusActive,
+		WrappingKey: &rsaKey.PublicKey,
+		CreatedAt:   time.Now(),
+	}
+	if err := ts.store.Put(publicOnly); err != nil {
+		t.Fatalf("put key: %v", err)
+	}
+	ctx := asPrincipal("default")
+	_, err = enc.DeriveKey(ctx, &pb.DeriveKeyRequest{RootKeyId: publicOnly.ID, Length: 32})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	for _, algo := range []pb.KeyAlgorithm{
+		pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256,
+		pb.KeyAlgorithm_KEY_ALGORITHM_X25519,
+	} {
+		root := ts.generate(t, &pb.GenerateKeyRequest{Algorithm: algo})
+		resp, err := enc.DeriveKey(ctx, &pb.DeriveKeyRequest{RootKeyId: root.ID, Length: 32})
+		if err != nil {
+			t.Fatalf("derive from %v: %v", algo, err)
+		}
+		if len(resp.DerivedKey) != 32 {
+			t.Fatalf("derived %d bytes from %v, want 32", len(resp.DerivedKey), algo)
+		}
+	}
+}
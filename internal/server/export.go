@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+func (s *KeyManagementServer) ImportWrappingKey(ctx context.Context, req *pb.ImportWrappingKeyRequest) (*pb.ImportWrappingKeyResponse, error) {
+	if err := s.authorize(ctx, "ImportWrappingKey", "", interceptor.PermissionWrappingKeys, map[string]string{}); err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(req.PublicKeyDer)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
+	pub, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "wrapping keys must be RSA public keys")
+	}
+	algo, ok := keystore.RSAAlgorithm(pub.N.BitLen())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "RSA wrapping keys must be 2048, 3072 or 4096 bits, got %d", pub.N.BitLen())
+	}
+
+	entry := &keystore.KeyEntry{
+		ID:          uuid.NewString(),
+		Algorithm:   algo,
+		Status:      keystore.StatusActive,
+		WrappingKey: pub,
+		Purpose:     keystore.PurposeKeyEncryption,
+		Mode:        keystore.ModeEncryptOnly,
+		Origin:      keystore.OriginImported,
+		CreatedAt:   time.Now(),
+		Labels:      req.Labels,
+	}
+	if err := s.store.Put(entry); err != nil {
+		return nil, status.Errorf(codes.Internal, "store key: %v", err)
+	}
+
+	meta := entryToProto(entry)
+	s.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_CREATED, meta)
+	s.audit.Log("ImportWrappingKey", entry.ID, "OK", "", principalMeta(ctx, map[string]string{
+		"algorithm": algo.String(),
+	}))
+	return &pb.ImportWrappingKeyResponse{Metadata: meta}, nil
+}
+
+func (s *KeyManagementServer) ExportKey(ctx context.Context, req *pb.ExportKeyRequest) (*pb.ExportKeyResponse, error) {
+	meta := principalMeta(ctx, map[string]string{"wrapping_key_id": req.WrappingKeyId})
+	if err := s.authorize(ctx, "ExportKey", req.KeyId, interceptor.PermissionExportKeys, meta); err != nil {
+		return nil, err
+	}
+	if req.KeyId == req.WrappingKeyId {
+		return nil, status.Error(codes.InvalidArgument, "a key cannot be exported under itself")
+	}
+	wrapping, err := s.exportWrappingKey(req.WrappingKeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if entry.Status == keystore.StatusDeactivated {
+		meta["reason"] = "key is deactivated"
+		s.audit.Log("ExportKey", req.KeyId, "ERROR", "", meta)
+		return nil, status.Error(codes.FailedPrecondition, "key is deactivated")
+	}
+	if !entry.Exportable {
+		meta["reason"] = "key is not exportable"
+		s.audit.Log("ExportKey", req.KeyId, "ERROR", "", meta)
+		return nil, status.Error(codes.FailedPrecondition, "key is not exportable")
+	}
+	if entry.Algorithm.IsECDSA() {
+		return nil, status.Error(codes.FailedPrecondition, "ECDSA keys cannot leave their HSM provider")
+	}
+	// As with key blocks, a symmetric wrapping key must not weaken the key
+	// it wraps. RSA keys strong enough for 256-bit keys are impractical, so
+	// uploaded keys are trusted at any of the accepted sizes.
+	if wrapping.Algorithm.IsSymmetric() && entry.Algorithm.Strength() > wrapping.Algorithm.Strength() {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v wrapping key is weaker than the %v key", wrapping.Algorithm, entry.Algorithm)
+	}
+
+	material, err := keyMaterial(entry)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v keys cannot be exported", entry.Algorithm)
+	}
+	algo, wrapped, err := wrapExport(wrapping, req.WrappingAlgorithm, material)
+	if err != nil {
+		return nil, err
+	}
+
+	meta["wrapping_algorithm"] = algo.String()
+	s.audit.Log("ExportKey", req.KeyId, "OK", "", meta)
+	return &pb.ExportKeyResponse{
+		WrappedKeyMaterial: wrapped,
+		WrappingAlgorithm:  algo,
+		Metadata:           entryToProto(entry),
+	}, nil
+}
+
+// authorize requires perm, which only the principals trusted to move key
+// material in or out of the vault are granted, and audits a refusal.
+func (s *KeyManagementServer) authorize(ctx context.Context, op, keyID, perm string, meta map[string]string) error {
+	p, _ := interceptor.PrincipalFromContext(ctx)
+	if p.HasPermission(perm) {
+		return nil
+	}
+	principalMeta(ctx, meta)
+	s.audit.Log(op, keyID, "DENIED", "", meta)
+	return status.Errorf(codes.PermissionDenied, "%s permission required", perm)
+}
+
+// exportWrappingKey loads an active AES or uploaded RSA key permitted to
+// wrap. Key block protection keys are kept to TR-31, so that what they
+// wrap always carries its key block header.
+func (s *KeyManagementServer) exportWrappingKey(id string) (*keystore.KeyEntry, error) {
+	wrapping, err := s.store.Get(id)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if wrapping.Status != keystore.StatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "wrapping key is not active")
+	}
+	switch {
+	case wrapping.Algorithm == keystore.AlgorithmAES128, wrapping.Algorithm == keystore.AlgorithmAES256:
+	case wrapping.Algorithm.IsRSA():
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "%v keys cannot wrap exported keys", wrapping.Algorithm)
+	}
+	if wrapping.Purpose == keystore.PurposeKeyBlockProtection {
+		return nil, status.Error(codes.FailedPrecondition, "key block protection keys only wrap TR-31 key blocks")
+	}
+	if err := checkPermits(wrapping, keystore.OpWrap); err != nil {
+		return nil, err
+	}
+	return wrapping, nil
+}
+
+// wrapExport wraps key material under wrapping with algo, or the default
+// scheme for the wrapping key: AES-KW for AES keys, RSA-OAEP for RSA keys.
+func wrapExport(wrapping *keystore.KeyEntry, algo pb.KeyWrapAlgorithm, material []byte) (pb.KeyWrapAlgorithm, []byte, error) {
+	if wrapping.Algorithm.IsRSA() {
+		if algo == pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_UNSPECIFIED {
+			algo = pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_OAEP
+		}
+		var wrapped []byte
+		var err error
+		switch algo {
+		case pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_OAEP:
+			// OAEP with SHA-256 fits k - 66 bytes in a k-byte RSA block.
+			if len(material) > wrapping.WrappingKey.Size()-2*32-2 {
+				return 0, nil, status.Errorf(codes.InvalidArgument, "key material is too long for %v under this key, use %v",
+					algo, pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_AES_KWP)
+			}
+			wrapped, err = crypto.WrapKeyRSAOAEP(wrapping.WrappingKey, material)
+		case pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_AES_KWP:
+			wrapped, err = crypto.WrapKeyRSAAES(wrapping.WrappingKey, material)
+		default:
+			return 0, nil, status.Errorf(codes.InvalidArgument, "%v cannot wrap under an RSA key", algo)
+		}
+		if err != nil {
+			return 0, nil, status.Errorf(codes.Internal, "wrap key: %v", err)
+		}
+		return algo, wrapped, nil
+	}
+
+	if algo == pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_UNSPECIFIED {
+		algo = pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_AES_KW
+	}
+	var wrapped []byte
+	var err error
+	switch algo {
+	case pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_AES_KW:
+		if len(material) < 16 || len(material)%8 != 0 {
+			return 0, nil, status.Errorf(codes.InvalidArgument, "%v needs key material of a multiple of 8 bytes, use %v",
+				algo, pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_AES_KWP)
+		}
+		wrapped, err = crypto.WrapKey(wrapping.SecretKey, material)
+	case pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_AES_KWP:
+		wrapped, err = crypto.WrapKeyWithPadding(wrapping.SecretKey, material)
+	default:
+		return 0, nil, status.Errorf(codes.InvalidArgument, "%v cannot wrap under an AES key", algo)
+	}
+	if err != nil {
+		return 0, nil, status.Errorf(codes.Internal, "wrap key: %v", err)
+	}
+	return algo, wrapped, nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+)
+
+func TestExportRequiresPermission(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm:  pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:    pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION,
+		Exportable: true,
+	})
+	kek := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION,
+	})
+	kbpk := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_KEY_BLOCK_PROTECTION,
+	})
+
+	exports := map[string]func(ctx context.Context) error{
+		"ExportKey": func(ctx context.Context) error {
+			_, err := ts.keys.ExportKey(ctx, &pb.ExportKeyRequest{KeyId: key.ID, WrappingKeyId: kek.ID})
+			return err
+		},
+		"ExportKeyBlock": func(ctx context.Context) error {
+			_, err := ts.keys.ExportKeyBlock(ctx, &pb.ExportKeyBlockRequest{KeyId: key.ID, KbpkKeyId: kbpk.ID})
+			return err
+		},
+		"BeginComponentExport": func(ctx context.Context) error {
+			_, err := ts.keys.BeginComponentExport(ctx, &pb.BeginComponentExportRequest{KeyId: key.ID, ComponentCount: 2})
+			return err
+		},
+	}
+	for op, export := range exports {
+		err := export(asPrincipal("default"))
+		wantCode(t, err, codes.PermissionDenied)
+		if e := waitAudit(t, ts.sub, op, "DENIED"); e.KeyID != key.ID || e.Metadata["principal"] != "default" {
+			t.Fatalf("%s: denial audited for key %q by %q", op, e.KeyID, e.Metadata["principal"])
+		}
+
+		if err := export(asPrincipal("keyadmin", interceptor.PermissionExportKeys)); err != nil {
+			t.Fatalf("%s with export-keys: %v", op, err)
+		}
+		waitAudit(t, ts.sub, op, "OK")
+	}
+}
+
+func TestExportKeyUnwraps(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm:  pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:    pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION,
+		Exportable: true,
+	})
+	kek := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION,
+	})
+
+	ctx := asPrincipal("keyadmin", interceptor.PermissionExportKeys)
+	resp, err := ts.keys.ExportKey(ctx, &pb.ExportKeyRequest{KeyId: key.ID, WrappingKeyId: kek.ID})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	got, err := crypto.UnwrapKey(kek.SecretKey, resp.WrappedKeyMaterial)
+	if err != nil {
+		t.Fatalf("unwrap: %v", err)
+	}
+	if !bytes.Equal(got, key.SecretKey) {
+		t.Fatal("unwrapped key differs from the exported key")
+	}
+	waitAudit(t, ts.sub, "ExportKey", "OK")
+
+	if _, err := ts.keys.DeactivateKey(ctx, &pb.DeactivateKeyRequest{KeyId: key.ID}); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	_, err = ts.keys.ExportKey(ctx, &pb.ExportKeyRequest{KeyId: key.ID, WrappingKeyId: kek.ID})
+	wantCode(t, err, codes.FailedPrecondition)
+	if e := waitAudit(t, ts.sub, "ExportKey", "ERROR"); e.Metadata["reason"] != "key is deactivated" {
+		t.Fatalf("deactivated export audited with reason %q", e.Metadata["reason"])
+	}
+}
+
+func TestImportWrappingKeyRequiresPermission(t *testing.T) {
+	ts := newTestServer(t)
+	_, err := ts.keys.ImportWrappingKey(asPrincipal("default"), &pb.ImportWrappingKeyRequest{})
+	wantCode(t, err, codes.PermissionDenied)
+	waitAudit(t, ts.sub, "ImportWrappingKey", "DENIED")
+
+	_, err = ts.keys.ImportWrappingKey(asPrincipal("keyadmin", interceptor.PermissionWrappingKeys), &pb.ImportWrappingKeyRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+}
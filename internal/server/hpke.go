@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// OpenHPKE decrypts a message sealed to a vault public key. HPKE
+// decapsulation is a key agreement, so the key must permit OpAgree.
+func (s *EncryptionServer) OpenHPKE(ctx context.Context, req *pb.OpenHPKERequest) (*pb.OpenHPKEResponse, error) {
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if err := checkPermits(entry, keystore.OpAgree); err != nil {
+		return nil, err
+	}
+	key, err := s.keys.agreementKey(entry)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := hpkeAEADFromProto(req.Aead)
+	if err != nil {
+		return nil, err
+	}
+
+	pt, err := crypto.OpenHPKE(key, alg, req.Info, req.Aad, req.Enc, req.Ciphertext)
+	if err != nil {
+		s.audit.Log("OpenHPKE", req.KeyId, "ERROR", "", map[string]string{"aead": alg.String()})
+		return nil, status.Error(codes.InvalidArgument, "hpke open failed")
+	}
+
+	s.audit.Log("OpenHPKE", req.KeyId, "OK", "", map[string]string{"aead": alg.String()})
+	return &pb.OpenHPKEResponse{Plaintext: pt}, nil
+}
+
+func hpkeAEADFromProto(a pb.HpkeAead) (crypto.HPKEAEAD, error) {
+	switch a {
+	case pb.HpkeAead_HPKE_AEAD_AES_128_GCM, pb.HpkeAead_HPKE_AEAD_UNSPECIFIED:
+		return crypto.HPKEAES128GCM, nil
+	case pb.HpkeAead_HPKE_AEAD_AES_256_GCM:
+		return crypto.HPKEAES256GCM, nil
+	case pb.HpkeAead_HPKE_AEAD_CHACHA20_POLY1305:
+		return crypto.HPKEChaCha20Poly1305, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported hpke aead: %v", a)
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+)
+
+// HSMAdminServer injects faults into the HSM providers started with fault
+// injection enabled.
+type HSMAdminServer struct {
+	pb.UnimplementedHSMAdminServiceServer
+	faults map[string]*hsm.FaultHSM
+	audit  *audit.Logger
+}
+
+// NewHSMAdminServer serves the fault-injecting providers keyed by provider
+// name.
+func NewHSMAdminServer(faults map[string]*hsm.FaultHSM, audit *audit.Logger) *HSMAdminServer {
+	return &HSMAdminServer{faults: faults, audit: audit}
+}
+
+func (s *HSMAdminServer) SetHSMFault(ctx context.Context, req *pb.SetHSMFaultRequest) (*pb.SetHSMFaultResponse, error) {
+	meta := map[string]string{"provider": req.Provider, "operation": operationString(req.Operation)}
+	f, err := s.faultProvider(ctx, "SetHSMFault", req.Provider, meta)
+	if err != nil {
+		return nil, err
+	}
+	op, err := operationFromProto(req.Operation)
+	if err != nil {
+		return nil, err
+	}
+	fault := faultFromProto(req.Fault)
+	if err := f.SetFault(op, fault); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	meta["latency"] = fault.Latency.String()
+	meta["error_rate"] = strconv.FormatFloat(fault.ErrorRate, 'g', -1, 64)
+	meta["corrupt_rate"] = strconv.FormatFloat(fault.CorruptRate, 'g', -1, 64)
+	meta["hang"] = strconv.FormatBool(fault.Hang)
+	s.audit.Log("SetHSMFault", "", "OK", "", meta)
+	return &pb.SetHSMFaultResponse{}, nil
+}
+
+func (s *HSMAdminServer) ClearHSMFaults(ctx context.Context, req *pb.ClearHSMFaultsRequest) (*pb.ClearHSMFaultsResponse, error) {
+	meta := map[string]string{"provider": req.Provider}
+	f, err := s.faultProvider(ctx, "ClearHSMFaults", req.Provider, meta)
+	if err != nil {
+		return nil, err
+	}
+	f.ClearFaults()
+	s.audit.Log("ClearHSMFaults", "", "OK", "", meta)
+	return &pb.ClearHSMFaultsResponse{}, nil
+}
+
+func (s *HSMAdminServer) ListHSMFaults(ctx context.Context, _ *pb.ListHSMFaultsRequest) (*pb.ListHSMFaultsResponse, error) {
+	if err := s.authorize(ctx, "ListHSMFaults", nil); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(s.faults))
+	for name := range s.faults {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	resp := &pb.ListHSMFaultsResponse{}
+	for _, name := range names {
+		pf := &pb.ProviderFaults{Provider: name}
+		faults := s.faults[name].Faults()
+		for _, op := range append([]hsm.Operation{hsm.OpAll}, hsm.Operations...) {
+			if fault, ok := faults[op]; ok {
+				pf.Faults = append(pf.Faults, &pb.OperationFault{Operation: operationToProto(op), Fault: faultToProto(fault)})
+			}
+		}
+		resp.Providers = append(resp.Providers, pf)
+	}
+	return resp, nil
+}
+
+// authorize requires the hsm-faults permission.
+func (s *HSMAdminServer) authorize(ctx context.Context, op string, meta map[string]string) error {
+	p, _ := interceptor.PrincipalFromContext(ctx)
+	if p.HasPermission(interceptor.PermissionHSMFaults) {
+		return nil
+	}
+	if p != nil {
+		if meta == nil {
+			meta = map[string]string{}
+		}
+		meta["principal"] = p.Name
+	}
+	s.audit.Log(op, "", "DENIED", "", meta)
+	return status.Error(codes.PermissionDenied, "hsm-faults permission required")
+}
+
+// faultProvider authorizes the caller and looks up a fault-injecting
+// provider.
+func (s *HSMAdminServer) faultProvider(ctx context.Context, op, name string, meta map[string]string) (*hsm.FaultHSM, error) {
+	if err := s.authorize(ctx, op, meta); err != nil {
+		return nil, err
+	}
+	f, ok := s.faults[name]
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "provider %q does not have fault injection enabled", name)
+	}
+	return f, nil
+}
+
+var operations = map[pb.HsmOperation]hsm.Operation{
+	pb.HsmOperation_HSM_OPERATION_UNSPECIFIED:  hsm.OpAll,
+	pb.HsmOperation_HSM_OPERATION_GENERATE_KEY: hsm.OpGenerateKey,
+	pb.HsmOperation_HSM_OPERATION_SIGN:         hsm.OpSign,
+	pb.HsmOperation_HSM_OPERATION_ECDH:         hsm.OpECDH,
+	pb.HsmOperation_HSM_OPERATION_ENCRYPT:      hsm.OpEncrypt,
+	pb.HsmOperation_HSM_OPERATION_DECRYPT:      hsm.OpDecrypt,
+	pb.HsmOperation_HSM_OPERATION_DERIVE:       hsm.OpDerive,
+	pb.HsmOperation_HSM_OPERATION_PROBE:        hsm.OpProbe,
+}
+
+func operationFromProto(op pb.HsmOperation) (hsm.Operation, error) {
+	o, ok := operations[op]
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unsupported hsm operation: %v", op)
+	}
+	return o, nil
+}
+
+func operationToProto(op hsm.Operation) pb.HsmOperation {
+	for p, o := range operations {
+		if o == op {
+			return p
+		}
+	}
+	return pb.HsmOperation_HSM_OPERATION_UNSPECIFIED
+}
+
+// operationString names op for the audit log.
+func operationString(op pb.HsmOperation) string {
+	o, ok := operations[op]
+	switch {
+	case !ok:
+		return op.String()
+	case o == hsm.OpAll:
+		return "all"
+	}
+	return string(o)
+}
+
+func faultFromProto(f *pb.HsmFault) hsm.Fault {
+	return hsm.Fault{
+		Latency:     f.GetLatency().AsDuration(),
+		ErrorRate:   f.GetErrorRate(),
+		CorruptRate: f.GetCorruptRate(),
+		Hang:        f.GetHang(),
+	}
+}
+
+func faultToProto(f hsm.Fault) *pb.HsmFault {
+	pf := &pb.HsmFault{ErrorRate: f.ErrorRate, CorruptRate: f.CorruptRate, Hang: f.Hang}
+	if f.Latency > 0 {
+		pf.Latency = durationpb.New(f.Latency)
+	}
+	return pf
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+)
+
+func TestHSMFaultsRequirePermission(t *testing.T) {
+	ts := newTestServer(t)
+	admin := NewHSMAdminServer(map[string]*hsm.FaultHSM{"software": hsm.NewFaultHSM(hsm.NewSoftwareHSM())}, ts.audit)
+	req := &pb.SetHSMFaultRequest{
+		Provider:  "software",
+		Operation: pb.HsmOperation_HSM_OPERATION_SIGN,
+		Fault:     &pb.HsmFault{ErrorRate: 1},
+	}
+
+	_, err := admin.SetHSMFault(asPrincipal("default"), req)
+	wantCode(t, err, codes.PermissionDenied)
+	waitAudit(t, ts.sub, "SetHSMFault", "DENIED")
+	_, err = admin.ListHSMFaults(asPrincipal("default"), &pb.ListHSMFaultsRequest{})
+	wantCode(t, err, codes.PermissionDenied)
+	_, err = admin.ClearHSMFaults(asPrincipal("default"), &pb.ClearHSMFaultsRequest{Provider: "software"})
+	wantCode(t, err, codes.PermissionDenied)
+
+	chaos := asPrincipal("chaos", interceptor.PermissionHSMFaults)
+	if _, err := admin.SetHSMFault(chaos, req); err != nil {
+		t.Fatalf("set fault: %v", err)
+	}
+	waitAudit(t, ts.sub, "SetHSMFault", "OK")
+	list, err := admin.ListHSMFaults(chaos, &pb.ListHSMFaultsRequest{})
+	if err != nil {
+		t.Fatalf("list faults: %v", err)
+	}
+	if len(list.Providers) != 1 || len(list.Providers[0].Faults) != 1 {
+		t.Fatalf("faults: got %v", list.Providers)
+	}
+
+	req.Provider = "missing"
+	_, err = admin.SetHSMFault(chaos, req)
+	wantCode(t, err, codes.FailedPrecondition)
+}
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// importTokenTTL is how long an import token and its wrapping key stay
+// valid.
+const importTokenTTL = 15 * time.Minute
+
+// Each pending import holds an RSA wrapping key that is costly to generate,
+// so there may only be a few at a time.
+const (
+	maxPendingImports             = 64
+	maxPendingImportsPerPrincipal = 8
+)
+
+var errTooManyImports = status.Error(codes.ResourceExhausted, "too many pending imports; complete them or wait for their tokens to expire")
+
+// pendingImport is a key import between GetImportParameters and ImportKey.
+type pendingImport struct {
+	// principal requested the import; only it may complete it.
+	principal string
+	// wrappingKey unwraps the key material. It is used once.
+	wrappingKey *rsa.PrivateKey
+	// key is the template of the key to import, without key material.
+	key       *keystore.KeyEntry
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+// importTokens holds pending imports by token until they are used or
+// expire, and counts them by principal.
+type importTokens struct {
+	mu          sync.Mutex
+	pending     map[string]*pendingImport
+	count       int
+	byPrincipal map[string]int
+}
+
+func newImportTokens() *importTokens {
+	return &importTokens{pending: make(map[string]*pendingImport), byPrincipal: make(map[string]int)}
+}
+
+// reserve counts an import for principal against the limits, before its
+// wrapping key is generated. The reservation is kept by add or given
+// back by release.
+func (t *importTokens) reserve(principal string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.count >= maxPendingImports || t.byPrincipal[principal] >= maxPendingImportsPerPrincipal {
+		return errTooManyImports
+	}
+	t.count++
+	t.byPrincipal[principal]++
+	return nil
+}
+
+// release gives back a reservation for principal. t.mu must not be held.
+func (t *importTokens) release(principal string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.unreserve(principal)
+}
+
+func (t *importTokens) unreserve(principal string) {
+	t.count--
+	if t.byPrincipal[principal]--; t.byPrincipal[principal] == 0 {
+		delete(t.byPrincipal, principal)
+	}
+}
+
+// add stores p under a new token, on the reservation made for
+// p.principal.
+func (t *importTokens) add(p *pendingImport, ttl time.Duration) string {
+	token := uuid.NewString()
+	p.expiresAt = time.Now().Add(ttl)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[token] = p
+	p.timer = time.AfterFunc(ttl, func() { t.take(token, p.principal) })
+	return token
+}
+
+// take removes and returns the import for token, if principal requested
+// it.
+func (t *importTokens) take(token, principal string) (*pendingImport, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.pending[token]
+	if !ok || p.principal != principal {
+		return nil, false
+	}
+	delete(t.pending, token)
+	t.unreserve(principal)
+	p.timer.Stop()
+	return p, time.Now().Before(p.expiresAt)
+}
+
+// GetImportParameters requires the import-keys permission. Keys that can
+// wrap other keys, such as KBPKs, also require wrapping-keys: a key whose
+// material the caller knows could otherwise be used to export keys and
+// unwrap them outside the vault.
+func (s *KeyManagementServer) GetImportParameters(ctx context.Context, req *pb.GetImportParametersRequest) (*pb.GetImportParametersResponse, error) {
+	if err := s.authorize(ctx, "GetImportParameters", "", interceptor.PermissionImportKeys, map[string]string{}); err != nil {
+		return nil, err
+	}
+	if req.Algorithm == pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "algorithm is required")
+	}
+	algo, err := algoFromProto(req.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if algo.IsRSA() {
+		return nil, status.Errorf(codes.InvalidArgument, "%v keys are uploaded with ImportWrappingKey", algo)
+	}
+	purpose := purposeFromProto(req.Purpose)
+	if err := checkPurpose(algo, purpose); err != nil {
+		return nil, err
+	}
+	key := &keystore.KeyEntry{
+		Algorithm:  algo,
+		Purpose:    purpose,
+		Mode:       modeFromProto(req.ModeOfUse),
+		Exportable: req.Exportable,
+		Labels:     req.Labels,
+	}
+	if algo.IsSymmetric() && (key.Permits(keystore.OpWrap) || key.Permits(keystore.OpUnwrap)) {
+		meta := map[string]string{"algorithm": algo.String(), "purpose": purpose.String()}
+		if err := s.authorize(ctx, "GetImportParameters", "", interceptor.PermissionWrappingKeys, meta); err != nil {
+			return nil, err
+		}
+	}
+	if algo.IsECDSA() {
+		key.Provider = req.Provider
+		if key.Provider == "" {
+			key.Provider = s.providers.Default()
+		}
+		p, err := s.providers.Get(key.Provider)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		if c, err := hsm.ProviderCapabilities(p); err == nil && !c.KeyImport {
+			return nil, status.Errorf(codes.FailedPrecondition, "hsm provider %q cannot import keys", key.Provider)
+		}
+	}
+
+	principal := principalName(ctx)
+	if err := s.imports.reserve(principal); err != nil {
+		s.audit.Log("GetImportParameters", "", "ERROR", "", principalMeta(ctx, map[string]string{"reason": "too many pending imports"}))
+		return nil, err
+	}
+	wrappingKey, err := crypto.GenerateWrappingKey()
+	if err != nil {
+		s.imports.release(principal)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	der, err := crypto.MarshalPublicKey(&wrappingKey.PublicKey)
+	if err != nil {
+		s.imports.release(principal)
+		return nil, status.Errorf(codes.Internal, "marshal wrapping key: %v", err)
+	}
+	p := &pendingImport{principal: principal, wrappingKey: wrappingKey, key: key}
+	token := s.imports.add(p, importTokenTTL)
+
+	s.audit.Log("GetImportParameters", "", "OK", "", importMeta(ctx, key))
+	return &pb.GetImportParametersResponse{
+		ImportToken:          token,
+		WrappingPublicKeyDer: der,
+		WrappingAlgorithm:    pb.KeyWrapAlgorithm_KEY_WRAP_ALGORITHM_RSA_AES_KWP,
+		ExpiresAt:            timestamppb.New(p.expiresAt),
+	}, nil
+}
+
+func (s *KeyManagementServer) ImportKey(ctx context.Context, req *pb.ImportKeyRequest) (*pb.ImportKeyResponse, error) {
+	p, ok := s.imports.take(req.ImportToken, principalName(ctx))
+	if !ok {
+		return nil, status.Error(codes.NotFound, "import token not found or expired")
+	}
+	meta := importMeta(ctx, p.key)
+
+	material, err := crypto.UnwrapKeyRSAAES(p.wrappingKey, req.WrappedKeyMaterial)
+	if err != nil {
+		meta["reason"] = "unwrap failed"
+		s.audit.Log("ImportKey", "", "ERROR", "", meta)
+		return nil, status.Error(codes.InvalidArgument, "key material failed to unwrap")
+	}
+	defer clear(material)
+
+	entry := *p.key
+	entry.ID = uuid.NewString()
+	entry.Status = keystore.StatusActive
+	entry.Origin = keystore.OriginImported
+	entry.CreatedAt = time.Now()
+	if err := s.setImportedKey(&entry, material); err != nil {
+		meta["reason"] = status.Convert(err).Message()
+		s.audit.Log("ImportKey", "", "ERROR", "", meta)
+		return nil, err
+	}
+	if err := s.store.Put(&entry); err != nil {
+		return nil, status.Errorf(codes.Internal, "store key: %v", err)
+	}
+
+	resp := entryToProto(&entry)
+	s.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_CREATED, resp)
+	s.audit.Log("ImportKey", entry.ID, "OK", "", meta)
+	return &pb.ImportKeyResponse{Metadata: resp}, nil
+}
+
+// setImportedKey checks key material against entry's algorithm and sets
+// it on entry. ECDSA private keys are imported into entry's provider.
+func (s *KeyManagementServer) setImportedKey(entry *keystore.KeyEntry, material []byte) error {
+	algo := entry.Algorithm
+	switch {
+	case algo.IsSymmetric():
+		if want := secretSize(algo); len(material) != want {
+			return status.Errorf(codes.InvalidArgument, "%v key material must be %d bytes, got %d", algo, want, len(material))
+		}
+		entry.SecretKey = bytes.Clone(material)
+	case algo == keystore.AlgorithmX25519:
+		key, err := crypto.UnmarshalAgreementKey(material)
+		if err != nil || key.Curve() != ecdh.X25519() {
+			return status.Error(codes.InvalidArgument, "key material is not a PKCS#8 X25519 private key")
+		}
+		entry.AgreementKey = key
+	case algo.KEM() != 0:
+		key, err := crypto.NewKEMPrivateKey(algo.KEM(), material)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid key material: %v", err)
+		}
+		entry.KEMKey = key
+	case algo.IsECDSA():
+		key, err := crypto.UnmarshalPrivateKey(material)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "key material is not a PKCS#8 ECDSA private key")
+		}
+		if want := curveFor(algo); key.Curve != want {
+			return status.Errorf(codes.InvalidArgument, "key is on curve %s, %v keys are on %s",
+				key.Curve.Params().Name, algo, want.Params().Name)
+		}
+		p, err := s.providers.Get(entry.Provider)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		handle, err := hsm.ImportKey(p, entry.ID, key)
+		if err != nil {
+			if errors.Is(err, hsm.ErrUnsupported) {
+				return status.Errorf(codes.FailedPrecondition, "hsm provider %q cannot import keys", entry.Provider)
+			}
+			return hsmError(codes.Internal, "import key", err)
+		}
+		entry.Handle = handle
+		entry.PublicKey = &key.PublicKey
+	default:
+		return status.Errorf(codes.InvalidArgument, "%v keys cannot be imported", algo)
+	}
+	return nil
+}
+
+// importMeta returns the audit metadata of a key import.
+func importMeta(ctx context.Context, key *keystore.KeyEntry) map[string]string {
+	meta := map[string]string{"algorithm": key.Algorithm.String()}
+	if key.Provider != "" {
+		meta["provider"] = key.Provider
+	}
+	return principalMeta(ctx, meta)
+}
+
+// principalName returns the name of the calling principal, or "" for an
+// unauthenticated call.
+func principalName(ctx context.Context) string {
+	if p, ok := interceptor.PrincipalFromContext(ctx); ok {
+		return p.Name
+	}
+	return ""
+}
+
+// principalMeta adds the calling principal to audit metadata.
+func principalMeta(ctx context.Context, meta map[string]string) map[string]string {
+	if p, ok := interceptor.PrincipalFromContext(ctx); ok {
+		meta["principal"] = p.Name
+	}
+	return meta
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+func TestImportKeyPermissions(t *testing.T) {
+	ts := newTestServer(t)
+	dataKey := &pb.GetImportParametersRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION,
+	}
+	_, err := ts.keys.GetImportParameters(asPrincipal("default"), dataKey)
+	wantCode(t, err, codes.PermissionDenied)
+	waitAudit(t, ts.sub, "GetImportParameters", "DENIED")
+
+	loader := asPrincipal("loader", interceptor.PermissionImportKeys)
+	params, err := ts.keys.GetImportParameters(loader, dataKey)
+	if err != nil {
+		t.Fatalf("get import parameters: %v", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(params.WrappingPublicKeyDer)
+	if err != nil {
+		t.Fatalf("parse wrapping key: %v", err)
+	}
+	material := bytes.Repeat([]byte{0x42}, 32)
+	wrapped, err := crypto.WrapKeyRSAAES(parsed.(*rsa.PublicKey), material)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	req := &pb.ImportKeyRequest{ImportToken: params.ImportToken, WrappedKeyMaterial: wrapped}
+
+	// The token is only good for the principal it was issued to.
+	_, err = ts.keys.ImportKey(asPrincipal("other", interceptor.PermissionImportKeys), req)
+	wantCode(t, err, codes.NotFound)
+	resp, err := ts.keys.ImportKey(loader, req)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	entry, err := ts.store.Get(resp.Metadata.KeyId)
+	if err != nil {
+		t.Fatalf("get imported key: %v", err)
+	}
+	if !bytes.Equal(entry.SecretKey, material) || entry.Origin != keystore.OriginImported {
+		t.Fatal("imported key does not carry the imported material")
+	}
+}
+
+func TestImportWrappingKeysRequiresPermission(t *testing.T) {
+	ts := newTestServer(t)
+	loader := asPrincipal("loader", interceptor.PermissionImportKeys)
+	for _, req := range []*pb.GetImportParametersRequest{
+		{Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256, Purpose: pb.KeyPurpose_KEY_PURPOSE_KEY_BLOCK_PROTECTION},
+		{Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256, Purpose: pb.KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION},
+		// Keys without a purpose may wrap too.
+		{Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_128},
+	} {
+		_, err := ts.keys.GetImportParameters(loader, req)
+		wantCode(t, err, codes.PermissionDenied)
+		if e := waitAudit(t, ts.sub, "GetImportParameters", "DENIED"); e.Metadata["principal"] != "loader" {
+			t.Fatalf("denial audited for %q", e.Metadata["principal"])
+		}
+	}
+
+	keyAdmin := asPrincipal("keyadmin", interceptor.PermissionImportKeys, interceptor.PermissionWrappingKeys)
+	if _, err := ts.keys.GetImportParameters(keyAdmin, &pb.GetImportParametersRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_KEY_BLOCK_PROTECTION,
+	}); err != nil {
+		t.Fatalf("import kbpk with wrapping-keys: %v", err)
+	}
+}
+
+func TestImportTokenLimits(t *testing.T) {
+	tokens := newImportTokens()
+	for range maxPendingImportsPerPrincipal {
+		if err := tokens.reserve("a"); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+	}
+	if err := tokens.reserve("a"); !errors.Is(err, errTooManyImports) {
+		t.Fatalf("reserve past the principal limit: got %v", err)
+	}
+
+	// A used token frees its principal's place.
+	token := tokens.add(&pendingImport{principal: "a"}, time.Minute)
+	if _, ok := tokens.take(token, "a"); !ok {
+		t.Fatal("take should return the pending import")
+	}
+	if err := tokens.reserve("a"); err != nil {
+		t.Fatalf("reserve after take: %v", err)
+	}
+
+	// An expired one does too.
+	tokens.add(&pendingImport{principal: "a"}, time.Millisecond)
+	deadline := time.Now().Add(5 * time.Second)
+	for tokens.reserve("a") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expired import should free its place")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := maxPendingImportsPerPrincipal; i < maxPendingImports; i++ {
+		if err := tokens.reserve(fmt.Sprintf("p%d", i)); err != nil {
+			t.Fatalf("reserve %d: %v", i, err)
+		}
+	}
+	if err := tokens.reserve("z"); !errors.Is(err, errTooManyImports) {
+		t.Fatalf("reserve past the overall limit: got %v", err)
+	}
+	tokens.release("a")
+	if err := tokens.reserve("z"); err != nil {
+		t.Fatalf("reserve after release: %v", err)
+	}
+}
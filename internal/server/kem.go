@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+func (s *KeyManagementServer) Encapsulate(ctx context.Context, req *pb.EncapsulateRequest) (*pb.EncapsulateResponse, error) {
+	var alg crypto.KEMAlgorithm
+	var ek []byte
+	if req.KeyId != "" {
+		entry, err := s.kemKey(req.KeyId, true)
+		if err != nil {
+			return nil, err
+		}
+		alg, ek = entry.KEMKey.Algorithm(), entry.KEMKey.EncapsulationKey()
+	} else {
+		algo, err := algoFromProto(req.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		if alg = algo.KEM(); alg == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "%v is not a KEM algorithm", algo)
+		}
+		ek = req.EncapsulationKey
+	}
+
+	secret, ct, err := crypto.Encapsulate(alg, ek)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	defer clear(secret)
+
+	out, derived, meta, err := s.returnSecret(secret, secretOutput{
+		output:    req.Output,
+		kdf:       req.KdfParams,
+		algorithm: req.DerivedKeyAlgorithm,
+		purpose:   req.DerivedKeyPurpose,
+		labels:    req.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	meta["kem"] = alg.String()
+	s.audit.Log("Encapsulate", req.KeyId, "OK", "", meta)
+	return &pb.EncapsulateResponse{Ciphertext: ct, SharedSecret: out, DerivedKey: derived}, nil
+}
+
+func (s *KeyManagementServer) Decapsulate(ctx context.Context, req *pb.DecapsulateRequest) (*pb.DecapsulateResponse, error) {
+	entry, err := s.kemKey(req.KeyId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := entry.KEMKey.Decapsulate(req.Ciphertext)
+	if err != nil {
+		s.audit.Log("Decapsulate", req.KeyId, "ERROR", "", nil)
+		return nil, status.Errorf(codes.InvalidArgument, "decapsulate: %v", err)
+	}
+	defer clear(secret)
+
+	out, derived, meta, err := s.returnSecret(secret, secretOutput{
+		output:    req.Output,
+		kdf:       req.KdfParams,
+		algorithm: req.DerivedKeyAlgorithm,
+		purpose:   req.DerivedKeyPurpose,
+		labels:    req.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Log("Decapsulate", req.KeyId, "OK", "", meta)
+	return &pb.DecapsulateResponse{SharedSecret: out, DerivedKey: derived}, nil
+}
+
+// kemKey loads a KEM key permitted for key establishment. New secrets are
+// only encapsulated to active keys; rotated keys can still decapsulate.
+func (s *KeyManagementServer) kemKey(id string, encapsulate bool) (*keystore.KeyEntry, error) {
+	entry, err := s.store.Get(id)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if encapsulate && entry.Status != keystore.StatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "key is not active")
+	}
+	if entry.KEMKey == nil {
+		return nil, status.Error(codes.FailedPrecondition, "key is not a KEM key")
+	}
+	if err := checkPermits(entry, keystore.OpAgree); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keyblock"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// ImportKeyBlock unwraps a TR-31 key block under a vault-held KBPK and stores
+// the key with the usage, mode of use and exportability from its header.
+func (s *KeyManagementServer) ImportKeyBlock(ctx context.Context, req *pb.ImportKeyBlockRequest) (*pb.ImportKeyBlockResponse, error) {
+	kbpk, version, err := s.keyBlockProtectionKey(req.KbpkKeyId, keystore.OpUnwrap)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.KeyBlock) == 0 || req.KeyBlock[0] != version {
+		return nil, status.Errorf(codes.InvalidArgument, "key block version must be %c for a %v kbpk", version, kbpk.Algorithm)
+	}
+
+	h, key, err := keyblock.Unwrap(kbpk.SecretKey, req.KeyBlock)
+	if err != nil {
+		s.audit.Log("ImportKeyBlock", "", "ERROR", "", map[string]string{"kbpk_key_id": req.KbpkKeyId})
+		if errors.Is(err, keyblock.ErrAuthentication) {
+			return nil, status.Error(codes.InvalidArgument, "key block authentication failed")
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key block: %v", err)
+	}
+
+	attrs, err := h.Attributes(len(key))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key block header: %v", err)
+	}
+
+	entry := &keystore.KeyEntry{
+		ID:         uuid.NewString(),
+		Algorithm:  attrs.Algorithm,
+		Status:     keystore.StatusActive,
+		SecretKey:  key,
+		Purpose:    attrs.Purpose,
+		Mode:       attrs.Mode,
+		Exportable: attrs.Exportable,
+		Origin:     keystore.OriginImported,
+		CreatedAt:  time.Now(),
+		Labels:     req.Labels,
+	}
+	if err := s.store.Put(entry); err != nil {
+		return nil, status.Errorf(codes.Internal, "store key: %v", err)
+	}
+
+	meta := entryToProto(entry)
+	s.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_CREATED, meta)
+	s.audit.Log("ImportKeyBlock", entry.ID, "OK", "", map[string]string{
+		"kbpk_key_id": req.KbpkKeyId,
+		"key_usage":   h.KeyUsage,
+	})
+
+	return &pb.ImportKeyBlockResponse{Metadata: meta}, nil
+}
+
+// ExportKeyBlock wraps an exportable symmetric key in a TR-31 key block under
+// a vault-held KBPK. Keys cannot be exported under a weaker KBPK.
+func (s *KeyManagementServer) ExportKeyBlock(ctx context.Context, req *pb.ExportKeyBlockRequest) (*pb.ExportKeyBlockResponse, error) {
+	meta := map[string]string{"kbpk_key_id": req.KbpkKeyId}
+	if err := s.authorize(ctx, "ExportKeyBlock", req.KeyId, interceptor.PermissionExportKeys, meta); err != nil {
+		return nil, err
+	}
+	if req.KeyId == req.KbpkKeyId {
+		return nil, status.Error(codes.InvalidArgument, "a key cannot be exported under itself")
+	}
+	kbpk, version, err := s.keyBlockProtectionKey(req.KbpkKeyId, keystore.OpWrap)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if entry.Status == keystore.StatusDeactivated {
+		return nil, status.Error(codes.FailedPrecondition, "key is deactivated")
+	}
+	if !entry.Exportable {
+		return nil, status.Error(codes.FailedPrecondition, "key is not exportable")
+	}
+	if entry.Algorithm.Strength() > kbpk.Algorithm.Strength() {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v kbpk is weaker than the %v key", kbpk.Algorithm, entry.Algorithm)
+	}
+
+	h, err := keyblock.HeaderFor(entry, version)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "key cannot be exported as a key block: %v", err)
+	}
+	block, err := keyblock.Wrap(kbpk.SecretKey, h, entry.SecretKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "wrap key block: %v", err)
+	}
+
+	s.audit.Log("ExportKeyBlock", req.KeyId, "OK", "", map[string]string{
+		"kbpk_key_id": req.KbpkKeyId,
+		"key_usage":   h.KeyUsage,
+	})
+	return &pb.ExportKeyBlockResponse{KeyBlock: block}, nil
+}
+
+// keyBlockProtectionKey loads an active KBPK permitted to perform op and
+// returns the key block version matching its algorithm.
+func (s *KeyManagementServer) keyBlockProtectionKey(id string, op keystore.KeyOperation) (*keystore.KeyEntry, byte, error) {
+	kbpk, err := s.store.Get(id)
+	if err != nil {
+		return nil, 0, keyError(err)
+	}
+	if kbpk.Status != keystore.StatusActive {
+		return nil, 0, status.Error(codes.FailedPrecondition, "kbpk is not active")
+	}
+
+	var version byte
+	switch kbpk.Algorithm {
+	case keystore.AlgorithmTDEA2Key, keystore.AlgorithmTDEA3Key:
+		version = keyblock.VersionB
+	case keystore.AlgorithmAES128, keystore.AlgorithmAES256:
+		version = keyblock.VersionD
+	default:
+		return nil, 0, status.Errorf(codes.FailedPrecondition, "%v keys cannot protect key blocks", kbpk.Algorithm)
+	}
+	if err := checkPermits(kbpk, op); err != nil {
+		return nil, 0, err
+	}
+	return kbpk, version, nil
+}
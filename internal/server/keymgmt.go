@@ -3,6 +3,9 @@ package server
 import (
 	"context"
 	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 
 	pb "github.com/glinharesb/vault-go/gen/vault/v1"
 	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/ceremony"
 	"github.com/glinharesb/vault-go/internal/crypto"
 	"github.com/glinharesb/vault-go/internal/hsm"
 	"github.com/glinharesb/vault-go/internal/keystore"
@@ -21,41 +25,46 @@ import (
 
 type KeyManagementServer struct {
 	pb.UnimplementedKeyManagementServiceServer
-	store keystore.Store
-	hsm   hsm.Provider
-	audit *audit.Logger
+	store     keystore.Store
+	providers *hsm.Registry
+	audit     *audit.Logger
 
 	mu          sync.RWMutex
 	subscribers []chan *pb.KeyEvent
+
+	ceremonies *ceremony.Manager
+	imports    *importTokens
 }
 
-func NewKeyManagementServer(store keystore.Store, h hsm.Provider, a *audit.Logger) *KeyManagementServer {
-	return &KeyManagementServer{
-		store: store,
-		hsm:   h,
-		audit: a,
+func NewKeyManagementServer(store keystore.Store, providers *hsm.Registry, a *audit.Logger) *KeyManagementServer {
+	s := &KeyManagementServer{
+		store:     store,
+		providers: providers,
+		audit:     a,
+		imports:   newImportTokens(),
 	}
+	s.ceremonies = ceremony.NewManager(s.ceremonyExpired)
+	return s
 }
 
 func (s *KeyManagementServer) GenerateKey(ctx context.Context, req *pb.GenerateKeyRequest) (*pb.GenerateKeyResponse, error) {
-	curve, algo, err := resolveCurve(req.Algorithm)
+	algo, err := algoFromProto(req.Algorithm)
 	if err != nil {
 		return nil, err
 	}
 
-	key, err := s.hsm.GenerateKey(curve)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "generate key: %v", err)
+	purpose := purposeFromProto(req.Purpose)
+	if err := checkPurpose(algo, purpose); err != nil {
+		return nil, err
 	}
 
-	entry := &keystore.KeyEntry{
-		ID:         uuid.NewString(),
-		Algorithm:  algo,
-		Status:     keystore.StatusActive,
-		PrivateKey: key,
-		CreatedAt:  time.Now(),
-		Labels:     req.Labels,
+	entry, err := s.newEntry(algo, req.Labels, req.Provider)
+	if err != nil {
+		return nil, err
 	}
+	entry.Purpose = purpose
+	entry.Mode = modeFromProto(req.ModeOfUse)
+	entry.Exportable = req.Exportable
 
 	if err := s.store.Put(entry); err != nil {
 		return nil, status.Errorf(codes.Internal, "store key: %v", err)
@@ -73,8 +82,19 @@ func (s *KeyManagementServer) GetPublicKey(ctx context.Context, req *pb.GetPubli
 	if err != nil {
 		return nil, keyError(err)
 	}
+	if entry.Algorithm.IsSymmetric() {
+		return nil, status.Error(codes.FailedPrecondition, "symmetric keys have no public key")
+	}
 
-	der, err := crypto.MarshalPublicKey(&entry.PrivateKey.PublicKey)
+	if entry.KEMKey != nil {
+		return &pb.GetPublicKeyResponse{
+			KeyId:            entry.ID,
+			Algorithm:        algoToProto(entry.Algorithm),
+			EncapsulationKey: entry.KEMKey.EncapsulationKey(),
+		}, nil
+	}
+
+	der, err := marshalPublicKey(entry)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "marshal public key: %v", err)
 	}
@@ -95,6 +115,9 @@ func (s *KeyManagementServer) ListKeys(ctx context.Context, req *pb.ListKeysRequ
 
 	var keys []*pb.KeyMetadata
 	for _, e := range entries {
+		if req.DerivationPathPrefix != "" && (e.DerivationPath == "" || !hasPathPrefix(e.DerivationPath, req.DerivationPathPrefix)) {
+			continue
+		}
 		keys = append(keys, entryToProto(e))
 	}
 	return &pb.ListKeysResponse{Keys: keys}, nil
@@ -108,26 +131,21 @@ func (s *KeyManagementServer) RotateKey(ctx context.Context, req *pb.RotateKeyRe
 	if old.Status != keystore.StatusActive {
 		return nil, status.Error(codes.FailedPrecondition, "can only rotate active keys")
 	}
-
-	// Generate new key with same algorithm
-	curve, _, err := resolveCurve(algoToProto(old.Algorithm))
-	if err != nil {
+	if old.DerivationPath != "" {
+		return nil, keyError(keystore.ErrDerivedKey)
+	}
+	if err := checkUserManaged(old); err != nil {
 		return nil, err
 	}
 
-	newKey, err := s.hsm.GenerateKey(curve)
+	// Generate new key with same algorithm
+	newEntry, err := s.newEntry(old.Algorithm, old.Labels, old.Provider)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "generate key: %v", err)
-	}
-
-	newEntry := &keystore.KeyEntry{
-		ID:         uuid.NewString(),
-		Algorithm:  old.Algorithm,
-		Status:     keystore.StatusActive,
-		PrivateKey: newKey,
-		CreatedAt:  time.Now(),
-		Labels:     old.Labels,
+		return nil, err
 	}
+	newEntry.Purpose = old.Purpose
+	newEntry.Mode = old.Mode
+	newEntry.Exportable = old.Exportable
 
 	if err := s.store.UpdateStatus(req.KeyId, keystore.StatusRotated); err != nil {
 		return nil, status.Errorf(codes.Internal, "update old key: %v", err)
@@ -148,11 +166,17 @@ func (s *KeyManagementServer) RotateKey(ctx context.Context, req *pb.RotateKeyRe
 }
 
 func (s *KeyManagementServer) DeactivateKey(ctx context.Context, req *pb.DeactivateKeyRequest) (*pb.DeactivateKeyResponse, error) {
+	if entry, err := s.store.Get(req.KeyId); err == nil {
+		if err := checkUserManaged(entry); err != nil {
+			return nil, err
+		}
+	}
 	if err := s.store.UpdateStatus(req.KeyId, keystore.StatusDeactivated); err != nil {
 		return nil, keyError(err)
 	}
 
 	entry, _ := s.store.Get(req.KeyId)
+	s.deactivateHSMKey(entry)
 	meta := entryToProto(entry)
 	s.broadcastEvent(pb.KeyEventType_KEY_EVENT_TYPE_DEACTIVATED, meta)
 	s.audit.Log("DeactivateKey", req.KeyId, "OK", "", nil)
@@ -210,24 +234,232 @@ func (s *KeyManagementServer) broadcastEvent(eventType pb.KeyEventType, meta *pb
 
 // helpers
 
-func resolveCurve(algo pb.KeyAlgorithm) (elliptic.Curve, keystore.KeyAlgorithm, error) {
+// newEntry generates key material for algo. ECDSA key pairs come from the
+// named HSM provider, or the default one; X25519, KEM and symmetric keys
+// are generated in software. RSA keys cannot be generated.
+func (s *KeyManagementServer) newEntry(algo keystore.KeyAlgorithm, labels map[string]string, provider string) (*keystore.KeyEntry, error) {
+	if algo.IsRSA() {
+		return nil, status.Errorf(codes.InvalidArgument, "%v keys cannot be generated, only uploaded with ImportWrappingKey", algo)
+	}
+	entry := &keystore.KeyEntry{
+		ID:        uuid.NewString(),
+		Algorithm: algo,
+		Status:    keystore.StatusActive,
+		CreatedAt: time.Now(),
+		Labels:    labels,
+	}
+
+	if algo.IsSymmetric() {
+		secret, err := generateSecret(algo)
+		if err != nil {
+			return nil, hsmError(codes.Internal, "generate key", err)
+		}
+		entry.SecretKey = secret
+		return entry, nil
+	}
+	if algo == keystore.AlgorithmX25519 {
+		key, err := crypto.GenerateX25519Key()
+		if err != nil {
+			return nil, hsmError(codes.Internal, "generate key", err)
+		}
+		entry.AgreementKey = key
+		return entry, nil
+	}
+	if kem := algo.KEM(); kem != 0 {
+		key, err := crypto.GenerateKEMKey(kem)
+		if err != nil {
+			return nil, hsmError(codes.Internal, "generate key", err)
+		}
+		entry.KEMKey = key
+		return entry, nil
+	}
+
+	if provider == "" {
+		provider = s.providers.Default()
+	}
+	p, err := s.providers.Get(provider)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	handle, pub, err := p.GenerateKey(entry.ID, curveFor(algo))
+	if err != nil {
+		return nil, hsmError(codes.Internal, "generate key", err)
+	}
+	entry.Handle = handle
+	entry.Provider = provider
+	entry.PublicKey = pub
+	return entry, nil
+}
+
+// marshalPublicKey encodes the public half of an ECDSA, X25519 or RSA key.
+func marshalPublicKey(entry *keystore.KeyEntry) ([]byte, error) {
+	if entry.AgreementKey != nil {
+		return crypto.MarshalPublicKey(entry.AgreementKey.PublicKey())
+	}
+	if entry.WrappingKey != nil {
+		return crypto.MarshalPublicKey(entry.WrappingKey)
+	}
+	return crypto.MarshalPublicKey(entry.PublicKey)
+}
+
+func algoFromProto(algo pb.KeyAlgorithm) (keystore.KeyAlgorithm, error) {
 	switch algo {
 	case pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256, pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED:
-		return elliptic.P256(), keystore.AlgorithmECDSAP256, nil
+		return keystore.AlgorithmECDSAP256, nil
 	case pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P384:
-		return elliptic.P384(), keystore.AlgorithmECDSAP384, nil
+		return keystore.AlgorithmECDSAP384, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_2KEY:
+		return keystore.AlgorithmTDEA2Key, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_3KEY:
+		return keystore.AlgorithmTDEA3Key, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_AES_128:
+		return keystore.AlgorithmAES128, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_AES_256:
+		return keystore.AlgorithmAES256, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_HMAC_SHA256:
+		return keystore.AlgorithmHMACSHA256, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_HMAC_SHA512:
+		return keystore.AlgorithmHMACSHA512, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_FPE_AES_256:
+		return keystore.AlgorithmFPEAES256, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_X25519:
+		return keystore.AlgorithmX25519, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_ML_KEM_768:
+		return keystore.AlgorithmMLKEM768, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_ML_KEM_1024:
+		return keystore.AlgorithmMLKEM1024, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_ML_KEM_768_X25519:
+		return keystore.AlgorithmMLKEM768X25519, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_CHACHA20_POLY1305:
+		return keystore.AlgorithmChaCha20Poly1305, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_XCHACHA20_POLY1305:
+		return keystore.AlgorithmXChaCha20Poly1305, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_AES_SIV:
+		return keystore.AlgorithmAESSIV, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_RSA_2048:
+		return keystore.AlgorithmRSA2048, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_RSA_3072:
+		return keystore.AlgorithmRSA3072, nil
+	case pb.KeyAlgorithm_KEY_ALGORITHM_RSA_4096:
+		return keystore.AlgorithmRSA4096, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported algorithm: %v", algo)
+	}
+}
+
+func curveFor(algo keystore.KeyAlgorithm) elliptic.Curve {
+	if algo == keystore.AlgorithmECDSAP384 {
+		return elliptic.P384()
+	}
+	return elliptic.P256()
+}
+
+// generateSecret creates random key material sized for a symmetric algorithm.
+func generateSecret(algo keystore.KeyAlgorithm) ([]byte, error) {
+	switch algo {
+	case keystore.AlgorithmTDEA2Key:
+		return crypto.GenerateTDEAKey(16)
+	case keystore.AlgorithmTDEA3Key:
+		return crypto.GenerateTDEAKey(24)
+	case keystore.AlgorithmAES128:
+		return crypto.GenerateSymmetricKey(16)
+	case keystore.AlgorithmAES256, keystore.AlgorithmFPEAES256:
+		return crypto.GenerateAESKey()
+	case keystore.AlgorithmChaCha20Poly1305, keystore.AlgorithmXChaCha20Poly1305:
+		return crypto.GenerateSymmetricKey(32)
+	case keystore.AlgorithmHMACSHA256:
+		return crypto.GenerateSymmetricKey(32)
+	case keystore.AlgorithmHMACSHA512, keystore.AlgorithmAESSIV:
+		return crypto.GenerateSymmetricKey(64)
 	default:
-		return nil, 0, status.Errorf(codes.InvalidArgument, "unsupported algorithm: %v", algo)
+		return nil, fmt.Errorf("not a symmetric algorithm: %v", algo)
 	}
 }
 
+// secretSize returns the key length in bytes of a symmetric algorithm, or 0
+// for key pairs.
+func secretSize(algo keystore.KeyAlgorithm) int {
+	switch algo {
+	case keystore.AlgorithmTDEA2Key, keystore.AlgorithmAES128:
+		return 16
+	case keystore.AlgorithmTDEA3Key:
+		return 24
+	case keystore.AlgorithmAES256, keystore.AlgorithmFPEAES256, keystore.AlgorithmHMACSHA256,
+		keystore.AlgorithmChaCha20Poly1305, keystore.AlgorithmXChaCha20Poly1305:
+		return 32
+	case keystore.AlgorithmHMACSHA512, keystore.AlgorithmAESSIV:
+		return 64
+	default:
+		return 0
+	}
+}
+
+// checkPurpose rejects purposes that cannot apply to the key type. AES-SIV
+// keys must be created with the deterministic encryption purpose so they
+// can never reach the randomized encryption paths. Token fingerprint keys
+// are only created by the tokenization service.
+func checkPurpose(algo keystore.KeyAlgorithm, purpose keystore.KeyPurpose) error {
+	if algo == keystore.AlgorithmAESSIV && purpose != keystore.PurposeDeterministicEncryption {
+		return status.Errorf(codes.InvalidArgument, "%v keys require the %v purpose", algo, keystore.PurposeDeterministicEncryption)
+	}
+	if purpose == keystore.PurposeAny {
+		return nil
+	}
+	var ok bool
+	switch purpose {
+	case keystore.PurposeSigning:
+		ok = algo.IsECDSA()
+	case keystore.PurposeKeyAgreement:
+		ok = !algo.IsSymmetric()
+	case keystore.PurposeDeterministicEncryption:
+		ok = algo == keystore.AlgorithmAESSIV
+	case keystore.PurposeTokenFingerprint:
+		return status.Errorf(codes.InvalidArgument, "purpose %v is reserved for the tokenization service", purpose)
+	default:
+		ok = algo.IsSymmetric()
+	}
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "purpose %v cannot be used with %v keys", purpose, algo)
+	}
+	return nil
+}
+
+// checkUserManaged refuses lifecycle changes to keys a service manages
+// itself. A domain's token fingerprint key must stay the same for as long
+// as its records do, or the same PAN would get a second token.
+func checkUserManaged(entry *keystore.KeyEntry) error {
+	if entry.Purpose == keystore.PurposeTokenFingerprint {
+		return status.Errorf(codes.FailedPrecondition, "%v keys are managed by the tokenization service", entry.Purpose)
+	}
+	return nil
+}
+
+// checkPermits returns FailedPrecondition when the key's purpose or mode of
+// use does not allow op.
+func checkPermits(entry *keystore.KeyEntry, op keystore.KeyOperation) error {
+	if !entry.Permits(op) {
+		return status.Errorf(codes.FailedPrecondition, "key purpose %v with mode %v does not permit this operation", entry.Purpose, entry.Mode)
+	}
+	return nil
+}
+
 func entryToProto(e *keystore.KeyEntry) *pb.KeyMetadata {
 	meta := &pb.KeyMetadata{
-		KeyId:     e.ID,
-		Algorithm: algoToProto(e.Algorithm),
-		Status:    statusToProto(e.Status),
-		CreatedAt: timestamppb.New(e.CreatedAt),
-		Labels:    e.Labels,
+		KeyId:      e.ID,
+		Algorithm:  algoToProto(e.Algorithm),
+		Status:     statusToProto(e.Status),
+		CreatedAt:  timestamppb.New(e.CreatedAt),
+		Labels:     e.Labels,
+		Purpose:    purposeToProto(e.Purpose),
+		ModeOfUse:  modeToProto(e.Mode),
+		Exportable: e.Exportable,
+
+		EncryptionCount: e.Encryptions,
+		EncryptionLimit: nonceLimit(e),
+		ParentKeyId:     e.ParentID,
+		DerivationPath:  e.DerivationPath,
+		Provider:        e.Provider,
+		Origin:          originToProto(e.Origin),
 	}
 	if !e.RotatedAt.IsZero() {
 		meta.RotatedAt = timestamppb.New(e.RotatedAt)
@@ -241,6 +473,40 @@ func algoToProto(a keystore.KeyAlgorithm) pb.KeyAlgorithm {
 		return pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P256
 	case keystore.AlgorithmECDSAP384:
 		return pb.KeyAlgorithm_KEY_ALGORITHM_ECDSA_P384
+	case keystore.AlgorithmTDEA2Key:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_2KEY
+	case keystore.AlgorithmTDEA3Key:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_TDEA_3KEY
+	case keystore.AlgorithmAES128:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_AES_128
+	case keystore.AlgorithmAES256:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_AES_256
+	case keystore.AlgorithmHMACSHA256:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_HMAC_SHA256
+	case keystore.AlgorithmHMACSHA512:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_HMAC_SHA512
+	case keystore.AlgorithmFPEAES256:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_FPE_AES_256
+	case keystore.AlgorithmX25519:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_X25519
+	case keystore.AlgorithmMLKEM768:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_ML_KEM_768
+	case keystore.AlgorithmMLKEM1024:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_ML_KEM_1024
+	case keystore.AlgorithmMLKEM768X25519:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_ML_KEM_768_X25519
+	case keystore.AlgorithmChaCha20Poly1305:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_CHACHA20_POLY1305
+	case keystore.AlgorithmXChaCha20Poly1305:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_XCHACHA20_POLY1305
+	case keystore.AlgorithmAESSIV:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_AES_SIV
+	case keystore.AlgorithmRSA2048:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_RSA_2048
+	case keystore.AlgorithmRSA3072:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_RSA_3072
+	case keystore.AlgorithmRSA4096:
+		return pb.KeyAlgorithm_KEY_ALGORITHM_RSA_4096
 	default:
 		return pb.KeyAlgorithm_KEY_ALGORITHM_UNSPECIFIED
 	}
@@ -272,9 +538,149 @@ func statusFromProto(s pb.KeyStatus) keystore.KeyStatus {
 	}
 }
 
+func originToProto(o keystore.KeyOrigin) pb.KeyOrigin {
+	switch o {
+	case keystore.OriginImported:
+		return pb.KeyOrigin_KEY_ORIGIN_IMPORTED
+	default:
+		return pb.KeyOrigin_KEY_ORIGIN_GENERATED
+	}
+}
+
+func purposeToProto(p keystore.KeyPurpose) pb.KeyPurpose {
+	switch p {
+	case keystore.PurposeSigning:
+		return pb.KeyPurpose_KEY_PURPOSE_SIGNING
+	case keystore.PurposeDataEncryption:
+		return pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION
+	case keystore.PurposeMAC:
+		return pb.KeyPurpose_KEY_PURPOSE_MAC
+	case keystore.PurposePINEncryption:
+		return pb.KeyPurpose_KEY_PURPOSE_PIN_ENCRYPTION
+	case keystore.PurposeKeyEncryption:
+		return pb.KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION
+	case keystore.PurposeKeyBlockProtection:
+		return pb.KeyPurpose_KEY_PURPOSE_KEY_BLOCK_PROTECTION
+	case keystore.PurposeCardVerification:
+		return pb.KeyPurpose_KEY_PURPOSE_CARD_VERIFICATION
+	case keystore.PurposePINVerification:
+		return pb.KeyPurpose_KEY_PURPOSE_PIN_VERIFICATION
+	case keystore.PurposeBaseDerivation:
+		return pb.KeyPurpose_KEY_PURPOSE_BASE_DERIVATION
+	case keystore.PurposeKeyAgreement:
+		return pb.KeyPurpose_KEY_PURPOSE_KEY_AGREEMENT
+	case keystore.PurposeDeterministicEncryption:
+		return pb.KeyPurpose_KEY_PURPOSE_DETERMINISTIC_ENCRYPTION
+	case keystore.PurposeTokenFingerprint:
+		return pb.KeyPurpose_KEY_PURPOSE_TOKEN_FINGERPRINT
+	default:
+		return pb.KeyPurpose_KEY_PURPOSE_UNSPECIFIED
+	}
+}
+
+func purposeFromProto(p pb.KeyPurpose) keystore.KeyPurpose {
+	switch p {
+	case pb.KeyPurpose_KEY_PURPOSE_SIGNING:
+		return keystore.PurposeSigning
+	case pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION:
+		return keystore.PurposeDataEncryption
+	case pb.KeyPurpose_KEY_PURPOSE_MAC:
+		return keystore.PurposeMAC
+	case pb.KeyPurpose_KEY_PURPOSE_PIN_ENCRYPTION:
+		return keystore.PurposePINEncryption
+	case pb.KeyPurpose_KEY_PURPOSE_KEY_ENCRYPTION:
+		return keystore.PurposeKeyEncryption
+	case pb.KeyPurpose_KEY_PURPOSE_KEY_BLOCK_PROTECTION:
+		return keystore.PurposeKeyBlockProtection
+	case pb.KeyPurpose_KEY_PURPOSE_CARD_VERIFICATION:
+		return keystore.PurposeCardVerification
+	case pb.KeyPurpose_KEY_PURPOSE_PIN_VERIFICATION:
+		return keystore.PurposePINVerification
+	case pb.KeyPurpose_KEY_PURPOSE_BASE_DERIVATION:
+		return keystore.PurposeBaseDerivation
+	case pb.KeyPurpose_KEY_PURPOSE_KEY_AGREEMENT:
+		return keystore.PurposeKeyAgreement
+	case pb.KeyPurpose_KEY_PURPOSE_DETERMINISTIC_ENCRYPTION:
+		return keystore.PurposeDeterministicEncryption
+	case pb.KeyPurpose_KEY_PURPOSE_TOKEN_FINGERPRINT:
+		return keystore.PurposeTokenFingerprint
+	default:
+		return keystore.PurposeAny
+	}
+}
+
+func modeToProto(m keystore.KeyMode) pb.KeyModeOfUse {
+	switch m {
+	case keystore.ModeEncryptOnly:
+		return pb.KeyModeOfUse_KEY_MODE_OF_USE_ENCRYPT_ONLY
+	case keystore.ModeDecryptOnly:
+		return pb.KeyModeOfUse_KEY_MODE_OF_USE_DECRYPT_ONLY
+	case keystore.ModeGenerateOnly:
+		return pb.KeyModeOfUse_KEY_MODE_OF_USE_GENERATE_ONLY
+	case keystore.ModeVerifyOnly:
+		return pb.KeyModeOfUse_KEY_MODE_OF_USE_VERIFY_ONLY
+	case keystore.ModeDeriveOnly:
+		return pb.KeyModeOfUse_KEY_MODE_OF_USE_DERIVE_ONLY
+	default:
+		return pb.KeyModeOfUse_KEY_MODE_OF_USE_UNSPECIFIED
+	}
+}
+
+func modeFromProto(m pb.KeyModeOfUse) keystore.KeyMode {
+	switch m {
+	case pb.KeyModeOfUse_KEY_MODE_OF_USE_ENCRYPT_ONLY:
+		return keystore.ModeEncryptOnly
+	case pb.KeyModeOfUse_KEY_MODE_OF_USE_DECRYPT_ONLY:
+		return keystore.ModeDecryptOnly
+	case pb.KeyModeOfUse_KEY_MODE_OF_USE_GENERATE_ONLY:
+		return keystore.ModeGenerateOnly
+	case pb.KeyModeOfUse_KEY_MODE_OF_USE_VERIFY_ONLY:
+		return keystore.ModeVerifyOnly
+	case pb.KeyModeOfUse_KEY_MODE_OF_USE_DERIVE_ONLY:
+		return keystore.ModeDeriveOnly
+	default:
+		return keystore.ModeAny
+	}
+}
+
+// deactivateHSMKey deactivates the private key of an ECDSA key in
+// providers that follow key lifecycle, so they refuse everything but
+// decryption with it too. The vault has already deactivated the key, so a
+// failure is only logged.
+func (s *KeyManagementServer) deactivateHSMKey(entry *keystore.KeyEntry) {
+	if entry == nil || entry.Handle == "" {
+		return
+	}
+	p, err := keyProvider(s.providers, entry)
+	if err == nil {
+		err = hsm.DeactivateKey(p, entry.Handle)
+	}
+	if err != nil && !errors.Is(err, hsm.ErrUnsupported) {
+		slog.Warn("deactivate hsm key", "key_id", entry.ID, "provider", entry.Provider, "error", err)
+	}
+}
+
+// keyProvider returns the HSM provider holding an ECDSA key.
+func keyProvider(providers *hsm.Registry, entry *keystore.KeyEntry) (hsm.Provider, error) {
+	p, err := providers.Get(entry.Provider)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return p, nil
+}
+
 func keyError(err error) error {
 	if err == keystore.ErrKeyNotFound {
 		return status.Error(codes.NotFound, "key not found")
 	}
+	if err == keystore.ErrDerivedKey {
+		return status.Error(codes.FailedPrecondition, "derived keys follow their root key; rotate or deactivate the root")
+	}
+	if err == keystore.ErrNotDerivable {
+		return status.Error(codes.FailedPrecondition, "root key must be a symmetric key whose purpose allows derivation")
+	}
+	if errors.Is(err, keystore.ErrNoSoftwareProvider) {
+		return status.Error(codes.FailedPrecondition, "ECDSA keys can only be derived with a software hsm provider configured")
+	}
 	return status.Errorf(codes.Internal, "%v", err)
 }
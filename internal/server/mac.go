@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"hash"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// minMacLength is the shortest truncated MAC accepted (ISO 8583 field 64/128
+// MACs are commonly 4 bytes).
+const minMacLength = 4
+
+type MacServer struct {
+	pb.UnimplementedMacServiceServer
+	store keystore.Store
+	audit *audit.Logger
+}
+
+func NewMacServer(store keystore.Store, a *audit.Logger) *MacServer {
+	return &MacServer{
+		store: store,
+		audit: a,
+	}
+}
+
+func (s *MacServer) GenerateMac(ctx context.Context, req *pb.GenerateMacRequest) (*pb.GenerateMacResponse, error) {
+	m, alg, err := s.newMac(req.KeyId, req.Algorithm, req.Padding, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMacLength(m, req.MacLength); err != nil {
+		return nil, err
+	}
+
+	m.Write(req.Data)
+	mac := truncateMac(m.Sum(nil), req.MacLength)
+
+	s.audit.Log("GenerateMac", req.KeyId, "OK", "", map[string]string{"algorithm": alg.String()})
+	return &pb.GenerateMacResponse{Mac: mac, KeyId: req.KeyId}, nil
+}
+
+func (s *MacServer) VerifyMac(ctx context.Context, req *pb.VerifyMacRequest) (*pb.VerifyMacResponse, error) {
+	m, alg, err := s.newMac(req.KeyId, req.Algorithm, req.Padding, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Mac) < minMacLength {
+		return nil, status.Errorf(codes.InvalidArgument, "mac must be at least %d bytes", minMacLength)
+	}
+
+	m.Write(req.Data)
+	valid := crypto.EqualMAC(m.Sum(nil), req.Mac)
+
+	s.audit.Log("VerifyMac", req.KeyId, "OK", "", map[string]string{"algorithm": alg.String()})
+	return &pb.VerifyMacResponse{Valid: valid}, nil
+}
+
+func (s *MacServer) GenerateMacStream(stream grpc.ClientStreamingServer[pb.MacStreamRequest, pb.GenerateMacResponse]) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "empty stream")
+	}
+	if err != nil {
+		return err
+	}
+
+	m, alg, err := s.newMac(first.KeyId, first.Algorithm, first.Padding, true)
+	if err != nil {
+		return err
+	}
+	if err := checkMacLength(m, first.MacLength); err != nil {
+		return err
+	}
+
+	m.Write(first.Data)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		m.Write(req.Data)
+	}
+
+	mac := truncateMac(m.Sum(nil), first.MacLength)
+	s.audit.Log("GenerateMacStream", first.KeyId, "OK", "", map[string]string{"algorithm": alg.String()})
+	return stream.SendAndClose(&pb.GenerateMacResponse{Mac: mac, KeyId: first.KeyId})
+}
+
+func (s *MacServer) VerifyMacStream(stream grpc.ClientStreamingServer[pb.MacStreamRequest, pb.VerifyMacResponse]) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "empty stream")
+	}
+	if err != nil {
+		return err
+	}
+
+	m, alg, err := s.newMac(first.KeyId, first.Algorithm, first.Padding, false)
+	if err != nil {
+		return err
+	}
+
+	expected := first.Mac
+	m.Write(first.Data)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(req.Mac) > 0 {
+			expected = req.Mac
+		}
+		m.Write(req.Data)
+	}
+
+	if len(expected) < minMacLength {
+		return status.Errorf(codes.InvalidArgument, "mac must be at least %d bytes", minMacLength)
+	}
+	valid := crypto.EqualMAC(m.Sum(nil), expected)
+
+	s.audit.Log("VerifyMacStream", first.KeyId, "OK", "", map[string]string{"algorithm": alg.String()})
+	return stream.SendAndClose(&pb.VerifyMacResponse{Valid: valid})
+}
+
+// newMac looks up the key and returns a streaming MAC for it. Generation
+// requires an active key; verification accepts any status.
+func (s *MacServer) newMac(keyID string, algo pb.MacAlgorithm, padding pb.MacPadding, generate bool) (hash.Hash, crypto.MACAlgorithm, error) {
+	entry, err := s.store.Get(keyID)
+	if err != nil {
+		return nil, 0, keyError(err)
+	}
+	op := keystore.OpVerifyMAC
+	if generate {
+		if entry.Status != keystore.StatusActive {
+			return nil, 0, status.Error(codes.FailedPrecondition, "key is not active")
+		}
+		op = keystore.OpGenerateMAC
+	}
+	if err := checkPermits(entry, op); err != nil {
+		return nil, 0, err
+	}
+
+	alg, err := macAlgorithmFor(algo, entry.Algorithm)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m, err := crypto.NewMAC(alg, entry.SecretKey, macPaddingFromProto(padding))
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "init mac: %v", err)
+	}
+	return m, alg, nil
+}
+
+// macAlgorithmFor maps the requested MAC algorithm and checks that the key
+// type can be used with it.
+func macAlgorithmFor(algo pb.MacAlgorithm, key keystore.KeyAlgorithm) (crypto.MACAlgorithm, error) {
+	var alg crypto.MACAlgorithm
+	var ok bool
+	switch algo {
+	case pb.MacAlgorithm_MAC_ALGORITHM_ISO9797_ALG1:
+		alg, ok = crypto.MACISO9797Alg1, key == keystore.AlgorithmTDEA2Key || key == keystore.AlgorithmTDEA3Key
+	case pb.MacAlgorithm_MAC_ALGORITHM_ISO9797_ALG3:
+		alg, ok = crypto.MACISO9797Alg3, key == keystore.AlgorithmTDEA2Key
+	case pb.MacAlgorithm_MAC_ALGORITHM_AES_CMAC:
+		alg, ok = crypto.MACAESCMAC, key == keystore.AlgorithmAES128 || key == keystore.AlgorithmAES256
+	case pb.MacAlgorithm_MAC_ALGORITHM_HMAC_SHA256:
+		alg, ok = crypto.MACHMACSHA256, key == keystore.AlgorithmHMACSHA256
+	case pb.MacAlgorithm_MAC_ALGORITHM_HMAC_SHA512:
+		alg, ok = crypto.MACHMACSHA512, key == keystore.AlgorithmHMACSHA512
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported mac algorithm: %v", algo)
+	}
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "mac algorithm %v cannot be used with %v key", alg, key)
+	}
+	return alg, nil
+}
+
+func macPaddingFromProto(p pb.MacPadding) crypto.MACPadding {
+	if p == pb.MacPadding_MAC_PADDING_ISO9797_METHOD_2 {
+		return crypto.PaddingMethod2
+	}
+	return crypto.PaddingMethod1
+}
+
+func checkMacLength(m hash.Hash, length int32) error {
+	if length == 0 {
+		return nil
+	}
+	if length < minMacLength || int(length) > m.Size() {
+		return status.Errorf(codes.InvalidArgument, "mac_length must be %d-%d bytes", minMacLength, m.Size())
+	}
+	return nil
+}
+
+func truncateMac(mac []byte, length int32) []byte {
+	if length == 0 {
+		return mac
+	}
+	return mac[:length]
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/hsm"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+// testServer is a key management server over a memory store that resolves
+// keys derived by path, as the server's does, with a subscription to its
+// audit log.
+type testServer struct {
+	keys  *KeyManagementServer
+	store keystore.Store
+	audit *audit.Logger
+	sub   *audit.Subscriber
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	software := hsm.NewSoftwareHSM()
+	store := keystore.NewDerivedStore(keystore.NewMemoryStore(), &keystore.SoftwareKeys{Provider: "software", HSM: software})
+	a := audit.NewLogger(256, nil)
+	sub := a.Subscribe()
+	t.Cleanup(func() {
+		a.Unsubscribe(sub)
+		a.Close()
+	})
+	providers := hsm.NewRegistry()
+	if err := providers.Register("software", software); err != nil {
+		t.Fatalf("register provider: %v", err)
+	}
+	return &testServer{keys: NewKeyManagementServer(store, providers, a), store: store, audit: a, sub: sub}
+}
+
+// asPrincipal returns a context authenticated as the named principal with
+// perms, as the auth interceptors leave it.
+func asPrincipal(name string, perms ...string) context.Context {
+	return interceptor.ContextWithPrincipal(context.Background(), &interceptor.Principal{Name: name, Permissions: perms})
+}
+
+// generate generates a key as the default principal and returns its entry.
+func (ts *testServer) generate(t *testing.T, req *pb.GenerateKeyRequest) *keystore.KeyEntry {
+	t.Helper()
+	resp, err := ts.keys.GenerateKey(asPrincipal("default"), req)
+	if err != nil {
+		t.Fatalf("generate %v key: %v", req.Algorithm, err)
+	}
+	entry, err := ts.store.Get(resp.Metadata.KeyId)
+	if err != nil {
+		t.Fatalf("get key: %v", err)
+	}
+	return entry
+}
+
+// wantCode fails the test unless err carries code.
+func wantCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != code {
+		t.Fatalf("got %v (%v), want %v", got, err, code)
+	}
+}
+
+// waitAudit waits for the next audit entry of operation op and checks its
+// status.
+func waitAudit(t *testing.T, sub *audit.Subscriber, op, wantStatus string) audit.Entry {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-sub.C:
+			if e.Operation != op {
+				continue
+			}
+			if e.Status != wantStatus {
+				t.Fatalf("%s audited %s, want %s", op, e.Status, wantStatus)
+			}
+			return e
+		case <-timeout:
+			t.Fatalf("no %s audit entry", op)
+		}
+	}
+}
@@ -18,16 +18,19 @@ import (
 
 type SigningServer struct {
 	pb.UnimplementedSigningServiceServer
-	store keystore.Store
-	hsm   hsm.Provider
-	audit *audit.Logger
+	store     keystore.Store
+	providers *hsm.Registry
+	// maxBatchSize is the most payloads a BatchSign request may hold.
+	maxBatchSize int
+	audit        *audit.Logger
 }
 
-func NewSigningServer(store keystore.Store, h hsm.Provider, a *audit.Logger) *SigningServer {
+func NewSigningServer(store keystore.Store, providers *hsm.Registry, maxBatchSize int, a *audit.Logger) *SigningServer {
 	return &SigningServer{
-		store: store,
-		hsm:   h,
-		audit: a,
+		store:        store,
+		providers:    providers,
+		maxBatchSize: maxBatchSize,
+		audit:        a,
 	}
 }
 
@@ -39,11 +42,21 @@ func (s *SigningServer) Sign(ctx context.Context, req *pb.SignRequest) (*pb.Sign
 	if entry.Status != keystore.StatusActive {
 		return nil, status.Error(codes.FailedPrecondition, "key is not active")
 	}
+	if !entry.Algorithm.IsECDSA() {
+		return nil, status.Error(codes.FailedPrecondition, "key does not support signing")
+	}
+	if err := checkPermits(entry, keystore.OpSign); err != nil {
+		return nil, err
+	}
+	p, err := keyProvider(s.providers, entry)
+	if err != nil {
+		return nil, err
+	}
 
-	sig, err := s.hsm.Sign(entry.PrivateKey, req.Data)
+	sig, err := p.Sign(entry.Handle, req.Data)
 	if err != nil {
 		s.audit.Log("Sign", req.KeyId, "ERROR", "", nil)
-		return nil, status.Errorf(codes.Internal, "sign: %v", err)
+		return nil, hsmError(codes.Internal, "sign", err)
 	}
 
 	s.audit.Log("Sign", req.KeyId, "OK", "", nil)
@@ -55,14 +68,27 @@ func (s *SigningServer) Verify(ctx context.Context, req *pb.VerifyRequest) (*pb.
 	if err != nil {
 		return nil, keyError(err)
 	}
+	if !entry.Algorithm.IsECDSA() {
+		return nil, status.Error(codes.FailedPrecondition, "key does not support signing")
+	}
+	if err := checkPermits(entry, keystore.OpVerify); err != nil {
+		return nil, err
+	}
+	p, err := keyProvider(s.providers, entry)
+	if err != nil {
+		return nil, err
+	}
 
-	valid := s.hsm.Verify(&entry.PrivateKey.PublicKey, req.Data, req.Signature)
+	valid := p.Verify(entry.PublicKey, req.Data, req.Signature)
 	s.audit.Log("Verify", req.KeyId, "OK", "", nil)
 
 	return &pb.VerifyResponse{Valid: valid}, nil
 }
 
 func (s *SigningServer) BatchSign(ctx context.Context, req *pb.BatchSignRequest) (*pb.BatchSignResponse, error) {
+	if len(req.Data) > s.maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch holds %d payloads, at most %d are allowed", len(req.Data), s.maxBatchSize)
+	}
 	entry, err := s.store.Get(req.KeyId)
 	if err != nil {
 		return nil, keyError(err)
@@ -70,6 +96,16 @@ func (s *SigningServer) BatchSign(ctx context.Context, req *pb.BatchSignRequest)
 	if entry.Status != keystore.StatusActive {
 		return nil, status.Error(codes.FailedPrecondition, "key is not active")
 	}
+	if !entry.Algorithm.IsECDSA() {
+		return nil, status.Error(codes.FailedPrecondition, "key does not support signing")
+	}
+	if err := checkPermits(entry, keystore.OpSign); err != nil {
+		return nil, err
+	}
+	p, err := keyProvider(s.providers, entry)
+	if err != nil {
+		return nil, err
+	}
 
 	results := make([]*pb.SignResult, len(req.Data))
 	sem := make(chan struct{}, runtime.NumCPU())
@@ -82,7 +118,7 @@ func (s *SigningServer) BatchSign(ctx context.Context, req *pb.BatchSignRequest)
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			sig, err := s.hsm.Sign(entry.PrivateKey, data)
+			sig, err := p.Sign(entry.Handle, data)
 			if err != nil {
 				results[i] = &pb.SignResult{Error: err.Error()}
 				return
@@ -122,7 +158,22 @@ func (s *SigningServer) StreamSign(stream grpc.BidiStreamingServer[pb.StreamSign
 			continue
 		}
 
-		sig, err := s.hsm.Sign(entry.PrivateKey, req.Data)
+		if !entry.Algorithm.IsECDSA() || !entry.Permits(keystore.OpSign) {
+			if sendErr := stream.Send(&pb.StreamSignResponse{Error: "key does not support signing"}); sendErr != nil {
+				return sendErr
+			}
+			continue
+		}
+
+		p, err := keyProvider(s.providers, entry)
+		if err != nil {
+			if sendErr := stream.Send(&pb.StreamSignResponse{Error: status.Convert(err).Message()}); sendErr != nil {
+				return sendErr
+			}
+			continue
+		}
+
+		sig, err := p.Sign(entry.Handle, req.Data)
 		if err != nil {
 			if sendErr := stream.Send(&pb.StreamSignResponse{Error: err.Error()}); sendErr != nil {
 				return sendErr
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/keystore"
+)
+
+func (s *EncryptionServer) EncryptStream(stream grpc.BidiStreamingServer[pb.EncryptStreamRequest, pb.EncryptStreamResponse]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return firstMessageError(err)
+	}
+	entry, err := s.store.Get(first.KeyId)
+	if err != nil {
+		return keyError(err)
+	}
+	if entry.Status != keystore.StatusActive {
+		return status.Error(codes.FailedPrecondition, "key is not active")
+	}
+	alg, err := streamAlgorithm(entry, keystore.OpEncrypt)
+	if err != nil {
+		return err
+	}
+
+	send := sendFunc(func(p []byte) error {
+		return stream.Send(&pb.EncryptStreamResponse{Chunk: p})
+	})
+	enc, err := crypto.NewStreamEncrypter(send, alg, entry.SecretKey, first.Aad, 0)
+	if err != nil {
+		return status.Errorf(codes.Internal, "encrypt: %v", err)
+	}
+
+	n, err := pumpStream(first, stream.Recv, (*pb.EncryptStreamRequest).GetChunk, enc)
+	if err != nil {
+		s.audit.Log("EncryptStream", first.KeyId, "ERROR", "", map[string]string{"bytes": strconv.FormatInt(n, 10)})
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	s.audit.Log("EncryptStream", first.KeyId, "OK", "", map[string]string{"bytes": strconv.FormatInt(n, 10)})
+	return nil
+}
+
+func (s *EncryptionServer) DecryptStream(stream grpc.BidiStreamingServer[pb.DecryptStreamRequest, pb.DecryptStreamResponse]) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return firstMessageError(err)
+	}
+	entry, err := s.store.Get(first.KeyId)
+	if err != nil {
+		return keyError(err)
+	}
+	alg, err := streamAlgorithm(entry, keystore.OpDecrypt)
+	if err != nil {
+		return err
+	}
+
+	send := sendFunc(func(p []byte) error {
+		return stream.Send(&pb.DecryptStreamResponse{Chunk: p})
+	})
+	dec := crypto.NewStreamDecrypter(send, alg, entry.SecretKey, first.Aad)
+
+	n, err := pumpStream(first, stream.Recv, (*pb.DecryptStreamRequest).GetChunk, dec)
+	if err == nil {
+		err = dec.Close()
+	}
+	if err != nil {
+		s.audit.Log("DecryptStream", first.KeyId, "ERROR", "", map[string]string{"bytes": strconv.FormatInt(n, 10)})
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.InvalidArgument, "decrypt: %v", err)
+	}
+
+	s.audit.Log("DecryptStream", first.KeyId, "OK", "", map[string]string{"bytes": strconv.FormatInt(n, 10)})
+	return nil
+}
+
+// streamAlgorithm returns the cipher a key streams with. Streaming takes
+// the same symmetric keys as Encrypt; ECDSA keys are not supported.
+func streamAlgorithm(entry *keystore.KeyEntry, op keystore.KeyOperation) (crypto.AEADAlgorithm, error) {
+	alg := entry.Algorithm.AEAD()
+	if alg == 0 {
+		return 0, status.Errorf(codes.FailedPrecondition, "%v keys do not support streaming encryption", entry.Algorithm)
+	}
+	if err := checkPermits(entry, op); err != nil {
+		return 0, err
+	}
+	return alg, nil
+}
+
+// pumpStream writes the chunk of first and of every following request to
+// w until the client closes its side, returning the bytes consumed.
+func pumpStream[Req any](first *Req, recv func() (*Req, error), chunk func(*Req) []byte, w io.Writer) (int64, error) {
+	var n int64
+	for req := first; ; {
+		if _, err := w.Write(chunk(req)); err != nil {
+			return n, err
+		}
+		n += int64(len(chunk(req)))
+
+		var err error
+		if req, err = recv(); errors.Is(err, io.EOF) {
+			return n, nil
+		} else if err != nil {
+			return n, err
+		}
+	}
+}
+
+func firstMessageError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "stream closed before the first message")
+	}
+	return err
+}
+
+// sendFunc adapts a response stream to an io.Writer that sends every write
+// as one message.
+type sendFunc func([]byte) error
+
+func (f sendFunc) Write(p []byte) (int, error) {
+	if err := f(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/audit"
+	"github.com/glinharesb/vault-go/internal/crypto"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/keystore"
+	"github.com/glinharesb/vault-go/internal/tokenize"
+)
+
+// maxTokenAttempts bounds retries when a random token collides with one
+// already issued in the domain.
+const maxTokenAttempts = 8
+
+// tokenDomainLabel names the domain a token fingerprint key belongs to.
+const tokenDomainLabel = "token_domain"
+
+// TokenizationServer issues and resolves PAN tokens. PANs never appear in
+// audit entries; only the domain and token are recorded.
+type TokenizationServer struct {
+	pb.UnimplementedTokenizationServiceServer
+	store  keystore.Store
+	tokens tokenize.Table
+	audit  *audit.Logger
+
+	// mu serializes the creation of domain fingerprint keys, cached in
+	// fingerprintKeys by domain.
+	mu              sync.Mutex
+	fingerprintKeys map[string][]byte
+}
+
+func NewTokenizationServer(store keystore.Store, tokens tokenize.Table, a *audit.Logger) *TokenizationServer {
+	return &TokenizationServer{
+		store:           store,
+		tokens:          tokens,
+		audit:           a,
+		fingerprintKeys: make(map[string][]byte),
+	}
+}
+
+func (s *TokenizationServer) Tokenize(ctx context.Context, req *pb.TokenizeRequest) (*pb.TokenizeResponse, error) {
+	if req.Domain == "" {
+		return nil, status.Error(codes.InvalidArgument, "domain is required")
+	}
+	if err := tokenize.ValidatePAN(req.Pan); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	method := tokenMethodFromProto(req.Method)
+	format := tokenize.Format{
+		LuhnValid:        req.Format.GetLuhnValid(),
+		PreserveLastFour: req.Format.GetPreserveLastFour(),
+	}
+
+	entry, err := s.store.Get(req.KeyId)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if entry.Status != keystore.StatusActive {
+		return nil, status.Error(codes.FailedPrecondition, "key is not active")
+	}
+	if err := checkTokenKey(entry, method); err != nil {
+		return nil, err
+	}
+	if err := checkPermits(entry, keystore.OpEncrypt); err != nil {
+		return nil, err
+	}
+
+	tableKey, err := tokenTableKey(entry)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "derive token keys: %v", err)
+	}
+	fpKey, err := s.domainFingerprintKey(req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	fp := panFingerprint(fpKey, req.Domain, req.Pan)
+
+	rec, err := s.tokens.Find(req.Domain, fp)
+	if errors.Is(err, tokenize.ErrNotFound) {
+		rec, err = s.issueToken(entry, tableKey, fp, req.Domain, req.Pan, method, format)
+	}
+	if err != nil {
+		s.audit.Log("Tokenize", req.KeyId, "ERROR", "", map[string]string{"domain": req.Domain})
+		return nil, err
+	}
+	if rec.Method != method || rec.Format != format {
+		return nil, status.Error(codes.FailedPrecondition, "pan is already tokenized in this domain with a different method or format")
+	}
+
+	s.audit.Log("Tokenize", req.KeyId, "OK", "", map[string]string{"domain": req.Domain, "token": rec.Token})
+	return &pb.TokenizeResponse{Token: rec.Token, KeyId: rec.KeyID}, nil
+}
+
+func (s *TokenizationServer) Detokenize(ctx context.Context, req *pb.DetokenizeRequest) (*pb.DetokenizeResponse, error) {
+	p, _ := interceptor.PrincipalFromContext(ctx)
+	meta := map[string]string{"domain": req.Domain, "token": req.Token}
+	if p != nil {
+		meta["principal"] = p.Name
+	}
+	if !p.HasPermission(interceptor.PermissionDetokenize) {
+		s.audit.Log("Detokenize", "", "DENIED", "", meta)
+		return nil, status.Error(codes.PermissionDenied, "detokenize permission required")
+	}
+
+	rec, err := s.tokens.Get(req.Domain, req.Token)
+	if errors.Is(err, tokenize.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "token not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "lookup token: %v", err)
+	}
+
+	entry, err := s.store.Get(rec.KeyID)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	if err := checkPermits(entry, keystore.OpDecrypt); err != nil {
+		return nil, err
+	}
+	tableKey, err := tokenTableKey(entry)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "derive token keys: %v", err)
+	}
+
+	pan, err := crypto.DecryptAESGCM(tableKey, rec.Ciphertext, tokenAAD(rec.Domain, rec.Token))
+	if err != nil {
+		s.audit.Log("Detokenize", rec.KeyID, "ERROR", "", meta)
+		return nil, status.Errorf(codes.Internal, "decrypt token record: %v", err)
+	}
+
+	s.audit.Log("Detokenize", rec.KeyID, "OK", "", meta)
+	return &pb.DetokenizeResponse{Pan: string(pan)}, nil
+}
+
+// issueToken generates a token and stores the encrypted PAN. A concurrent
+// request for the same PAN may win the race; its record is returned then.
+func (s *TokenizationServer) issueToken(entry *keystore.KeyEntry, tableKey, fp []byte, domain, pan string, method tokenize.Method, format tokenize.Format) (*tokenize.Record, error) {
+	for range maxTokenAttempts {
+		var token string
+		var err error
+		switch method {
+		case tokenize.MethodFPE:
+			var c *crypto.FPE
+			if c, err = crypto.NewFPE(crypto.FPEFF1, entry.SecretKey, crypto.DigitsAlphabet); err != nil {
+				return nil, status.Errorf(codes.Internal, "init fpe: %v", err)
+			}
+			token, err = format.Derive(c, []byte(domain), pan)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "derive token: %v", err)
+			}
+		default:
+			if token, err = format.Random(pan); err != nil {
+				return nil, status.Errorf(codes.Internal, "generate token: %v", err)
+			}
+		}
+
+		ct, err := crypto.EncryptAESGCM(tableKey, []byte(pan), tokenAAD(domain, token))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "encrypt pan: %v", err)
+		}
+
+		rec, err := s.tokens.Put(&tokenize.Record{
+			Domain:      domain,
+			Token:       token,
+			KeyID:       entry.ID,
+			Method:      method,
+			Format:      format,
+			Fingerprint: fp,
+			Ciphertext:  ct,
+			CreatedAt:   time.Now(),
+		})
+		if errors.Is(err, tokenize.ErrTokenCollision) && method == tokenize.MethodRandom {
+			continue
+		}
+		if errors.Is(err, tokenize.ErrTokenCollision) {
+			return nil, status.Error(codes.AlreadyExists, "derived token is already issued in this domain")
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "store token: %v", err)
+		}
+		return rec, nil
+	}
+	return nil, status.Error(codes.ResourceExhausted, "could not find an unused token")
+}
+
+// checkTokenKey verifies the key type matches the tokenization method.
+func checkTokenKey(entry *keystore.KeyEntry, method tokenize.Method) error {
+	want := keystore.AlgorithmAES256
+	if method == tokenize.MethodFPE {
+		want = keystore.AlgorithmFPEAES256
+	}
+	if entry.Algorithm != want {
+		return status.Errorf(codes.FailedPrecondition, "%v tokens require a %v key", method, want)
+	}
+	return nil
+}
+
+// tokenTableKey derives the key a token record's PAN is encrypted under
+// from the tokenizing key.
+func tokenTableKey(entry *keystore.KeyEntry) ([]byte, error) {
+	return crypto.DeriveKey(entry.SecretKey, []byte("vault-token-table"), 32)
+}
+
+// domainFingerprintKey returns the key PANs are fingerprinted with in
+// domain, creating it on the domain's first use. It does not depend on the
+// tokenizing key, so a PAN keeps its token across key rotations and keys.
+func (s *TokenizationServer) domainFingerprintKey(domain string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.fingerprintKeys[domain]; ok {
+		return key, nil
+	}
+
+	entries, err := s.store.List(0)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list keys: %v", err)
+	}
+	var found *keystore.KeyEntry
+	for _, e := range entries {
+		if e.Purpose != keystore.PurposeTokenFingerprint || e.Labels[tokenDomainLabel] != domain {
+			continue
+		}
+		// Should two ever exist, the oldest is the one records use.
+		if found == nil || e.CreatedAt.Before(found.CreatedAt) {
+			found = e
+		}
+	}
+	if found == nil {
+		secret, err := crypto.GenerateSymmetricKey(32)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "generate fingerprint key: %v", err)
+		}
+		found = &keystore.KeyEntry{
+			ID:        uuid.NewString(),
+			Algorithm: keystore.AlgorithmHMACSHA256,
+			Status:    keystore.StatusActive,
+			SecretKey: secret,
+			Purpose:   keystore.PurposeTokenFingerprint,
+			CreatedAt: time.Now(),
+			Labels:    map[string]string{tokenDomainLabel: domain},
+		}
+		if err := s.store.Put(found); err != nil {
+			return nil, status.Errorf(codes.Internal, "store fingerprint key: %v", err)
+		}
+		s.audit.Log("CreateTokenDomain", found.ID, "OK", "", map[string]string{"domain": domain})
+	}
+	s.fingerprintKeys[domain] = found.SecretKey
+	return found.SecretKey, nil
+}
+
+// panFingerprint identifies a PAN within a domain without revealing it.
+func panFingerprint(key []byte, domain, pan string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(domain))
+	m.Write([]byte{0})
+	m.Write([]byte(pan))
+	return m.Sum(nil)
+}
+
+// tokenAAD binds an encrypted PAN to its domain and token.
+func tokenAAD(domain, token string) []byte {
+	return []byte(domain + "\x00" + token)
+}
+
+func tokenMethodFromProto(m pb.TokenMethod) tokenize.Method {
+	if m == pb.TokenMethod_TOKEN_METHOD_FPE {
+		return tokenize.MethodFPE
+	}
+	return tokenize.MethodRandom
+}
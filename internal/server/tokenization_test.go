@@ -0,0 +1,87 @@
+package server
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/interceptor"
+	"github.com/glinharesb/vault-go/internal/tokenize"
+)
+
+const testPAN = "4111111111111111"
+
+func TestDetokenizeRequiresPermission(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION,
+	})
+	tokens := NewTokenizationServer(ts.store, tokenize.NewMemoryTable(), ts.audit)
+	tok, err := tokens.Tokenize(asPrincipal("default"), &pb.TokenizeRequest{
+		KeyId:  key.ID,
+		Domain: "payments",
+		Pan:    testPAN,
+		Method: pb.TokenMethod_TOKEN_METHOD_RANDOM,
+	})
+	if err != nil {
+		t.Fatalf("tokenize: %v", err)
+	}
+	if tok.Token == testPAN {
+		t.Fatal("token should not be the PAN")
+	}
+
+	req := &pb.DetokenizeRequest{Domain: "payments", Token: tok.Token}
+	_, err = tokens.Detokenize(asPrincipal("default"), req)
+	wantCode(t, err, codes.PermissionDenied)
+	if e := waitAudit(t, ts.sub, "Detokenize", "DENIED"); e.Metadata["principal"] != "default" {
+		t.Fatalf("denial audited for %q", e.Metadata["principal"])
+	}
+
+	resp, err := tokens.Detokenize(asPrincipal("ops", interceptor.PermissionDetokenize), req)
+	if err != nil {
+		t.Fatalf("detokenize: %v", err)
+	}
+	if resp.Pan != testPAN {
+		t.Fatalf("detokenized %q, want %q", resp.Pan, testPAN)
+	}
+
+	_, err = tokens.Detokenize(asPrincipal("ops", interceptor.PermissionDetokenize), &pb.DetokenizeRequest{Domain: "other", Token: tok.Token})
+	wantCode(t, err, codes.NotFound)
+}
+
+func TestFingerprintKeyIsManaged(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.generate(t, &pb.GenerateKeyRequest{
+		Algorithm: pb.KeyAlgorithm_KEY_ALGORITHM_AES_256,
+		Purpose:   pb.KeyPurpose_KEY_PURPOSE_DATA_ENCRYPTION,
+	})
+	tokens := NewTokenizationServer(ts.store, tokenize.NewMemoryTable(), ts.audit)
+	req := &pb.TokenizeRequest{KeyId: key.ID, Domain: "payments", Pan: testPAN}
+	first, err := tokens.Tokenize(asPrincipal("default"), req)
+	if err != nil {
+		t.Fatalf("tokenize: %v", err)
+	}
+	fpKey := waitAudit(t, ts.sub, "CreateTokenDomain", "OK").KeyID
+
+	ctx := asPrincipal("default")
+	_, err = ts.keys.RotateKey(ctx, &pb.RotateKeyRequest{KeyId: fpKey})
+	wantCode(t, err, codes.FailedPrecondition)
+	_, err = ts.keys.DeactivateKey(ctx, &pb.DeactivateKeyRequest{KeyId: fpKey})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	// Rotating the tokenizing key keeps the PAN's token.
+	rotated, err := ts.keys.RotateKey(ctx, &pb.RotateKeyRequest{KeyId: key.ID})
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	req.KeyId = rotated.NewKey.KeyId
+	again, err := tokens.Tokenize(ctx, req)
+	if err != nil {
+		t.Fatalf("tokenize again: %v", err)
+	}
+	if again.Token != first.Token {
+		t.Fatal("PAN should keep its token in the domain")
+	}
+}
@@ -0,0 +1,153 @@
+package tokenize
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// ErrNoDistinctToken is returned by Derive when the only token the format
+// allows for a PAN is the PAN itself.
+var ErrNoDistinctToken = errors.New("no token distinct from the pan satisfies the format")
+
+// Method selects how a token is produced from a PAN.
+type Method int
+
+const (
+	// MethodRandom draws the token from a CSPRNG.
+	MethodRandom Method = iota + 1
+	// MethodFPE derives the token with FF1 under the domain as tweak.
+	MethodFPE
+)
+
+func (m Method) String() string {
+	switch m {
+	case MethodRandom:
+		return "RANDOM"
+	case MethodFPE:
+		return "FPE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Format constrains the shape of a token. Tokens always have the same
+// length as the PAN and consist only of digits.
+type Format struct {
+	// LuhnValid makes tokens pass the Luhn check.
+	LuhnValid bool `json:"luhn_valid,omitempty"`
+	// PreserveLastFour copies the last four PAN digits into the token.
+	PreserveLastFour bool `json:"preserve_last_four,omitempty"`
+}
+
+const (
+	minPANLength = 12
+	maxPANLength = 19
+	// maxRandomAttempts bounds the draws for a random token that satisfies
+	// the format and differs from the PAN.
+	maxRandomAttempts = 1000
+)
+
+// ValidatePAN checks that pan is 12-19 decimal digits.
+func ValidatePAN(pan string) error {
+	if len(pan) < minPANLength || len(pan) > maxPANLength {
+		return fmt.Errorf("pan must be %d-%d digits", minPANLength, maxPANLength)
+	}
+	for i := range len(pan) {
+		if pan[i] < '0' || pan[i] > '9' {
+			return errors.New("pan must contain only digits")
+		}
+	}
+	return nil
+}
+
+// Luhn reports whether the decimal string s passes the Luhn check.
+func Luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Random returns a random token for pan.
+func (f Format) Random(pan string) (string, error) {
+	body, suffix := f.split(pan)
+	for range maxRandomAttempts {
+		digits, err := randomDigits(len(body))
+		if err != nil {
+			return "", err
+		}
+		token := digits + suffix
+		if token != pan && f.accepts(token) {
+			return token, nil
+		}
+	}
+	return "", errors.New("no random token satisfies the format")
+}
+
+// Derive returns the FF1 token for pan. With LuhnValid, the cipher is
+// cycle-walked until the token passes the Luhn check; this stays a
+// permutation of Luhn-valid numbers only if pan itself is Luhn-valid.
+// Like Random, it never returns pan itself.
+func (f Format) Derive(c *crypto.FPE, tweak []byte, pan string) (string, error) {
+	if f.LuhnValid && !Luhn(pan) {
+		return "", errors.New("pan fails the luhn check")
+	}
+	body, suffix := f.split(pan)
+	// The walk terminates: at worst it cycles back to pan. Every number it
+	// passes is then on pan's cycle, so none other is acceptable and
+	// walking on would only repeat it.
+	for {
+		var err error
+		if body, err = c.Encrypt(tweak, body); err != nil {
+			return "", err
+		}
+		token := body + suffix
+		if token == pan {
+			return "", ErrNoDistinctToken
+		}
+		if f.accepts(token) {
+			return token, nil
+		}
+	}
+}
+
+func (f Format) split(pan string) (body, suffix string) {
+	if f.PreserveLastFour {
+		return pan[:len(pan)-4], pan[len(pan)-4:]
+	}
+	return pan, ""
+}
+
+func (f Format) accepts(token string) bool {
+	return !f.LuhnValid || Luhn(token)
+}
+
+func randomDigits(n int) (string, error) {
+	out := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("random digits: %w", err)
+		}
+		for _, b := range buf {
+			// Reject 250-255 so every digit is equally likely.
+			if b < 250 && len(out) < n {
+				out = append(out, '0'+b%10)
+			}
+		}
+	}
+	return string(out), nil
+}
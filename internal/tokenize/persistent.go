@@ -0,0 +1,100 @@
+package tokenize
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// PersistentTable wraps MemoryTable and persists to a JSON file using atomic
+// rename. Records only contain encrypted PANs.
+type PersistentTable struct {
+	*MemoryTable
+	path string
+}
+
+// NewPersistentTable creates a table that persists to the given file path,
+// loading existing records on startup.
+func NewPersistentTable(path string) (*PersistentTable, error) {
+	pt := &PersistentTable{
+		MemoryTable: NewMemoryTable(),
+		path:        path,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create data dir: %w", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if err := pt.load(); err != nil {
+			return nil, fmt.Errorf("load existing data: %w", err)
+		}
+		slog.Info("token table loaded", "tokens", len(pt.byToken))
+	}
+
+	return pt, nil
+}
+
+func (pt *PersistentTable) Put(r *Record) (*Record, error) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	stored, err := pt.put(r)
+	if err != nil || stored != r {
+		return stored, err
+	}
+	if err := pt.save(); err != nil {
+		delete(pt.byToken, tokenKey(r.Domain, r.Token))
+		delete(pt.byFingerprint, fingerprintKey(r.Domain, r.Fingerprint))
+		return nil, err
+	}
+	return r, nil
+}
+
+// save writes all records to a temp file then atomically renames it.
+// The caller must hold pt.mu.
+func (pt *PersistentTable) save() error {
+	records := make([]*Record, 0, len(pt.byToken))
+	for _, r := range pt.byToken {
+		records = append(records, r)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json: %w", err)
+	}
+
+	tmpPath := pt.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, pt.path); err != nil {
+		return fmt.Errorf("atomic rename: %w", err)
+	}
+
+	return nil
+}
+
+// load reads records from the persisted file.
+func (pt *PersistentTable) load() error {
+	data, err := os.ReadFile(pt.path)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	var records []*Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("unmarshal json: %w", err)
+	}
+
+	for _, r := range records {
+		if _, err := pt.put(r); err != nil {
+			return fmt.Errorf("load token in domain %s: %w", r.Domain, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package tokenize implements the PAN token table and token formats used by
+// the tokenization service.
+package tokenize
+
+import (
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when no token exists in the domain.
+	ErrNotFound = errors.New("token not found")
+	// ErrTokenCollision is returned when a token is already issued in the
+	// domain for a different PAN.
+	ErrTokenCollision = errors.New("token already issued for a different pan")
+)
+
+// Record is a row of the token table. The PAN is only held encrypted, and
+// is identified for deduplication by a keyed fingerprint.
+type Record struct {
+	Domain      string    `json:"domain"`
+	Token       string    `json:"token"`
+	KeyID       string    `json:"key_id"`
+	Method      Method    `json:"method"`
+	Format      Format    `json:"format"`
+	Fingerprint []byte    `json:"fingerprint"`
+	Ciphertext  []byte    `json:"ciphertext"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Table stores issued tokens per domain.
+type Table interface {
+	// Get returns the record for token in domain.
+	Get(domain, token string) (*Record, error)
+	// Find returns the record for a PAN fingerprint in domain.
+	Find(domain string, fingerprint []byte) (*Record, error)
+	// Put stores r. If the domain already holds a record with the same
+	// fingerprint, that record is returned instead and r is discarded.
+	Put(r *Record) (*Record, error)
+}
+
+// MemoryTable is a thread-safe in-memory token table.
+type MemoryTable struct {
+	mu            sync.RWMutex
+	byToken       map[string]*Record
+	byFingerprint map[string]*Record
+}
+
+func NewMemoryTable() *MemoryTable {
+	return &MemoryTable{
+		byToken:       make(map[string]*Record),
+		byFingerprint: make(map[string]*Record),
+	}
+}
+
+func (m *MemoryTable) Get(domain, token string) (*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.byToken[tokenKey(domain, token)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return r, nil
+}
+
+func (m *MemoryTable) Find(domain string, fingerprint []byte) (*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.byFingerprint[fingerprintKey(domain, fingerprint)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return r, nil
+}
+
+func (m *MemoryTable) Put(r *Record) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.put(r)
+}
+
+func (m *MemoryTable) put(r *Record) (*Record, error) {
+	fk := fingerprintKey(r.Domain, r.Fingerprint)
+	if existing, ok := m.byFingerprint[fk]; ok {
+		return existing, nil
+	}
+	tk := tokenKey(r.Domain, r.Token)
+	if _, ok := m.byToken[tk]; ok {
+		return nil, ErrTokenCollision
+	}
+	m.byToken[tk] = r
+	m.byFingerprint[fk] = r
+	return r, nil
+}
+
+func tokenKey(domain, token string) string {
+	return domain + "\x00" + token
+}
+
+func fingerprintKey(domain string, fingerprint []byte) string {
+	return domain + "\x00" + hex.EncodeToString(fingerprint)
+}
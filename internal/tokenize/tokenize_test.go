@@ -0,0 +1,136 @@
+package tokenize
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+func TestLuhn(t *testing.T) {
+	for pan, want := range map[string]bool{
+		"4111111111111111": true,
+		"5500005555555559": true,
+		"4111111111111112": false,
+		"79927398713":      true,
+	} {
+		if got := Luhn(pan); got != want {
+			t.Errorf("Luhn(%s) = %v, want %v", pan, got, want)
+		}
+	}
+}
+
+func TestValidatePAN(t *testing.T) {
+	if err := ValidatePAN("4111111111111111"); err != nil {
+		t.Fatalf("valid pan: %v", err)
+	}
+	for _, pan := range []string{"41111111111", "41111111111111111111", "4111-1111-1111-1111"} {
+		if err := ValidatePAN(pan); err == nil {
+			t.Errorf("%s: expected error", pan)
+		}
+	}
+}
+
+func TestRandomFormat(t *testing.T) {
+	pan := "4111111111111111"
+	f := Format{LuhnValid: true, PreserveLastFour: true}
+	for range 50 {
+		token, err := f.Random(pan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(token) != len(pan) || token == pan {
+			t.Fatalf("unexpected token %s", token)
+		}
+		if !strings.HasSuffix(token, "1111") || !Luhn(token) {
+			t.Fatalf("token %s does not match the format", token)
+		}
+	}
+}
+
+func TestDeriveIsDeterministicAndUnique(t *testing.T) {
+	key, _ := crypto.GenerateAESKey()
+	c, err := crypto.NewFPE(crypto.FPEFF1, key, crypto.DigitsAlphabet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := Format{LuhnValid: true, PreserveLastFour: true}
+	tweak := []byte("payments")
+
+	seen := map[string]string{}
+	for _, pan := range []string{"4111111111111111", "5500005555555559", "4012888888881881", "4222222222222220"} {
+		token, err := f.Derive(c, tweak, pan)
+		if err != nil {
+			t.Fatalf("%s: %v", pan, err)
+		}
+		again, _ := f.Derive(c, tweak, pan)
+		if token != again {
+			t.Fatalf("%s: derivation is not deterministic", pan)
+		}
+		if !Luhn(token) || token[12:] != pan[12:] {
+			t.Fatalf("%s: token %s does not match the format", pan, token)
+		}
+		if other, dup := seen[token]; dup {
+			t.Fatalf("%s and %s share token %s", pan, other, token)
+		}
+		seen[token] = pan
+	}
+
+	if _, err := f.Derive(c, tweak, "4111111111111112"); err == nil {
+		t.Fatal("expected error for a pan failing the luhn check")
+	}
+}
+
+func TestMemoryTableDeduplicates(t *testing.T) {
+	table := NewMemoryTable()
+	r := &Record{Domain: "d1", Token: "1234", Fingerprint: []byte{1}}
+	if got, err := table.Put(r); err != nil || got != r {
+		t.Fatalf("put: %v", err)
+	}
+
+	dup := &Record{Domain: "d1", Token: "5678", Fingerprint: []byte{1}}
+	if got, err := table.Put(dup); err != nil || got != r {
+		t.Fatalf("duplicate fingerprint should return the existing record, got %+v, %v", got, err)
+	}
+
+	collide := &Record{Domain: "d1", Token: "1234", Fingerprint: []byte{2}}
+	if _, err := table.Put(collide); !errors.Is(err, ErrTokenCollision) {
+		t.Fatalf("expected ErrTokenCollision, got %v", err)
+	}
+
+	other := &Record{Domain: "d2", Token: "1234", Fingerprint: []byte{1}}
+	if got, err := table.Put(other); err != nil || got != other {
+		t.Fatalf("domains must be independent: %v", err)
+	}
+
+	if _, err := table.Get("d1", "5678"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPersistentTableReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	table, err := NewPersistentTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Record{Domain: "d1", Token: "4111110000001111", KeyID: "k1", Method: MethodRandom,
+		Format: Format{PreserveLastFour: true}, Fingerprint: []byte{1, 2}, Ciphertext: []byte{3, 4}}
+	if _, err := table.Put(r); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewPersistentTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.Find("d1", []byte{1, 2})
+	if err != nil {
+		t.Fatalf("find after reload: %v", err)
+	}
+	if got.Token != r.Token || got.Method != MethodRandom || !got.Format.PreserveLastFour {
+		t.Fatalf("record mismatch: %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Package hpke seals messages to vault keys with HPKE (RFC 9180), so a
+// sender needs only the recipient's public key and no round trip to the
+// vault. The key holder opens them with EncryptionService.OpenHPKE.
+package hpke
+
+import (
+	"fmt"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+// Sealed is an encrypted message and the parameters needed to open it.
+type Sealed struct {
+	// Enc is the encapsulated key.
+	Enc []byte
+	// Ciphertext is the sealed message.
+	Ciphertext []byte
+	// AEAD is the AEAD the message was sealed with.
+	AEAD pb.HpkeAead
+}
+
+// Seal encrypts plaintext to a P-256, P-384 or X25519 public key as
+// returned in GetPublicKeyResponse.public_key_der. info and aad must be
+// supplied again to open the message.
+func Seal(publicKeyDER []byte, aead pb.HpkeAead, info, aad, plaintext []byte) (*Sealed, error) {
+	pub, err := crypto.ParseAgreementPublicKey(publicKeyDER)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := aeadFromProto(aead)
+	if err != nil {
+		return nil, err
+	}
+	enc, ct, err := crypto.SealHPKE(pub, alg, info, aad, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if aead == pb.HpkeAead_HPKE_AEAD_UNSPECIFIED {
+		aead = pb.HpkeAead_HPKE_AEAD_AES_128_GCM
+	}
+	return &Sealed{Enc: enc, Ciphertext: ct, AEAD: aead}, nil
+}
+
+// OpenRequest returns the OpenHPKE request for the message sealed to keyID.
+func (s *Sealed) OpenRequest(keyID string, info, aad []byte) *pb.OpenHPKERequest {
+	return &pb.OpenHPKERequest{
+		KeyId:      keyID,
+		Enc:        s.Enc,
+		Ciphertext: s.Ciphertext,
+		Info:       info,
+		Aad:        aad,
+		Aead:       s.AEAD,
+	}
+}
+
+func aeadFromProto(a pb.HpkeAead) (crypto.HPKEAEAD, error) {
+	switch a {
+	case pb.HpkeAead_HPKE_AEAD_AES_128_GCM, pb.HpkeAead_HPKE_AEAD_UNSPECIFIED:
+		return crypto.HPKEAES128GCM, nil
+	case pb.HpkeAead_HPKE_AEAD_AES_256_GCM:
+		return crypto.HPKEAES256GCM, nil
+	case pb.HpkeAead_HPKE_AEAD_CHACHA20_POLY1305:
+		return crypto.HPKEChaCha20Poly1305, nil
+	default:
+		return 0, fmt.Errorf("unsupported hpke aead: %v", a)
+	}
+}
@@ -0,0 +1,50 @@
+package hpke
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+
+	pb "github.com/glinharesb/vault-go/gen/vault/v1"
+	"github.com/glinharesb/vault-go/internal/crypto"
+)
+
+func TestSealOpen(t *testing.T) {
+	ecdsaKey, err := crypto.GenerateECDSAKey(elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ecdsaKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := crypto.MarshalPublicKey(&ecdsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := Seal(der, pb.HpkeAead_HPKE_AEAD_UNSPECIFIED, []byte("info"), []byte("aad"), []byte("hello"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	req := sealed.OpenRequest("key-1", []byte("info"), []byte("aad"))
+	if req.Aead != pb.HpkeAead_HPKE_AEAD_AES_128_GCM {
+		t.Fatalf("aead: got %v", req.Aead)
+	}
+
+	pt, err := crypto.OpenHPKE(key, crypto.HPKEAES128GCM, req.Info, req.Aad, req.Enc, req.Ciphertext)
+	if err != nil || !bytes.Equal(pt, []byte("hello")) {
+		t.Fatalf("open: %q, %v", pt, err)
+	}
+}
+
+func TestSealRejectsBadInput(t *testing.T) {
+	if _, err := Seal([]byte("junk"), pb.HpkeAead_HPKE_AEAD_AES_128_GCM, nil, nil, nil); err == nil {
+		t.Fatal("expected error for malformed public key")
+	}
+	key, _ := crypto.GenerateX25519Key()
+	der, _ := crypto.MarshalPublicKey(key.PublicKey())
+	if _, err := Seal(der, pb.HpkeAead(99), nil, nil, nil); err == nil {
+		t.Fatal("expected error for unknown aead")
+	}
+}